@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/maxboyko1/TubePlanner/pkg/transit"
+)
+
+// Interval in minutes between the candidate departure times considered by
+// the advisor
+const adviceStep = 5
+
+// Line statuses fetched longer ago than this are considered too stale to
+// base departure advice on
+const adviceStatusMaxAge = 24 * time.Hour
+
+// Advise whether to leave now or wait up to maxWait minutes for the trip
+// between the specified stations, based on the closures reported in the
+// status history store and any run times differing by time band, then
+// return the journey for the recommended departure. Any other search options
+// (which may be nil) apply to every departure.
+func RunAdvisor(start, dest string, maxWait uint16, statusStore string,
+	opts *transit.SearchOptions) *transit.Journey {
+	now := time.Now()
+	statuses, err := transit.OpenStatusHistory(statusStore).Latest(now.Add(-adviceStatusMaxAge))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: Could not read status history: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Each candidate departure is planned on a fresh graph, since running
+	// the shortest paths algorithm consumes the priority queue
+	plan := func(wait uint16) (*transit.Journey, error) {
+		graph, nodeMap := buildGraph()
+		waitOpts := transit.SearchOptions{}
+		if opts != nil {
+			waitOpts = *opts
+		}
+		waitOpts.DepartAt = now.Add(time.Duration(wait) * time.Minute)
+		// Lines already closed by the options (e.g. for a strike) stay
+		// closed, whatever their recorded status
+		closedLines := transit.ClosedLinesAt(statuses, waitOpts.DepartAt)
+		for line, closed := range waitOpts.ClosedLines {
+			closedLines[line] = closedLines[line] || closed
+		}
+		waitOpts.ClosedLines = closedLines
+		return transit.RunShortestPaths(&graph, nodeMap, start, dest, &waitOpts)
+	}
+	options, best := transit.AdviseDeparture(maxWait, adviceStep, func(wait uint16) (uint16, bool) {
+		journey, err := plan(wait)
+		if err != nil {
+			return 0, false
+		}
+		return journey.TotalMinutes, true
+	})
+	if best == nil {
+		fmt.Fprintf(os.Stderr, "ERROR: No route available from %s to %s within the next %d minutes\n",
+			start, dest, maxWait)
+		os.Exit(1)
+	}
+	transit.PrintDepartureAdvice(options, *best)
+	fmt.Println()
+	journey, _ := plan(best.Wait)
+	return journey
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/maxboyko1/TubePlanner/pkg/transit"
+)
+
+// Check the number of routes asked for with --alternatives, which must be
+// at least one and no more than transit.MaxAlternatives
+func checkAlternatives(n int) error {
+	if n < 1 || n > transit.MaxAlternatives {
+		return fmt.Errorf("--alternatives must be between 1 and %d", transit.MaxAlternatives)
+	}
+	return nil
+}
+
+// Describe how the travel time of an alternative route compares with that
+// of the fastest route
+func comparedWithFastest(route, fastest *transit.Route) string {
+	switch diff := int(route.TotalMinutes()) - int(fastest.TotalMinutes()); {
+	case diff > 0:
+		return fmt.Sprintf("%d minutes slower", diff)
+	case diff < 0:
+		return fmt.Sprintf("%d minutes faster, but less convenient", -diff)
+	default:
+		return "just as fast"
+	}
+}
+
+// Describe where an alternative route stands on the specified criterion,
+// other than travel time, that the routes are sorted by
+func describeOrder(journey *transit.Journey, order transit.JourneyOrder) string {
+	switch order {
+	case transit.OrderByChanges:
+		return fmt.Sprintf("%d changes", journey.Changes())
+	case transit.OrderByWalk:
+		return fmt.Sprintf("%d minutes walking", journey.WalkingMinutes())
+	case transit.OrderByFare:
+		if journey.Fare == nil {
+			return "fare unknown"
+		}
+		return journey.Fare.String()
+	}
+	return ""
+}
+
+// Print the specified alternative routes, fastest first, sorted by the
+// specified criterion in the specified output format. Fares are estimated
+// for setting off at the specified time. Text output notes how much slower
+// each route is than the fastest, and where it stands on the criterion.
+// Each route is stamped with its hash and the data version and options of
+// the specified stamp.
+func printAlternatives(routes []*transit.Route, order transit.JourneyOrder, departAt time.Time,
+	format string, width int, detailed bool, needs transit.AccessibilityAids, base *transit.Stamp) {
+	fastest := routes[0]
+	journeys := make(map[*transit.Route]*transit.Journey, len(routes))
+	for _, route := range routes {
+		journey := route.Journey()
+		if len(journey.Legs) > 0 {
+			if fare, err := transit.EstimateFare(journey, departAt); err == nil {
+				journey.Fare = &fare
+			}
+		}
+		journey.Stamp = transit.NewStamp(journey, base.DataVersion, base.Options)
+		journeys[route] = journey
+	}
+	routes = slices.Clone(routes)
+	slices.SortStableFunc(routes, func(a, b *transit.Route) int {
+		return transit.CompareJourneys(journeys[a], journeys[b], order)
+	})
+
+	switch format {
+	case "json":
+		sorted := make([]*transit.Journey, len(routes))
+		for idx, route := range routes {
+			sorted[idx] = journeys[route]
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		enc.Encode(sorted)
+	case "symbols":
+		for _, route := range routes {
+			fmt.Printf("%s [route %s, data %s]\n", transit.JourneySymbols(journeys[route]),
+				journeys[route].Stamp.RouteHash, base.DataVersion)
+		}
+	case "markdown":
+		for idx, route := range routes {
+			if idx > 0 {
+				fmt.Println()
+			}
+			fmt.Printf("Route %d of %d (%d minutes):\n\n", idx+1, len(routes), route.TotalMinutes())
+			fmt.Print(transit.JourneyMarkdown(journeys[route], departAt))
+			fmt.Printf("\nStamp: %s\n", journeys[route].Stamp)
+		}
+	case "csv":
+		// One table for every route, told apart by a leading route column
+		w := csv.NewWriter(os.Stdout)
+		w.Write(append([]string{"Route"}, transit.LegTableHeader...))
+		for idx, route := range routes {
+			for _, row := range transit.JourneyLegRows(journeys[route], departAt) {
+				w.Write(append([]string{strconv.Itoa(idx + 1)}, row...))
+			}
+			fmt.Fprintf(os.Stderr, "Route %d stamp: %s\n", idx+1, journeys[route].Stamp)
+		}
+		w.Flush()
+	default:
+		if order != transit.OrderByTime {
+			fmt.Printf("Sorted by %s.\n\n", order)
+		}
+		for idx, route := range routes {
+			if idx > 0 {
+				fmt.Println()
+			}
+			note := "fastest"
+			if route != fastest {
+				note = comparedWithFastest(route, fastest)
+			}
+			if order != transit.OrderByTime {
+				note += "; " + describeOrder(journeys[route], order)
+			}
+			fmt.Printf("Route %d of %d (%s):\n", idx+1, len(routes), note)
+			printRouteDirections(route, width, detailed, needs, base)
+		}
+	}
+}
+
+// Print the specified Pareto-optimal routes, fastest first, in the specified
+// output format, giving in text output the journey time, number of changes
+// and minutes walking of each, and stamping each as printAlternatives does
+func printTradeoffs(tradeoffs []transit.Tradeoff, format string, width int, detailed bool,
+	needs transit.AccessibilityAids, base *transit.Stamp) {
+	if format != "text" {
+		routes := make([]*transit.Route, len(tradeoffs))
+		for idx, tradeoff := range tradeoffs {
+			routes[idx] = tradeoff.Route
+		}
+		printAlternatives(routes, transit.OrderByTime, time.Time{}, format, width, detailed, needs, base)
+		return
+	}
+	for idx, tradeoff := range tradeoffs {
+		if idx > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("Route %d of %d (%d minutes, %d changes, %d minutes walking):\n", idx+1, len(tradeoffs),
+			tradeoff.TotalMinutes(), tradeoff.Changes, tradeoff.WalkingMinutes)
+		printRouteDirections(tradeoff.Route, width, detailed, needs, base)
+	}
+}
+
+// Print the directions for the specified route, with any notes on the
+// coverage of the transit data and on the specified accessibility needs,
+// and its stamp, with the data version and options of the specified one
+func printRouteDirections(route *transit.Route, width int, detailed bool, needs transit.AccessibilityAids,
+	base *transit.Stamp) {
+	journey := route.Journey()
+	if width > 0 {
+		transit.PrintDirectionsWidth(journey, width, detailed)
+	} else {
+		transit.PrintDirections(journey, detailed)
+	}
+	transit.PrintDataCoverage(journey)
+	if needs != 0 {
+		transit.PrintAccessibilityNotes(journey, needs)
+	}
+	fmt.Printf("Stamp: %s\n", transit.NewStamp(journey, base.DataVersion, base.Options))
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/maxboyko1/TubePlanner/pkg/transit"
+)
+
+// --alternatives accepts from one route up to the most the gRPC API allows,
+// and nothing outside that
+func TestCheckAlternatives(t *testing.T) {
+	for _, tc := range []struct {
+		n     int
+		valid bool
+	}{
+		{-1, false},
+		{0, false},
+		{1, true},
+		{3, true},
+		{transit.MaxAlternatives, true},
+		{transit.MaxAlternatives + 1, false},
+	} {
+		if err := checkAlternatives(tc.n); (err == nil) != tc.valid {
+			t.Errorf("checkAlternatives(%d) = %v, want valid %t", tc.n, err, tc.valid)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/maxboyko1/TubePlanner/pkg/transit"
+)
+
+// Columns of the rows written by --batch, one row per trip
+var batchHeader = []string{"from", "to", "minutes", "changes", "route", "error"}
+
+// Plan each trip listed in the CSV file at the specified path, or stdin if
+// it is "-", with the specified Planner, writing one CSV row per trip to
+// stdout as soon as it is planned. Each record gives the start and
+// destination as from,to, and any further columns are ignored, as is a
+// from,to header row and lines starting with "#". Trips that cannot be
+// planned get a row giving the reason, so the rows match the trips one for
+// one; only a file that cannot be read at all is fatal.
+func runBatch(planner *transit.Planner, path string, stepFree bool) {
+	var input io.Reader = os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+			os.Exit(1)
+		}
+		defer file.Close()
+		input = file
+	}
+	reader := csv.NewReader(input)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	w := csv.NewWriter(os.Stdout)
+	w.Write(batchHeader)
+
+	began := time.Now()
+	trips, failed := 0, 0
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			w.Flush()
+			fmt.Fprintf(os.Stderr, "ERROR: %s: %v\n", path, err)
+			os.Exit(1)
+		}
+		if trips == 0 && len(record) >= 2 && strings.EqualFold(record[0], "from") &&
+			strings.EqualFold(record[1], "to") {
+			continue
+		}
+		trips++
+		row := batchRow(planner, record, stepFree)
+		if row[len(row)-1] != "" {
+			failed++
+		}
+		w.Write(row)
+		w.Flush()
+	}
+	if err := w.Error(); err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Fprintf(os.Stderr, "Planned %d of %d trips in %v.\n", trips-failed, trips,
+		time.Since(began).Round(time.Millisecond))
+}
+
+// Return the row of --batch output for the trip given by the specified
+// record
+func batchRow(planner *transit.Planner, record []string, stepFree bool) []string {
+	failure := func(from, to string, err error) []string {
+		return []string{from, to, "", "", "", err.Error()}
+	}
+	if len(record) < 2 {
+		return failure(strings.Join(record, ""), "", errors.New("Expected a start and destination as from,to"))
+	}
+	from, to := strings.TrimSpace(record[0]), strings.TrimSpace(record[1])
+	nodeMap := planner.Graph().NodeMap()
+	for idx, station := range []string{from, to} {
+		if err := checkTripStation(nodeMap, station, idx > 0, stepFree); err != nil {
+			return failure(from, to, err)
+		}
+	}
+	for _, station := range []string{from, to} {
+		if planner.Options.AvoidStations[station] {
+			return failure(from, to, fmt.Errorf("Cannot avoid %s, where the trip begins or ends", station))
+		}
+	}
+	route, err := planner.Plan(from, to)
+	if err != nil {
+		return failure(from, to, noRouteError(planner, from, to, err))
+	}
+	journey := route.Journey()
+	return []string{from, to, strconv.Itoa(int(journey.TotalMinutes)), strconv.Itoa(journey.Changes()),
+		routeSummary(journey), ""}
+}
+
+// Return a short summary of the specified journey's route, e.g. "Victoria to
+// Stockwell, Northern to Bank", naming each line ridden and where to get off
+// it, along with any walks between stations
+func routeSummary(journey *transit.Journey) string {
+	parts := make([]string, 0, len(journey.Legs))
+	for _, leg := range journey.Legs {
+		switch leg.Type {
+		case string(transit.ModeRail):
+			parts = append(parts, leg.Line+" to "+leg.To)
+		case string(transit.ModeStationInterchange):
+			parts = append(parts, "walk to "+leg.To)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
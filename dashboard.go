@@ -0,0 +1,85 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/maxboyko1/TubePlanner/pkg/transit"
+)
+
+// ANSI escape sequence moving the cursor home and clearing the terminal,
+// used to redraw the dashboard in place
+const clearScreen = "\033[H\033[2J"
+
+// Entry point for the "dashboard" subcommand, which shows the configured
+// commutes and the latest line statuses full-screen, refreshing periodically
+// until interrupted. Each refresh fetches the line statuses afresh and adds
+// them to the status history store, unless --no-fetch is given.
+func RunDashboardCommand(args []string) {
+	fs := flag.NewFlagSet("dashboard", flag.ExitOnError)
+	commutesFlag := fs.String("commutes", transit.DefaultCommutesPath(),
+		"CSV `file` of commutes to show, one name,from,to per line")
+	storeFlag := fs.String("status-store", transit.DefaultStatusHistoryPath(), "path of the line status history store")
+	intervalFlag := fs.Duration("interval", time.Minute, "time between refreshes")
+	onceFlag := fs.Bool("once", false, "print the dashboard once and exit, without clearing the screen")
+	noInputFlag := fs.Bool("no-input", false, "run unattended, e.g. from cron, printing the dashboard once as --once does")
+	noFetchFlag := fs.Bool("no-fetch", false, "show the statuses already in the store without fetching new ones")
+	timeoutFlag := fs.Duration("live-timeout", 5*time.Second, "how long to wait for the line statuses on each refresh")
+	dataSourceFlags.register(fs)
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "USAGE: ./tubeplanner dashboard [--commutes <file>] [--interval 1m] [--once] [--no-input] [--no-fetch] "+dataSourceUsage)
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if *noInputFlag {
+		*onceFlag = true
+	}
+	if *intervalFlag < time.Second {
+		fmt.Fprintln(os.Stderr, "ERROR: Refresh interval must be at least 1s")
+		os.Exit(1)
+	}
+
+	_, nodeMap := buildGraph()
+	store := transit.OpenStatusHistory(*storeFlag)
+	for {
+		// Commutes and statuses are reloaded on every refresh, so that edits
+		// to the commutes file and newly recorded statuses show up
+		commutes, err := transit.LoadCommutes(*commutesFlag)
+		if errors.Is(err, os.ErrNotExist) {
+			fmt.Fprintf(os.Stderr, "ERROR: No commutes configured, add some to %s "+
+				"as name,from,to lines\n", *commutesFlag)
+			os.Exit(1)
+		} else if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+			os.Exit(1)
+		}
+		var fetchErr error
+		if !*noFetchFlag {
+			fetchErr = recordLineStatuses(store, *timeoutFlag)
+		}
+		now := time.Now()
+		statuses, err := store.Latest(now.Add(-adviceStatusMaxAge))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: Could not read status history: %v\n", err)
+			os.Exit(1)
+		}
+
+		if *onceFlag {
+			transit.RenderDashboard(os.Stdout, nodeMap, commutes, statuses, now)
+			if fetchErr != nil {
+				fmt.Fprintf(os.Stderr, "WARNING: %v\n", fetchErr)
+			}
+			return
+		}
+		fmt.Print(clearScreen)
+		transit.RenderDashboard(os.Stdout, nodeMap, commutes, statuses, now)
+		if fetchErr != nil {
+			fmt.Fprintf(os.Stderr, "WARNING: %v\n", fetchErr)
+		}
+		fmt.Printf("\nRefreshing every %s. Press Ctrl-C to quit.\n", *intervalFlag)
+		time.Sleep(*intervalFlag)
+	}
+}
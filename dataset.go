@@ -0,0 +1,145 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/maxboyko1/TubePlanner/pkg/transit"
+)
+
+// Entry point for the "dataset" subcommand, supporting "dataset export" to
+// write the built-in data (or a GTFS feed's or OpenStreetMap extract's) out
+// as a YAML dataset to start editing from, "dataset validate <file>" to check
+// a YAML dataset (or a directory of CSV files) against the schema, and
+// "dataset lint [--fix] <file>" to look for subtler problems and fix the
+// safe ones
+func RunDatasetCommand(args []string) {
+	usage := func() {
+		fmt.Fprintln(os.Stderr, "USAGE: ./tubeplanner dataset export [--gtfs <feed> | --osm <extract>] [--out <file>]")
+		fmt.Fprintln(os.Stderr, "       ./tubeplanner dataset validate <file or CSV directory>")
+		fmt.Fprintln(os.Stderr, "       ./tubeplanner dataset lint [--fix] [--out <file>] <file>")
+		os.Exit(1)
+	}
+	if len(args) == 0 {
+		usage()
+	}
+	switch args[0] {
+	case "export":
+		fs := flag.NewFlagSet("dataset export", flag.ExitOnError)
+		outFlag := fs.String("out", "", "YAML `file` to write the dataset to, instead of stdout")
+		var paths dataSourcePaths
+		fs.StringVar(&paths.gtfs, "gtfs", "", "convert the GTFS `feed` (directory or zip) instead of the built-in data")
+		fs.StringVar(&paths.osm, "osm", "", "convert the OpenStreetMap XML `extract` instead of the built-in data")
+		fs.Parse(args[1:])
+		source, err := paths.source()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+			os.Exit(1)
+		}
+		railLinks, interchanges, err := transit.LoadDataset(source)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+			os.Exit(1)
+		}
+		if *outFlag == "" {
+			err = transit.WriteYAMLDataset(os.Stdout, railLinks, interchanges)
+		} else {
+			err = transit.WriteFileAtomic(*outFlag, func(out io.Writer) error {
+				return transit.WriteYAMLDataset(out, railLinks, interchanges)
+			})
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: Writing dataset: %v\n", err)
+			os.Exit(1)
+		}
+	case "validate":
+		if len(args) != 2 {
+			usage()
+		}
+		source := transit.YAMLDataSource(args[1])
+		if transit.IsCSVDataDir(args[1]) {
+			source = transit.CSVDataSource(args[1])
+		}
+		railLinks, interchanges, err := transit.LoadDataset(source)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("%s is valid: %d rail links, %d interchanges.\n",
+			args[1], len(railLinks), len(interchanges))
+	case "lint":
+		runDatasetLint(args[1:], usage)
+	default:
+		usage()
+	}
+}
+
+// Lint the YAML dataset named in the specified arguments of "dataset lint",
+// printing each issue found and exiting with an error if there are any. With
+// --fix, the safe fixes are made and the dataset written back (or to --out),
+// with a report of each change, exiting with an error only if any issue is
+// left unfixed.
+func runDatasetLint(args []string, usage func()) {
+	fs := flag.NewFlagSet("dataset lint", flag.ExitOnError)
+	fixFlag := fs.Bool("fix", false, "fix the issues that can be fixed safely and write the dataset back")
+	outFlag := fs.String("out", "", "with --fix, the YAML `file` to write the fixed dataset to, instead of the original")
+	fs.Parse(args)
+	if fs.NArg() != 1 || (*outFlag != "" && !*fixFlag) {
+		usage()
+	}
+	path := fs.Arg(0)
+	file, err := os.Open(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+		os.Exit(1)
+	}
+	railLinks, interchanges, issues, err := transit.LintYAMLDataset(file)
+	file.Close()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: %s: %v\n", path, err)
+		os.Exit(1)
+	}
+
+	fixable, unfixed := 0, 0
+	for _, issue := range issues {
+		switch {
+		case issue.Fix == "":
+			fmt.Printf("%s: %s\n", path, issue.Message)
+			unfixed++
+		case *fixFlag:
+			fmt.Printf("%s: %s: %s\n", path, issue.Message, issue.Fix)
+			fixable++
+		default:
+			fmt.Printf("%s: %s (fixable)\n", path, issue.Message)
+			fixable++
+		}
+	}
+	switch {
+	case len(issues) == 0:
+		fmt.Printf("%s: no issues found.\n", path)
+		return
+	case !*fixFlag:
+		fmt.Printf("%d issues, %d of them fixable with --fix.\n", len(issues), fixable)
+		os.Exit(1)
+	case fixable > 0:
+		out := path
+		if *outFlag != "" {
+			out = *outFlag
+		}
+		err := transit.WriteFileAtomic(out, func(w io.Writer) error {
+			return transit.WriteYAMLDataset(w, railLinks, interchanges)
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: Writing dataset: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Fixed %d issues, wrote %s: %d rail links, %d interchanges.\n",
+			fixable, out, len(railLinks), len(interchanges))
+	}
+	if unfixed > 0 {
+		fmt.Fprintf(os.Stderr, "ERROR: Issues left to fix by hand: %d\n", unfixed)
+		os.Exit(1)
+	}
+}
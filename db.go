@@ -0,0 +1,138 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/maxboyko1/TubePlanner/pkg/transit"
+)
+
+// Entry point for the "db" subcommand, supporting "db init <file>" to store
+// the transit data in use in a SQLite network database for --db, and
+// "db add-link", "db remove-link", "db add-interchange" and
+// "db remove-interchange" to update it one connection at a time
+func RunDatabaseCommand(args []string) {
+	usage := func() {
+		fmt.Fprintln(os.Stderr, "USAGE: ./tubeplanner db init [--dataset <file> | --data-dir <directory> | --gtfs <feed> | --osm <extract>] <file>")
+		fmt.Fprintln(os.Stderr, "       ./tubeplanner db add-link <file> <from> <to> <line> <minutes>")
+		fmt.Fprintln(os.Stderr, "       ./tubeplanner db remove-link <file> <from> <to> <line>")
+		fmt.Fprintln(os.Stderr, "       ./tubeplanner db add-interchange <file> <from> <from line> <to> <to line> <minutes>")
+		fmt.Fprintln(os.Stderr, "       ./tubeplanner db remove-interchange <file> <from> <from line> <to> <to line>")
+		os.Exit(1)
+	}
+	if len(args) == 0 {
+		usage()
+	}
+	minutes := func(arg string) uint16 {
+		minutes, err := strconv.ParseUint(arg, 10, 16)
+		if err != nil || minutes == 0 {
+			fmt.Fprintf(os.Stderr, "ERROR: Invalid number of minutes: %s\n", arg)
+			os.Exit(1)
+		}
+		return uint16(minutes)
+	}
+	var err error
+	switch args[0] {
+	case "init":
+		runDatabaseInit(args[1:], usage)
+		return
+	case "add-link":
+		if len(args) != 6 {
+			usage()
+		}
+		db := transit.NetworkDatabase{Path: args[1]}
+		err = db.AddRailLink(transit.NewRailLink(args[2], args[3], args[4], minutes(args[5])))
+		if err == nil {
+			fmt.Printf("Set %s to %s on the %s line to %s minutes.\n", args[2], args[3], args[4], args[5])
+		}
+	case "remove-link":
+		if len(args) != 5 {
+			usage()
+		}
+		db := transit.NetworkDatabase{Path: args[1]}
+		var removed bool
+		if removed, err = db.RemoveRailLink(args[2], args[3], args[4]); err == nil && !removed {
+			err = fmt.Errorf("no rail link between %s and %s on the %s line", args[2], args[3], args[4])
+		} else if err == nil {
+			fmt.Printf("Removed %s to %s on the %s line.\n", args[2], args[3], args[4])
+		}
+	case "add-interchange":
+		if len(args) != 7 {
+			usage()
+		}
+		db := transit.NetworkDatabase{Path: args[1]}
+		err = db.AddInterchange(transit.NewInterchange(args[2], args[3], args[4], args[5], minutes(args[6])))
+		if err == nil {
+			fmt.Printf("Set %s (%s) to %s (%s) to %s minutes.\n", args[2], args[3], args[4], args[5], args[6])
+		}
+	case "remove-interchange":
+		if len(args) != 6 {
+			usage()
+		}
+		db := transit.NetworkDatabase{Path: args[1]}
+		var removed bool
+		if removed, err = db.RemoveInterchange(args[2], args[3], args[4], args[5]); err == nil && !removed {
+			err = fmt.Errorf("no interchange between %s (%s) and %s (%s)", args[2], args[3], args[4], args[5])
+		} else if err == nil {
+			fmt.Printf("Removed %s (%s) to %s (%s).\n", args[2], args[3], args[4], args[5])
+		}
+	default:
+		usage()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// Create the network database named in the specified arguments of
+// "db init", filling it with the built-in data or the dataset, CSV files,
+// GTFS feed or OpenStreetMap extract given, along with where each station is
+func runDatabaseInit(args []string, usage func()) {
+	fs := flag.NewFlagSet("db init", flag.ExitOnError)
+	var paths dataSourcePaths
+	fs.StringVar(&paths.dataset, "dataset", "", "store the YAML dataset `file` instead of the built-in data")
+	fs.StringVar(&paths.dataDir, "data-dir", "", "store the CSV files in the `directory` instead of the built-in data")
+	fs.StringVar(&paths.gtfs, "gtfs", "", "store the GTFS `feed` (directory or zip) instead of the built-in data")
+	fs.StringVar(&paths.osm, "osm", "", "store the OpenStreetMap XML `extract` instead of the built-in data")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		usage()
+	}
+	source, err := paths.source()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+		os.Exit(1)
+	}
+	railLinks, err := source.GetRailLinks()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+		os.Exit(1)
+	}
+	interchanges, err := source.GetInterchanges()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+		os.Exit(1)
+	}
+	stations, err := source.GetStations()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+		os.Exit(1)
+	}
+	coords := make(map[string]transit.Coordinates)
+	for _, station := range stations {
+		if station.Location != nil {
+			coords[station.Name] = *station.Location
+		}
+	}
+
+	db := transit.NetworkDatabase{Path: fs.Arg(0)}
+	if err := db.Create(railLinks, interchanges, coords); err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Wrote %s: %d rail links, %d interchanges, %d stations located.\n",
+		db.Path, len(railLinks), len(interchanges), len(coords))
+}
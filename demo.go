@@ -0,0 +1,150 @@
+package main
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/maxboyko1/TubePlanner/pkg/transit"
+)
+
+// Sample network for the "demo" subcommand: part of central London on six
+// lines, cut down from the built-in data
+//
+//go:embed demo.yaml
+var demoDataset string
+
+// The trip planned at every stop of the demo tour, which has a quick direct
+// route that is neither step-free nor off the Northern line
+const demoStart, demoDest = "King's Cross St. Pancras", "Waterloo"
+
+// Represents one stop of the demo tour: what it shows, the command that
+// plans the same trip on the full network, the search options it sets, and
+// a check of the route found against the fastest one, so that the tour
+// doubles as a smoke test of the whole planner
+type demoStop struct {
+	title   string
+	command string
+	setup   func(opts *transit.SearchOptions)
+	check   func(route, fastest *transit.Route) error
+}
+
+// Return whether the specified route rides the specified line at any point
+func ridesLine(route *transit.Route, line string) bool {
+	return slices.ContainsFunc(route.Journey().Legs, func(leg transit.Leg) bool {
+		return leg.IsRide() && leg.Line == line
+	})
+}
+
+var demoTour = []demoStop{
+	{
+		title:   "The fastest route",
+		command: `./tubeplanner "King's Cross St. Pancras" Waterloo`,
+		setup:   func(*transit.SearchOptions) {},
+		check: func(route, fastest *transit.Route) error {
+			if !ridesLine(route, "Northern") {
+				return errors.New("the fastest route no longer rides the Northern line")
+			}
+			return nil
+		},
+	},
+	{
+		title:   "A step-free route, using only stations with lifts or ramps to the platforms",
+		command: `./tubeplanner --step-free "King's Cross St. Pancras" Waterloo`,
+		setup:   func(opts *transit.SearchOptions) { opts.StepFree = true },
+		check: func(route, fastest *transit.Route) error {
+			if route.TotalMinutes() < fastest.TotalMinutes() {
+				return fmt.Errorf("the step-free route takes %d minutes, less than the fastest route's %d",
+					route.TotalMinutes(), fastest.TotalMinutes())
+			}
+			return nil
+		},
+	},
+	{
+		title:   "Avoiding the Northern line",
+		command: `./tubeplanner --avoid-line Northern "King's Cross St. Pancras" Waterloo`,
+		setup:   func(opts *transit.SearchOptions) { opts.ClosedLines = map[string]bool{"Northern": true} },
+		check: func(route, fastest *transit.Route) error {
+			if ridesLine(route, "Northern") {
+				return errors.New("the route still rides the Northern line")
+			}
+			return nil
+		},
+	},
+}
+
+// Plan the demo trip on the specified graph as the stop says, returning the
+// route found if it passes the stop's check against the specified fastest
+// route
+func (stop demoStop) plan(graph *transit.Graph, fastest *transit.Route) (*transit.Route, error) {
+	planner := transit.NewPlanner(graph)
+	stop.setup(&planner.Options)
+	route, err := planner.Plan(demoStart, demoDest)
+	if err != nil {
+		return nil, err
+	}
+	if err := stop.check(route, fastest); err != nil {
+		return nil, err
+	}
+	return route, nil
+}
+
+// Return the graph of the sample network built into the program
+func loadDemoGraph() (*transit.Graph, error) {
+	railLinks, interchanges, err := transit.ParseYAMLDataset(strings.NewReader(demoDataset))
+	if err != nil {
+		return nil, err
+	}
+	nodeMap, err := transit.BuildDatasetGraph(railLinks, interchanges)
+	if err != nil {
+		return nil, err
+	}
+	return transit.NewGraph(nodeMap), nil
+}
+
+// Entry point for the "demo" subcommand, which loads a small sample network
+// built into the program and plans the same trip on it a few different ways,
+// printing each command and its directions as a guided tour. The routes
+// found are checked as it goes, and the program exits with an error if any
+// is wrong.
+func RunDemoCommand(args []string) {
+	if len(args) != 0 {
+		fmt.Fprintln(os.Stderr, "USAGE: ./tubeplanner demo")
+		os.Exit(1)
+	}
+	graph, err := loadDemoGraph()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: Invalid demo dataset: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("TubePlanner demo: a sample network of %d stations in central London, "+
+		"with all-day run times.\n", len(graph.Stations()))
+
+	fastest, err := transit.NewPlanner(graph).Plan(demoStart, demoDest)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: Planning the fastest demo route: %v\n", err)
+		os.Exit(1)
+	}
+	failures := 0
+	for idx, stop := range demoTour {
+		fmt.Printf("\n%d) %s\n$ %s\n", idx+1, stop.title, stop.command)
+		route, err := stop.plan(graph, fastest)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: Demo stop %d: %v\n", idx+1, err)
+			failures++
+			continue
+		}
+		for _, line := range route.Directions(false, false) {
+			fmt.Println(line)
+		}
+	}
+	if failures > 0 {
+		fmt.Fprintf(os.Stderr, "ERROR: %d of the %d demo stops went wrong\n", failures, len(demoTour))
+		os.Exit(1)
+	}
+	fmt.Println("\nThat's the tour. The commands above plan the same trips on the full network; " +
+		"see the README for everything else.")
+}
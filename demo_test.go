@@ -0,0 +1,43 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/maxboyko1/TubePlanner/pkg/transit"
+)
+
+// Return the graph built from the bundled transit data alone, with none of
+// the user's graph cache, station overrides, lift outages or link times
+func defaultGraph(t *testing.T) *transit.Graph {
+	t.Helper()
+	_, nodeMap, err := transit.BuildTransitGraph(transit.DefaultGraphOptions())
+	if err != nil {
+		t.Fatalf("BuildTransitGraph() failed: %v", err)
+	}
+	return transit.NewGraph(nodeMap)
+}
+
+// Every stop of the demo tour finds a route passing its check, both on the
+// sample network and on the full network its commands plan the trip on
+func TestDemoTour(t *testing.T) {
+	demo, err := loadDemoGraph()
+	if err != nil {
+		t.Fatalf("loading the demo dataset: %v", err)
+	}
+	for name, graph := range map[string]*transit.Graph{"demo": demo, "default": defaultGraph(t)} {
+		fastest, err := transit.NewPlanner(graph).Plan(demoStart, demoDest)
+		if err != nil {
+			t.Fatalf("%s network: planning the fastest route: %v", name, err)
+		}
+		if fastest.Start() != demoStart || fastest.Destination() != demoDest ||
+			fastest.TotalMinutes() == 0 || fastest.TotalMinutes() > 30 {
+			t.Errorf("%s network: fastest route goes from %s to %s in %d minutes",
+				name, fastest.Start(), fastest.Destination(), fastest.TotalMinutes())
+		}
+		for idx, stop := range demoTour {
+			if _, err := stop.plan(graph, fastest); err != nil {
+				t.Errorf("%s network: stop %d (%s): %v", name, idx+1, stop.title, err)
+			}
+		}
+	}
+}
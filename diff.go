@@ -0,0 +1,156 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/maxboyko1/TubePlanner/pkg/transit"
+)
+
+// Entry point for the "diff" subcommand, which builds the transit graph from
+// two versions of the transit data and reports how the journey times
+// between pairs of stations change from the first to the second, e.g. to
+// weigh up a proposed new link or the effect of a closure
+func RunDiffCommand(args []string) {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	pairsFlag := fs.String("pairs", "", "CSV `file` of trips to compare, one from,to per line")
+	sampleFlag := fs.Int("sample", 1000,
+		"compare this many `N` trips between random pairs of stations (0 for every pair)")
+	seedFlag := fs.Uint64("seed", 1, "`seed` for choosing the random pairs of stations, the same each run")
+	topFlag := fs.Int("top", 20, "list the `N` biggest changes (0 for every change)")
+	formatFlag := fs.String("format", "text", "output `format`: a text summary, or csv of every trip that changed")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "USAGE: ./tubeplanner diff [--pairs <file> | --sample 1000] [--top 20] [--format csv] "+
+			"<before> <after>")
+		fmt.Fprintln(os.Stderr, "Each of <before> and <after> is a YAML dataset, CSV data directory, GTFS feed, "+
+			"OSM extract, network database, or builtin for the built-in data.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 2 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	if *formatFlag != "text" && *formatFlag != "csv" {
+		fmt.Fprintf(os.Stderr, "ERROR: Unknown output format: %s\n", *formatFlag)
+		os.Exit(1)
+	}
+	if *sampleFlag < 0 || *topFlag < 0 {
+		fmt.Fprintln(os.Stderr, "ERROR: --sample and --top cannot be negative")
+		os.Exit(1)
+	}
+
+	// The graph cache holds the graph of the transit data in use, so is left
+	// alone while building those being compared
+	var nodeMaps [2]transit.NodeMap
+	for idx, path := range fs.Args() {
+		opts := graphOptions
+		opts.Source, opts.CachePath = transit.DataSourceAt(path), ""
+		_, nodeMap, err := transit.BuildTransitGraph(opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+			os.Exit(1)
+		}
+		nodeMaps[idx] = nodeMap
+	}
+	before, after := nodeMaps[0], nodeMaps[1]
+
+	var pairs [][2]string
+	switch {
+	case *pairsFlag != "":
+		file, err := os.Open(*pairsFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+			os.Exit(1)
+		}
+		// Stations new in the second version, or gone from it, may be given
+		either := transit.UnionStations(before, after)
+		pairs, err = transit.LoadStationPairs(file, either)
+		file.Close()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: %s: %v\n", *pairsFlag, err)
+			os.Exit(1)
+		}
+	case *sampleFlag == 0:
+		pairs = transit.AllStationPairs(before, after)
+	default:
+		pairs = transit.SampleStationPairs(before, after, *sampleFlag, rand.New(rand.NewPCG(*seedFlag, 0)))
+	}
+	diff := transit.CompareNetworks(before, after, pairs, nil)
+
+	if *formatFlag == "csv" {
+		minutes := func(t uint16) string {
+			if t == math.MaxUint16 {
+				return ""
+			}
+			return strconv.Itoa(int(t))
+		}
+		w := csv.NewWriter(os.Stdout)
+		w.Write([]string{"from", "to", "before", "after", "change"})
+		for _, change := range diff.Changes {
+			w.Write([]string{change.From, change.To, minutes(change.Before), minutes(change.After),
+				strconv.Itoa(change.Minutes())})
+		}
+		w.Flush()
+		return
+	}
+	printNetworkDiff(diff, fs.Arg(0), fs.Arg(1), *topFlag)
+}
+
+// Print a summary of how the trips compared between the specified versions
+// of the transit data change, followed by the specified number of biggest
+// changes (or every change if zero)
+func printNetworkDiff(diff *transit.NetworkDiff, beforeName, afterName string, top int) {
+	fmt.Printf("Compared %d trips from %s to %s:\n", diff.Trips, beforeName, afterName)
+	fmt.Printf("- %d faster\n- %d slower\n- %d unchanged\n", diff.Faster, diff.Slower, diff.Unchanged)
+	if diff.Connected > 0 {
+		fmt.Printf("- %d only possible after the change\n", diff.Connected)
+	}
+	if diff.Disconnected > 0 {
+		fmt.Printf("- %d no longer possible\n", diff.Disconnected)
+	}
+	if len(diff.Changes) == 0 {
+		return
+	}
+	total := 0
+	for _, change := range diff.Changes {
+		total += change.Minutes()
+	}
+	verb := "adds"
+	if total < 0 {
+		verb, total = "saves", -total
+	}
+	fmt.Printf("Over the trips compared, the change %s %d minutes in all, %.2f minutes a trip on average.\n",
+		verb, total, float64(total)/float64(diff.Trips))
+
+	changes := diff.Changes
+	if top > 0 && len(changes) > top {
+		changes = changes[:top]
+		fmt.Printf("\nThe %d biggest changes:\n", top)
+	} else {
+		fmt.Println("\nChanges:")
+	}
+	minutes := func(t uint16) string {
+		if t == math.MaxUint16 {
+			return "no route"
+		}
+		return strconv.Itoa(int(t))
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "From\tTo\tBefore\tAfter\tChange")
+	for _, change := range changes {
+		delta := fmt.Sprintf("%+d", change.Minutes())
+		if change.Before == math.MaxUint16 || change.After == math.MaxUint16 {
+			delta = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", change.From, change.To, minutes(change.Before),
+			minutes(change.After), delta)
+	}
+	tw.Flush()
+}
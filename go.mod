@@ -0,0 +1,3 @@
+module github.com/maxboyko1/TubePlanner
+
+go 1.24
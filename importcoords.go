@@ -0,0 +1,105 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/maxboyko1/TubePlanner/pkg/transit"
+)
+
+// Entry point for the "import-coords" subcommand, which fills in missing
+// station coordinates from a reference CSV file or the TfL StopPoint API and
+// writes the merged result back out as Go source
+func RunImportCoordsCommand(args []string, nodeMap transit.NodeMap) {
+	fs := flag.NewFlagSet("import-coords", flag.ExitOnError)
+	csvFlag := fs.String("csv", "", "reference CSV `file` with station name, latitude and longitude columns")
+	tflFlag := fs.Bool("tfl", false, "look stations up through the TfL StopPoint API")
+	outFlag := fs.String("out", "pkg/transit/stationcoords.go", "Go source `file` to write the coordinates to")
+	overwriteFlag := fs.Bool("overwrite", false, "replace coordinates already in the dataset")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "USAGE: ./tubeplanner import-coords (--csv <file> | --tfl) [--out <file>] [--overwrite]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if (*csvFlag == "") == !*tflFlag {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	var reference map[string]transit.Coordinates
+	if *csvFlag != "" {
+		file, err := os.Open(*csvFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+			os.Exit(1)
+		}
+		reference, err = transit.ReadReferenceCoordinates(file)
+		file.Close()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: %s: %v\n", *csvFlag, err)
+			os.Exit(1)
+		}
+	}
+
+	stations := make([]string, 0, len(nodeMap))
+	for station := range nodeMap {
+		stations = append(stations, station)
+	}
+	slices.Sort(stations)
+	coords := transit.GetStationCoordinates()
+	client := &http.Client{Timeout: 10 * time.Second}
+	imported, missing := 0, make([]string, 0)
+	for _, station := range stations {
+		if _, exists := coords[station]; exists && !*overwriteFlag {
+			continue
+		}
+		found := false
+		if reference != nil {
+			var c transit.Coordinates
+			for _, candidate := range transit.StationNameCandidates(station) {
+				if c, found = reference[candidate]; found {
+					coords[station] = c
+					break
+				}
+			}
+		} else {
+			c, ok, err := transit.LookupTfLCoordinates(client, station)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "ERROR: Looking up %s: %v\n", station, err)
+				os.Exit(1)
+			}
+			if found = ok; found {
+				coords[station] = c
+			}
+		}
+		if found {
+			imported++
+		} else if _, exists := coords[station]; !exists {
+			missing = append(missing, station)
+		}
+	}
+
+	source, err := transit.GenerateCoordinatesSource(coords)
+	if err == nil {
+		err = transit.WriteFileAtomic(*outFlag, func(out io.Writer) error {
+			_, err := out.Write(source)
+			return err
+		})
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: Writing %s: %v\n", *outFlag, err)
+		os.Exit(1)
+	}
+	fmt.Printf("Imported coordinates for %d stations into %s.\n", imported, *outFlag)
+	if len(missing) > 0 {
+		fmt.Printf("No coordinates found for %d stations:\n", len(missing))
+		for _, station := range missing {
+			fmt.Printf("- %s\n", station)
+		}
+	}
+}
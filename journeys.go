@@ -0,0 +1,153 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/maxboyko1/TubePlanner/pkg/transit"
+)
+
+// Open the journey store at the specified location, exiting if it cannot be
+// used
+func openJourneyStore(location string) transit.JourneyStore {
+	store, err := transit.OpenJourneyStore(location)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+		os.Exit(1)
+	}
+	return store
+}
+
+// Return the favourite with the specified name from the journey store at the
+// specified location, exiting if there is none
+func findFavourite(location, name string) transit.Favourite {
+	favourites, err := openJourneyStore(location).Favourites("")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: Could not read favourites: %v\n", err)
+		os.Exit(1)
+	}
+	for _, favourite := range favourites {
+		if favourite.Name == name {
+			return favourite
+		}
+	}
+	fmt.Fprintf(os.Stderr, "ERROR: No favourite named %s, see ./tubeplanner favourites list\n", name)
+	os.Exit(1)
+	return transit.Favourite{}
+}
+
+// Add the specified journey to the history in the journey store at the
+// specified location, warning rather than failing if it cannot be written
+func recordJourney(location string, journey *transit.Journey) {
+	store, err := transit.OpenJourneyStore(location)
+	if err == nil {
+		err = store.RecordJourney("", transit.NewHistoryEntry(journey, time.Now()))
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "WARNING: Could not add the journey to the history: %v\n", err)
+	}
+}
+
+// Entry point for the "favourites" subcommand, supporting
+// "favourites add <name> <start> <destination>", "favourites list" and
+// "favourites remove <name>", each with [--journey-store <path>]
+func RunFavouritesCommand(args []string) {
+	usage := func() {
+		fmt.Fprintln(os.Stderr, "USAGE: ./tubeplanner favourites add <name> <start> <destination> [--journey-store <path>]")
+		fmt.Fprintln(os.Stderr, "       ./tubeplanner favourites list [--journey-store <path>]")
+		fmt.Fprintln(os.Stderr, "       ./tubeplanner favourites remove <name> [--journey-store <path>]")
+		os.Exit(1)
+	}
+	// Each action takes its arguments before any flags
+	arity := map[string]int{"add": 3, "list": 0, "remove": 1}
+	if len(args) == 0 {
+		usage()
+	}
+	n, known := arity[args[0]]
+	if !known || len(args) < 1+n {
+		usage()
+	}
+	params := args[1 : 1+n]
+	fs := flag.NewFlagSet("favourites "+args[0], flag.ExitOnError)
+	storeFlag := fs.String("journey-store", transit.DefaultJourneyStorePath(),
+		"path of the JSON or SQLite (.db) journey store")
+	fs.Parse(args[1+n:])
+	if fs.NArg() != 0 {
+		usage()
+	}
+	store := openJourneyStore(*storeFlag)
+
+	switch args[0] {
+	case "add":
+		favourite := transit.Favourite{Name: strings.TrimPrefix(params[0], "@"), From: params[1], To: params[2]}
+		_, nodeMap := buildGraph()
+		for _, station := range []string{favourite.From, favourite.To} {
+			if _, exists := nodeMap[station]; !exists {
+				fmt.Fprintf(os.Stderr, "ERROR: %s is not a valid station\n", station)
+				os.Exit(1)
+			}
+		}
+		if err := store.SaveFavourite("", favourite); err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: Could not save the favourite: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Saved %s to %s as @%s.\n", favourite.From, favourite.To, favourite.Name)
+	case "list":
+		favourites, err := store.Favourites("")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: Could not read favourites: %v\n", err)
+			os.Exit(1)
+		}
+		if len(favourites) == 0 {
+			fmt.Println("No favourites saved.")
+		}
+		for _, favourite := range favourites {
+			fmt.Printf("@%s: %s to %s\n", favourite.Name, favourite.From, favourite.To)
+		}
+	case "remove":
+		name := strings.TrimPrefix(params[0], "@")
+		removed, err := store.RemoveFavourite("", name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: Could not remove the favourite: %v\n", err)
+			os.Exit(1)
+		}
+		if !removed {
+			fmt.Fprintf(os.Stderr, "ERROR: No favourite named %s\n", name)
+			os.Exit(1)
+		}
+		fmt.Printf("Removed @%s.\n", name)
+	}
+}
+
+// Entry point for the "history" subcommand, listing the journeys most
+// recently planned, supporting "history [--limit <N>] [--journey-store <path>]"
+func RunHistoryCommand(args []string) {
+	fs := flag.NewFlagSet("history", flag.ExitOnError)
+	limitFlag := fs.Int("limit", 20, "show at most `N` journeys, or 0 for all")
+	storeFlag := fs.String("journey-store", transit.DefaultJourneyStorePath(),
+		"path of the JSON or SQLite (.db) journey store")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "USAGE: ./tubeplanner history [--limit 20] [--journey-store <path>]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 0 || *limitFlag < 0 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	history, err := openJourneyStore(*storeFlag).History("", *limitFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: Could not read the journey history: %v\n", err)
+		os.Exit(1)
+	}
+	if len(history) == 0 {
+		fmt.Println("No journeys planned yet.")
+	}
+	for _, entry := range history {
+		fmt.Printf("%s  %s to %s: %d min, %d change(s) via %s\n", entry.PlannedAt.Local().Format("2006-01-02 15:04"),
+			entry.From, entry.To, entry.Minutes, entry.Changes, strings.Join(entry.Lines, ", "))
+	}
+}
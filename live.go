@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/maxboyko1/TubePlanner/pkg/transit"
+)
+
+// Return the latest status of each line for --live: fetched from the TfL API
+// within the specified timeout and added to the status history store, or,
+// if that fails, the latest statuses already in the store
+func loadLiveStatuses(storePath string, timeout time.Duration) map[string]transit.LineStatus {
+	store := transit.OpenStatusHistory(storePath)
+	now := time.Now()
+	client := &http.Client{Timeout: timeout}
+	fetched, err := transit.FetchLineStatuses(client, now)
+	if err == nil {
+		if err := store.Record(fetched); err != nil {
+			fmt.Fprintf(os.Stderr, "WARNING: Could not record line statuses: %v\n", err)
+		}
+		statuses := make(map[string]transit.LineStatus, len(fetched))
+		for _, status := range fetched {
+			statuses[status.Line] = status
+		}
+		return statuses
+	}
+
+	statuses, storeErr := store.Latest(now.Add(-adviceStatusMaxAge))
+	if storeErr != nil || len(statuses) == 0 {
+		fmt.Fprintf(os.Stderr, "WARNING: Could not fetch live line statuses (%v), "+
+			"planning without them\n", err)
+		return nil
+	}
+	var fetchedAt time.Time
+	for _, status := range statuses {
+		if status.FetchedAt.After(fetchedAt) {
+			fetchedAt = status.FetchedAt
+		}
+	}
+	fmt.Fprintf(os.Stderr, "WARNING: Could not fetch live line statuses (%v), "+
+		"using those recorded at %s\n", err, fetchedAt.Local().Format("15:04"))
+	return statuses
+}
+
+// Fetch the latest status of each line from the TfL API within the specified
+// timeout and add them to the specified status history store, for the
+// commands which keep running and so build up the history as they go
+func recordLineStatuses(store *transit.StatusHistory, timeout time.Duration) error {
+	client := &http.Client{Timeout: timeout}
+	fetched, err := transit.FetchLineStatuses(client, time.Now())
+	if err != nil {
+		return fmt.Errorf("Could not fetch live line statuses: %v", err)
+	}
+	if err := store.Record(fetched); err != nil {
+		return fmt.Errorf("Could not record line statuses: %v", err)
+	}
+	return nil
+}
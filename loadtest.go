@@ -0,0 +1,164 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"flag"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/maxboyko1/TubePlanner/pkg/transit"
+)
+
+// Entry point for the "loadtest" subcommand, which drives a server started
+// with "serve" with routing queries from several workers at once and
+// reports its throughput, latencies and error rate, for sizing deployments.
+// Exits with status 1 if more requests fail than --max-errors allows.
+func RunLoadTestCommand(args []string) {
+	fs := flag.NewFlagSet("loadtest", flag.ExitOnError)
+	urlFlag := fs.String("url", "http://localhost:8080", "base `URL` of the server to test")
+	concurrencyFlag := fs.Int("concurrency", 8, "`number` of requests to keep under way at once")
+	durationFlag := fs.Duration("duration", 30*time.Second, "how long to keep sending requests")
+	requestsFlag := fs.Int("requests", 0,
+		"send this `number` of requests in all instead of running for --duration")
+	distributionFlag := fs.String("distribution", "uniform",
+		"how trips are chosen: `uniform` between any two stations, or hubs, favouring stations with more lines")
+	pairsFlag := fs.String("pairs", "", "CSV `file` of trips to choose from instead, one from,to per line")
+	dataFlag := fs.String("data", "builtin",
+		"transit data the server was started with, to choose stations from (a `path`, or builtin)")
+	queryFlag := fs.String("query", "", "further `parameters` to send with every request, e.g. stepFree=true")
+	seedFlag := fs.Uint64("seed", 1, "`seed` for choosing the trips, the same each run")
+	timeoutFlag := fs.Duration("timeout", 10*time.Second, "how long to wait for each response")
+	maxErrorsFlag := fs.Float64("max-errors", 1,
+		"`percentage` of requests allowed to fail before exiting with status 1")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "USAGE: ./tubeplanner loadtest [--url http://localhost:8080] [--concurrency 8] "+
+			"[--duration 30s | --requests N] [--distribution uniform|hubs | --pairs <file>]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 0 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	if *concurrencyFlag < 1 {
+		fmt.Fprintln(os.Stderr, "ERROR: --concurrency must be at least 1")
+		os.Exit(1)
+	}
+	if *requestsFlag < 0 || *durationFlag <= 0 {
+		fmt.Fprintln(os.Stderr, "ERROR: --requests cannot be negative, and --duration must be greater than zero")
+		os.Exit(1)
+	}
+	query, err := url.ParseQuery(*queryFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: Invalid --query: %v\n", err)
+		os.Exit(1)
+	}
+
+	opts := graphOptions
+	opts.Source, opts.CachePath = transit.DataSourceAt(*dataFlag), ""
+	_, nodeMap, err := transit.BuildTransitGraph(opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+		os.Exit(1)
+	}
+	var trips transit.ODDistribution
+	switch {
+	case *pairsFlag != "":
+		file, err := os.Open(*pairsFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+			os.Exit(1)
+		}
+		pairs, err := transit.LoadStationPairs(file, nodeMap)
+		file.Close()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: %s: %v\n", *pairsFlag, err)
+			os.Exit(1)
+		}
+		if len(pairs) == 0 {
+			fmt.Fprintf(os.Stderr, "ERROR: %s lists no trips\n", *pairsFlag)
+			os.Exit(1)
+		}
+		trips = transit.PairsOD(pairs)
+	case len(nodeMap) < 2:
+		fmt.Fprintln(os.Stderr, "ERROR: The transit data has fewer than two stations to choose trips between")
+		os.Exit(1)
+	case *distributionFlag == "uniform":
+		trips = transit.UniformOD(slices.Sorted(maps.Keys(nodeMap)))
+	case *distributionFlag == "hubs":
+		trips = transit.HubWeightedOD(nodeMap)
+	default:
+		fmt.Fprintf(os.Stderr, "ERROR: Unknown trip distribution: %s\n", *distributionFlag)
+		os.Exit(1)
+	}
+
+	// Keep a connection open for every worker rather than the default two
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = *concurrencyFlag
+	test := &transit.LoadTest{
+		URL:         *urlFlag,
+		Client:      &http.Client{Timeout: *timeoutFlag, Transport: transport},
+		Concurrency: *concurrencyFlag,
+		Requests:    *requestsFlag,
+		Duration:    *durationFlag,
+		Trips:       trips,
+		Query:       query,
+		Seed:        *seedFlag,
+	}
+	// Ctrl-C ends the test early, still reporting what it found
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if test.Requests > 0 {
+		fmt.Fprintf(os.Stderr, "Sending %d requests to %s from %d workers...\n", test.Requests, test.URL,
+			test.Concurrency)
+	} else {
+		fmt.Fprintf(os.Stderr, "Sending requests to %s from %d workers for %v...\n", test.URL, test.Concurrency,
+			test.Duration)
+	}
+	result, err := transit.RunLoadTest(ctx, test)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+		os.Exit(1)
+	}
+	printLoadTestResult(result)
+	if 100*result.ErrorRate() > *maxErrorsFlag {
+		os.Exit(1)
+	}
+}
+
+// Print the throughput, outcomes and latencies of a load test, followed by
+// each kind of failure, most common first
+func printLoadTestResult(result *transit.LoadTestResult) {
+	fmt.Printf("%d requests in %.1fs: %.1f requests a second.\n", result.Requests, result.Elapsed.Seconds(),
+		result.Throughput())
+	fmt.Printf("- %d journeys\n- %d with no route\n- %d failed (%.2f%%)\n", result.OK, result.NoRoute,
+		result.Failed, 100*result.ErrorRate())
+	if result.Requests > 0 {
+		fmt.Printf("Latency: p50 %v, p90 %v, p99 %v, max %v.\n", roundLatency(result.Percentile(50)),
+			roundLatency(result.Percentile(90)), roundLatency(result.Percentile(99)),
+			roundLatency(result.Percentile(100)))
+	}
+	if len(result.Failures) == 0 {
+		return
+	}
+	fmt.Println("Failures:")
+	failures := slices.SortedFunc(maps.Keys(result.Failures), func(a, b string) int {
+		return cmp.Or(result.Failures[b]-result.Failures[a], strings.Compare(a, b))
+	})
+	for _, failure := range failures {
+		fmt.Printf("- %d × %s\n", result.Failures[failure], failure)
+	}
+}
+
+// Round a latency for reading, to a hundredth of a millisecond
+func roundLatency(d time.Duration) time.Duration {
+	return d.Round(10 * time.Microsecond)
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/maxboyko1/TubePlanner/pkg/transit"
+)
+
+// Entry point for the "matrix" subcommand, which prints the travel times
+// from each of a list of origin stations to each of a list of destination
+// stations, e.g. for analysts comparing many trips at once
+func RunMatrixCommand(args []string) {
+	fs := flag.NewFlagSet("matrix", flag.ExitOnError)
+	origins, dests := make([]string, 0), make([]string, 0)
+	fs.Func("from", "origin `station` (repeatable)", func(station string) error {
+		origins = append(origins, station)
+		return nil
+	})
+	fs.Func("to", "destination `station` (repeatable)", func(station string) error {
+		dests = append(dests, station)
+		return nil
+	})
+	formatFlag := fs.String("format", "text", "output `format`: a text table, csv or json")
+	dataSourceFlags.register(fs)
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "USAGE: ./tubeplanner matrix [--format csv|json] "+dataSourceUsage+" --from <station>... --to <station>...")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 0 || len(origins) == 0 || len(dests) == 0 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	if *formatFlag != "text" && *formatFlag != "csv" && *formatFlag != "json" {
+		fmt.Fprintf(os.Stderr, "ERROR: Unknown output format: %s\n", *formatFlag)
+		os.Exit(1)
+	}
+	_, nodeMap := buildGraph()
+	opts := &transit.SearchOptions{DepartAt: time.Now()}
+
+	if *formatFlag == "json" {
+		matrix, err := transit.NewTravelTimeMatrix(nodeMap, origins, dests, opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+			os.Exit(1)
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		enc.Encode(matrix)
+		return
+	}
+	times, err := transit.TravelTimes(nodeMap, origins, dests, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *formatFlag == "csv" {
+		w := csv.NewWriter(os.Stdout)
+		w.Write(append([]string{"from"}, dests...))
+		for i, origin := range origins {
+			row := []string{origin}
+			for _, minutes := range times[i] {
+				if minutes == math.MaxUint16 {
+					row = append(row, "")
+				} else {
+					row = append(row, strconv.Itoa(int(minutes)))
+				}
+			}
+			w.Write(row)
+		}
+		w.Flush()
+		return
+	}
+
+	fmt.Println("Travel times in minutes, from each row to each column:")
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprint(tw, "\t")
+	for _, dest := range dests {
+		fmt.Fprintf(tw, "%s\t", dest)
+	}
+	fmt.Fprintln(tw)
+	for i, origin := range origins {
+		fmt.Fprintf(tw, "%s\t", origin)
+		for _, minutes := range times[i] {
+			if minutes == math.MaxUint16 {
+				fmt.Fprint(tw, "no route\t")
+			} else {
+				fmt.Fprintf(tw, "%d\t", minutes)
+			}
+		}
+		fmt.Fprintln(tw)
+	}
+	tw.Flush()
+}
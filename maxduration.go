@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"time"
+
+	"github.com/maxboyko1/TubePlanner/pkg/transit"
+)
+
+// Represents one of the constraints given on the command line, along with
+// how to plan without it when looking for what lengthened a trip
+type relaxation struct {
+	option string
+	relax  func(opts *transit.SearchOptions)
+}
+
+// Return a relaxation reopening the specified lines, for --avoid-line or
+// --strike
+func reopenLines(option string, lines ...string) relaxation {
+	return relaxation{option, func(opts *transit.SearchOptions) {
+		opts.ClosedLines = maps.Clone(opts.ClosedLines)
+		for _, line := range lines {
+			delete(opts.ClosedLines, line)
+		}
+	}}
+}
+
+// Exit with an error if the planned trip takes longer than the limit given
+// with --max-duration, listing which of the constraints in effect would
+// shorten the trip if loosened. A limit of zero means no limit.
+func checkMaxDuration(planner *transit.Planner, start, dest string, minutes uint16,
+	limit time.Duration, relaxations []relaxation) {
+	limitMinutes := limit.Minutes()
+	if limit <= 0 || float64(minutes) <= limitMinutes {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "ERROR: The trip from %s to %s takes %d minutes, more than the "+
+		"--max-duration of %.0f minutes\n", start, dest, minutes, limitMinutes)
+	shorter := 0
+	for _, r := range relaxations {
+		relaxed := transit.NewPlanner(planner.Graph())
+		relaxed.Options = planner.Options
+		r.relax(&relaxed.Options)
+		route, err := relaxed.Plan(start, dest)
+		if err != nil || route.TotalMinutes() >= minutes {
+			continue
+		}
+		if shorter == 0 {
+			fmt.Fprintln(os.Stderr, "Loosening these options would shorten it:")
+		}
+		shorter++
+		within := ""
+		if float64(route.TotalMinutes()) <= limitMinutes {
+			within = ", within the limit"
+		}
+		fmt.Fprintf(os.Stderr, "- without %s: %d minutes%s\n", r.option, route.TotalMinutes(), within)
+	}
+	if shorter == 0 {
+		fmt.Fprintln(os.Stderr, "None of the options given lengthen it, so the transit data may "+
+			"be missing links near either end.")
+	}
+	os.Exit(1)
+}
@@ -0,0 +1,122 @@
+package transit
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Cost in minutes charged for each interchange at a station lacking any of
+// the aids a passenger needs, steering routes towards stations with them
+const accessibilityPenalty = 10
+
+// Aids needed by passengers with each kind of impairment accepted by
+// --accessibility
+var accessibilityProfiles = map[string]AccessibilityAids{
+	"hearing": HearingLoop | VisualDisplays,
+	"visual":  TactilePaving | AudioAnnouncements,
+}
+
+// Names of the individual aids, in the order they are listed to users
+var accessibilityAidNames = []struct {
+	aid  AccessibilityAids
+	name string
+}{
+	{TactilePaving, "tactile paving"},
+	{HearingLoop, "hearing loops"},
+	{AudioAnnouncements, "audio announcements"},
+	{VisualDisplays, "visual displays"},
+}
+
+// Parse a comma-separated list of accessibility profiles (e.g.
+// "hearing,visual") into the combined set of aids they need
+func ParseAccessibilityNeeds(s string) (AccessibilityAids, error) {
+	var needs AccessibilityAids
+	for _, profile := range strings.Split(s, ",") {
+		aids, exists := accessibilityProfiles[strings.TrimSpace(profile)]
+		if !exists {
+			return 0, fmt.Errorf("unknown accessibility profile %q (expected hearing or visual)", profile)
+		}
+		needs |= aids
+	}
+	return needs, nil
+}
+
+// Return the names of the aids in the specified set
+func (aids AccessibilityAids) Names() []string {
+	names := make([]string, 0)
+	for _, an := range accessibilityAidNames {
+		if aids&an.aid != 0 {
+			names = append(names, an.name)
+		}
+	}
+	return names
+}
+
+// Return a boarding penalty charging accessibilityPenalty minutes for
+// boarding at a station which lacks any of the specified aids. The start of
+// the trip is fixed, so in effect this penalizes interchanges at such
+// stations.
+func AccessibilityBoardingPenalty(needs AccessibilityAids) func(station, line string) uint16 {
+	stationAids := GetStationAids()
+	return func(station, line string) uint16 {
+		if needs&^stationAids[station] != 0 {
+			return accessibilityPenalty
+		}
+		return 0
+	}
+}
+
+// Return the sum of the specified boarding penalties, either of which may be
+// nil
+func CombinePenalties(a, b func(station, line string) uint16) func(station, line string) uint16 {
+	if a == nil {
+		return b
+	} else if b == nil {
+		return a
+	}
+	return func(station, line string) uint16 {
+		return AddTime(a(station, line), b(station, line))
+	}
+}
+
+// Return a description of each station along the specified journey where
+// the passenger starts, changes or finishes which is not known to provide
+// all of the specified aids
+func MissingAccessibilityAids(journey *Journey, needs AccessibilityAids) []string {
+	if len(journey.Legs) == 0 {
+		return nil
+	}
+	stations := []string{journey.From}
+	for _, leg := range journey.Legs {
+		if leg.IsInterchange() {
+			stations = append(stations, leg.From, leg.To)
+		}
+	}
+	stations = append(stations, journey.To)
+
+	stationAids := GetStationAids()
+	missing := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, station := range stations {
+		if lacking := needs &^ stationAids[station]; lacking != 0 && !seen[station] {
+			seen[station] = true
+			missing = append(missing, fmt.Sprintf("%s: no known %s", station,
+				strings.Join(lacking.Names(), " or ")))
+		}
+	}
+	return slices.Clip(missing)
+}
+
+// Print a note listing the stations along the journey which are not known
+// to provide the aids the passenger needs
+func PrintAccessibilityNotes(journey *Journey, needs AccessibilityAids) {
+	missing := MissingAccessibilityAids(journey, needs)
+	if len(missing) == 0 {
+		return
+	}
+	fmt.Println("Note: some stations on this journey may lack the aids you need:")
+	for _, note := range missing {
+		fmt.Printf("- %s\n", note)
+	}
+}
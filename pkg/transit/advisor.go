@@ -0,0 +1,76 @@
+package transit
+
+import (
+	"fmt"
+	"time"
+)
+
+// Estimated outcome of setting off on a journey after waiting a number of
+// minutes, with the arrival also measured in minutes from now
+type DepartureOption struct {
+	Wait    uint16
+	Arrival uint16
+}
+
+// Evaluate setting off now and after every step minutes up to maxWait, using
+// the specified function to calculate the travel time of a journey begun
+// after a given wait. Return every feasible option along with the one that
+// arrives earliest, preferring the shortest wait when arrivals are equal
+// (nil if no option is feasible at all).
+func AdviseDeparture(maxWait, step uint16,
+	travelTime func(wait uint16) (uint16, bool)) ([]DepartureOption, *DepartureOption) {
+	options := make([]DepartureOption, 0)
+	var best *DepartureOption = nil
+	for wait := uint16(0); wait <= maxWait; wait += step {
+		if minutes, ok := travelTime(wait); ok {
+			options = append(options, DepartureOption{wait, AddTime(wait, minutes)})
+			if best == nil || options[len(options)-1].Arrival < best.Arrival {
+				best = &options[len(options)-1]
+			}
+		}
+		if maxWait-wait < step {
+			break
+		}
+	}
+	// Pointers into a slice may be invalidated by later appends, so return
+	// a copy of the best option instead
+	if best != nil {
+		bestCopy := *best
+		best = &bestCopy
+	}
+	return options, best
+}
+
+// Return the set of lines the specified statuses report as closed at time t
+func ClosedLinesAt(statuses map[string]LineStatus, t time.Time) map[string]bool {
+	closed := make(map[string]bool)
+	for line, status := range statuses {
+		if status.ClosedAt(t) {
+			closed[line] = true
+		}
+	}
+	return closed
+}
+
+// Print a summary of the evaluated departure options and the recommendation
+func PrintDepartureAdvice(options []DepartureOption, best DepartureOption) {
+	fmt.Println("Departure options:")
+	if options[0].Wait != 0 {
+		fmt.Println("- Leave now: no route available")
+	}
+	for _, option := range options {
+		if option.Wait == 0 {
+			fmt.Printf("- Leave now: arrive in %d minutes\n", option.Arrival)
+		} else {
+			fmt.Printf("- Wait %d minutes: arrive in %d minutes\n", option.Wait, option.Arrival)
+		}
+	}
+	if best.Wait == 0 {
+		fmt.Println("Recommendation: leave now.")
+	} else if options[0].Wait != 0 {
+		fmt.Printf("Recommendation: wait %d minutes.\n", best.Wait)
+	} else {
+		fmt.Printf("Recommendation: wait %d minutes, arriving %d minutes sooner than leaving now.\n",
+			best.Wait, options[0].Arrival-best.Arrival)
+	}
+}
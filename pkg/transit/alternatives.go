@@ -0,0 +1,175 @@
+package transit
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+	"strings"
+)
+
+// Number of paths through the graph examined per alternative route asked
+// for, beyond which the search for alternatives gives up. Many paths differ
+// only in which of several lines sharing the same tracks they ride, and are
+// not counted as distinct routes.
+const alternativePathsPerRoute = 10
+
+// Represents a path through the graph found while searching for alternative
+// routes, along with its search cost
+type candidatePath struct {
+	nodes []*Node
+	links []*Link
+	cost  uint16
+}
+
+// Compare two paths by search cost, breaking ties as every search does (see
+// preferTie), so that the fastest path listed is the one the planner finds
+func (path candidatePath) compare(other candidatePath) int {
+	changes := func(links []*Link) (n uint16) {
+		for _, link := range links {
+			n += linkChanges(link)
+		}
+		return n
+	}
+	key := func(nodes []*Node) (key tieKey) {
+		for _, node := range nodes {
+			key = key.then(node)
+		}
+		return key
+	}
+	if c := cmp.Compare(path.cost, other.cost); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(changes(path.links), changes(other.links)); c != 0 {
+		return c
+	}
+	return key(path.nodes).compare(key(other.nodes))
+}
+
+// Return the search cost of following the specified links from the first of
+// the specified Nodes, begun where the search stands there at the start,
+// through to leaving the last Node's station
+func pathCost(starts map[*Node]progress, nodes []*Node, links []*Link, opts *SearchOptions) uint16 {
+	at := starts[nodes[0]]
+	for idx, link := range links {
+		at = opts.linkCost(nodes[idx], link, at)
+	}
+	return AddTime(at.cost, opts.accessTime(nodes[len(nodes)-1]))
+}
+
+// Return a key identifying the path through the specified Nodes. When
+// stationsOnly is set, paths through the same stations on different lines
+// get the same key, however many times they change line along the way.
+func pathKey(nodes []*Node, stationsOnly bool) string {
+	var sb strings.Builder
+	for idx, node := range nodes {
+		if stationsOnly && idx > 0 && nodes[idx-1].station == node.station {
+			continue
+		}
+		sb.WriteString(node.station)
+		if !stationsOnly {
+			sb.WriteString("\x01" + node.line)
+		}
+		sb.WriteString("\x00")
+	}
+	return sb.String()
+}
+
+// Return up to k distinct routes between the specified stations, fastest
+// first, using Yen's k-shortest paths algorithm. Each route after the first
+// is the fastest remaining deviation from the routes found before it. Routes
+// passing through the same stations as a faster route, only on a different
+// line sharing its tracks, are skipped. Fewer than k routes are returned
+// when no more distinct ones can be found.
+func KShortestPaths(nodeMap NodeMap, start, dest string, k int, opts *SearchOptions) ([]*Route, error) {
+	if start == dest {
+		return []*Route{{arrivedJourney(start)}}, nil
+	}
+	var yenOpts SearchOptions
+	if opts != nil {
+		yenOpts = *opts
+	}
+	isDest := map[string]bool{dest: true}
+	starts := startCosts(nodeMap, start, &yenOpts)
+	npq := ResetGraph(nodeMap)
+	nodes, links, err := shortestPath(&npq, starts, isDest, &yenOpts)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := func(path candidatePath) *Route {
+		return &Route{newJourney(path.nodes, path.links, &yenOpts)}
+	}
+	accepted := []candidatePath{{nodes, links, pathCost(starts, nodes, links, &yenOpts)}}
+	routes := []*Route{snapshot(accepted[0])}
+	seenRoutes := map[string]bool{pathKey(nodes, true): true}
+	seenPaths := map[string]bool{pathKey(nodes, false): true}
+	candidates := make([]candidatePath, 0)
+
+	for len(routes) < k && len(accepted) < k*alternativePathsPerRoute {
+		last := accepted[len(accepted)-1]
+		// Deviate from the last path accepted at each of its Nodes in turn,
+		// or at the very start (spur -1) by boarding a different line there
+		for spur := -1; spur < len(last.nodes)-1; spur++ {
+			yenOpts.excludedNodes = make(map[*Node]bool)
+			yenOpts.excludedLinks = make(map[*Link]bool)
+			// Set aside the next link of every accepted path sharing the
+			// same root, so that the deviation differs from all of them
+			for _, path := range accepted {
+				if spur >= len(path.links) || pathKey(path.nodes[:spur+1], false) != pathKey(last.nodes[:spur+1], false) {
+					continue
+				}
+				if spur < 0 {
+					yenOpts.excludedNodes[path.nodes[0]] = true
+				} else {
+					yenOpts.excludedLinks[path.links[spur]] = true
+				}
+			}
+			spurStarts := make(map[*Node]progress)
+			if spur < 0 {
+				spurStarts = startCosts(nodeMap, start, &yenOpts)
+			} else {
+				// The deviation may not loop back through the root
+				for _, node := range last.nodes[:spur] {
+					yenOpts.excludedNodes[node] = true
+				}
+				at := starts[last.nodes[0]]
+				for idx, link := range last.links[:spur] {
+					at = yenOpts.linkCost(last.nodes[idx], link, at)
+				}
+				spurStarts[last.nodes[spur]] = at
+			}
+			npq := ResetGraph(nodeMap)
+			spurNodes, spurLinks, err := shortestPath(&npq, spurStarts, isDest, &yenOpts)
+			if errors.Is(err, ErrDeadlineExceeded) {
+				return nil, err
+			} else if err != nil {
+				continue
+			}
+			path := candidatePath{nodes: slices.Concat(last.nodes[:max(spur, 0)], spurNodes),
+				links: slices.Concat(last.links[:max(spur, 0)], spurLinks)}
+			if key := pathKey(path.nodes, false); !seenPaths[key] {
+				seenPaths[key] = true
+				path.cost = pathCost(starts, path.nodes, path.links, &yenOpts)
+				candidates = append(candidates, path)
+			}
+		}
+		yenOpts.excludedNodes, yenOpts.excludedLinks = nil, nil
+		if len(candidates) == 0 {
+			break
+		}
+		// Accept the cheapest candidate, breaking ties as the searches do
+		best := 0
+		for idx, path := range candidates {
+			if path.compare(candidates[best]) < 0 {
+				best = idx
+			}
+		}
+		path := candidates[best]
+		candidates = slices.Delete(candidates, best, best+1)
+		accepted = append(accepted, path)
+		if key := pathKey(path.nodes, true); !seenRoutes[key] {
+			seenRoutes[key] = true
+			routes = append(routes, snapshot(path))
+		}
+	}
+	return routes, nil
+}
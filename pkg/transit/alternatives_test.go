@@ -0,0 +1,47 @@
+package transit
+
+import "testing"
+
+// The first of the k shortest routes is the one the planner finds, and the
+// rest follow it fastest first, each through different stations
+func TestKShortestPathsMatchShortestPath(t *testing.T) {
+	nodeMap := defaultNodeMap(t)
+	for _, opts := range []*SearchOptions{nil, {Optimize: OptimizeChanges}} {
+		for _, pair := range samplePairs(nodeMap, 30) {
+			npq := ResetGraph(nodeMap)
+			fastest, err := RunShortestPaths(&npq, nodeMap, pair[0], pair[1], opts)
+			if err != nil {
+				t.Fatalf("%s to %s: %v", pair[0], pair[1], err)
+			}
+			routes, err := KShortestPaths(nodeMap, pair[0], pair[1], 4, opts)
+			if err != nil {
+				t.Fatalf("%s to %s: KShortestPaths failed: %v", pair[0], pair[1], err)
+			}
+			if got, want := routes[0].Journey().RouteHash(), fastest.RouteHash(); got != want {
+				t.Errorf("%s to %s: first of the k shortest routes is %s, the shortest path %s",
+					pair[0], pair[1], got, want)
+			}
+			seen := make(map[string]bool)
+			for idx, route := range routes {
+				journey := route.Journey()
+				key := journey.From
+				for _, leg := range journey.Legs {
+					for _, stop := range leg.Stops {
+						key += "\x00" + stop.Station
+					}
+					if leg.To != leg.From {
+						key += "\x00" + leg.To
+					}
+				}
+				if seen[key] {
+					t.Errorf("%s to %s: route %d repeats the stations of an earlier one", pair[0], pair[1], idx+1)
+				}
+				seen[key] = true
+				if opts == nil && idx > 0 && journey.TotalMinutes < routes[idx-1].TotalMinutes() {
+					t.Errorf("%s to %s: route %d takes %d minutes, less than the %d of the one before",
+						pair[0], pair[1], idx+1, journey.TotalMinutes, routes[idx-1].TotalMinutes())
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package transit
+
+import (
+	"cmp"
+	"container/heap"
+	"slices"
+)
+
+// Identifies a Node of the graph, the platforms of a line at a station, for
+// analyses of the network which only read the graph
+type NodeID struct {
+	Station string
+	Line    string
+}
+
+// Represents a link of the graph from one Node to another, followed in that
+// direction, with its time in minutes and its mode
+type Edge struct {
+	From    NodeID
+	To      NodeID
+	Minutes uint16
+	Mode    LinkMode
+}
+
+// Return the identity of the specified Node
+func (node *Node) ID() NodeID {
+	return NodeID{node.station, node.line}
+}
+
+// Order NodeIDs by station, then by line
+func compareNodeIDs(a, b NodeID) int {
+	return cmp.Or(cmp.Compare(a.Station, b.Station), cmp.Compare(a.Line, b.Line))
+}
+
+// Return every Node of the graph, sorted by station and then line
+func (g *Graph) Nodes() []NodeID {
+	ids := make([]NodeID, 0, len(g.nodeMap))
+	for _, lines := range g.nodeMap {
+		for _, node := range lines {
+			ids = append(ids, node.ID())
+		}
+	}
+	slices.SortFunc(ids, compareNodeIDs)
+	return ids
+}
+
+// Return every link of the graph as an Edge, sorted by the Node it leaves
+// and then the Node it reaches. Every connection runs both ways (see
+// AddConnection), so each appears once in each direction.
+func (g *Graph) Edges() []Edge {
+	edges := make([]Edge, 0)
+	for _, lines := range g.nodeMap {
+		for _, node := range lines {
+			for _, link := range node.adj {
+				edges = append(edges, Edge{node.ID(), link.endNode.ID(), link.time, link.attrs.Mode})
+			}
+		}
+	}
+	slices.SortFunc(edges, func(a, b Edge) int {
+		return cmp.Or(compareNodeIDs(a.From, b.From), compareNodeIDs(a.To, b.To),
+			cmp.Compare(a.Minutes, b.Minutes), cmp.Compare(a.Mode, b.Mode))
+	})
+	return edges
+}
+
+// Return the number of links leaving the specified Node, or zero if it is
+// not in the graph
+func (g *Graph) OutDegree(id NodeID) int {
+	if node, exists := g.nodeMap[id.Station][id.Line]; exists {
+		return len(node.adj)
+	}
+	return 0
+}
+
+// Return the number of links reaching the specified Node, or zero if it is
+// not in the graph
+func (g *Graph) InDegree(id NodeID) int {
+	degree := 0
+	for _, lines := range g.nodeMap {
+		for _, node := range lines {
+			for _, link := range node.adj {
+				if link.endNode.station == id.Station && link.endNode.line == id.Line {
+					degree++
+				}
+			}
+		}
+	}
+	return degree
+}
+
+// Return the betweenness centrality of every Node of the graph: for each
+// ordered pair of other Nodes, the share of the quickest paths between them
+// that pass through it, summed over all pairs. Paths are weighed by the
+// times of their links alone, with no waits for trains, using Brandes'
+// algorithm. Nodes at busy interchanges and on trunk sections of lines
+// score highest. This reads the graph without searching it, so it need not
+// take turns with Planners searching the same graph.
+func (g *Graph) Betweenness() map[NodeID]float64 {
+	nodes := make([]*Node, 0)
+	for _, lines := range g.nodeMap {
+		for _, node := range lines {
+			nodes = append(nodes, node)
+		}
+	}
+	scores := make(map[NodeID]float64, len(nodes))
+	for _, node := range nodes {
+		scores[node.ID()] = 0
+	}
+
+	for _, source := range nodes {
+		// Find the quickest paths from the source to every Node, counting
+		// how many there are to each and which Nodes they arrive from
+		dist := map[*Node]uint16{source: 0}
+		paths := map[*Node]float64{source: 1}
+		preds := make(map[*Node][]*Node)
+		settled := make(map[*Node]bool)
+		order := make([]*Node, 0, len(nodes))
+		queue := frontierQueue{{source, 0, 0}}
+		for len(queue) > 0 {
+			cur := heap.Pop(&queue).(frontierEntry).node
+			if settled[cur] {
+				continue
+			}
+			settled[cur] = true
+			order = append(order, cur)
+			for _, link := range cur.adj {
+				next, cost := link.endNode, AddTime(dist[cur], link.time)
+				known, reached := dist[next]
+				switch {
+				case !reached || cost < known:
+					dist[next], paths[next], preds[next] = cost, paths[cur], []*Node{cur}
+					heap.Push(&queue, frontierEntry{next, cost, 0})
+				case cost == known && !settled[next]:
+					paths[next] += paths[cur]
+					preds[next] = append(preds[next], cur)
+				}
+			}
+		}
+
+		// Credit each Node with its share of the paths through it, working
+		// back from the Nodes reached last
+		dependency := make(map[*Node]float64)
+		for _, node := range slices.Backward(order) {
+			for _, pred := range preds[node] {
+				dependency[pred] += paths[pred] / paths[node] * (1 + dependency[node])
+			}
+			if node != source {
+				scores[node.ID()] += dependency[node]
+			}
+		}
+	}
+	return scores
+}
@@ -0,0 +1,179 @@
+package transit
+
+import (
+	"container/heap"
+	"errors"
+	"math"
+	"slices"
+	"time"
+)
+
+// Number of times PlanArriveBy may plan the trip forwards to settle on the
+// departure time, first moving it earlier until the trip arrives in time,
+// then a minute at a time later while it still does
+const (
+	maxEarlierDepartures = 10
+	maxLaterDepartures   = 30
+)
+
+// Return a copy of the options with the trip beginning the specified number
+// of minutes before the specified time, so that the time-dependent parts of
+// the search can be asked about that moment with zero minutes elapsed
+func (opts SearchOptions) before(t time.Time, minutes uint16) *SearchOptions {
+	opts.DepartAt = t.Add(-time.Duration(minutes) * time.Minute)
+	return &opts
+}
+
+// Return the latest time a trip from the specified start station to any of
+// the specified destinations can set off and still arrive by the specified
+// time, under the specified search options (which may be nil). This runs
+// Dijkstra's algorithm backwards in time from the destinations, over the
+// reversed graph, finding how long before the arrival time the trip must be
+// aboard at each Node. Each link takes its run time, and each train its
+// wait, for the moment it is reached, and lines are only boarded while they
+// are running. Penalties are left out, as they are not part of the trip's
+// times. Planning forwards from the time found gives the same arrival,
+// except around the edges of the peak, where a link's run time or a train's
+// wait may change between the moments the two searches judge it by.
+func LatestDeparture(nodeMap NodeMap, start string, dests []string, arriveBy time.Time,
+	opts *SearchOptions) (time.Time, error) {
+	if slices.Contains(dests, start) {
+		return arriveBy, nil
+	}
+	var base SearchOptions
+	if opts != nil {
+		base = *opts
+	}
+	// The checks below which depend on the time are made at the moment each
+	// link is reached, so the others are made with no departure time
+	base.DepartAt = time.Time{}
+	reverse := reverseLinks(nodeMap)
+	isDest := make(map[string]bool)
+	npq := ResetGraph(nodeMap)
+	for _, dest := range dests {
+		isDest[dest] = true
+		for _, node := range nodeMap[dest] {
+			if !base.closed(node) && !base.ClosedStations[dest] && !base.excludedNodes[node] && base.accessible(node) {
+				npq.update(node, base.accessTime(node))
+			}
+		}
+	}
+
+	// Setting off on foot to another station means neither walking in to a
+	// platform nor waiting for a train at the start
+	var latest uint16 = math.MaxUint16
+	for len(npq) > 0 {
+		if opts != nil && !opts.Deadline.IsZero() && time.Now().After(opts.Deadline) {
+			return time.Time{}, ErrDeadlineExceeded
+		}
+		curNode := heap.Pop(&npq).(*Node)
+		if curNode.totalTime == math.MaxUint16 {
+			break
+		}
+		for _, rl := range reverse[curNode] {
+			// Trips end at the first platform of a destination station they
+			// reach, so none travel on from one
+			if isDest[rl.fromNode.station] || base.closed(rl.fromNode) || base.blocked(rl.fromNode, rl.link) {
+				continue
+			}
+			// Following a rail link ends aboard the current Node's train,
+			// while an interchange ends by boarding it after the wait
+			cost := curNode.totalTime
+			if rl.link.attrs.Mode != ModeRail {
+				boarding := base.before(arriveBy, cost)
+				if !boarding.running(curNode, 0) {
+					continue
+				}
+				cost = AddTime(cost, boarding.changeWait(rl.link, 0, boarding.linkTime(rl.link, 0)))
+			}
+			cost = AddTime(cost, base.before(arriveBy, cost).linkTime(rl.link, 0))
+			if !rl.link.attrs.ValidAt(arriveBy.Add(-time.Duration(cost) * time.Minute)) {
+				continue
+			}
+			if rl.fromNode.station == start && rl.link.attrs.Mode == ModeStationInterchange {
+				if !base.excludedNodes[rl.fromNode] && base.accessible(rl.fromNode) {
+					latest = min(latest, cost)
+				}
+				continue
+			}
+			if cost < rl.fromNode.totalTime {
+				npq.update(rl.fromNode, cost)
+			}
+		}
+	}
+
+	// Otherwise setting off means walking in to the platform and waiting for
+	// a train, on whichever line lets the trip leave latest
+	for _, node := range nodeMap[start] {
+		if node.totalTime == math.MaxUint16 || base.closed(node) || base.excludedNodes[node] ||
+			!base.accessible(node) {
+			continue
+		}
+		boarding := base.before(arriveBy, node.totalTime)
+		if !boarding.running(node, 0) {
+			continue
+		}
+		wait := boarding.boardWait(node, 0, 0)
+		latest = min(latest, AddTime(AddTime(node.totalTime, wait), base.accessTime(node)))
+	}
+	if latest == math.MaxUint16 {
+		return time.Time{}, ErrNoRoute
+	}
+	return arriveBy.Add(-time.Duration(latest) * time.Minute), nil
+}
+
+// Plan the trip from the specified start station to any of the specified
+// destinations which sets off as late as possible while still arriving by
+// the specified time, returning it along with the time it sets off. The
+// departure found by LatestDeparture is checked by planning the trip
+// forwards from it with the search options (which may be nil) as
+// RunShortestPathsToAny does, then moved earlier while the trip planned
+// arrives too late, and later while it would still arrive in time.
+// ErrNoRoute is returned if no departure can be found that arrives in time.
+func PlanArriveBy(nodeMap NodeMap, start string, dests []string, arriveBy time.Time,
+	opts *SearchOptions) (*Route, time.Time, error) {
+	departAt, err := LatestDeparture(nodeMap, start, dests, arriveBy, opts)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	var planOpts SearchOptions
+	if opts != nil {
+		planOpts = *opts
+	}
+	// Return the trip planned setting off at the specified time, and how
+	// many minutes after the arrival time it arrives
+	plan := func(departAt time.Time) (*Route, time.Duration, error) {
+		planOpts.DepartAt = departAt
+		npq := ResetGraph(nodeMap)
+		journey, err := RunShortestPathsToAny(&npq, nodeMap, start, dests, &planOpts)
+		if err != nil {
+			return nil, 0, err
+		}
+		route := &Route{journey}
+		return route, departAt.Add(time.Duration(route.TotalMinutes()) * time.Minute).Sub(arriveBy), nil
+	}
+
+	var route *Route
+	for range maxEarlierDepartures {
+		planned, late, err := plan(departAt)
+		if err != nil && !errors.Is(err, ErrNoRoute) {
+			return nil, time.Time{}, err
+		}
+		if err == nil && late <= 0 {
+			route = planned
+			break
+		}
+		departAt = departAt.Add(-max(late, time.Minute))
+	}
+	if route == nil {
+		return nil, time.Time{}, ErrNoRoute
+	}
+	for range maxLaterDepartures {
+		planned, late, err := plan(departAt.Add(time.Minute))
+		if err != nil || late > 0 {
+			break
+		}
+		route, departAt = planned, departAt.Add(time.Minute)
+	}
+	return route, departAt, nil
+}
@@ -0,0 +1,232 @@
+package transit
+
+import (
+	"container/heap"
+	"math"
+	"sync"
+	"time"
+)
+
+// Represents a place on a flat map of the network in km east and north of
+// the origin of latitude and longitude, which is near enough to the true
+// distances across London, and quicker to measure than DistanceKM
+type mapPoint struct {
+	x, y float64
+}
+
+// Return the distance between two places on the map in km
+func (a mapPoint) distance(b mapPoint) float64 {
+	return math.Hypot(a.x-b.x, a.y-b.y)
+}
+
+// The station coordinates built into the transit data as places on the map,
+// worked out once, as every search consults them
+var stationPoints = sync.OnceValue(func() map[string]mapPoint {
+	return mapPoints(GetStationCoordinates())
+})
+
+// Return each of the specified station coordinates as a place on the map
+func mapPoints(coords map[string]Coordinates) map[string]mapPoint {
+	const kmPerDegree = 111.32
+	scale := math.Cos(51.5 * math.Pi / 180)
+	points := make(map[string]mapPoint, len(coords))
+	for station, c := range coords {
+		points[station] = mapPoint{c.lon * kmPerDegree * scale, c.lat * kmPerDegree}
+	}
+	return points
+}
+
+// Holds the greatest speeds, in km a minute, of the links of each mode in a
+// part of the graph, judged from the coordinates of their ends, with rail
+// links at their quickest time of day and walks at the usual walking speed.
+// Instant is set if any link joins two places apart in no time at all.
+type linkSpeeds struct {
+	rail, lineInterchange, stationInterchange float64
+	instant                                   bool
+}
+
+// Return the greatest speeds of the links of the part of the graph reachable
+// from the specified Node, working them out and sharing them with every Node
+// there the first time they are needed. Links are not added after a graph
+// is built, so they hold until a new graph is built.
+func graphSpeeds(from *Node, points map[string]mapPoint) *linkSpeeds {
+	if from.speeds != nil {
+		return from.speeds
+	}
+	speeds := &linkSpeeds{}
+	reached := []*Node{from}
+	seen := map[*Node]bool{from: true}
+	for idx := 0; idx < len(reached); idx++ {
+		node := reached[idx]
+		a, aKnown := points[node.station]
+		for _, link := range node.adj {
+			if !seen[link.endNode] {
+				seen[link.endNode] = true
+				reached = append(reached, link.endNode)
+			}
+			b, bKnown := points[link.endNode.station]
+			if !aKnown || !bKnown || link.endNode.station == node.station {
+				continue
+			}
+			least := link.time
+			for _, bandTime := range link.attrs.BandTimes {
+				least = min(least, bandTime)
+			}
+			km := a.distance(b)
+			if least == 0 {
+				speeds.instant = speeds.instant || km > 0
+				continue
+			}
+			switch link.attrs.Mode {
+			case ModeRail:
+				speeds.rail = max(speeds.rail, km/float64(least))
+			case ModeLineInterchange:
+				speeds.lineInterchange = max(speeds.lineInterchange, km/float64(least))
+			case ModeStationInterchange:
+				speeds.stationInterchange = max(speeds.stationInterchange, km/float64(least))
+			}
+		}
+	}
+	for _, node := range reached {
+		node.speeds = speeds
+	}
+	return speeds
+}
+
+// Gives a lower bound on the search cost from any Node to the nearest of a
+// search's destinations: the straight-line distance there at the greatest
+// speed any link of the graph allows. No trip can beat that, so A* guided by
+// it still finds the quickest.
+type distanceHeuristic struct {
+	points      map[string]mapPoint
+	dests       []mapPoint
+	kmPerMinute float64
+}
+
+// Return the distance heuristic for a search from the specified start Nodes
+// to the specified destinations under the specified search options (which
+// may be nil), using the station coordinates built into the transit data,
+// or nil if it cannot be used: if any destination has no known coordinates,
+// or no link the search could follow joins two stations with known
+// coordinates, or one joins two places apart in no time at all
+func newDistanceHeuristic(starts map[*Node]progress, isDest map[string]bool,
+	opts *SearchOptions) *distanceHeuristic {
+	points := stationPoints()
+	if len(points) == 0 || len(isDest) == 0 {
+		return nil
+	}
+	dests := make([]mapPoint, 0, len(isDest))
+	for dest := range isDest {
+		p, known := points[dest]
+		if !known {
+			return nil
+		}
+		dests = append(dests, p)
+	}
+
+	// Walks go quicker at faster walking speeds, and lifts only slow them
+	var kmPerMinute float64
+	for node := range starts {
+		speeds := graphSpeeds(node, points)
+		if speeds.instant {
+			return nil
+		}
+		interchangeSpeed, streetSpeed := 1.0, 1.0
+		if opts != nil {
+			interchangeSpeed, streetSpeed = max(1, opts.InterchangeSpeed), max(1, opts.StreetSpeed)
+		}
+		kmPerMinute = max(kmPerMinute, speeds.rail, speeds.lineInterchange*interchangeSpeed,
+			speeds.stationInterchange*streetSpeed)
+	}
+	if kmPerMinute == 0 {
+		return nil
+	}
+	return &distanceHeuristic{points, dests, kmPerMinute}
+}
+
+// Return the lower bound on the search cost from the specified Node to the
+// nearest destination, zero for stations with no known coordinates
+func (dh *distanceHeuristic) bound(node *Node) uint16 {
+	p, known := dh.points[node.station]
+	if !known {
+		return 0
+	}
+	km := math.Inf(1)
+	for _, dest := range dh.dests {
+		km = min(km, p.distance(dest))
+	}
+	return uint16(min(math.Floor(km/dh.kmPerMinute), math.MaxUint16-1))
+}
+
+// Run an A* search from the specified start Nodes to the destination which
+// can be reached soonest, as shortestPath does, but visiting Nodes in order
+// of their search cost plus the heuristic's bound on the cost still to come,
+// so that Nodes heading away from the destinations are mostly never visited.
+// Stations with no known coordinates have a bound of zero, so a Node may be
+// visited again if a cheaper way to it turns up later.
+func astarPath(starts map[*Node]progress, isDest map[string]bool, dh *distanceHeuristic,
+	opts *SearchOptions) ([]*Node, []*Link, error) {
+	nodePrev := make(map[*Node]*Node)
+	linkPrev := make(map[*Node]*Link)
+	queue := make(frontierQueue, 0, len(starts))
+	for node, start := range starts {
+		node.totalTime, node.elapsed, node.changes = start.cost, start.elapsed, 0
+		heap.Push(&queue, frontierEntry{node, AddTime(start.cost, dh.bound(node)), 0})
+	}
+	prev := func(node *Node) *Node { return nodePrev[node] }
+	var bestNode *Node
+	var bestTime uint16 = math.MaxUint16
+	for len(queue) > 0 {
+		if opts != nil && !opts.Deadline.IsZero() && time.Now().After(opts.Deadline) {
+			return nil, nil, ErrDeadlineExceeded
+		}
+		entry := heap.Pop(&queue).(frontierEntry)
+		curNode := entry.node
+		// No trip through the remaining Nodes can improve on the best
+		// arrival, or match it to break the tie, as the bounds never
+		// overestimate
+		if entry.cost > bestTime || (bestNode == nil && entry.cost == bestTime) {
+			break
+		}
+		// Skip entries for Nodes since reached more cheaply, or as cheaply
+		// with fewer interchanges
+		if entry.cost != AddTime(curNode.totalTime, dh.bound(curNode)) || entry.changes != curNode.changes {
+			continue
+		}
+		if isDest[curNode.station] {
+			if !opts.accessible(curNode) {
+				continue
+			}
+			arrival := AddTime(curNode.totalTime, opts.accessTime(curNode))
+			if arrival < bestTime || (bestNode != nil && arrival == bestTime &&
+				preferTie(curNode.changes, bestNode.changes,
+					func() tieKey { return tieKeyTo(curNode, prev) },
+					func() tieKey { return tieKeyTo(bestNode, prev) })) {
+				bestNode, bestTime = curNode, arrival
+			}
+			continue
+		}
+		for _, link := range curNode.adj {
+			if opts.blocked(curNode, link) {
+				continue
+			}
+			endNode := link.endNode
+			reached := opts.linkCost(curNode, link, curNode.progress(starts))
+			cost := reached.cost
+			changes := AddTime(curNode.changes, linkChanges(link))
+			if cost < endNode.totalTime || (cost == endNode.totalTime && cost != math.MaxUint16 &&
+				preferTie(changes, endNode.changes,
+					func() tieKey { return tieKeyTo(curNode, prev).then(endNode) },
+					func() tieKey { return tieKeyTo(endNode, prev) })) {
+				endNode.totalTime, endNode.elapsed, endNode.changes = cost, reached.elapsed, changes
+				nodePrev[endNode], linkPrev[endNode] = curNode, link
+				heap.Push(&queue, frontierEntry{endNode, AddTime(cost, dh.bound(endNode)), changes})
+			}
+		}
+	}
+	if bestNode == nil {
+		return nil, nil, ErrNoRoute
+	}
+	route, links := tracePath(bestNode, nodePrev, linkPrev)
+	return route, links, nil
+}
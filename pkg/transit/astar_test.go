@@ -0,0 +1,77 @@
+package transit
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"testing"
+)
+
+// Return a made-up network of stations a kilometre or so apart on a grid of
+// the specified size, with a line along each row and down each column, a
+// change of lines at every station and walks to the stations diagonally
+// next to them, placing the stations on the map until the test ends so that
+// A* can search it. Run times are random but the same every run.
+func gridNodeMap(t *testing.T, size int) (NodeMap, []string) {
+	t.Helper()
+	rng := rand.New(rand.NewPCG(3, 4))
+	station := func(row, col int) string { return fmt.Sprintf("Grid %c%d", 'A'+row, col+1) }
+	rowLine := func(row int) string { return fmt.Sprintf("Row %c", 'A'+row) }
+	colLine := func(col int) string { return fmt.Sprintf("Column %d", col+1) }
+
+	npq := make(NodePriorityQueue, 0)
+	nodeMap := make(NodeMap)
+	coords := make(map[string]Coordinates)
+	var stations []string
+	connect := func(connection any, mode LinkMode) {
+		AddConnection(&npq, nodeMap, connection, LinkAttributes{Mode: mode})
+	}
+	for row := range size {
+		for col := range size {
+			name := station(row, col)
+			stations = append(stations, name)
+			coords[name] = NewCoordinates(51.45+0.01*float64(row), -0.2+0.016*float64(col))
+			ic := NewInterchange(name, rowLine(row), name, colLine(col), uint16(1+rng.IntN(4)))
+			connect(&ic, ModeLineInterchange)
+			if col+1 < size {
+				link := NewRailLink(name, station(row, col+1), rowLine(row), uint16(2+rng.IntN(3)))
+				connect(&link, ModeRail)
+			}
+			if row+1 < size {
+				link := NewRailLink(name, station(row+1, col), colLine(col), uint16(2+rng.IntN(3)))
+				connect(&link, ModeRail)
+			}
+			if row+1 < size && col+1 < size {
+				walk := NewInterchange(name, rowLine(row), station(row+1, col+1), rowLine(row+1),
+					uint16(15+rng.IntN(5)))
+				connect(&walk, ModeStationInterchange)
+			}
+		}
+	}
+	useStationCoordinates(t, coords)
+	return nodeMap, stations
+}
+
+// A* finds the same trips as Dijkstra's algorithm under any options, given
+// stations placed on the map
+func TestAStarMatchesDijkstra(t *testing.T) {
+	nodeMap, stations := gridNodeMap(t, 6)
+	for _, opts := range []*SearchOptions{
+		nil,
+		{Optimize: OptimizeChanges},
+		{InterchangeSpeed: 0.5, StreetSpeed: 0.75},
+		{ClosedLines: map[string]bool{"Row C": true, "Column 4": true}},
+	} {
+		for _, start := range stations {
+			for _, dest := range stations {
+				if start == dest {
+					continue
+				}
+				results := searchAll(nodeMap, nil, start, dest, opts)
+				if _, ran := results["A*"]; !ran && results["Dijkstra"].err == nil {
+					t.Fatalf("%s to %s: A* did not search the graph with options %+v", start, dest, opts)
+				}
+				checkSearchesAgree(t, start, dest, results)
+			}
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package transit
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Write a file at the specified path with the contents produced by the
+// specified function, atomically: the contents go to a temporary file in the
+// same directory, which only replaces the file once fully written and synced.
+// An interrupted or failed write leaves any previous file as it was.
+func WriteFileAtomic(path string, write func(io.Writer) error) error {
+	temp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+	if err := write(temp); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Sync(); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(temp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(temp.Name(), path)
+}
+
+// Append the specified lines to the file at the specified path in a single
+// write, creating the file and its directory if needed. If an earlier write
+// was interrupted partway through a line, that incomplete line is dropped
+// first, so that the new lines do not run on from it.
+func appendLines(path string, lines []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return err
+	}
+	if err := truncateIncompleteLine(file); err != nil {
+		file.Close()
+		return err
+	}
+	if _, err := file.Write(lines); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Truncate the specified file after its last newline, removing whatever
+// follows it
+func truncateIncompleteLine(file *os.File) error {
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	end := info.Size()
+	chunk := make([]byte, 4096)
+	for pos := end; pos > 0; {
+		n := min(pos, int64(len(chunk)))
+		pos -= n
+		if _, err := file.ReadAt(chunk[:n], pos); err != nil {
+			return err
+		}
+		if idx := bytes.LastIndexByte(chunk[:n], '\n'); idx >= 0 {
+			end = pos + int64(idx) + 1
+			break
+		}
+		end = pos
+	}
+	if end == info.Size() {
+		return nil
+	}
+	return file.Truncate(end)
+}
+
+// Call the specified function on every non-blank line read from a JSON lines
+// file at the specified path, reporting the position of any line it fails to
+// decode. A last line which fails to decode and lacks its newline was cut
+// short by an interrupted write, so it is skipped rather than reported.
+func scanJSONLines(r io.Reader, path string, decode func(line []byte) error) error {
+	reader := bufio.NewReader(r)
+	for lineNum := 1; ; lineNum++ {
+		line, err := reader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+		if len(bytes.TrimSpace(line)) > 0 {
+			if decodeErr := decode(line); decodeErr != nil && err != io.EOF {
+				return fmt.Errorf("%s:%d: corrupt line: %v", path, lineNum, decodeErr)
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package transit
+
+import (
+	"container/heap"
+	"math"
+	"slices"
+	"time"
+)
+
+// Represents a Node reached by one side of a bidirectional search at the
+// specified search cost, making the specified number of interchanges
+type frontierEntry struct {
+	node    *Node
+	cost    uint16
+	changes uint16
+}
+
+// Min heap of frontier entries by search cost, then interchanges, then
+// station and line. A Node may appear more than once as better ways to it
+// are found, with all but the best skipped.
+type frontierQueue []frontierEntry
+
+func (fq frontierQueue) Len() int { return len(fq) }
+func (fq frontierQueue) Less(i, j int) bool {
+	a, b := fq[i], fq[j]
+	return a.cost < b.cost || (a.cost == b.cost &&
+		(a.changes < b.changes || (a.changes == b.changes && compareNodes(a.node, b.node) < 0)))
+}
+func (fq frontierQueue) Swap(i, j int) { fq[i], fq[j] = fq[j], fq[i] }
+
+func (fq *frontierQueue) Push(x any) { *fq = append(*fq, x.(frontierEntry)) }
+
+func (fq *frontierQueue) Pop() any {
+	old := *fq
+	entry := old[len(old)-1]
+	*fq = old[:len(old)-1]
+	return entry
+}
+
+// Represents one side of a bidirectional search: the least search cost found
+// for each Node it has reached and the interchanges made on the way, the
+// Node it was reached from and the link between them, and the Nodes whose
+// cost is final. Searching backwards, the Node a Node was reached from is
+// the next one along the trip.
+type searchFrontier struct {
+	backward bool
+	costs    map[*Node]uint16
+	changes  map[*Node]uint16
+	from     map[*Node]*Node
+	via      map[*Node]*Link
+	settled  map[*Node]bool
+	queue    frontierQueue
+}
+
+func newSearchFrontier(backward bool) *searchFrontier {
+	return &searchFrontier{backward: backward, costs: make(map[*Node]uint16), changes: make(map[*Node]uint16),
+		from: make(map[*Node]*Node), via: make(map[*Node]*Link), settled: make(map[*Node]bool)}
+}
+
+// Record the specified Node as reached at the specified cost, making the
+// specified number of interchanges, from the specified Node by the specified
+// link, unless it has already been settled or reached more cheaply, or as
+// cheaply by a preferable way (see preferTie), returning whether it was
+// recorded
+func (sf *searchFrontier) reach(node *Node, cost, changes uint16, from *Node, link *Link) bool {
+	if sf.settled[node] {
+		return false
+	}
+	if known, exists := sf.costs[node]; exists && (known < cost || (known == cost &&
+		!preferTie(changes, sf.changes[node], func() tieKey { return sf.keyVia(node, from) },
+			func() tieKey { return sf.keyVia(node, sf.from[node]) }))) {
+		return false
+	}
+	sf.costs[node], sf.changes[node], sf.from[node], sf.via[node] = cost, changes, from, link
+	heap.Push(&sf.queue, frontierEntry{node, cost, changes})
+	return true
+}
+
+// Return the key (see preferTie) of the trip to the specified Node, or on
+// from it when searching backwards, if it is reached from the specified Node
+// (nil for where the side began)
+func (sf *searchFrontier) keyVia(node, from *Node) tieKey {
+	step := func(n *Node) *Node {
+		if n == node {
+			return from
+		}
+		return sf.from[n]
+	}
+	if sf.backward {
+		return tieKeyFrom(node, step)
+	}
+	return tieKeyTo(node, step)
+}
+
+// Return the least cost of any Node yet to be settled, dropping entries for
+// Nodes already settled, or math.MaxUint16 if there are none
+func (sf *searchFrontier) top() uint16 {
+	for len(sf.queue) > 0 && sf.settled[sf.queue[0].node] {
+		heap.Pop(&sf.queue)
+	}
+	if len(sf.queue) == 0 {
+		return math.MaxUint16
+	}
+	return sf.queue[0].cost
+}
+
+// Remove and return the Node of least cost yet to be settled, marking it as
+// settled. The queue must not be empty, as checked by top.
+func (sf *searchFrontier) settle() *Node {
+	node := heap.Pop(&sf.queue).(frontierEntry).node
+	sf.settled[node] = true
+	return node
+}
+
+// Return whether a search with the specified options (which may be nil)
+// costs every link the same whenever it is followed, so that it can be
+// searched backwards from the destination as well as forwards from the start
+func (opts *SearchOptions) timeIndependent() bool {
+	return opts == nil || opts.DepartAt.IsZero()
+}
+
+// Return whether the options (which may be nil) leave every search option
+// unset, apart from any deadline, so that the search can be answered from a
+// contraction hierarchy
+func (opts *SearchOptions) unconstrained() bool {
+	if opts == nil {
+		return true
+	}
+	defaultSpeed := func(speed float64) bool { return speed == 0 || speed == 1 }
+	return len(opts.ClosedLines) == 0 && len(opts.ClosedStations) == 0 && len(opts.AvoidStations) == 0 &&
+		opts.BoardingPenalty == nil && len(opts.InterchangePenalty) == 0 && defaultSpeed(opts.InterchangeSpeed) &&
+		defaultSpeed(opts.StreetSpeed) && opts.DepartAt.IsZero() &&
+		(opts.Optimize == "" || opts.Optimize == OptimizeTime) && len(opts.LineDelays) == 0 &&
+		!opts.StepFree && len(opts.excludedNodes) == 0 && len(opts.excludedLinks) == 0
+}
+
+// Run Dijkstra's algorithm from both ends at once: forwards from the
+// specified start Nodes, each beginning with the specified search cost, and
+// backwards from the platforms of the specified destination stations, each
+// beginning with the walk out of the station. The two searches take turns,
+// whichever has fewer Nodes waiting going next, until no trip through a Node
+// they have yet to settle could beat the best trip found where they meet.
+// This settles far fewer Nodes than searching forwards alone on long trips
+// across large networks. Every link must cost the same whenever it is
+// followed (see SearchOptions.timeIndependent). As with shortestPath, the
+// Nodes visited and the links followed between them are returned, though
+// travel times are left for recomputeRouteTimes to set.
+func bidirectionalPath(nodeMap NodeMap, starts map[*Node]progress, isDest map[string]bool,
+	opts *SearchOptions) ([]*Node, []*Link, error) {
+	forward, backward := newSearchFrontier(false), newSearchFrontier(true)
+	for node, start := range starts {
+		forward.reach(node, start.cost, 0, nil, nil)
+	}
+	for dest := range isDest {
+		for _, node := range nodeMap[dest] {
+			if !opts.closed(node) && opts.accessible(node) && (opts == nil || !opts.excludedNodes[node]) {
+				backward.reach(node, opts.accessTime(node), 0, nil, nil)
+			}
+		}
+	}
+
+	// The best trip found so far, through the Node where the searches met.
+	// Trips of equal cost are told apart by preferTie, joining the trip to
+	// the meeting with the trip on from it.
+	var meeting *Node
+	var bestCost uint16 = math.MaxUint16
+	meetingKey := func(node *Node) tieKey {
+		return forward.keyVia(node, forward.from[node]).join(backward.keyVia(node, backward.from[node]))
+	}
+	meet := func(node *Node) {
+		forwardCost, reachedForward := forward.costs[node]
+		backwardCost, reachedBackward := backward.costs[node]
+		if !reachedForward || !reachedBackward {
+			return
+		}
+		cost := AddTime(forwardCost, backwardCost)
+		changes := AddTime(forward.changes[node], backward.changes[node])
+		if cost < bestCost || (meeting != nil && cost == bestCost && meeting != node &&
+			preferTie(changes, AddTime(forward.changes[meeting], backward.changes[meeting]),
+				func() tieKey { return meetingKey(node) }, func() tieKey { return meetingKey(meeting) })) {
+			meeting, bestCost = node, cost
+		}
+	}
+	for node := range starts {
+		meet(node)
+	}
+
+	// Searching on while trips could still match the best one found lets
+	// ties be broken however the trips meet
+	for cost := AddTime(forward.top(), backward.top()); cost < bestCost ||
+		(cost == bestCost && cost != math.MaxUint16); cost = AddTime(forward.top(), backward.top()) {
+		if opts != nil && !opts.Deadline.IsZero() && time.Now().After(opts.Deadline) {
+			return nil, nil, ErrDeadlineExceeded
+		}
+		if len(forward.queue) <= len(backward.queue) {
+			// Trips end at the first platform of a destination station they
+			// reach, so none travel on from one
+			curNode := forward.settle()
+			if isDest[curNode.station] {
+				continue
+			}
+			for _, link := range curNode.adj {
+				if opts.blocked(curNode, link) {
+					continue
+				}
+				at := progress{cost: forward.costs[curNode], origin: starts[curNode].origin}
+				cost := opts.linkCost(curNode, link, at).cost
+				changes := AddTime(forward.changes[curNode], linkChanges(link))
+				if forward.reach(link.endNode, cost, changes, curNode, link) {
+					meet(link.endNode)
+				}
+			}
+		} else {
+			// Every connection runs both ways (see AddConnection), so the
+			// links into the current Node are those back from its neighbours
+			curNode := backward.settle()
+			for _, back := range curNode.adj {
+				prevNode := back.endNode
+				if isDest[prevNode.station] {
+					continue
+				}
+				for _, link := range prevNode.adj {
+					if link.endNode != curNode || opts.blocked(prevNode, link) {
+						continue
+					}
+					at := progress{origin: starts[prevNode].origin}
+					cost := AddTime(opts.linkCost(prevNode, link, at).cost, backward.costs[curNode])
+					changes := AddTime(linkChanges(link), backward.changes[curNode])
+					if backward.reach(prevNode, cost, changes, curNode, link) {
+						meet(prevNode)
+					}
+				}
+			}
+		}
+	}
+	if meeting == nil {
+		return nil, nil, ErrNoRoute
+	}
+
+	// Follow the links back to the start from where the searches met, then
+	// on to the destination
+	route, links := []*Node{meeting}, make([]*Link, 0)
+	for node := meeting; forward.from[node] != nil; node = forward.from[node] {
+		route = append(route, forward.from[node])
+		links = append(links, forward.via[node])
+	}
+	slices.Reverse(route)
+	slices.Reverse(links)
+	for node := meeting; backward.from[node] != nil; node = backward.from[node] {
+		route = append(route, backward.from[node])
+		links = append(links, backward.via[node])
+	}
+	return route, links, nil
+}
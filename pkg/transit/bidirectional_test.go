@@ -0,0 +1,26 @@
+package transit
+
+import "testing"
+
+// Searching from both ends finds the same trip as Dijkstra's algorithm, under
+// each kind of option which leaves costs the same at every time of day
+func TestBidirectionalMatchesDijkstra(t *testing.T) {
+	nodeMap := defaultNodeMap(t)
+	for _, opts := range []*SearchOptions{
+		nil,
+		{Optimize: OptimizeChanges},
+		{InterchangeSpeed: 0.5, StreetSpeed: 0.75},
+		{InterchangePenalty: map[LinkMode]uint16{ModeLineInterchange: 3, ModeStationInterchange: 6}},
+		{ClosedLines: map[string]bool{"Victoria": true, "Jubilee": true},
+			ClosedStations: map[string]bool{"Green Park": true}},
+		{StepFree: true},
+	} {
+		for _, pair := range samplePairs(nodeMap, 150) {
+			results := searchAll(nodeMap, nil, pair[0], pair[1], opts)
+			if _, ran := results["bidirectional"]; !ran {
+				t.Fatalf("bidirectional search did not run with options %+v", opts)
+			}
+			checkSearchesAgree(t, pair[0], pair[1], results)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package transit
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Kinds of day a line may run on, combined as a set of bits in ServiceDays
+type DayType uint8
+
+const (
+	DayWeekday DayType = 1 << iota
+	DayWeekend
+	DayNight
+)
+
+// Names of the kinds of day, as given to --when
+var dayTypeNames = map[DayType]string{
+	DayWeekday: "weekday",
+	DayWeekend: "weekend",
+	DayNight:   "night",
+}
+
+func (d DayType) String() string {
+	if name, exists := dayTypeNames[d]; exists {
+		return name
+	}
+	return fmt.Sprintf("DayType(%d)", uint8(d))
+}
+
+// Parse the name of a kind of day: weekday, weekend or night
+func ParseDayType(s string) (DayType, error) {
+	for day, name := range dayTypeNames {
+		if s == name {
+			return day, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown service profile %q, expected weekday, weekend or night", s)
+}
+
+// Return the kinds of day on which trains call at the specified Node, under
+// the specified calendar. Lines the calendar leaves out run on weekdays and
+// at weekends but not at night.
+func nodeDays(node *Node, calendar []ServiceDays) DayType {
+	days, stationListed := DayWeekday|DayWeekend, false
+	for _, sd := range calendar {
+		if sd.line != node.line {
+			continue
+		}
+		if slices.Contains(sd.stations, node.station) {
+			days, stationListed = sd.days, true
+		} else if sd.stations == nil && !stationListed {
+			days = sd.days
+		}
+	}
+	return days
+}
+
+// Remove from the graph the platforms of every line whose trains do not call
+// there on the specified kind of day, according to the specified calendar,
+// along with any links to them, and any stations left without platforms. At
+// night, the lines still running take on the specified night services in
+// place of their daytime ones. Returns the number of platforms removed.
+func ApplyServiceProfile(nodeMap NodeMap, day DayType, calendar []ServiceDays,
+	nightServices []LineService) int {
+	removed := 0
+	for station, lines := range nodeMap {
+		for line, node := range lines {
+			if nodeDays(node, calendar)&day == 0 {
+				delete(lines, line)
+				removed++
+			}
+		}
+		if len(lines) == 0 {
+			delete(nodeMap, station)
+		}
+	}
+	for _, lines := range nodeMap {
+		for _, node := range lines {
+			node.adj = slices.DeleteFunc(node.adj, func(link *Link) bool {
+				return nodeMap[link.endNode.station][link.endNode.line] != link.endNode
+			})
+		}
+	}
+	if day == DayNight {
+		ApplyLineServices(nodeMap, nightServices)
+	}
+	return removed
+}
@@ -0,0 +1,655 @@
+package transit
+
+import (
+	"bufio"
+	"cmp"
+	"container/heap"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Version of the contraction hierarchy file format, to be increased whenever
+// it or the way hierarchies are built changes so that older files are rebuilt
+const hierarchyVersion = 2
+
+// Most Nodes settled by each witness search while contracting a Node. A
+// witness search cut short only means adding a shortcut that may not be
+// needed, so this trades a few extra shortcuts for a much quicker build.
+const witnessSettleLimit = 500
+
+// Returned when loading a contraction hierarchy built for a different graph
+var ErrStaleHierarchy = errors.New("contraction hierarchy is out of date")
+
+// Represents an edge of a contraction hierarchy: either a link of the graph,
+// given by its position among the links of the Node it leaves, or a shortcut
+// standing for the two edges either side of a Node contracted between them.
+// The interchanges made along it are worked out from the links, rather than
+// stored.
+type hierarchyEdge struct {
+	from, to      int32
+	cost          uint16
+	link          int32
+	first, second int32
+	changes       uint16
+}
+
+// Return the edge's cost packed together with the interchanges made along
+// it (see packCost)
+func (edge hierarchyEdge) weight() int {
+	return packCost(edge.cost, edge.changes)
+}
+
+// Pack a search cost and a number of interchanges into one number, which
+// orders trips by cost and then by interchanges as preferTie does, so that
+// the hierarchy breaks ties between trips the same way as other searches
+func packCost(cost, changes uint16) int {
+	return int(cost)<<16 | int(changes)
+}
+
+// Return the sum of two packed costs, adding costs and interchanges apart
+func addPacked(a, b int) int {
+	return packCost(AddTime(uint16(a>>16), uint16(b>>16)), AddTime(uint16(a), uint16(b)))
+}
+
+// Represents a contraction hierarchy over a transit graph, which answers
+// queries for the fastest trip between two stations by searching only
+// upwards through the ranks of its Nodes from either end, visiting a small
+// fraction of the graph. It is built for searches without any search
+// options, so queries with closures, penalties, a departure time or any
+// other option set still search the graph itself. Queries leave the graph
+// untouched, so any number may run at once.
+type ContractionHierarchy struct {
+	nodeMap NodeMap
+	nodes   []*Node
+	index   map[*Node]int32
+	rank    []int32
+	edges   []hierarchyEdge
+	// Edges out of each Node to Nodes of higher rank, and into each Node
+	// from Nodes of higher rank
+	up   [][]int32
+	down [][]int32
+}
+
+// Represents a contraction hierarchy as stored in a file, with a key
+// identifying the graph it was built for
+type cachedHierarchy struct {
+	Key   string
+	Rank  []int32
+	Edges []cachedHierarchyEdge
+}
+
+// Represents an edge of a stored contraction hierarchy
+type cachedHierarchyEdge struct {
+	From, To      int32
+	Cost          uint16
+	Link          int32
+	First, Second int32
+}
+
+// Return the default location of the contraction hierarchy file, inside the
+// user's cache directory (falling back to the working directory)
+func DefaultHierarchyPath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "tubeplanner-hierarchy.cache"
+	}
+	return filepath.Join(dir, "tubeplanner", "hierarchy.cache")
+}
+
+// Return a contraction hierarchy over the specified graph with its Nodes in
+// a fixed order and no edges yet
+func newContractionHierarchy(nodeMap NodeMap) *ContractionHierarchy {
+	ch := &ContractionHierarchy{nodeMap: nodeMap, index: make(map[*Node]int32)}
+	for _, lines := range nodeMap {
+		for _, node := range lines {
+			ch.nodes = append(ch.nodes, node)
+		}
+	}
+	slices.SortFunc(ch.nodes, func(a, b *Node) int {
+		return cmp.Or(strings.Compare(a.station, b.station), strings.Compare(a.line, b.line))
+	})
+	for idx, node := range ch.nodes {
+		ch.index[node] = int32(idx)
+	}
+	return ch
+}
+
+// Return a key identifying the specified graph as it is searched without
+// search options: its Nodes, their waits and access times, and the cost of
+// every link. Any change to them gives a different key.
+func hierarchyKey(ch *ContractionHierarchy) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "version %d\n", hierarchyVersion)
+	for _, node := range ch.nodes {
+		fmt.Fprintf(hash, "%s\x00%s %d %d\n", node.station, node.line, node.boardTime, node.accessTime)
+		for _, link := range node.adj {
+			fmt.Fprintf(hash, "-> %d %d\n", ch.index[link.endNode], (*SearchOptions)(nil).linkCost(node, link, progress{}).cost)
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// Sort the edges of the hierarchy into those leading up and down its ranks
+func (ch *ContractionHierarchy) indexEdges() {
+	ch.up = make([][]int32, len(ch.nodes))
+	ch.down = make([][]int32, len(ch.nodes))
+	for id, edge := range ch.edges {
+		if ch.rank[edge.to] > ch.rank[edge.from] {
+			ch.up[edge.from] = append(ch.up[edge.from], int32(id))
+		} else {
+			ch.down[edge.to] = append(ch.down[edge.to], int32(id))
+		}
+	}
+}
+
+// Represents a Node of a contraction hierarchy, by its position, queued at
+// the specified priority: a packed search cost (see packCost), or how soon
+// to contract it
+type rankedEntry struct {
+	node     int32
+	priority int
+}
+
+// Min heap of hierarchy Nodes by priority, then position, so that Nodes of
+// equal priority always come out in the same order. A Node may appear more
+// than once, with all but the entry of least priority skipped.
+type rankedQueue []rankedEntry
+
+func (rq rankedQueue) Len() int { return len(rq) }
+func (rq rankedQueue) Less(i, j int) bool {
+	return rq[i].priority < rq[j].priority || (rq[i].priority == rq[j].priority && rq[i].node < rq[j].node)
+}
+func (rq rankedQueue) Swap(i, j int) { rq[i], rq[j] = rq[j], rq[i] }
+
+func (rq *rankedQueue) Push(x any) { *rq = append(*rq, x.(rankedEntry)) }
+
+func (rq *rankedQueue) Pop() any {
+	old := *rq
+	entry := old[len(old)-1]
+	*rq = old[:len(old)-1]
+	return entry
+}
+
+// Build a contraction hierarchy over the specified graph for searches
+// without search options. Nodes are contracted one at a time, those adding
+// the fewest shortcuts compared with the edges they remove going first, and
+// each contracted Node is bypassed by a shortcut between every pair of its
+// remaining neighbours whose fastest way between them runs through it, or
+// ties with the fastest. The order of contraction gives the ranks of the
+// Nodes. Of two edges between the same Nodes, the one preferred by the
+// tie-break of every search (see preferTie) is kept.
+func BuildContractionHierarchy(nodeMap NodeMap) *ContractionHierarchy {
+	ch := newContractionHierarchy(nodeMap)
+	n := len(ch.nodes)
+	// The best edge between each pair of Nodes not yet contracted, by the
+	// Node at either end
+	out := make([]map[int32]int32, n)
+	in := make([]map[int32]int32, n)
+	for idx := range n {
+		out[idx], in[idx] = make(map[int32]int32), make(map[int32]int32)
+	}
+	addEdge := func(edge hierarchyEdge) {
+		if existing, exists := out[edge.from][edge.to]; exists {
+			if weight := ch.edges[existing].weight(); weight < edge.weight() || (weight == edge.weight() &&
+				ch.edgeKey(edge).compare(ch.edgeKey(ch.edges[existing])) >= 0) {
+				return
+			}
+		}
+		id := int32(len(ch.edges))
+		ch.edges = append(ch.edges, edge)
+		out[edge.from][edge.to], in[edge.to][edge.from] = id, id
+	}
+	for from, node := range ch.nodes {
+		for pos, link := range node.adj {
+			if to := ch.index[link.endNode]; to != int32(from) {
+				addEdge(hierarchyEdge{int32(from), to, (*SearchOptions)(nil).linkCost(node, link, progress{}).cost,
+					int32(pos), -1, -1, linkChanges(link)})
+			}
+		}
+	}
+
+	// Return the cheapest packed costs from the specified Node to others
+	// not yet contracted, avoiding the Node being contracted, as far as the
+	// specified cost and the settle limit allow
+	witness := func(from, skip int32, maxCost int) map[int32]int {
+		costs := map[int32]int{from: 0}
+		settled := make(map[int32]bool)
+		queue := rankedQueue{{from, 0}}
+		for len(queue) > 0 && len(settled) < witnessSettleLimit {
+			entry := heap.Pop(&queue).(rankedEntry)
+			if settled[entry.node] {
+				continue
+			}
+			if entry.priority > maxCost {
+				break
+			}
+			settled[entry.node] = true
+			for to, id := range out[entry.node] {
+				if to == skip {
+					continue
+				}
+				cost := addPacked(costs[entry.node], ch.edges[id].weight())
+				if known, exists := costs[to]; !exists || cost < known {
+					costs[to] = cost
+					heap.Push(&queue, rankedEntry{to, cost})
+				}
+			}
+		}
+		return costs
+	}
+	// Return the shortcuts needed to contract the specified Node, as the
+	// pairs of edges into and out of it that each would stand for
+	shortcuts := func(node int32) [][2]int32 {
+		needed := make([][2]int32, 0)
+		for from, inID := range in[node] {
+			var maxCost int
+			pairs := false
+			for to, outID := range out[node] {
+				if to != from {
+					maxCost = max(maxCost, addPacked(ch.edges[inID].weight(), ch.edges[outID].weight()))
+					pairs = true
+				}
+			}
+			if !pairs {
+				continue
+			}
+			costs := witness(from, node, maxCost)
+			for to, outID := range out[node] {
+				if to == from {
+					continue
+				}
+				// A way around the Node only as good as the way through it
+				// may lose the tie-break (see preferTie), so the shortcut is
+				// still needed
+				viaCost := addPacked(ch.edges[inID].weight(), ch.edges[outID].weight())
+				if known, exists := costs[to]; !exists || known >= viaCost {
+					needed = append(needed, [2]int32{inID, outID})
+				}
+			}
+		}
+		return needed
+	}
+	// Nodes are ordered by how many more edges contracting them leaves,
+	// spread out by how many of their neighbours were contracted already
+	contractedNeighbours := make([]int, n)
+	priority := func(node int32) int {
+		return len(shortcuts(node)) - len(in[node]) - len(out[node]) + 2*contractedNeighbours[node]
+	}
+
+	queue := make(rankedQueue, 0, n)
+	for node := range int32(n) {
+		queue = append(queue, rankedEntry{node, priority(node)})
+	}
+	heap.Init(&queue)
+	ch.rank = make([]int32, n)
+	contracted := make([]bool, n)
+	for next := int32(0); len(queue) > 0; {
+		entry := heap.Pop(&queue).(rankedEntry)
+		if contracted[entry.node] {
+			continue
+		}
+		// Contracting other Nodes changes the priority of this one, so it
+		// is only contracted if it still goes first
+		if updated := priority(entry.node); len(queue) > 0 && updated > queue[0].priority {
+			heap.Push(&queue, rankedEntry{entry.node, updated})
+			continue
+		}
+		// Shortcuts are added in a fixed order, for the same hierarchy from
+		// the same graph every time
+		node := entry.node
+		needed := shortcuts(node)
+		slices.SortFunc(needed, func(a, b [2]int32) int {
+			return cmp.Or(cmp.Compare(a[0], b[0]), cmp.Compare(a[1], b[1]))
+		})
+		for _, pair := range needed {
+			first, second := ch.edges[pair[0]], ch.edges[pair[1]]
+			addEdge(hierarchyEdge{first.from, second.to, AddTime(first.cost, second.cost), -1,
+				pair[0], pair[1], AddTime(first.changes, second.changes)})
+		}
+		for from := range in[node] {
+			delete(out[from], node)
+			contractedNeighbours[from]++
+		}
+		for to := range out[node] {
+			delete(in[to], node)
+			contractedNeighbours[to]++
+		}
+		contracted[node] = true
+		ch.rank[node] = next
+		next++
+	}
+	ch.indexEdges()
+	return ch
+}
+
+// Returned by searches of a contraction hierarchy whose fastest way passes
+// through a platform of a destination station before the end, which trips
+// planned by searching the graph never do
+var errHierarchyDetour = errors.New("fastest way passes through a destination station")
+
+// Return the links of the graph that the specified hierarchy edge stands for,
+// in order, appended to those given
+func (ch *ContractionHierarchy) unpack(id int32, links []*Link) []*Link {
+	edge := ch.edges[id]
+	if edge.link >= 0 {
+		return append(links, ch.nodes[edge.from].adj[edge.link])
+	}
+	return ch.unpack(edge.second, ch.unpack(edge.first, links))
+}
+
+// Return the packed cost (see packCost) of boarding a train at the Node the
+// specified edge leaves when the trip sets off along the edge from there at
+// its origin, which the edge's own cost leaves out (see linkCost)
+func (ch *ContractionHierarchy) originCost(id int32) int {
+	edge := ch.edges[id]
+	for edge.link < 0 {
+		edge = ch.edges[edge.first]
+	}
+	node := ch.nodes[edge.from]
+	if node.adj[edge.link].attrs.Mode == ModeStationInterchange {
+		return 0
+	}
+	return packCost((*SearchOptions)(nil).originBoarding(node).cost, 0)
+}
+
+// Represents one side of a search of a contraction hierarchy: the least
+// packed search cost found for each Node it has reached (see packCost), the
+// edge followed to reach it (or -1 for where the side began), and the Nodes
+// whose cost is final. Searching backwards, edges are followed against
+// their direction, from the Node they lead to.
+type hierarchyFrontier struct {
+	ch       *ContractionHierarchy
+	backward bool
+	costs    map[int32]int
+	via      map[int32]int32
+	settled  map[int32]bool
+	queue    rankedQueue
+}
+
+// Record the specified Node as reached at the specified packed cost by the
+// specified edge, unless it has already been settled or reached more
+// cheaply, or as cheaply by a preferable way (see preferTie), returning
+// whether it was recorded
+func (hf *hierarchyFrontier) reach(node int32, cost int, via int32) bool {
+	if hf.settled[node] {
+		return false
+	}
+	if known, exists := hf.costs[node]; exists && (known < cost ||
+		(known == cost && hf.key(node, via).compare(hf.key(node, hf.via[node])) >= 0)) {
+		return false
+	}
+	hf.costs[node], hf.via[node] = cost, via
+	heap.Push(&hf.queue, rankedEntry{node, cost})
+	return true
+}
+
+// Return the edges followed to reach the specified Node, or on from it when
+// searching backwards, in the order the trip follows them, if it is reached
+// by the specified edge
+func (hf *hierarchyFrontier) edgesVia(node, via int32) []int32 {
+	edges := make([]int32, 0)
+	for id := via; id >= 0; id = hf.via[node] {
+		edges = append(edges, id)
+		if hf.backward {
+			node = hf.ch.edges[id].to
+		} else {
+			node = hf.ch.edges[id].from
+		}
+	}
+	if !hf.backward {
+		slices.Reverse(edges)
+	}
+	return edges
+}
+
+// Return the key (see preferTie) of the trip to the specified Node, or on
+// from it when searching backwards, if it is reached by the specified edge
+func (hf *hierarchyFrontier) key(node, via int32) tieKey {
+	edges := hf.edgesVia(node, via)
+	if !hf.backward && len(edges) > 0 {
+		node = hf.ch.edges[edges[0]].from
+	}
+	return hf.ch.keyAlong(node, edges)
+}
+
+// Return the least packed cost of any Node yet to be settled, dropping
+// entries for Nodes already settled, or math.MaxInt if there are none
+func (hf *hierarchyFrontier) top() int {
+	for len(hf.queue) > 0 && hf.settled[hf.queue[0].node] {
+		heap.Pop(&hf.queue)
+	}
+	if len(hf.queue) == 0 {
+		return math.MaxInt
+	}
+	return hf.queue[0].priority
+}
+
+// Remove and return the Node of least cost yet to be settled, marking it as
+// settled. The queue must not be empty, as checked by top.
+func (hf *hierarchyFrontier) settle() int32 {
+	node := heap.Pop(&hf.queue).(rankedEntry).node
+	hf.settled[node] = true
+	return node
+}
+
+// Return the key (see preferTie) of the trip from the specified Node
+// following the specified edges
+func (ch *ContractionHierarchy) keyAlong(start int32, edges []int32) tieKey {
+	links := make([]*Link, 0, len(edges))
+	for _, id := range edges {
+		links = ch.unpack(id, links)
+	}
+	return linksKey(ch.nodes[start], links)
+}
+
+// Return the key (see preferTie) of the trip along the specified edge, which
+// need not have been added to the hierarchy yet
+func (ch *ContractionHierarchy) edgeKey(edge hierarchyEdge) tieKey {
+	if edge.link >= 0 {
+		return linksKey(ch.nodes[edge.from], []*Link{ch.nodes[edge.from].adj[edge.link]})
+	}
+	return linksKey(ch.nodes[edge.from], ch.unpack(edge.second, ch.unpack(edge.first, nil)))
+}
+
+// Return the key (see preferTie) of the trip from the specified Node
+// following the specified links
+func linksKey(start *Node, links []*Link) tieKey {
+	key := tieKey{}.then(start)
+	for _, link := range links {
+		key = key.then(link.endNode)
+	}
+	return key
+}
+
+// Search the hierarchy for the fastest trip from the specified start station
+// to any of the specified destination stations, upwards through the ranks
+// from either end until neither side can improve on the best trip found
+// where they meet, or match it to break the tie, returning the Nodes visited
+// and the links followed
+func (ch *ContractionHierarchy) search(start string, isDest map[string]bool) ([]*Node, []*Link, error) {
+	newFrontier := func(backward bool) *hierarchyFrontier {
+		return &hierarchyFrontier{ch: ch, backward: backward, costs: make(map[int32]int),
+			via: make(map[int32]int32), settled: make(map[int32]bool)}
+	}
+	forward, backward := newFrontier(false), newFrontier(true)
+	// The edges of the trip through the specified Node where the searches
+	// meet, and the Node it starts from
+	tripEdges := func(node int32) (int32, []int32) {
+		edges := forward.edgesVia(node, forward.via[node])
+		first := node
+		if len(edges) > 0 {
+			first = ch.edges[edges[0]].from
+		}
+		return first, append(edges, backward.edgesVia(node, backward.via[node])...)
+	}
+	meeting, bestCost := int32(-1), math.MaxInt
+	meet := func(node int32) {
+		forwardCost, reachedForward := forward.costs[node]
+		backwardCost, reachedBackward := backward.costs[node]
+		if !reachedForward || !reachedBackward {
+			return
+		}
+		cost := addPacked(forwardCost, backwardCost)
+		if cost < bestCost || (cost == bestCost && meeting >= 0 && meeting != node &&
+			ch.keyAlong(tripEdges(node)).compare(ch.keyAlong(tripEdges(meeting))) < 0) {
+			meeting, bestCost = node, cost
+		}
+	}
+	origin := make(map[int32]bool)
+	for node, start := range startCosts(ch.nodeMap, start, nil) {
+		forward.reach(ch.index[node], packCost(start.cost, 0), -1)
+		origin[ch.index[node]] = start.origin
+	}
+	// Edges leaving the origin also count boarding a train there, whichever
+	// side of the search follows them
+	leaving := func(node, id int32) int {
+		if origin[node] {
+			return ch.originCost(id)
+		}
+		return 0
+	}
+	for dest := range isDest {
+		for _, node := range ch.nodeMap[dest] {
+			backward.reach(ch.index[node], packCost((*SearchOptions)(nil).accessTime(node), 0), -1)
+			meet(ch.index[node])
+		}
+	}
+
+	for {
+		forwardTop, backwardTop := forward.top(), backward.top()
+		if least := min(forwardTop, backwardTop); least > bestCost || least == math.MaxInt {
+			break
+		}
+		if forwardTop <= backwardTop {
+			node := forward.settle()
+			for _, id := range ch.up[node] {
+				edge := ch.edges[id]
+				cost := addPacked(addPacked(forward.costs[node], leaving(node, id)), edge.weight())
+				if forward.reach(edge.to, cost, id) {
+					meet(edge.to)
+				}
+			}
+		} else {
+			node := backward.settle()
+			for _, id := range ch.down[node] {
+				edge := ch.edges[id]
+				cost := addPacked(addPacked(leaving(edge.from, id), edge.weight()), backward.costs[node])
+				if backward.reach(edge.from, cost, id) {
+					meet(edge.from)
+				}
+			}
+		}
+	}
+	if meeting < 0 {
+		return nil, nil, ErrNoRoute
+	}
+
+	// Follow the edges back to the start from where the searches met, then
+	// on to the destination, and unpack their shortcuts into links
+	first, edges := tripEdges(meeting)
+	links := make([]*Link, 0, len(edges))
+	for _, id := range edges {
+		links = ch.unpack(id, links)
+	}
+	route := []*Node{ch.nodes[first]}
+	for _, link := range links {
+		if isDest[route[len(route)-1].station] {
+			return nil, nil, errHierarchyDetour
+		}
+		route = append(route, link.endNode)
+	}
+	return route, links, nil
+}
+
+// Calculate the fastest trip from the specified start station to whichever
+// of the specified destinations can be reached soonest, as
+// RunShortestPathsToAny does without search options, the Journey's To being
+// the destination chosen. The graph is left untouched.
+func (ch *ContractionHierarchy) ShortestPath(start string, dests []string) (*Journey, error) {
+	if slices.Contains(dests, start) {
+		return arrivedJourney(start), nil
+	}
+	isDest := make(map[string]bool)
+	for _, dest := range dests {
+		isDest[dest] = true
+	}
+	route, links, err := ch.search(start, isDest)
+	if errors.Is(err, errHierarchyDetour) {
+		// On the rare occasions the hierarchy finds a faster way on through a
+		// destination station than ending there, the graph itself is
+		// searched, which also leaves it untouched without search options
+		route, links, err = bidirectionalPath(ch.nodeMap, startCosts(ch.nodeMap, start, nil), isDest, nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return newJourney(route, links, nil), nil
+}
+
+// Write the hierarchy to a file at the specified path, creating its
+// directory if needed
+func (ch *ContractionHierarchy) Save(path string) error {
+	stored := cachedHierarchy{Key: hierarchyKey(ch), Rank: ch.rank,
+		Edges: make([]cachedHierarchyEdge, len(ch.edges))}
+	for idx, edge := range ch.edges {
+		stored.Edges[idx] = cachedHierarchyEdge{edge.from, edge.to, edge.cost, edge.link, edge.first, edge.second}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return WriteFileAtomic(path, func(w io.Writer) error {
+		return gob.NewEncoder(w).Encode(stored)
+	})
+}
+
+// Read the contraction hierarchy stored at the specified path for the
+// specified graph, returning ErrStaleHierarchy if it was built for a graph
+// differing from it in any way
+func LoadContractionHierarchy(path string, nodeMap NodeMap) (*ContractionHierarchy, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var stored cachedHierarchy
+	if err := gob.NewDecoder(bufio.NewReader(file)).Decode(&stored); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	ch := newContractionHierarchy(nodeMap)
+	if stored.Key != hierarchyKey(ch) {
+		return nil, ErrStaleHierarchy
+	}
+	if len(stored.Rank) != len(ch.nodes) {
+		return nil, fmt.Errorf("%s: expected ranks for %d nodes", path, len(ch.nodes))
+	}
+	ch.rank = stored.Rank
+	ch.edges = make([]hierarchyEdge, len(stored.Edges))
+	valid := func(node int32) bool { return node >= 0 && int(node) < len(ch.nodes) }
+	for idx, se := range stored.Edges {
+		switch {
+		case !valid(se.From) || !valid(se.To):
+			return nil, fmt.Errorf("%s: edge %d joins unknown nodes", path, idx)
+		case se.Link >= 0 && int(se.Link) >= len(ch.nodes[se.From].adj):
+			return nil, fmt.Errorf("%s: edge %d follows an unknown link", path, idx)
+		case se.Link < 0 && (se.First < 0 || se.Second < 0 || int(se.First) >= idx || int(se.Second) >= idx):
+			return nil, fmt.Errorf("%s: shortcut %d stands for unknown edges", path, idx)
+		}
+		ch.edges[idx] = hierarchyEdge{se.From, se.To, se.Cost, se.Link, se.First, se.Second, 0}
+		if se.Link >= 0 {
+			ch.edges[idx].changes = linkChanges(ch.nodes[se.From].adj[se.Link])
+		} else {
+			ch.edges[idx].changes = AddTime(ch.edges[se.First].changes, ch.edges[se.Second].changes)
+		}
+	}
+	ch.indexEdges()
+	return ch, nil
+}
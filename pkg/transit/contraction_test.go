@@ -0,0 +1,36 @@
+package transit
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// The contraction hierarchy finds the same trips as Dijkstra's algorithm, both
+// as built and once saved and loaded again
+func TestContractionHierarchyMatchesDijkstra(t *testing.T) {
+	nodeMap := defaultNodeMap(t)
+	built := BuildContractionHierarchy(nodeMap)
+	path := filepath.Join(t.TempDir(), "hierarchy.gob")
+	if err := built.Save(path); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+	loaded, err := LoadContractionHierarchy(path, nodeMap)
+	if err != nil {
+		t.Fatalf("LoadContractionHierarchy() failed: %v", err)
+	}
+
+	for _, ch := range []*ContractionHierarchy{built, loaded} {
+		for _, pair := range samplePairs(nodeMap, 300) {
+			results := searchAll(nodeMap, ch, pair[0], pair[1], nil)
+			checkSearchesAgree(t, pair[0], pair[1], results)
+
+			journey, err := ch.ShortestPath(pair[0], []string{pair[1]})
+			if err != nil {
+				t.Errorf("%s to %s: ShortestPath() failed: %v", pair[0], pair[1], err)
+			} else if want := results["Dijkstra"]; journey.TotalMinutes != want.cost {
+				t.Errorf("%s to %s: ShortestPath() takes %d minutes, Dijkstra's algorithm %d",
+					pair[0], pair[1], journey.TotalMinutes, want.cost)
+			}
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package transit
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// Returned when building a graph in which lines meet at stations but there
+// is no way of changing between lines anywhere, neither from the data nor
+// assumed, as for a feed without interchange times
+var ErrNoInterchanges = errors.New("no interchanges between lines")
+
+// Add a line interchange taking the specified minutes between every pair of
+// lines serving the same station for which the transit data defines no
+// interchange, marking each as assumed so that journeys relying on one can
+// be flagged. Zero minutes adds none. Stations and lines are visited in
+// sorted order so the graph comes out the same on every run.
+func AddAssumedInterchanges(npq *NodePriorityQueue, nodeMap NodeMap, minutes uint16) {
+	if minutes == 0 {
+		return
+	}
+	stations := make([]string, 0, len(nodeMap))
+	for station := range nodeMap {
+		stations = append(stations, station)
+	}
+	slices.Sort(stations)
+	for _, station := range stations {
+		lines := make([]string, 0, len(nodeMap[station]))
+		for line := range nodeMap[station] {
+			lines = append(lines, line)
+		}
+		slices.Sort(lines)
+		for i, lineA := range lines {
+			for _, lineB := range lines[i+1:] {
+				nodeA, nodeB := nodeMap[station][lineA], nodeMap[station][lineB]
+				if slices.ContainsFunc(nodeA.adj, func(link *Link) bool {
+					return link.endNode == nodeB
+				}) {
+					continue
+				}
+				ic := Interchange{station, lineA, station, lineB, minutes}
+				AddConnection(npq, nodeMap, &ic, LinkAttributes{Mode: ModeLineInterchange, Assumed: true})
+			}
+		}
+	}
+}
+
+// Return an error wrapping ErrNoInterchanges if stations of the specified
+// graph are served by several lines but no interchange between lines exists
+// at any of them, so that no trip could ever change lines. Gaps at only some
+// stations are left to ValidateGraph to report.
+func checkLineChanges(nodeMap NodeMap) error {
+	shared := make([]string, 0)
+	for station, lines := range nodeMap {
+		if len(lines) < 2 {
+			continue
+		}
+		shared = append(shared, station)
+		for _, node := range lines {
+			for _, link := range node.adj {
+				if link.attrs.Mode == ModeLineInterchange {
+					return nil
+				}
+			}
+		}
+	}
+	if len(shared) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: no time for changing lines at %s or any other station where lines meet",
+		ErrNoInterchanges, slices.Min(shared))
+}
+
+// Return a description of every part of the specified journey which relies
+// on values assumed in the absence of transit data, rather than on the data
+// itself: interchanges and walks without times, and lines boarded without
+// a service frequency to work out the wait from. A decoded journey has these
+// in its DataWarnings instead.
+func DataCoverageFlags(journey *Journey) []string {
+	flags := make([]string, 0)
+	unscheduled := make(map[string]bool)
+	hops := journey.hops()
+	for idx, hop := range hops {
+		boarding := hop.link.attrs.Mode == ModeRail && (idx == 0 || hops[idx-1].link.attrs.Mode != ModeRail)
+		if boarding && hop.from.service == nil && !unscheduled[hop.from.line] {
+			unscheduled[hop.from.line] = true
+			flags = append(flags, fmt.Sprintf(
+				"No service frequency for the %s line, assumed %d minutes' wait for its trains",
+				hop.from.line, hop.from.boardTime))
+		}
+		if !hop.link.attrs.Assumed {
+			continue
+		}
+		if hop.link.attrs.Mode == ModeStationInterchange {
+			flags = append(flags, fmt.Sprintf(
+				"No walking time from %s to %s, estimated %d minutes from the distance between them",
+				hop.from.station, hop.to.station, hop.link.time))
+		} else {
+			flags = append(flags, fmt.Sprintf(
+				"No interchange time between the %s and %s lines at %s, assumed %d minutes",
+				hop.from.line, hop.to.line, hop.from.station, hop.link.time))
+		}
+	}
+	return flags
+}
+
+// Print a warning listing any parts of the specified journey which rely on
+// assumed values, so that users know which times are less certain
+func PrintDataCoverage(journey *Journey) {
+	flags := journey.DataWarnings
+	if len(flags) == 0 {
+		return
+	}
+	fmt.Println("Note: parts of this journey rely on assumed data:")
+	for _, flag := range flags {
+		fmt.Printf("- %s\n", flag)
+	}
+}
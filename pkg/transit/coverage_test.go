@@ -0,0 +1,102 @@
+package transit
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// Changes between lines with no interchange in the data are only assumed
+// when asked for, and journeys relying on one are flagged
+func TestAddAssumedInterchanges(t *testing.T) {
+	build := func(minutes uint16) NodeMap {
+		npq := make(NodePriorityQueue, 0)
+		nodeMap := make(NodeMap)
+		for _, link := range []RailLink{
+			NewRailLink("Oxford Circus", "Green Park", "Victoria", 2),
+			NewRailLink("Green Park", "Westminster", "Jubilee", 2),
+		} {
+			AddConnection(&npq, nodeMap, &link, LinkAttributes{Mode: ModeRail})
+		}
+		AddAssumedInterchanges(&npq, nodeMap, minutes)
+		ApplyLineServices(nodeMap, GetLineServices())
+		return nodeMap
+	}
+
+	nodeMap := build(0)
+	if _, err := NewPlanner(NewGraph(nodeMap)).Plan("Oxford Circus", "Westminster"); err == nil {
+		t.Error("planned a trip changing lines at Green Park, where no interchange is assumed")
+	}
+	gap := slices.IndexFunc(ValidateGraph(nodeMap, nil), func(issue GraphIssue) bool {
+		return issue.Check == CheckInterchange && issue.Warning &&
+			strings.HasPrefix(issue.Message, "no interchange between the Jubilee and Victoria lines at Green Park")
+	})
+	if gap < 0 {
+		t.Error("ValidateGraph() did not warn of the missing interchange at Green Park")
+	}
+
+	route, err := NewPlanner(NewGraph(build(5))).Plan("Oxford Circus", "Westminster")
+	if err != nil {
+		t.Fatalf("planning a trip changing lines at Green Park: %v", err)
+	}
+	flags := DataCoverageFlags(route.Journey())
+	if len(flags) != 1 || !strings.Contains(flags[0], "assumed 5 minutes") {
+		t.Errorf("DataCoverageFlags() = %q, want the assumed change at Green Park", flags)
+	}
+}
+
+// The bundled transit data gets no assumed interchanges unless asked for
+func TestDefaultGraphAssumesNothing(t *testing.T) {
+	for _, lines := range defaultNodeMap(t) {
+		for _, node := range lines {
+			for _, link := range node.adj {
+				if link.attrs.Assumed && link.attrs.Mode == ModeLineInterchange {
+					t.Fatalf("%s has an assumed interchange to %s", describeNode(node), describeNode(link.endNode))
+				}
+			}
+		}
+	}
+}
+
+// Building a graph fails loudly, rather than leaving trips unable to change
+// lines anywhere, when lines meet but the data has no interchanges and none
+// are assumed
+func TestBuildWithoutInterchanges(t *testing.T) {
+	railLinks := []RailLink{
+		NewRailLink("Oxford Circus", "Green Park", "Victoria", 2),
+		NewRailLink("Green Park", "Westminster", "Jubilee", 2),
+	}
+	opts := GraphOptions{}
+	if _, err := buildDatasetGraph(railLinks, nil, nil, opts); !errors.Is(err, ErrNoInterchanges) {
+		t.Errorf("building without interchanges gave error %v, want ErrNoInterchanges", err)
+	}
+	opts.AssumedInterchangeMinutes = 5
+	if _, err := buildDatasetGraph(railLinks, nil, nil, opts); err != nil {
+		t.Errorf("building with assumed interchanges failed: %v", err)
+	}
+	if _, err := buildDatasetGraph(railLinks[:1], nil, nil, GraphOptions{}); err != nil {
+		t.Errorf("building a single line failed: %v", err)
+	}
+}
+
+// Journeys boarding a line without a service frequency are flagged, once
+// for each such line
+func TestDataCoverageFlagsMissingFrequency(t *testing.T) {
+	npq := make(NodePriorityQueue, 0)
+	nodeMap := make(NodeMap)
+	for _, link := range []RailLink{
+		NewRailLink("Alpha", "Bravo", "Red", 3),
+		NewRailLink("Bravo", "Charlie", "Red", 3),
+	} {
+		AddConnection(&npq, nodeMap, &link, LinkAttributes{Mode: ModeRail})
+	}
+	route, err := NewPlanner(NewGraph(nodeMap)).Plan("Alpha", "Charlie")
+	if err != nil {
+		t.Fatalf("planning a trip on the Red line: %v", err)
+	}
+	flags := DataCoverageFlags(route.Journey())
+	if len(flags) != 1 || !strings.HasPrefix(flags[0], "No service frequency for the Red line") {
+		t.Errorf("DataCoverageFlags() = %q, want the Red line's missing frequency", flags)
+	}
+}
@@ -0,0 +1,125 @@
+package transit
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+)
+
+// Returned when a quicker search and Dijkstra's algorithm disagree on the
+// best trip, which means the quicker search has a bug
+var ErrCrossCheckFailed = errors.New("cross-check failed")
+
+// Copy every Node reachable from the specified start Nodes, so that a second
+// search can run on the copies at the same time as another on the originals,
+// returning a queue of the copies ready for searching along with the start
+// costs and search options (which may be nil) translated to them
+func copySearchGraph(starts map[*Node]progress, opts *SearchOptions) (NodePriorityQueue,
+	map[*Node]progress, *SearchOptions) {
+	copies := make(map[*Node]*Node)
+	reached := make([]*Node, 0, len(starts))
+	for node := range starts {
+		copies[node] = nil
+		reached = append(reached, node)
+	}
+	for idx := 0; idx < len(reached); idx++ {
+		for _, link := range reached[idx].adj {
+			if _, seen := copies[link.endNode]; !seen {
+				copies[link.endNode] = nil
+				reached = append(reached, link.endNode)
+			}
+		}
+	}
+	npq := make(NodePriorityQueue, 0, len(reached))
+	for _, node := range reached {
+		copied := *node
+		copied.totalTime, copied.elapsed, copied.changes = math.MaxUint16, 0, 0
+		copies[node] = &copied
+		npq.Push(&copied)
+	}
+	linkCopies := make(map[*Link]*Link)
+	for _, node := range reached {
+		copied := copies[node]
+		copied.adj = make([]*Link, len(node.adj))
+		for idx, link := range node.adj {
+			copied.adj[idx] = &Link{copies[link.endNode], link.time, link.attrs}
+			linkCopies[link] = copied.adj[idx]
+		}
+	}
+
+	copyStarts := make(map[*Node]progress, len(starts))
+	for node, start := range starts {
+		copyStarts[copies[node]] = start
+	}
+	if opts == nil {
+		return npq, copyStarts, nil
+	}
+	copyOpts := *opts
+	copyOpts.excludedNodes = make(map[*Node]bool, len(opts.excludedNodes))
+	for node, excluded := range opts.excludedNodes {
+		if copied, exists := copies[node]; exists {
+			copyOpts.excludedNodes[copied] = excluded
+		}
+	}
+	copyOpts.excludedLinks = make(map[*Link]bool, len(opts.excludedLinks))
+	for link, excluded := range opts.excludedLinks {
+		if copied, exists := linkCopies[link]; exists {
+			copyOpts.excludedLinks[copied] = excluded
+		}
+	}
+	return npq, copyStarts, &copyOpts
+}
+
+// Return the search cost of the trip following the specified links from the
+// first of the specified Nodes, as the searches work it out
+func tripCost(route []*Node, links []*Link, starts map[*Node]progress, opts *SearchOptions) uint16 {
+	at := starts[route[0]]
+	for idx, link := range links {
+		at = opts.linkCost(route[idx], link, at)
+	}
+	return AddTime(at.cost, opts.accessTime(route[len(route)-1]))
+}
+
+// Run the specified search, which the named router makes from the specified
+// start Nodes to the specified destinations, while Dijkstra's algorithm
+// makes the same search on a copy of the graph, returning the search's trip
+// if both agree on its search cost, or on there being no trip at all
+func crossChecked(router string, starts map[*Node]progress, isDest map[string]bool, opts *SearchOptions,
+	search func() ([]*Node, []*Link, error)) ([]*Node, []*Link, error) {
+	npq, copyStarts, copyOpts := copySearchGraph(starts, opts)
+	var wantRoute []*Node
+	var wantLinks []*Link
+	var wantErr error
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		wantRoute, wantLinks, wantErr = dijkstraPath(&npq, copyStarts, isDest, copyOpts)
+	}()
+	route, links, err := search()
+	wg.Wait()
+
+	for _, e := range []error{err, wantErr} {
+		if e != nil && !errors.Is(e, ErrNoRoute) {
+			return nil, nil, e
+		}
+	}
+	switch {
+	case err != nil && wantErr != nil:
+		return nil, nil, err
+	case err != nil:
+		return nil, nil, fmt.Errorf("%w: %s found no trip to %s, but Dijkstra's algorithm found one costing %d minutes",
+			ErrCrossCheckFailed, router, wantRoute[len(wantRoute)-1].station,
+			tripCost(wantRoute, wantLinks, copyStarts, copyOpts))
+	case wantErr != nil:
+		return nil, nil, fmt.Errorf("%w: %s found a trip to %s costing %d minutes, but Dijkstra's algorithm found none",
+			ErrCrossCheckFailed, router, route[len(route)-1].station, tripCost(route, links, starts, opts))
+	}
+	if got, want := tripCost(route, links, starts, opts), tripCost(wantRoute, wantLinks, copyStarts, copyOpts); got != want {
+		return nil, nil, fmt.Errorf("%w: %s found a trip from %s to %s costing %d minutes, but Dijkstra's algorithm found one to %s costing %d",
+			ErrCrossCheckFailed, router, route[0].station, route[len(route)-1].station, got,
+			wantRoute[len(wantRoute)-1].station, want)
+	}
+	return route, links, nil
+}
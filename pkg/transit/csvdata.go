@@ -0,0 +1,178 @@
+package transit
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Names of the CSV files in a data directory holding each section of the
+// dataset, named after the sections of a YAML dataset
+const (
+	railLinksCSV    = "railLinks.csv"
+	interchangesCSV = "interchanges.csv"
+)
+
+// Return whether the specified directory holds a CSV dataset, judged by
+// whether it has a rail links file
+func IsCSVDataDir(dir string) bool {
+	info, err := os.Stat(filepath.Join(dir, railLinksCSV))
+	return err == nil && !info.IsDir()
+}
+
+// Call the specified function on every record of the CSV file at the
+// specified path holding the named section of a dataset, with the record's
+// fields keyed by the column names of its header row and the line it was
+// found on. The header must name every field of the section's schema (see
+// yamlDatasetSchema), in any order, and no others. Blank lines and lines
+// starting with "#" are skipped, and fields are trimmed of spaces.
+func readDatasetCSV(path, section string, visit func(fields map[string]string, lineNum int) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err == io.EOF {
+		return fmt.Errorf("%s: empty file, expected a header row", path)
+	} else if err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+	schema := yamlDatasetSchema[section]
+	columns := make([]string, len(header))
+	for idx, column := range header {
+		columns[idx] = strings.TrimSpace(strings.TrimPrefix(column, "\ufeff"))
+		if _, known := schema[columns[idx]]; !known {
+			return fmt.Errorf("%s:1: unknown column %q", path, columns[idx])
+		}
+		if slices.Contains(columns[:idx], columns[idx]) {
+			return fmt.Errorf("%s:1: duplicate column %q", path, columns[idx])
+		}
+	}
+	for _, field := range slices.Sorted(maps.Keys(schema)) {
+		if !slices.Contains(columns, field) {
+			return fmt.Errorf("%s:1: missing column %q", path, field)
+		}
+	}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+		lineNum, _ := reader.FieldPos(0)
+		if len(record) != len(columns) {
+			return fmt.Errorf("%s:%d: expected %d fields (%s), found %d", path, lineNum, len(columns),
+				strings.Join(columns, ","), len(record))
+		}
+		fields := make(map[string]string, len(columns))
+		for idx, column := range columns {
+			fields[column] = strings.TrimSpace(record[idx])
+			if fields[column] == "" {
+				return fmt.Errorf("%s:%d: field %q must not be empty", path, lineNum, column)
+			}
+		}
+		if err := visit(fields, lineNum); err != nil {
+			return fmt.Errorf("%s:%d: %v", path, lineNum, err)
+		}
+	}
+}
+
+// Parse the time field of a dataset CSV record as a whole number of minutes
+func parseCSVMinutes(s string) (uint16, error) {
+	n, err := strconv.ParseUint(s, 10, 16)
+	if err != nil || n == math.MaxUint16 {
+		return 0, fmt.Errorf("field \"time\" must be a whole number of minutes, not %q", s)
+	}
+	return uint16(n), nil
+}
+
+// Read a dataset of rail links and interchanges from the railLinks.csv and
+// interchanges.csv files in the specified directory, each with a header row
+// naming the same fields as the YAML format, e.g. "from,to,line,time". The
+// interchanges file may be left out. Records are rejected, with the file and
+// line they are on, for missing or empty fields, times that are not whole
+// numbers, connections from a station to itself, connections listed more
+// than once (in either direction, as every connection runs both ways), and
+// interchanges at station/line pairs no rail link serves.
+func LoadCSVDataset(dir string) ([]RailLink, []Interchange, error) {
+	served := make(map[[2]string]bool)
+	seen := make(map[string]int)
+	railLinks := make([]RailLink, 0)
+	err := readDatasetCSV(filepath.Join(dir, railLinksCSV), "railLinks",
+		func(f map[string]string, lineNum int) error {
+			transitTime, err := parseCSVMinutes(f["time"])
+			if err != nil {
+				return err
+			}
+			if f["from"] == f["to"] {
+				return fmt.Errorf("rail link from %s to itself", f["from"])
+			}
+			ends := []string{f["from"], f["to"]}
+			slices.Sort(ends)
+			key := strings.Join(append(ends, f["line"]), "\x00")
+			if first, dup := seen[key]; dup {
+				return fmt.Errorf("rail link between %s and %s on the %s line is already listed on line %d",
+					f["from"], f["to"], f["line"], first)
+			}
+			seen[key] = lineNum
+			railLinks = append(railLinks, RailLink{f["from"], f["to"], f["line"], transitTime})
+			served[[2]string{f["from"], f["line"]}] = true
+			served[[2]string{f["to"], f["line"]}] = true
+			return nil
+		})
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(railLinks) == 0 {
+		return nil, nil, fmt.Errorf("%s: no rail links", filepath.Join(dir, railLinksCSV))
+	}
+
+	clear(seen)
+	interchanges := make([]Interchange, 0)
+	err = readDatasetCSV(filepath.Join(dir, interchangesCSV), "interchanges",
+		func(f map[string]string, lineNum int) error {
+			transitTime, err := parseCSVMinutes(f["time"])
+			if err != nil {
+				return err
+			}
+			if f["from"] == f["to"] && f["fromLine"] == f["toLine"] {
+				return fmt.Errorf("interchange from the %s line at %s to itself", f["fromLine"], f["from"])
+			}
+			ends := []string{f["from"] + "\x00" + f["fromLine"], f["to"] + "\x00" + f["toLine"]}
+			for _, end := range [][2]string{{f["from"], f["fromLine"]}, {f["to"], f["toLine"]}} {
+				if !served[end] {
+					return fmt.Errorf("no rail link serves %s on the %s line", end[0], end[1])
+				}
+			}
+			slices.Sort(ends)
+			key := strings.Join(ends, "\x00")
+			if first, dup := seen[key]; dup {
+				return fmt.Errorf("interchange between %s (%s) and %s (%s) is already listed on line %d",
+					f["from"], f["fromLine"], f["to"], f["toLine"], first)
+			}
+			seen[key] = lineNum
+			interchanges = append(interchanges,
+				Interchange{f["from"], f["fromLine"], f["to"], f["toLine"], transitTime})
+			return nil
+		})
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, nil, err
+	}
+	return railLinks, interchanges, nil
+}
@@ -0,0 +1,157 @@
+package transit
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Represents a regular trip shown on the dashboard, such as the journey to
+// work
+type Commute struct {
+	Name string
+	From string
+	To   string
+}
+
+// Return the default location of the commutes file, inside the user's config
+// directory (falling back to the working directory)
+func DefaultCommutesPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "commutes.csv"
+	}
+	return filepath.Join(dir, "tubeplanner", "commutes.csv")
+}
+
+// Read the commutes to show on the dashboard from a CSV file with one
+// "name,from,to" record per line. Lines starting with "#" are comments.
+func LoadCommutes(path string) ([]Commute, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	commutes := make([]Commute, 0)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		if len(record) != 3 {
+			lineNum, _ := reader.FieldPos(0)
+			return nil, fmt.Errorf("%s:%d: expected name,from,to", path, lineNum)
+		}
+		commutes = append(commutes, Commute{strings.TrimSpace(record[0]),
+			strings.TrimSpace(record[1]), strings.TrimSpace(record[2])})
+	}
+	return commutes, nil
+}
+
+// How close to a line's last train the dashboard starts showing when it
+// leaves
+const lastTrainNotice = time.Hour
+
+// Return when the first train of the specified journey, begun at the
+// specified time, is expected to leave, and how often trains of its line
+// run then, along with when the last one leaves if that is soon. The
+// transit data has no timetables, so this is worked out from the line's
+// frequency. Returns "-" for journeys taking no train, or whose first line
+// has no known frequency.
+func nextDepartures(journey *Journey, now time.Time) string {
+	idx := slices.IndexFunc(journey.Legs, func(leg Leg) bool { return leg.IsRide() })
+	if idx < 0 || journey.Legs[idx].start == nil || journey.Legs[idx].start.service == nil {
+		return "-"
+	}
+	ride := journey.Legs[idx]
+	service := ride.start.service
+	departs := now.Add(time.Duration(ride.Depart) * time.Minute)
+	next := fmt.Sprintf("%s %s, every %d min", ride.Line, departs.Format("15:04"), service.headwayAt(departs))
+	if last := service.lastTrainAfter(departs); last.Sub(departs) <= lastTrainNotice {
+		next += ", last " + last.Format("15:04")
+	}
+	return next
+}
+
+// Render one dashboard frame showing, for each commute, how long it takes
+// and when it arrives if begun now given the current line closures, and
+// when its first train is expected, along with the latest status of every
+// line
+func RenderDashboard(w io.Writer, nodeMap NodeMap, commutes []Commute,
+	statuses map[string]LineStatus, now time.Time) {
+	fmt.Fprintf(w, "TubePlanner dashboard, %s\n\n", now.Format("Mon 2 Jan 15:04"))
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "COMMUTE\tLEAVE NOW\tARRIVE\tNEXT TRAIN\tVIA\tALERTS")
+	opts := &SearchOptions{ClosedLines: ClosedLinesAt(statuses, now), DepartAt: now}
+	for _, commute := range commutes {
+		if _, exists := nodeMap[commute.From]; !exists {
+			fmt.Fprintf(tw, "%s\t-\t-\t-\t-\t%s is not a valid station\n", commute.Name, commute.From)
+			continue
+		}
+		if _, exists := nodeMap[commute.To]; !exists {
+			fmt.Fprintf(tw, "%s\t-\t-\t-\t-\t%s is not a valid station\n", commute.Name, commute.To)
+			continue
+		}
+		npq := ResetGraph(nodeMap)
+		journey, err := RunShortestPaths(&npq, nodeMap, commute.From, commute.To, opts)
+		if err != nil {
+			fmt.Fprintf(tw, "%s\t-\t-\t-\t-\tNo route available\n", commute.Name)
+			continue
+		}
+		lines, alerts := make([]string, 0), make([]string, 0)
+		for _, leg := range journey.Legs {
+			if !leg.IsRide() || slices.Contains(lines, leg.Line) {
+				continue
+			}
+			lines = append(lines, leg.Line)
+			if status, exists := statuses[leg.Line]; exists && status.Severity != "Good Service" {
+				alerts = append(alerts, fmt.Sprintf("%s: %s", leg.Line, status.Severity))
+			}
+		}
+		arrival := now.Add(time.Duration(journey.TotalMinutes) * time.Minute)
+		fmt.Fprintf(tw, "%s\t%d min\t%s\t%s\t%s\t%s\n", commute.Name, journey.TotalMinutes,
+			arrival.Format("15:04"), nextDepartures(journey, now), strings.Join(lines, ", "),
+			strings.Join(alerts, "; "))
+	}
+	tw.Flush()
+
+	fmt.Fprintln(w)
+	tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "LINE\tSTATUS\tAS OF")
+	lines := make([]string, 0)
+	for _, stationLines := range nodeMap {
+		for line := range stationLines {
+			if !slices.Contains(lines, line) {
+				lines = append(lines, line)
+			}
+		}
+	}
+	slices.Sort(lines)
+	for _, line := range lines {
+		status, exists := statuses[line]
+		if !exists {
+			fmt.Fprintf(tw, "%s\tNo recent status\t-\n", line)
+			continue
+		}
+		severity := status.Severity
+		if status.Reason != "" {
+			severity += " (" + status.Reason + ")"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", line, severity, status.FetchedAt.Local().Format("15:04"))
+	}
+	tw.Flush()
+}
@@ -0,0 +1,35 @@
+package transit
+
+import (
+	"testing"
+	"time"
+)
+
+// The dashboard shows when the first train of a commute is expected and how
+// often its line runs then, and when the last train leaves once it is near
+func TestNextDepartures(t *testing.T) {
+	npq, nodeMap := make(NodePriorityQueue, 0), make(NodeMap)
+	link := NewRailLink("Waterloo", "Bank", "Waterloo & City", 5)
+	AddConnection(&npq, nodeMap, &link, LinkAttributes{Mode: ModeRail})
+	ApplyLineServices(nodeMap, GetLineServices())
+
+	cases := []struct {
+		now  time.Time
+		want string
+	}{
+		{time.Date(2026, 10, 20, 8, 0, 0, 0, time.UTC), "Waterloo & City 08:02, every 3 min"},
+		{time.Date(2026, 10, 20, 14, 0, 0, 0, time.UTC), "Waterloo & City 14:03, every 5 min"},
+		{time.Date(2026, 10, 20, 23, 50, 0, 0, time.UTC), "Waterloo & City 23:53, every 5 min, last 00:30"},
+		{time.Date(2026, 10, 21, 0, 10, 0, 0, time.UTC), "Waterloo & City 00:13, every 5 min, last 00:30"},
+	}
+	for _, c := range cases {
+		npq := ResetGraph(nodeMap)
+		journey, err := RunShortestPaths(&npq, nodeMap, "Waterloo", "Bank", &SearchOptions{DepartAt: c.now})
+		if err != nil {
+			t.Fatalf("at %s: planning failed: %v", c.now.Format("15:04"), err)
+		}
+		if got := nextDepartures(journey, c.now); got != c.want {
+			t.Errorf("at %s: next departures %q, want %q", c.now.Format("15:04"), got, c.want)
+		}
+	}
+}
@@ -0,0 +1,313 @@
+package transit
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Represents a single scalar value read from a YAML dataset, along with
+// whether it was quoted and the line it was found on, for error messages
+type yamlScalar struct {
+	value  string
+	quoted bool
+	line   int
+}
+
+// Represents one entry of a section of a YAML dataset, mapping field names
+// to their values
+type yamlEntry struct {
+	fields map[string]yamlScalar
+	line   int
+}
+
+// Fields of the entries of each section of a YAML dataset, as the name of
+// each field mapped to whether it holds a number (rather than a string). All
+// fields are required.
+var yamlDatasetSchema = map[string]map[string]bool{
+	"railLinks": {
+		"from": false, "to": false, "line": false, "time": true,
+	},
+	"interchanges": {
+		"from": false, "fromLine": false, "to": false, "toLine": false, "time": true,
+	},
+}
+
+// Return the rail links and interchanges the specified DataSource supplies,
+// or the built-in data's if it is nil
+func LoadDataset(source DataSource) ([]RailLink, []Interchange, error) {
+	if source == nil {
+		source = BuiltinDataSource()
+	}
+	railLinks, err := source.GetRailLinks()
+	if err != nil {
+		return nil, nil, err
+	}
+	interchanges, err := source.GetInterchanges()
+	if err != nil {
+		return nil, nil, err
+	}
+	return railLinks, interchanges, nil
+}
+
+// Strip any comment from the specified line of YAML, leaving "#" characters
+// within quoted scalars or words alone
+func stripYAMLComment(line string) string {
+	var quote rune
+	for idx, r := range line {
+		before := strings.TrimRight(line[:idx], " ")
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case (r == '"' || r == '\'') &&
+			(before == "" || strings.HasSuffix(before, ":") || strings.HasSuffix(before, "-")):
+			quote = r
+		case r == '#' && (idx == 0 || line[idx-1] == ' '):
+			return line[:idx]
+		}
+	}
+	return line
+}
+
+// Parse a scalar value written in YAML's plain, single-quoted or
+// double-quoted style
+func parseYAMLScalar(s string, lineNum int) (yamlScalar, error) {
+	s = strings.TrimSpace(s)
+	switch {
+	case strings.HasPrefix(s, `"`):
+		value, err := strconv.Unquote(s)
+		if err != nil {
+			return yamlScalar{}, fmt.Errorf("line %d: invalid double-quoted value %s", lineNum, s)
+		}
+		return yamlScalar{value, true, lineNum}, nil
+	case strings.HasPrefix(s, "'"):
+		if len(s) < 2 || !strings.HasSuffix(s, "'") {
+			return yamlScalar{}, fmt.Errorf("line %d: invalid single-quoted value %s", lineNum, s)
+		}
+		return yamlScalar{strings.ReplaceAll(s[1:len(s)-1], "''", "'"), true, lineNum}, nil
+	case s == "" || strings.ContainsAny(s[:1], "[]{}&*!|>%@`"):
+		return yamlScalar{}, fmt.Errorf("line %d: unsupported value %q, only plain or quoted "+
+			"scalars are allowed", lineNum, s)
+	}
+	return yamlScalar{s, false, lineNum}, nil
+}
+
+// Parse a "key: value" pair from a YAML mapping
+func parseYAMLField(s string, lineNum int) (string, yamlScalar, error) {
+	if strings.ContainsAny(s[:1], "[]{}&*!|>%@`") {
+		return "", yamlScalar{}, fmt.Errorf("line %d: unsupported YAML %q, only block-style "+
+			"mappings are allowed", lineNum, s)
+	}
+	key, value, found := strings.Cut(s, ":")
+	key = strings.TrimSpace(key)
+	if !found || key == "" || (value != "" && !strings.HasPrefix(value, " ")) {
+		return "", yamlScalar{}, fmt.Errorf("line %d: expected \"key: value\"", lineNum)
+	}
+	scalar, err := parseYAMLScalar(value, lineNum)
+	return key, scalar, err
+}
+
+// Parse the subset of YAML used by datasets: a top-level mapping from section
+// names to block sequences of flat mappings, with values in plain or quoted
+// style. Returns the entries of each section.
+func parseYAMLSections(r io.Reader) (map[string][]yamlEntry, error) {
+	sections := make(map[string][]yamlEntry)
+	var section string
+	var entry *yamlEntry
+	entryIndent := -1
+	scanner := bufio.NewScanner(r)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimRight(stripYAMLComment(scanner.Text()), " \r")
+		if strings.TrimSpace(line) == "" || line == "---" {
+			continue
+		}
+		if strings.Contains(line, "\t") {
+			return nil, fmt.Errorf("line %d: tabs are not allowed for indentation", lineNum)
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		content := line[indent:]
+
+		switch {
+		case indent == 0:
+			name, found := strings.CutSuffix(content, ":")
+			if !found || strings.ContainsAny(name, ": ") {
+				return nil, fmt.Errorf("line %d: expected a section name followed by \":\"", lineNum)
+			}
+			if _, known := yamlDatasetSchema[name]; !known {
+				return nil, fmt.Errorf("line %d: unknown section %q", lineNum, name)
+			}
+			if _, seen := sections[name]; seen {
+				return nil, fmt.Errorf("line %d: duplicate section %q", lineNum, name)
+			}
+			section, entry = name, nil
+			sections[section] = make([]yamlEntry, 0)
+			continue
+		case section == "":
+			return nil, fmt.Errorf("line %d: expected a section name", lineNum)
+		case content == "-" || strings.HasPrefix(content, "- "):
+			sections[section] = append(sections[section],
+				yamlEntry{make(map[string]yamlScalar), lineNum})
+			entry = &sections[section][len(sections[section])-1]
+			rest := strings.TrimLeft(strings.TrimPrefix(content, "-"), " ")
+			entryIndent = len(line) - len(rest)
+			if rest == "" {
+				entryIndent = -1
+				continue
+			}
+			content = rest
+		case entry == nil:
+			return nil, fmt.Errorf("line %d: expected a \"- \" sequence entry", lineNum)
+		case entryIndent < 0:
+			entryIndent = indent
+		case indent != entryIndent:
+			return nil, fmt.Errorf("line %d: inconsistent indentation", lineNum)
+		}
+
+		key, value, err := parseYAMLField(content, lineNum)
+		if err != nil {
+			return nil, err
+		}
+		if _, known := yamlDatasetSchema[section][key]; !known {
+			return nil, fmt.Errorf("line %d: unknown field %q in %s", lineNum, key, section)
+		}
+		if _, seen := entry.fields[key]; seen {
+			return nil, fmt.Errorf("line %d: duplicate field %q", lineNum, key)
+		}
+		entry.fields[key] = value
+	}
+	return sections, scanner.Err()
+}
+
+// Check the fields of the specified dataset entry against the schema of its
+// section, returning its string fields and its time
+func checkYAMLEntry(section string, entry yamlEntry) (map[string]string, uint16, error) {
+	strs := make(map[string]string)
+	var transitTime uint16
+	for _, field := range slices.Sorted(maps.Keys(yamlDatasetSchema[section])) {
+		isNumber := yamlDatasetSchema[section][field]
+		scalar, exists := entry.fields[field]
+		if !exists {
+			return nil, 0, fmt.Errorf("line %d: %s entry is missing field %q", entry.line, section, field)
+		}
+		if isNumber {
+			n, err := strconv.ParseUint(scalar.value, 10, 16)
+			if err != nil || scalar.quoted || n == math.MaxUint16 {
+				return nil, 0, fmt.Errorf("line %d: field %q must be a whole number of minutes, not %q",
+					scalar.line, field, scalar.value)
+			}
+			transitTime = uint16(n)
+			continue
+		}
+		if _, err := strconv.ParseFloat(scalar.value, 64); err == nil && !scalar.quoted {
+			return nil, 0, fmt.Errorf("line %d: field %q must be a string, quote %q to use it as one",
+				scalar.line, field, scalar.value)
+		}
+		switch strings.ToLower(scalar.value) {
+		case "true", "false", "null", "~", "yes", "no":
+			if !scalar.quoted {
+				return nil, 0, fmt.Errorf("line %d: field %q must be a string, quote %q to use it as one",
+					scalar.line, field, scalar.value)
+			}
+		case "":
+			return nil, 0, fmt.Errorf("line %d: field %q must not be empty", scalar.line, field)
+		}
+		strs[field] = scalar.value
+	}
+	return strs, transitTime, nil
+}
+
+// Read a dataset of rail links and interchanges in YAML format, as documented
+// in the README, rejecting unknown sections and fields, missing fields,
+// values of the wrong type, and interchanges between station/line pairs no
+// rail link serves
+func ParseYAMLDataset(r io.Reader) ([]RailLink, []Interchange, error) {
+	return parseYAMLDataset(r, true)
+}
+
+// Read a YAML dataset as ParseYAMLDataset does, only rejecting interchanges
+// at station/line pairs no rail link serves if requireServed is set
+func parseYAMLDataset(r io.Reader, requireServed bool) ([]RailLink, []Interchange, error) {
+	sections, err := parseYAMLSections(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	served := make(map[[2]string]bool)
+	railLinks := make([]RailLink, 0, len(sections["railLinks"]))
+	for _, entry := range sections["railLinks"] {
+		f, transitTime, err := checkYAMLEntry("railLinks", entry)
+		if err != nil {
+			return nil, nil, err
+		}
+		if f["from"] == f["to"] {
+			return nil, nil, fmt.Errorf("line %d: rail link from %s to itself", entry.line, f["from"])
+		}
+		railLinks = append(railLinks, RailLink{f["from"], f["to"], f["line"], transitTime})
+		served[[2]string{f["from"], f["line"]}] = true
+		served[[2]string{f["to"], f["line"]}] = true
+	}
+	if len(railLinks) == 0 {
+		return nil, nil, fmt.Errorf("dataset has no rail links")
+	}
+	interchanges := make([]Interchange, 0, len(sections["interchanges"]))
+	for _, entry := range sections["interchanges"] {
+		f, transitTime, err := checkYAMLEntry("interchanges", entry)
+		if err != nil {
+			return nil, nil, err
+		}
+		if f["from"] == f["to"] && f["fromLine"] == f["toLine"] {
+			return nil, nil, fmt.Errorf("line %d: interchange from the %s line at %s to itself",
+				entry.line, f["fromLine"], f["from"])
+		}
+		for _, end := range [][2]string{{f["from"], f["fromLine"]}, {f["to"], f["toLine"]}} {
+			if requireServed && !served[end] {
+				return nil, nil, fmt.Errorf("line %d: no rail link serves %s on the %s line",
+					entry.line, end[0], end[1])
+			}
+		}
+		interchanges = append(interchanges,
+			Interchange{f["from"], f["fromLine"], f["to"], f["toLine"], transitTime})
+	}
+	return railLinks, interchanges, nil
+}
+
+// Return the specified string as a YAML scalar, in plain style where that
+// reads back as the same string and double-quoted otherwise
+func yamlString(s string) string {
+	_, numErr := strconv.ParseFloat(s, 64)
+	switch {
+	case s == "" || numErr == nil || strings.TrimSpace(s) != s,
+		strings.ContainsAny(s[:1], "-?:,[]{}#&*!|>'\"%@`"),
+		strings.Contains(s, ": ") || strings.Contains(s, " #"):
+		return strconv.Quote(s)
+	}
+	switch strings.ToLower(s) {
+	case "true", "false", "null", "~", "yes", "no":
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// Write the specified rail links and interchanges as a YAML dataset
+func WriteYAMLDataset(w io.Writer, railLinks []RailLink, interchanges []Interchange) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "# TubePlanner dataset, see the README for the schema")
+	fmt.Fprintln(bw, "railLinks:")
+	for _, rl := range railLinks {
+		fmt.Fprintf(bw, "  - from: %s\n    to: %s\n    line: %s\n    time: %d\n",
+			yamlString(rl.fromStation), yamlString(rl.toStation), yamlString(rl.line), rl.transitTime)
+	}
+	fmt.Fprintln(bw, "interchanges:")
+	for _, ic := range interchanges {
+		fmt.Fprintf(bw, "  - from: %s\n    fromLine: %s\n    to: %s\n    toLine: %s\n    time: %d\n",
+			yamlString(ic.fromStation), yamlString(ic.fromLine), yamlString(ic.toStation),
+			yamlString(ic.toLine), ic.transitTime)
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,213 @@
+package transit
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Supplies the network a transit graph is built from, so that programs
+// using the library can plan on their own city's network, or one kept in a
+// database or behind a remote API, by passing their own implementation to
+// BuildTransitGraph or LoadGraph as GraphOptions.Source. Each method is
+// called once per graph built.
+type DataSource interface {
+	// The rail links between neighbouring stations, each of which runs both
+	// ways
+	GetRailLinks() ([]RailLink, error)
+	// The interchanges between lines at a station, or on foot between
+	// nearby stations, each of which runs both ways
+	GetInterchanges() ([]Interchange, error)
+	// The stations of the network with where they are, where known, which
+	// joins stations near each other by walks where the network has no
+	// interchange between them (see GraphOptions). Stations named by the
+	// rail links but not listed here are used without coordinates.
+	GetStations() ([]Station, error)
+}
+
+// Represents a station supplied by a DataSource, along with where it is, or
+// with a nil Location if that is not known
+type Station struct {
+	Name     string
+	Location *Coordinates
+}
+
+// Return a rail link between the specified stations on the specified line,
+// taking the specified number of minutes, for use by a DataSource
+func NewRailLink(from, to, line string, minutes uint16) RailLink {
+	return RailLink{from, to, line, minutes}
+}
+
+// Return an interchange from a line at one station to a line at the same
+// or a nearby station, taking the specified number of minutes, for use by a
+// DataSource
+func NewInterchange(from, fromLine, to, toLine string, minutes uint16) Interchange {
+	return Interchange{from, fromLine, to, toLine, minutes}
+}
+
+// Implemented by the DataSources of this package, whose data is built in or
+// kept in files, so that graphs built from them can be cached: write what
+// kind of data it is to the specified hash, and stamp each file it is read
+// from
+type cacheableSource interface {
+	identify(hash io.Writer, stamp func(path string) error) error
+}
+
+// Supplies the rail links and interchanges returned by a function which
+// loads them both at once, such as from a file, loading them the first time
+// either is needed. Stations are those the rail links name, located by the
+// built-in station coordinates. The kind and files identify the data for the
+// graph cache.
+type loadedDataSource struct {
+	load         func() ([]RailLink, []Interchange, error)
+	kind         string
+	files        func(stamp func(path string) error) error
+	once         sync.Once
+	railLinks    []RailLink
+	interchanges []Interchange
+	err          error
+}
+
+// Identify the data for the graph cache by its kind and files
+func (source *loadedDataSource) identify(hash io.Writer, stamp func(path string) error) error {
+	fmt.Fprintln(hash, source.kind)
+	if source.files == nil {
+		return nil
+	}
+	return source.files(stamp)
+}
+
+func (source *loadedDataSource) loaded() ([]RailLink, []Interchange, error) {
+	source.once.Do(func() {
+		source.railLinks, source.interchanges, source.err = source.load()
+	})
+	return source.railLinks, source.interchanges, source.err
+}
+
+func (source *loadedDataSource) GetRailLinks() ([]RailLink, error) {
+	railLinks, _, err := source.loaded()
+	return railLinks, err
+}
+
+func (source *loadedDataSource) GetInterchanges() ([]Interchange, error) {
+	_, interchanges, err := source.loaded()
+	return interchanges, err
+}
+
+func (source *loadedDataSource) GetStations() ([]Station, error) {
+	railLinks, _, err := source.loaded()
+	if err != nil {
+		return nil, err
+	}
+	return knownStations(railLinks, GetStationCoordinates()), nil
+}
+
+// Return the stations the specified rail links name, sorted by name, each
+// located by the specified coordinates where they include it
+func knownStations(railLinks []RailLink, coords map[string]Coordinates) []Station {
+	names := make(map[string]bool)
+	for _, rl := range railLinks {
+		names[rl.fromStation], names[rl.toStation] = true, true
+	}
+	stations := make([]Station, 0, len(names))
+	for _, name := range slices.Sorted(maps.Keys(names)) {
+		station := Station{Name: name}
+		if c, known := coords[name]; known {
+			station.Location = &c
+		}
+		stations = append(stations, station)
+	}
+	return stations
+}
+
+// Return the DataSource supplying the rail links and interchanges in
+// transitdata.go
+func BuiltinDataSource() DataSource {
+	return &loadedDataSource{kind: "builtin", load: func() ([]RailLink, []Interchange, error) {
+		return GetRailLinks(), GetInterchanges(), nil
+	}}
+}
+
+// Return the DataSource supplying the YAML dataset at the specified path
+// (see ParseYAMLDataset)
+func YAMLDataSource(path string) DataSource {
+	return &loadedDataSource{load: func() ([]RailLink, []Interchange, error) {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer file.Close()
+		railLinks, interchanges, err := ParseYAMLDataset(file)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %v", path, err)
+		}
+		return railLinks, interchanges, nil
+	}, kind: "dataset", files: func(stamp func(path string) error) error {
+		return stamp(path)
+	}}
+}
+
+// Return the DataSource supplying the CSV dataset in the specified directory
+// (see LoadCSVDataset)
+func CSVDataSource(dir string) DataSource {
+	return &loadedDataSource{load: func() ([]RailLink, []Interchange, error) {
+		return LoadCSVDataset(dir)
+	}, kind: "csv", files: func(stamp func(path string) error) error {
+		for _, name := range []string{railLinksCSV, interchangesCSV} {
+			if err := stamp(filepath.Join(dir, name)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+		}
+		return nil
+	}}
+}
+
+// Return the DataSource supplying the rail services of the GTFS feed at the
+// specified path (see LoadGTFS)
+func GTFSDataSource(path string) DataSource {
+	return &loadedDataSource{load: func() ([]RailLink, []Interchange, error) {
+		railLinks, interchanges, err := LoadGTFS(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %v", path, err)
+		}
+		return railLinks, interchanges, nil
+	}, kind: "gtfs", files: func(stamp func(path string) error) error {
+		return filepath.WalkDir(path, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil || entry.IsDir() {
+				return err
+			}
+			return stamp(path)
+		})
+	}}
+}
+
+// Return the DataSource for the transit data at the specified path, judged
+// by its form: the built-in data if the path is "builtin", a CSV dataset if
+// it is a directory with a railLinks.csv, a YAML dataset if it has a .yaml
+// or .yml extension, an OpenStreetMap extract if it has an .osm or .osm.bz2
+// extension, a network database if it has a .db, .sqlite or .sqlite3
+// extension, or else a GTFS feed (a directory or zip file)
+func DataSourceAt(path string) DataSource {
+	lower := strings.ToLower(path)
+	switch {
+	case path == "builtin":
+		return BuiltinDataSource()
+	case IsCSVDataDir(path):
+		return CSVDataSource(path)
+	case strings.HasSuffix(lower, ".yaml") || strings.HasSuffix(lower, ".yml"):
+		return YAMLDataSource(path)
+	case strings.HasSuffix(lower, ".osm") || strings.HasSuffix(lower, ".osm.bz2"):
+		return OSMDataSource(path)
+	case strings.HasSuffix(lower, ".db") || strings.HasSuffix(lower, ".sqlite") ||
+		strings.HasSuffix(lower, ".sqlite3"):
+		return NetworkDatabase{path}
+	}
+	return GTFSDataSource(path)
+}
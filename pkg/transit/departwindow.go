@@ -0,0 +1,172 @@
+package transit
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Most departures SampleDepartures plans in one window, e.g. every minute
+// for a few hours
+const MaxDepartureSamples = 500
+
+// One of the departures planned by SampleDepartures: the time it sets off,
+// and the trip planned for that time, nil if there was no route then
+type DepartureSample struct {
+	DepartAt time.Time
+	Route    *Route
+}
+
+// Parse a window of departure times of the form "08:00-09:00", on the day
+// of the specified time. A window ending earlier in the day than it starts
+// ends the next day, e.g. "23:30-00:30".
+func ParseDepartWindow(s string, now time.Time) (time.Time, time.Time, error) {
+	fromText, toText, found := strings.Cut(s, "-")
+	if !found {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid departure window %q (expected HH:MM-HH:MM)", s)
+	}
+	var ends [2]time.Time
+	for idx, text := range []string{fromText, toText} {
+		clock, err := time.ParseInLocation("15:04", strings.TrimSpace(text), now.Location())
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid departure window %q (expected HH:MM-HH:MM)", s)
+		}
+		ends[idx] = time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(),
+			0, 0, now.Location())
+	}
+	if ends[1].Before(ends[0]) {
+		ends[1] = ends[1].AddDate(0, 0, 1)
+	}
+	return ends[0], ends[1], nil
+}
+
+// Plan the fastest trip between the specified stations setting off at each
+// of the times from the start of the specified window to its end, the
+// specified interval apart, with the Planner's other options. A departure
+// with no route, e.g. before the first trains, has a nil Route rather than
+// failing the rest. The Planner's departure time is ignored.
+func (p *Planner) SampleDepartures(start, dest string, from, to time.Time,
+	every time.Duration) ([]DepartureSample, error) {
+	if every <= 0 {
+		return nil, errors.New("the interval between departures must be greater than zero")
+	}
+	if to.Before(from) {
+		return nil, errors.New("the departure window ends before it starts")
+	}
+	if count := to.Sub(from)/every + 1; count > MaxDepartureSamples {
+		return nil, fmt.Errorf("the departure window holds %d departures, more than the %d allowed",
+			count, MaxDepartureSamples)
+	}
+	graph := p.graph.Load()
+	for _, station := range []string{start, dest} {
+		if !graph.HasStation(station) {
+			return nil, &UnknownStationError{station}
+		}
+	}
+	graph.mu.Lock()
+	defer graph.mu.Unlock()
+	began := time.Now()
+	planOpts := p.Options
+	samples := make([]DepartureSample, 0)
+	for departAt := from; !departAt.After(to); departAt = departAt.Add(every) {
+		planOpts.DepartAt = departAt
+		npq := ResetGraph(graph.nodeMap)
+		journey, err := RunShortestPaths(&npq, graph.nodeMap, start, dest, &planOpts)
+		if err != nil && !errors.Is(err, ErrNoRoute) {
+			p.logSearch(began, err, "sampled departures", "start", start, "destination", dest)
+			return nil, err
+		}
+		sample := DepartureSample{DepartAt: departAt}
+		if err == nil {
+			sample.Route = &Route{journey}
+		}
+		samples = append(samples, sample)
+	}
+	p.logSearch(began, nil, "sampled departures", "start", start, "destination", dest,
+		"departures", len(samples))
+	return samples, nil
+}
+
+// Return the way the specified journey goes as the lines ridden and where
+// each is left, e.g. "Northern to Bank, Central to Epping", which tells
+// apart trips changing between the same lines at different stations
+func JourneyRouteSummary(journey *Journey) string {
+	parts := make([]string, 0)
+	for idx, leg := range journey.Legs {
+		switch {
+		case leg.Type == string(ModeRail):
+			// Consecutive legs on the same line are one ride
+			if idx+1 < len(journey.Legs) && journey.Legs[idx+1].Type == string(ModeRail) &&
+				journey.Legs[idx+1].Line == leg.Line {
+				continue
+			}
+			parts = append(parts, fmt.Sprintf("%s to %s", leg.Line, leg.To))
+		case leg.Type == string(ModeStationInterchange):
+			parts = append(parts, "walk to "+leg.To)
+		}
+	}
+	if len(parts) == 0 {
+		return "already at destination"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Print the trip planned for each of the specified departures, giving the
+// way it goes whenever that changes from the departure before, followed by
+// the departures with the quickest and slowest trips and how often the
+// route changed over the window
+func PrintDepartureSamples(samples []DepartureSample) {
+	var quickest, slowest *DepartureSample
+	var previous string
+	changes, missing := 0, 0
+	fmt.Println("Departures:")
+	for idx := range samples {
+		sample := &samples[idx]
+		if sample.Route == nil {
+			fmt.Printf("- %s: no route available\n", sample.DepartAt.Format("15:04"))
+			missing++
+			continue
+		}
+		minutes := sample.Route.TotalMinutes()
+		arrival := sample.DepartAt.Add(time.Duration(minutes) * time.Minute)
+		summary := JourneyRouteSummary(sample.Route.journey)
+		line := fmt.Sprintf("- %s: %d minutes, arriving %s", sample.DepartAt.Format("15:04"), minutes,
+			arrival.Format("15:04"))
+		if summary != previous {
+			line += ", via " + summary
+			if previous != "" {
+				changes++
+			}
+			previous = summary
+		}
+		fmt.Println(line)
+		if quickest == nil || minutes < quickest.Route.TotalMinutes() {
+			quickest = sample
+		}
+		if slowest == nil || minutes > slowest.Route.TotalMinutes() {
+			slowest = sample
+		}
+	}
+	if quickest == nil {
+		fmt.Println("No route is available at any of these departures.")
+		return
+	}
+	if quickest.Route.TotalMinutes() == slowest.Route.TotalMinutes() && missing > 0 {
+		fmt.Printf("Every departure with a route takes %d minutes.\n", quickest.Route.TotalMinutes())
+	} else if quickest.Route.TotalMinutes() == slowest.Route.TotalMinutes() {
+		fmt.Printf("Every departure takes %d minutes.\n", quickest.Route.TotalMinutes())
+	} else {
+		fmt.Printf("Quickest: leave at %s (%d minutes). Slowest: leave at %s (%d minutes).\n",
+			quickest.DepartAt.Format("15:04"), quickest.Route.TotalMinutes(),
+			slowest.DepartAt.Format("15:04"), slowest.Route.TotalMinutes())
+	}
+	switch changes {
+	case 0:
+		fmt.Println("The route is the same throughout.")
+	case 1:
+		fmt.Println("The route changes once over the window.")
+	default:
+		fmt.Printf("The route changes %d times over the window.\n", changes)
+	}
+}
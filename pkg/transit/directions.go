@@ -0,0 +1,82 @@
+package transit
+
+import (
+	"fmt"
+)
+
+// From the specified journey, print a clear, readable series of directions
+// for the user to follow to complete their trip, detailed with walking
+// guidance for interchanges if requested
+func PrintDirections(journey *Journey, detailed bool) {
+	for _, line := range DirectionLines(journey, false, detailed) {
+		fmt.Println(line)
+	}
+}
+
+// Return the directions printed by PrintDirections as individual lines of
+// text, optionally in a terser compact form suited to narrow displays. In
+// detailed directions, each interchange with walking guidance in the transit
+// data is followed by an indented line giving it, and each ride by one
+// describing the line and its trains (see LineDetailsText). Rides needing a
+// boarding ramp are preceded by a step asking staff for one. Times are
+// countdowns if RelativeDirections is set, and line names are in colour if
+// ColourDirections is.
+func DirectionLines(journey *Journey, compact, detailed bool) []string {
+	var phrases DirectionPhrases = StandardPhrases{}
+	if compact {
+		phrases = CompactPhrases{}
+	}
+	if RelativeDirections {
+		phrases = RelativePhrases{Lead: RelativeLead}
+	}
+	if ColourDirections {
+		phrases = ColourPhrases{phrases}
+	}
+	return RenderDirections(journey, phrases, detailed)
+}
+
+// Return the directions for the specified journey as individual lines of
+// text, worded by the specified phrases. Only the legs' own fields are used,
+// so decoded journeys can be rendered just as planned ones can.
+func RenderDirections(journey *Journey, phrases DirectionPhrases, detailed bool) []string {
+	if len(journey.Legs) == 0 {
+		return []string{phrases.AlreadyThere()}
+	}
+	lines := []string{phrases.Begin(journey.From)}
+	addGuidance := func(guidance string) {
+		if detailed && guidance != "" {
+			lines = append(lines, phrases.Guidance(guidance))
+		}
+	}
+	step := 2
+	for _, leg := range journey.Legs {
+		switch leg.Type {
+		case LegBoard, LegAlight:
+			continue
+		case string(ModeRail):
+			if leg.BoardingRamp || leg.AlightingRamp {
+				alightAt := ""
+				if leg.AlightingRamp {
+					alightAt = leg.To
+				}
+				lines = append(lines, phrases.RequestRamp(step, leg.From, leg.Line, leg.BoardingRamp, alightAt))
+				step++
+			}
+			lines = append(lines, phrases.Board(step, leg.Line))
+			addGuidance(leg.LineDetails)
+			for _, stop := range leg.Stops {
+				lines = append(lines, phrases.Stop(stop.Station, stop.Time, stop.Closed))
+			}
+		case string(ModeLineInterchange):
+			lines = append(lines, phrases.ChangeLines(step, leg.To, leg.ToLine, leg.Arrive))
+			addGuidance(leg.Guidance)
+		case string(ModeStationInterchange):
+			lines = append(lines, phrases.Walk(step, leg.From, leg.To, leg.Arrive))
+			addGuidance(leg.Guidance)
+		default:
+			panic("invalid journey leg type: " + leg.Type)
+		}
+		step++
+	}
+	return append(lines, phrases.Arrive(step, journey.To, journey.TotalMinutes))
+}
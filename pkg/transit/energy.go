@@ -0,0 +1,41 @@
+package transit
+
+import (
+	"math"
+	"slices"
+)
+
+// Extra search cost, as a share of the run time, of travelling on a
+// deep-level line when minimizing energy
+const deepLevelWeight = 0.5
+
+// Search cost in minutes charged for every train boarded when minimizing
+// energy, for the energy spent accelerating a train away from each stop a
+// passenger adds to their trip
+const boardingEnergyPenalty = 5
+
+// Return the search cost added for travelling along the specified rail link
+// on a deep-level line when the options minimize energy
+func (opts *SearchOptions) energyPenalty(link *Link, runTime uint16) uint16 {
+	if opts == nil || opts.Optimize != OptimizeEnergy || link.attrs.Mode != ModeRail ||
+		!slices.Contains(GetDeepLevelLines(), link.endNode.line) {
+		return 0
+	}
+	return uint16(min(math.Round(float64(runTime)*deepLevelWeight), MaxTravelTime))
+}
+
+// Return the minutes spent on deep-level lines along the specified journey,
+// and the number of trains boarded, the measures weighed when minimizing
+// energy
+func RouteEnergy(journey *Journey) (deepMinutes uint16, boardings int) {
+	deepLines := GetDeepLevelLines()
+	for _, leg := range journey.Legs {
+		switch {
+		case leg.Type == LegBoard || leg.IsInterchange():
+			boardings++
+		case leg.IsRide() && slices.Contains(deepLines, leg.Line):
+			deepMinutes += leg.Minutes
+		}
+	}
+	return deepMinutes, boardings
+}
@@ -0,0 +1,130 @@
+package transit
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// Names of the facilities accepted by --need, as listed to users
+var facilityNames = map[Facility]string{
+	FacilityToilet:     "toilets",
+	FacilityBabyChange: "baby changing",
+}
+
+// Parse the name of a facility (e.g. "toilet")
+func ParseFacility(s string) (Facility, error) {
+	if _, exists := facilityNames[Facility(s)]; !exists {
+		return "", fmt.Errorf("unknown facility %q, expected toilet or baby-change", s)
+	}
+	return Facility(s), nil
+}
+
+// Return where in each station the specified facility is found, for the
+// stations known to provide it
+func FacilityLocations(facility Facility) map[string]string {
+	locations := make(map[string]string)
+	for _, sf := range GetStationFacilities() {
+		if sf.facility == facility {
+			locations[sf.station] = sf.location
+		}
+	}
+	return locations
+}
+
+// Return the stations along the specified planned journey at which the
+// passenger can get off to use the specified facility, in the order they are
+// reached: the start, every station the trains call at, and the destination
+func FacilityStops(journey *Journey, facility Facility) []string {
+	locations := FacilityLocations(facility)
+	stops := make([]string, 0)
+	for _, node := range journey.nodes() {
+		if _, provided := locations[node.station]; provided && !node.closed &&
+			!slices.Contains(stops, node.station) {
+			stops = append(stops, node.station)
+		}
+	}
+	return slices.Clip(stops)
+}
+
+// Plan the fastest trip between the specified stations calling at a station
+// with the specified facility. The fastest trip overall is kept if it calls
+// at one already; otherwise the trip is planned via each station with the
+// facility in turn, and the fastest of those kept. Any search options (which
+// may be nil) apply throughout.
+func PlanFacilityRoute(nodeMap NodeMap, start, dest string, facility Facility,
+	opts *SearchOptions) (*Journey, error) {
+	npq := ResetGraph(nodeMap)
+	journey, err := RunShortestPaths(&npq, nodeMap, start, dest, opts)
+	if err != nil || len(journey.Legs) == 0 || len(FacilityStops(journey, facility)) > 0 {
+		return journey, err
+	}
+
+	var viaOpts SearchOptions
+	if opts != nil {
+		viaOpts = *opts
+	}
+	starts := startCosts(nodeMap, start, &viaOpts)
+	isDest := map[string]bool{dest: true}
+	var best *candidatePath
+	for _, via := range slices.Sorted(maps.Keys(FacilityLocations(facility))) {
+		if _, exists := nodeMap[via]; !exists || viaOpts.ClosedStations[via] || viaOpts.AvoidStations[via] {
+			continue
+		}
+		npq := ResetGraph(nodeMap)
+		toNodes, toLinks, err := shortestPath(&npq, starts, map[string]bool{via: true}, &viaOpts)
+		if errors.Is(err, ErrDeadlineExceeded) {
+			return nil, err
+		} else if err != nil || toNodes[len(toNodes)-1].closed {
+			continue
+		}
+		// Carry on from the via station without looping back through the
+		// way there
+		arrival := toNodes[len(toNodes)-1]
+		at := starts[toNodes[0]]
+		for idx, link := range toLinks {
+			at = viaOpts.linkCost(toNodes[idx], link, at)
+		}
+		viaOpts.excludedNodes = make(map[*Node]bool)
+		for _, node := range toNodes[:len(toNodes)-1] {
+			viaOpts.excludedNodes[node] = true
+		}
+		npq = ResetGraph(nodeMap)
+		onNodes, onLinks, err := shortestPath(&npq, map[*Node]progress{arrival: at}, isDest, &viaOpts)
+		viaOpts.excludedNodes = nil
+		if errors.Is(err, ErrDeadlineExceeded) {
+			return nil, err
+		} else if err != nil {
+			continue
+		}
+		path := candidatePath{nodes: slices.Concat(toNodes[:len(toNodes)-1], onNodes),
+			links: slices.Concat(toLinks, onLinks)}
+		path.cost = pathCost(starts, path.nodes, path.links, &viaOpts)
+		if best == nil || path.cost < best.cost {
+			best = &path
+		}
+	}
+	if best == nil {
+		return nil, ErrNoRoute
+	}
+	return newJourney(best.nodes, best.links, &viaOpts), nil
+}
+
+// Print where the passenger can find the specified facility along the
+// specified planned journey
+func PrintFacilityNotes(journey *Journey, facility Facility) {
+	if len(journey.Legs) == 0 {
+		return
+	}
+	locations := FacilityLocations(facility)
+	stops := FacilityStops(journey, facility)
+	if len(stops) == 0 {
+		fmt.Printf("Note: no %s are known to be provided along this journey.\n", facilityNames[facility])
+		return
+	}
+	fmt.Printf("Stations with %s on this journey:\n", facilityNames[facility])
+	for _, station := range stops {
+		fmt.Printf("- %s: %s\n", station, locations[station])
+	}
+}
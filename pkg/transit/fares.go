@@ -0,0 +1,183 @@
+package transit
+
+import (
+	"fmt"
+	"maps"
+	"time"
+)
+
+// Flat pay as you go fare for a tram journey, in pence, charged on top of
+// any zonal fare for the rest of the trip
+const tramFare = 175
+
+// Name of the line whose stops have flat fares rather than zones
+const tramLine = "Tramlink"
+
+// Represents the estimated pay as you go fare for a journey, in pence, along
+// with whether the peak fare applies and the range of zones it is charged
+// for (zero if the journey is only by tram)
+type Fare struct {
+	Pence    uint16 `json:"pence"`
+	Peak     bool   `json:"peak"`
+	LowZone  uint8  `json:"lowZone,omitempty"`
+	HighZone uint8  `json:"highZone,omitempty"`
+	Tram     bool   `json:"tram,omitempty"`
+}
+
+// Return the fare as it is printed with the directions, e.g.
+// "£3.50 (peak, zones 1-2)"
+func (fare Fare) String() string {
+	band := OffPeakBand
+	if fare.Peak {
+		band = PeakBand
+	}
+	var charged string
+	switch {
+	case fare.LowZone == 0:
+		charged = "tram"
+	case fare.LowZone == fare.HighZone:
+		charged = fmt.Sprintf("zone %d", fare.LowZone)
+	default:
+		charged = fmt.Sprintf("zones %d-%d", fare.LowZone, fare.HighZone)
+	}
+	if fare.LowZone != 0 && fare.Tram {
+		charged += " plus tram"
+	}
+	return fmt.Sprintf("£%d.%02d (%s, %s)", fare.Pence/100, fare.Pence%100, band, charged)
+}
+
+// Return the pay as you go fare for a rail journey within the specified
+// range of zones
+func zoneFare(low, high uint8, peak bool) (uint16, error) {
+	for _, fare := range GetZoneFares() {
+		if (fare.viaZone1 && low == 1 && fare.zones == high) ||
+			(!fare.viaZone1 && low > 1 && fare.zones == high-low+1) {
+			if peak {
+				return fare.peak, nil
+			}
+			return fare.offPeak, nil
+		}
+	}
+	return 0, fmt.Errorf("no fare known for zones %d-%d", low, high)
+}
+
+// Return the estimated pay as you go fare for the specified planned journey
+// when setting off at the specified time. Rail journeys are charged for the
+// narrowest range of zones covering every station the journey passes
+// through, stations on a zone boundary counting in whichever zone is
+// cheaper, at the peak fare on weekday mornings and evenings (see TimeBand).
+// Any tram leg costs a flat fare on top. No fare can be estimated for a
+// journey through a station outside the zonal fares area.
+func EstimateFare(journey *Journey, departAt time.Time) (Fare, error) {
+	fare := Fare{Peak: TimeBand(departAt) == PeakBand}
+	var low, high uint8
+	for _, node := range journey.nodes() {
+		if node.line == tramLine {
+			fare.Tram = true
+			continue
+		}
+		if node.zone.low == 0 {
+			return Fare{}, fmt.Errorf("%s is outside the zonal fares area",
+				node.station)
+		}
+		// The zones charged for must reach back to the nearest end of each
+		// station's zones, and forward to the furthest start of any
+		if high == 0 {
+			low, high = node.zone.high, node.zone.low
+		}
+		low, high = min(low, node.zone.high), max(high, node.zone.low)
+	}
+	if high != 0 {
+		if low > high {
+			// Every station is in all the zones from high to low, and only
+			// zone 1 costs more than any other on its own
+			high = low
+		}
+		pence, err := zoneFare(low, high, fare.Peak)
+		if err != nil {
+			return Fare{}, err
+		}
+		fare.Pence, fare.LowZone, fare.HighZone = pence, low, high
+	}
+	if fare.Tram {
+		fare.Pence += tramFare
+	}
+	return fare, nil
+}
+
+// Print the estimated fare for the specified planned journey, if one can be
+// estimated
+func PrintFare(journey *Journey, departAt time.Time) {
+	if len(journey.Legs) == 0 {
+		return
+	}
+	if fare, err := EstimateFare(journey, departAt); err == nil {
+		fmt.Printf("Estimated fare: %s, pay as you go.\n", fare)
+	} else {
+		fmt.Printf("Estimated fare: unknown (%v).\n", err)
+	}
+}
+
+// Return whether a station may be passed through by a journey charged for
+// the specified range of zones
+func inZones(nodeMap NodeMap, station string, low, high uint8) bool {
+	for line, node := range nodeMap[station] {
+		if line != tramLine && (node.zone.low == 0 || node.zone.high < low || node.zone.low > high) {
+			return false
+		}
+	}
+	return true
+}
+
+// Plan the cheapest trip between the specified stations, breaking ties on
+// travel time, and return its estimated fare (see EstimateFare). The graph
+// is searched once for each range of zones the trip could be charged for,
+// avoiding every station outside it, and the cheapest of the routes found
+// wins. If no route has a fare that can be estimated, the fastest route is
+// returned with a zero Fare. Any other search options (which may be nil)
+// apply to every search.
+func PlanCheapestRoute(nodeMap NodeMap, start, dest string, opts *SearchOptions) (*Journey, Fare, error) {
+	zoneOpts := SearchOptions{}
+	if opts != nil {
+		zoneOpts = *opts
+	}
+	zoneOpts.Optimize = OptimizeTime
+
+	var best *Journey
+	var bestFare Fare
+	for low := uint8(1); low <= 9; low++ {
+		for high := low; high <= 9; high++ {
+			if !inZones(nodeMap, start, low, high) || !inZones(nodeMap, dest, low, high) {
+				continue
+			}
+			candidate := zoneOpts
+			candidate.AvoidStations = maps.Clone(zoneOpts.AvoidStations)
+			if candidate.AvoidStations == nil {
+				candidate.AvoidStations = make(map[string]bool)
+			}
+			for station := range nodeMap {
+				if !inZones(nodeMap, station, low, high) {
+					candidate.AvoidStations[station] = true
+				}
+			}
+			npq := ResetGraph(nodeMap)
+			journey, err := RunShortestPaths(&npq, nodeMap, start, dest, &candidate)
+			if err != nil || len(journey.Legs) == 0 {
+				continue
+			}
+			fare, err := EstimateFare(journey, zoneOpts.DepartAt)
+			if err == nil && (best == nil || fare.Pence < bestFare.Pence ||
+				(fare.Pence == bestFare.Pence && journey.TotalMinutes < best.TotalMinutes)) {
+				best, bestFare = journey, fare
+			}
+		}
+	}
+	if best == nil {
+		// No route stays within the zonal fares area, so plan the fastest
+		// route for the caller to report on as usual
+		npq := ResetGraph(nodeMap)
+		journey, err := RunShortestPaths(&npq, nodeMap, start, dest, &zoneOpts)
+		return journey, Fare{}, err
+	}
+	return best, bestFare, nil
+}
@@ -0,0 +1,107 @@
+package transit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Base URL of the public OpenStreetMap Nominatim service
+const NominatimURL = "https://nominatim.openstreetmap.org"
+
+// User-Agent sent to OpenStreetMap services, whose usage policies require
+// requests to identify the application
+const httpUserAgent = "TubePlanner (github.com/maxboyko1/TubePlanner)"
+
+// Least time between requests to a Nominatim service, as its usage policy
+// asks of clients
+const nominatimInterval = time.Second
+
+// Returned by a Geocoder when nothing matches the address it is given
+var ErrAddressNotFound = errors.New("address not found")
+
+// Represents a way of turning a postcode or address into coordinates, so
+// that places can be given by address and reached from their nearest
+// station. NominatimGeocoder is the default; other providers, or fixed
+// lookups for testing, can be substituted with a GeocoderFunc.
+type Geocoder interface {
+	Geocode(address string) (Coordinates, error)
+}
+
+// Adapts an ordinary function to the Geocoder interface
+type GeocoderFunc func(address string) (Coordinates, error)
+
+// Return the coordinates of the specified address by calling the function
+func (f GeocoderFunc) Geocode(address string) (Coordinates, error) {
+	return f(address)
+}
+
+// Return the Coordinates at the specified latitude and longitude in decimal
+// degrees, e.g. for Geocoders outside this package
+func NewCoordinates(lat, lon float64) Coordinates {
+	return Coordinates{lat, lon}
+}
+
+// Represents a Geocoder looking addresses up through a Nominatim service,
+// limited to Great Britain and to one request a second
+type NominatimGeocoder struct {
+	client  *http.Client
+	baseURL string
+	mu      sync.Mutex
+	last    time.Time
+}
+
+// Return a Geocoder using the Nominatim service at the specified base URL
+// (NominatimURL for the public one) through the specified client
+func NewNominatimGeocoder(client *http.Client, baseURL string) *NominatimGeocoder {
+	return &NominatimGeocoder{client: client, baseURL: baseURL}
+}
+
+// Look the specified postcode or address up, returning the coordinates of
+// the best match, or ErrAddressNotFound if there is none
+func (g *NominatimGeocoder) Geocode(address string) (Coordinates, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if wait := nominatimInterval - time.Since(g.last); wait > 0 {
+		time.Sleep(wait)
+	}
+	defer func() { g.last = time.Now() }()
+
+	query := url.Values{"q": {address}, "format": {"jsonv2"}, "limit": {"1"}, "countrycodes": {"gb"}}
+	req, err := http.NewRequest(http.MethodGet, g.baseURL+"/search?"+query.Encode(), nil)
+	if err != nil {
+		return Coordinates{}, err
+	}
+	// Nominatim refuses requests which do not identify the application
+	req.Header.Set("User-Agent", httpUserAgent)
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return Coordinates{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Coordinates{}, fmt.Errorf("Nominatim returned %s", resp.Status)
+	}
+	var matches []struct {
+		Lat string `json:"lat"`
+		Lon string `json:"lon"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&matches); err != nil {
+		return Coordinates{}, err
+	}
+	if len(matches) == 0 {
+		return Coordinates{}, ErrAddressNotFound
+	}
+	lat, latErr := strconv.ParseFloat(matches[0].Lat, 64)
+	lon, lonErr := strconv.ParseFloat(matches[0].Lon, 64)
+	if latErr != nil || lonErr != nil {
+		return Coordinates{}, fmt.Errorf("Nominatim returned invalid coordinates %q, %q",
+			matches[0].Lat, matches[0].Lon)
+	}
+	return Coordinates{lat, lon}, nil
+}
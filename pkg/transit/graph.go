@@ -0,0 +1,544 @@
+// Package transit builds a graph of the London commuter transit network and
+// plans the fastest trips across it. Programs embedding the planner start
+// from LoadGraph and NewPlanner; the lower-level graph and search functions
+// used by the tubeplanner command are exported too.
+package transit
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+)
+
+// Represents an "edge" in the transit graph, either a rail link or an
+// interchange, with its usual time and any further attributes
+type Link struct {
+	endNode *Node
+	time    uint16
+	attrs   LinkAttributes
+}
+
+// Represents the kind of connection a Link makes. The values double as the
+// link types returned alongside routes by RunShortestPaths.
+type LinkMode string
+
+const (
+	ModeRail               LinkMode = "rail"
+	ModeLineInterchange    LinkMode = "line interchange"
+	ModeStationInterchange LinkMode = "station interchange"
+)
+
+// Represents the attributes of a Link beyond where it leads and how long it
+// usually takes. Zero values mean the attribute is unknown or does not apply,
+// so new attributes can be added here without changing how links are built
+// or searched elsewhere.
+type LinkAttributes struct {
+	Mode LinkMode
+	// Whether the time is assumed in the absence of transit data
+	Assumed bool
+	// Run times in particular time bands, overriding the usual time then
+	BandTimes map[string]uint16
+	// Whether the link is known to be usable without steps, and the lifts
+	// that depends on. An interchange stops being step-free while any of
+	// its lifts is out of service.
+	StepFree bool
+	Lifts    []Lift
+	// How crowded the link usually is, from 1 (quiet) to 5 (very busy)
+	Crowding uint8
+	// Period outside of which the link may not be used, e.g. while it is
+	// being built or after a service is withdrawn; either end may be zero
+	ValidFrom  time.Time
+	ValidUntil time.Time
+	// Whether the link crosses from one fare zone into another
+	CrossesZoneBoundary bool
+	// Minutes trains stand at the station the link leaves before setting
+	// off along it, beyond the usual stop already part of its time
+	Dwell uint16
+	// Least minutes an interchange between lines takes, from arriving on
+	// one to being able to board the other, or zero if not known (see
+	// ApplyConnectionTimes)
+	MinConnection uint16
+}
+
+// Identifies a lift at a station by its name there, e.g. "Jubilee lift"
+type Lift struct {
+	Station string
+	Name    string
+}
+
+// Return whether the link may be used at the specified time, according to
+// its validity window
+func (attrs LinkAttributes) ValidAt(t time.Time) bool {
+	return (attrs.ValidFrom.IsZero() || !t.Before(attrs.ValidFrom)) &&
+		(attrs.ValidUntil.IsZero() || t.Before(attrs.ValidUntil))
+}
+
+// Return the Node the link leads to
+func (link *Link) EndNode() *Node {
+	return link.endNode
+}
+
+// Return the usual time taken to traverse the link in minutes
+func (link *Link) Time() uint16 {
+	return link.time
+}
+
+// Return the attributes of the link
+func (link *Link) Attributes() LinkAttributes {
+	return link.attrs
+}
+
+// Represents a "vertex" in the transit graph, with each existing combination
+// of station name and line name being its own vertex
+type Node struct {
+	station   string
+	line      string
+	adj       []*Link
+	totalTime uint16
+	index     int
+	// Interchanges made on the way to the Node by the best path the current
+	// search has found, for choosing between paths of equal cost
+	changes uint16
+	// Minutes actually taken to reach the Node by that path, which unlike
+	// its search cost leave out any penalties, for telling the time of day
+	elapsed uint16
+	// Whether the station has been closed, and the override closing it
+	closed  bool
+	closure *StationOverride
+	// Extra wait for a train, and walking time between the gates and the
+	// platforms when starting or ending a trip here, in minutes
+	boardTime  uint16
+	accessTime uint16
+	// Operating hours and frequency of the line, if known
+	service *LineService
+	// Fare zone of the station, zero if it has none
+	zone FareZone
+	// Whether the platforms can be reached from the street without steps,
+	// the extra minutes the lifts take if so, the lifts that depends on, and
+	// the outages of them in effect when the graph was built
+	stepFree bool
+	liftTime uint16
+	lifts    []Lift
+	outages  []LiftOutage
+	// Whether getting on or off the line's trains here needs a manual
+	// boarding ramp
+	ramp bool
+	// Greatest speeds of the links of the Node's part of the graph, shared
+	// by its every Node once an A* search has worked them out
+	speeds *linkSpeeds
+}
+
+// Return the name of the station the Node represents
+func (node *Node) Station() string {
+	return node.station
+}
+
+// Return the name of the line the Node represents
+func (node *Node) Line() string {
+	return node.line
+}
+
+// Return the links leaving the Node
+func (node *Node) Links() []*Link {
+	return node.adj
+}
+
+// Return the travel time in minutes to reach the Node found by the latest
+// search of the graph
+func (node *Node) TotalTime() uint16 {
+	return node.totalTime
+}
+
+// Returned when no route exists between the requested stations, e.g. because
+// the only lines serving them are closed
+var ErrNoRoute = errors.New("no route available")
+
+// Returned when a search is abandoned for running past its deadline
+var ErrDeadlineExceeded = errors.New("search deadline exceeded")
+
+// Longest travel time a reachable node can be given, one minute short of the
+// math.MaxUint16 that marks a node as not yet reached
+const MaxTravelTime = math.MaxUint16 - 1
+
+// Add two travel times, saturating at MaxTravelTime rather than wrapping
+// around, so an implausibly long path can never appear shorter than it really
+// is yet is still found. A time of math.MaxUint16 ("not yet reached") stays
+// unreached whatever is added to it
+func AddTime(a, b uint16) uint16 {
+	if a == math.MaxUint16 || b == math.MaxUint16 {
+		return math.MaxUint16
+	}
+	if a > MaxTravelTime-b {
+		return MaxTravelTime
+	}
+	return a + b
+}
+
+// Map of each station and line name combination to its corresponding Node
+// pointer in the graph
+type NodeMap map[string]map[string]*Node
+
+// List of all nodes in the graph, min heap-ordered according to the shortest
+// time taken to arrive there from user's chosen starting point (all necessary
+// Go heap interface methods are implemented below)
+type NodePriorityQueue []*Node
+
+// Return number of nodes in the heap
+func (npq NodePriorityQueue) Len() int {
+	return len(npq)
+}
+
+// Return whether the total travel time to Node at index i is less than the
+// total travel time to Node at index j, breaking ties by the interchanges
+// made on the way to each and then by station and line, so that Nodes are
+// always visited in the same order
+func (npq NodePriorityQueue) Less(i, j int) bool {
+	a, b := npq[i], npq[j]
+	if a.totalTime != b.totalTime {
+		return a.totalTime < b.totalTime
+	}
+	if a.changes != b.changes {
+		return a.changes < b.changes
+	}
+	return compareNodes(a, b) < 0
+}
+
+// Swap positions of Nodes at indices i and j in the heap
+func (npq NodePriorityQueue) Swap(i, j int) {
+	npq[i], npq[j] = npq[j], npq[i]
+	npq[i].index = i
+	npq[j].index = j
+}
+
+// Add a new Node to the end of the heap
+func (npq *NodePriorityQueue) Push(x any) {
+	n := len(*npq)
+	node := x.(*Node)
+	node.index = n
+	*npq = append(*npq, node)
+}
+
+// Remove the minimum priority Node from the heap and return it
+func (npq *NodePriorityQueue) Pop() any {
+	old := *npq
+	n := len(old)
+	node := old[n-1]
+	old[n-1] = nil
+	node.index = -1
+	*npq = old[0 : n-1]
+	return node
+}
+
+// Update the specified node with a new total travel time, then restore the
+// heap ordering
+func (npq *NodePriorityQueue) update(node *Node, newTotalTime uint16) {
+	node.totalTime = newTotalTime
+	heap.Fix(npq, node.index)
+}
+
+// Helper function for BuildTransitGraph() which adds a connection between two
+// Nodes with the specified transit time and link attributes to the graph
+func AddConnection(npq *NodePriorityQueue, nodeMap NodeMap, connection any, attrs LinkAttributes) {
+	// Retrieve station/line names and transit time for the specified connection
+	var stationA, lineA, stationB, lineB string
+	var transitTime uint16
+	switch conn := connection.(type) {
+	case *RailLink:
+		stationA, stationB = conn.fromStation, conn.toStation
+		lineA, lineB = conn.line, conn.line
+		transitTime = conn.transitTime
+	case *Interchange:
+		stationA, stationB = conn.fromStation, conn.toStation
+		lineA, lineB = conn.fromLine, conn.toLine
+		transitTime = conn.transitTime
+	default:
+		panic(fmt.Sprintf("connection type must be *RailLink or *Interchange, not %T", connection))
+	}
+
+	// Create a graph Node for the first station/line if it does not exist already,
+	// with travel distance initialized to infinity
+	var nodeAExists bool = false
+	_, mapAExists := nodeMap[stationA]
+	if mapAExists {
+		_, nodeAExists = nodeMap[stationA][lineA]
+	} else {
+		nodeMap[stationA] = make(map[string]*Node)
+	}
+	if !nodeAExists {
+		newNode := &Node{stationA, lineA, make([]*Link, 0), math.MaxUint16, 0, 0, 0, false, nil, 0, 0, nil, FareZone{}, false, 0, nil, nil, false, nil}
+		npq.Push(newNode)
+		nodeMap[stationA][lineA] = newNode
+	}
+
+	// Create a graph Node for the second station/line, if it does not exist already,
+	// with travel distance initialized to infinity
+	var nodeBExists bool = false
+	_, mapBExists := nodeMap[stationB]
+	if mapBExists {
+		_, nodeBExists = nodeMap[stationB][lineB]
+	} else {
+		nodeMap[stationB] = make(map[string]*Node)
+	}
+	if !nodeBExists {
+		newNode := &Node{stationB, lineB, make([]*Link, 0), math.MaxUint16, 0, 0, 0, false, nil, 0, 0, nil, FareZone{}, false, 0, nil, nil, false, nil}
+		npq.Push(newNode)
+		nodeMap[stationB][lineB] = newNode
+	}
+
+	// Add a link to node B to node A's adjacency list, and vice versa
+	nodeA, nodeB := nodeMap[stationA][lineA], nodeMap[stationB][lineB]
+	nodeA.adj = append(nodeA.adj, &Link{nodeB, transitTime, attrs})
+	nodeB.adj = append(nodeB.adj, &Link{nodeA, transitTime, attrs})
+}
+
+// Settings for building a transit graph with BuildTransitGraph or LoadGraph.
+// The zero value builds the graph from the built-in data alone, with no
+// walks between nearby stations, and reads and writes no files; start from
+// DefaultGraphOptions for the planner's usual walks.
+type GraphOptions struct {
+	// Network to build the graph from, or nil for the built-in data
+	Source DataSource
+	// File caching the graph built from the source between runs, rebuilt
+	// whenever the data changes. Empty means the graph is always built
+	// afresh. Only the sources of this package, whose files can be checked
+	// for changes, are cached.
+	CachePath string
+	// Files of station overrides, lift outages and the user's own link times
+	// to apply on top of the data (see LoadStationOverrides, LoadLiftOutages
+	// and LoadLinkTimes). Empty means none.
+	StationOverridesPath string
+	LiftOutagesPath      string
+	LinkTimesPath        string
+	// Distance in metres within which stations with known coordinates are
+	// joined by walks wherever the data has no interchange between them.
+	// Zero means no walks are added.
+	WalkRadiusMetres float64
+	// Minutes assumed for changing between two lines at a station where the
+	// data has no interchange time for them. Zero assumes nothing, leaving
+	// trips unable to change lines there; ValidateGraph reports every such
+	// gap, and building fails with ErrNoInterchanges if the data has none
+	// at all.
+	AssumedInterchangeMinutes uint16
+	// Kind of day whose services the graph is filtered to, so that only the
+	// lines running then are routed over. Zero keeps every line, with its
+	// usual daytime service.
+	ServiceProfile DayType
+	// Whether to keep each station in the graph (see PruneGraph), or nil to
+	// keep them all
+	Area func(station string) bool
+	// Logger told how the graph was built, e.g. whether the graph cache was
+	// used, and which Planners searching a Graph log to unless given their
+	// own. Nil logs nothing.
+	Logger *slog.Logger
+}
+
+// Return the options the tubeplanner command builds the graph with before
+// any of its flags are applied: the built-in data, with stations within 400
+// metres of each other joined by walks, and no files read or written
+func DefaultGraphOptions() GraphOptions {
+	return GraphOptions{WalkRadiusMetres: 400}
+}
+
+// Build the transit graph as the specified options say: add each rail link
+// and interchange of the data as a connection in the graph, along with walks
+// between nearby stations and any assumed interchanges, then apply any
+// station overrides and lift outages currently in effect and the user's own
+// link times, filter it to the service profile and prune it to the area.
+// The graph is read from the cache instead when that was built from the same
+// data.
+func BuildTransitGraph(opts GraphOptions) (NodePriorityQueue, NodeMap, error) {
+	logger := loggerOrDiscard(opts.Logger)
+	var nodeMap NodeMap
+	var err error
+	if opts.CachePath != "" {
+		nodeMap, err = cachedBaseGraph(opts, logger)
+	} else {
+		nodeMap, err = buildBaseGraph(opts)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	now := time.Now()
+	if opts.StationOverridesPath != "" {
+		overrides, err := LoadStationOverrides(opts.StationOverridesPath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid station overrides: %v", err)
+		}
+		for _, station := range ApplyStationOverrides(nodeMap, overrides, now) {
+			logger.Warn("ignored station override of unknown station", "path", opts.StationOverridesPath,
+				"station", station)
+		}
+		if len(overrides) > 0 {
+			logger.Debug("applied station overrides", "path", opts.StationOverridesPath,
+				"overrides", len(overrides))
+		}
+	}
+	if opts.LiftOutagesPath != "" {
+		outages, err := LoadLiftOutages(opts.LiftOutagesPath)
+		if err == nil {
+			err = ApplyLiftOutages(nodeMap, outages, now)
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid lift outages: %v", err)
+		}
+		if len(outages) > 0 {
+			logger.Debug("applied lift outages", "path", opts.LiftOutagesPath, "outages", len(outages))
+		}
+	}
+	if opts.LinkTimesPath != "" {
+		linkTimes, err := LoadLinkTimes(opts.LinkTimesPath)
+		if err == nil {
+			err = ApplyLinkTimes(nodeMap, linkTimes)
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid link times: %v", err)
+		}
+		if len(linkTimes) > 0 {
+			logger.Debug("applied link times", "path", opts.LinkTimesPath, "overrides", len(linkTimes))
+		}
+	}
+	if opts.ServiceProfile != 0 {
+		removed := ApplyServiceProfile(nodeMap, opts.ServiceProfile, GetServiceCalendar(), GetNightServices())
+		logger.Debug("applied service profile", "profile", opts.ServiceProfile, "removed", removed)
+	}
+	if opts.Area != nil {
+		PruneGraph(nodeMap, opts.Area)
+	}
+	return ResetGraph(nodeMap), nodeMap, nil
+}
+
+// Return the DataSource the specified options build the graph from
+func graphSource(opts GraphOptions) DataSource {
+	if opts.Source == nil {
+		return BuiltinDataSource()
+	}
+	return opts.Source
+}
+
+// Build the transit graph from the source the specified options give,
+// before any station overrides or pruning, which depend on when and how the
+// graph is used
+func buildBaseGraph(opts GraphOptions) (NodeMap, error) {
+	return buildSourceGraph(graphSource(opts), opts)
+}
+
+// Build the transit graph from the network the specified DataSource
+// supplies, as BuildDatasetGraph does, but joining stations by walks
+// according to the coordinates the source gives and adding interchanges as
+// the specified options say
+func buildSourceGraph(source DataSource, opts GraphOptions) (NodeMap, error) {
+	railLinks, err := source.GetRailLinks()
+	var interchanges []Interchange
+	if err == nil {
+		interchanges, err = source.GetInterchanges()
+	}
+	var stations []Station
+	if err == nil {
+		stations, err = source.GetStations()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid dataset: %v", err)
+	}
+	coords := make(map[string]Coordinates)
+	for _, station := range stations {
+		if station.Location != nil {
+			coords[station.Name] = *station.Location
+		}
+	}
+	return buildDatasetGraph(railLinks, interchanges, coords, opts)
+}
+
+// Build a transit graph from the specified rail links and interchanges, e.g.
+// as read by ParseYAMLDataset, adding the walks of DefaultGraphOptions and
+// the built-in zones, run times, service times and step-free access of the
+// stations and lines they name, just as for the transit data in use. Unlike
+// BuildTransitGraph, this neither reads nor writes the graph cache, and no
+// station overrides, lift outages or pruning are applied.
+func BuildDatasetGraph(railLinks []RailLink, interchanges []Interchange) (NodeMap, error) {
+	return buildDatasetGraph(railLinks, interchanges, GetStationCoordinates(), DefaultGraphOptions())
+}
+
+// Build a transit graph as BuildDatasetGraph does, joining stations by walks
+// according to the specified coordinates and adding walks and assumed
+// interchanges as the specified options say
+func buildDatasetGraph(railLinks []RailLink, interchanges []Interchange,
+	coords map[string]Coordinates, opts GraphOptions) (NodeMap, error) {
+	npq, nodeMap := make(NodePriorityQueue, 0), make(NodeMap)
+
+	for _, rl := range railLinks {
+		AddConnection(&npq, nodeMap, &rl, LinkAttributes{Mode: ModeRail})
+	}
+	for _, ic := range interchanges {
+		if ic.fromStation == ic.toStation {
+			AddConnection(&npq, nodeMap, &ic, LinkAttributes{Mode: ModeLineInterchange})
+		} else {
+			AddConnection(&npq, nodeMap, &ic, LinkAttributes{Mode: ModeStationInterchange})
+		}
+	}
+	AddWalkingInterchanges(&npq, nodeMap, coords, opts.WalkRadiusMetres)
+	AddAssumedInterchanges(&npq, nodeMap, opts.AssumedInterchangeMinutes)
+	if err := checkLineChanges(nodeMap); err != nil {
+		return nil, err
+	}
+	MarkZoneBoundaries(nodeMap, GetStationZones())
+	if err := ApplyBandedRunTimes(nodeMap, GetBandedRunTimes()); err != nil {
+		return nil, fmt.Errorf("invalid banded run times: %v", err)
+	}
+	ApplyDwellTimes(nodeMap, GetStationDwellTimes())
+	ApplyConnectionTimes(nodeMap, GetMinimumConnectionTimes())
+	ApplyServiceTimes(nodeMap, GetLineWaits(), GetPlatformAccessTimes())
+	ApplyLineServices(nodeMap, GetLineServices())
+	ApplyStepFreeAccess(nodeMap, GetStepFreeAccess(), GetStepFreeLines(), GetLiftDependencies())
+	ApplyBoardingRamps(nodeMap, GetBoardingRamps())
+	return nodeMap, nil
+}
+
+// Return a fresh priority queue holding every Node in the graph, with travel
+// times reset to infinity, so that the graph can be searched again after a
+// previous run of the shortest paths algorithm has consumed its queue
+func ResetGraph(nodeMap NodeMap) NodePriorityQueue {
+	npq := make(NodePriorityQueue, 0)
+	for _, lines := range nodeMap {
+		for _, node := range lines {
+			node.totalTime, node.elapsed, node.changes = math.MaxUint16, 0, 0
+			npq.Push(node)
+		}
+	}
+	return npq
+}
+
+// Return a copy of the graph which can be searched independently of the
+// original, e.g. concurrently with it. Link attributes and line services are
+// shared with the original, and must be left unchanged.
+func CloneGraph(nodeMap NodeMap) NodeMap {
+	clones := make(map[*Node]*Node)
+	clone := make(NodeMap, len(nodeMap))
+	for station, lines := range nodeMap {
+		clone[station] = make(map[string]*Node, len(lines))
+		for line, node := range lines {
+			copied := *node
+			clones[node] = &copied
+			clone[station][line] = &copied
+		}
+	}
+	for node, copied := range clones {
+		copied.adj = make([]*Link, len(node.adj))
+		for idx, link := range node.adj {
+			copied.adj[idx] = &Link{clones[link.endNode], link.time, link.attrs}
+		}
+	}
+	return clone
+}
+
+// Return whether any station in the graph is served by the specified line
+func LineExists(nodeMap NodeMap, line string) bool {
+	for _, lines := range nodeMap {
+		if _, exists := lines[line]; exists {
+			return true
+		}
+	}
+	return false
+}
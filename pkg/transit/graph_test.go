@@ -0,0 +1,64 @@
+package transit
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestAddTime(t *testing.T) {
+	tests := []struct {
+		a, b, want uint16
+	}{
+		{0, 0, 0},
+		{3, 4, 7},
+		{MaxTravelTime - 1, 1, MaxTravelTime},
+		{MaxTravelTime, 1, MaxTravelTime},
+		{40000, 30000, MaxTravelTime},
+		{MaxTravelTime, MaxTravelTime, MaxTravelTime},
+		{math.MaxUint16, 0, math.MaxUint16},
+		{0, math.MaxUint16, math.MaxUint16},
+		{math.MaxUint16, 1, math.MaxUint16},
+		{math.MaxUint16, math.MaxUint16, math.MaxUint16},
+	}
+	for _, test := range tests {
+		if got := AddTime(test.a, test.b); got != test.want {
+			t.Errorf("AddTime(%d, %d) = %d, want %d", test.a, test.b, got, test.want)
+		}
+	}
+}
+
+// A path whose links add up to more than MaxTravelTime minutes must not wrap
+// around to look quicker than a short one, but must still be found
+func TestSearchSaturatesLongPaths(t *testing.T) {
+	npq := make(NodePriorityQueue, 0)
+	nodeMap := make(NodeMap)
+	rail := LinkAttributes{Mode: ModeRail}
+	for _, link := range []RailLink{
+		NewRailLink("A", "B", "Slow", 30000),
+		NewRailLink("B", "C", "Slow", 30000),
+		NewRailLink("C", "D", "Slow", 30000),
+	} {
+		AddConnection(&npq, nodeMap, &link, rail)
+	}
+
+	// Wrapped times would send the search round the line forever, so it is
+	// given a deadline
+	opts := &SearchOptions{Deadline: time.Now().Add(10 * time.Second)}
+	npq = ResetGraph(nodeMap)
+	journey, err := RunShortestPaths(&npq, nodeMap, "A", "D", opts)
+	if err != nil {
+		t.Fatalf("A to D failed: %v", err)
+	}
+	if journey.TotalMinutes != MaxTravelTime {
+		t.Errorf("A to D takes %d minutes, want it saturated at %d", journey.TotalMinutes, MaxTravelTime)
+	}
+
+	times := TravelTimesFrom(nodeMap, "A", nil)
+	if times["C"] != 60000 {
+		t.Errorf("time to C = %d, want 60000", times["C"])
+	}
+	if got, reached := times["D"]; !reached || got != MaxTravelTime {
+		t.Errorf("time to D = %d (reached %t), want it saturated at %d", got, reached, MaxTravelTime)
+	}
+}
@@ -0,0 +1,247 @@
+package transit
+
+import (
+	"bufio"
+	"cmp"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Version of the graph cache format, to be increased whenever the structure
+// of cached graphs changes so that older caches are rebuilt
+const graphCacheVersion = 4
+
+// Returned when loading a graph cache built from different transit data than
+// is now in use
+var ErrStaleGraphCache = errors.New("graph cache is out of date")
+
+// Represents a transit graph as stored in a cache file: its Nodes in a fixed
+// order, with links referring to the Nodes they lead to by position, along
+// with a key identifying the transit data it was built from
+type cachedGraph struct {
+	Key      string
+	Nodes    []cachedNode
+	Services []cachedService
+}
+
+// Represents a Node of a cached graph. Service is one more than the position
+// of the line's service in the graph's services, or zero if it has none.
+type cachedNode struct {
+	Station    string
+	Line       string
+	Links      []cachedLink
+	BoardTime  uint16
+	AccessTime uint16
+	Service    int
+	ZoneLow    uint8
+	ZoneHigh   uint8
+	StepFree   bool
+	LiftTime   uint16
+	Lifts      []Lift
+	Ramp       bool
+}
+
+// Represents a link of a cached graph, leading to the Node at the specified
+// position
+type cachedLink struct {
+	End   int
+	Time  uint16
+	Attrs LinkAttributes
+}
+
+// Represents the operating hours and frequency of a line in a cached graph
+type cachedService struct {
+	Line           string
+	FirstTrain     uint16
+	LastTrain      uint16
+	PeakHeadway    uint16
+	OffPeakHeadway uint16
+}
+
+// Return the default location of the graph cache, inside the user's cache
+// directory (falling back to the working directory)
+func DefaultGraphCachePath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "tubeplanner-graph.cache"
+	}
+	return filepath.Join(dir, "tubeplanner", "graph.cache")
+}
+
+// Return a key identifying the transit data the graph would be built from
+// with the specified options: the program itself, whose built-in data is
+// compiled in, and the YAML dataset, CSV files, every file of the GTFS feed,
+// OpenStreetMap extract or network database in use, each by its path, size
+// and modification time, along with the walks and assumed interchanges
+// added. Any change to them gives a different key.
+func graphSourceKey(source cacheableSource, opts GraphOptions) (string, error) {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "version %d\n", graphCacheVersion)
+	fmt.Fprintf(hash, "walk radius %g\n", opts.WalkRadiusMetres)
+	fmt.Fprintf(hash, "assumed interchange %d\n", opts.AssumedInterchangeMinutes)
+	stamp := func(path string) error {
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(hash, "%s %d %d\n", path, info.Size(), info.ModTime().UnixNano())
+		return nil
+	}
+	executable, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	if err := stamp(executable); err != nil {
+		return "", err
+	}
+	if err := source.identify(hash, stamp); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Write the specified graph, as built from the transit data identified by
+// the specified key, to a cache file at the specified path, creating its
+// directory if needed. Travel times and search progress are not saved.
+func SaveGraphCache(path, key string, nodeMap NodeMap) error {
+	nodes := make([]*Node, 0)
+	for _, lines := range nodeMap {
+		for _, node := range lines {
+			nodes = append(nodes, node)
+		}
+	}
+	slices.SortFunc(nodes, func(a, b *Node) int {
+		return cmp.Or(strings.Compare(a.station, b.station), strings.Compare(a.line, b.line))
+	})
+	positions := make(map[*Node]int, len(nodes))
+	for idx, node := range nodes {
+		positions[node] = idx
+	}
+
+	graph := cachedGraph{Key: key, Nodes: make([]cachedNode, len(nodes))}
+	services := make(map[*LineService]int)
+	for idx, node := range nodes {
+		cn := cachedNode{Station: node.station, Line: node.line, BoardTime: node.boardTime,
+			AccessTime: node.accessTime, ZoneLow: node.zone.low, ZoneHigh: node.zone.high,
+			StepFree: node.stepFree, LiftTime: node.liftTime, Lifts: node.lifts, Ramp: node.ramp}
+		for _, link := range node.adj {
+			cn.Links = append(cn.Links, cachedLink{positions[link.endNode], link.time, link.attrs})
+		}
+		if node.service != nil {
+			if _, seen := services[node.service]; !seen {
+				ls := node.service
+				graph.Services = append(graph.Services, cachedService{ls.line, ls.firstTrain,
+					ls.lastTrain, ls.peakHeadway, ls.offPeakHeadway})
+				services[node.service] = len(graph.Services)
+			}
+			cn.Service = services[node.service]
+		}
+		graph.Nodes[idx] = cn
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return WriteFileAtomic(path, func(w io.Writer) error {
+		return gob.NewEncoder(w).Encode(graph)
+	})
+}
+
+// Read the graph cached at the specified path, returning ErrStaleGraphCache
+// if it was built from transit data other than that identified by the
+// specified key
+func LoadGraphCache(path, key string) (NodeMap, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var graph cachedGraph
+	if err := gob.NewDecoder(bufio.NewReader(file)).Decode(&graph); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	if graph.Key != key {
+		return nil, ErrStaleGraphCache
+	}
+
+	services := make([]LineService, len(graph.Services))
+	for idx, cs := range graph.Services {
+		services[idx] = LineService{cs.Line, cs.FirstTrain, cs.LastTrain, cs.PeakHeadway, cs.OffPeakHeadway}
+	}
+	nodes := make([]*Node, len(graph.Nodes))
+	nodeMap := make(NodeMap)
+	for idx, cn := range graph.Nodes {
+		nodes[idx] = &Node{station: cn.Station, line: cn.Line, adj: make([]*Link, 0, len(cn.Links)),
+			totalTime: math.MaxUint16, boardTime: cn.BoardTime, accessTime: cn.AccessTime,
+			zone: FareZone{cn.ZoneLow, cn.ZoneHigh}, stepFree: cn.StepFree, liftTime: cn.LiftTime,
+			lifts: cn.Lifts, ramp: cn.Ramp}
+		if cn.Service > len(services) {
+			return nil, fmt.Errorf("%s: invalid service for %s on the %s line", path, cn.Station, cn.Line)
+		} else if cn.Service > 0 {
+			nodes[idx].service = &services[cn.Service-1]
+		}
+		if nodeMap[cn.Station] == nil {
+			nodeMap[cn.Station] = make(map[string]*Node)
+		}
+		nodeMap[cn.Station][cn.Line] = nodes[idx]
+	}
+	for idx, cn := range graph.Nodes {
+		for _, cl := range cn.Links {
+			if cl.End < 0 || cl.End >= len(nodes) {
+				return nil, fmt.Errorf("%s: invalid link from %s on the %s line", path, cn.Station, cn.Line)
+			}
+			nodes[idx].adj = append(nodes[idx].adj, &Link{nodes[cl.End], cl.Time, cl.Attrs})
+		}
+	}
+	return nodeMap, nil
+}
+
+// Return the graph built as the specified options say, from the cache at
+// their CachePath if it is up to date, or else built afresh and cached there
+// for next time. Failing to read or write the cache only means building the
+// graph again, and is logged to the specified logger. Sources from outside
+// this package cannot be checked for changes, so their graphs are always
+// built afresh.
+func cachedBaseGraph(opts GraphOptions, logger *slog.Logger) (NodeMap, error) {
+	path := opts.CachePath
+	source, ok := graphSource(opts).(cacheableSource)
+	if !ok {
+		logger.Debug("not caching the graph of a data source from outside the package")
+		return buildBaseGraph(opts)
+	}
+	key, err := graphSourceKey(source, opts)
+	if err != nil {
+		logger.Warn("cannot identify the transit data, so not using the graph cache", "error", err)
+		return buildBaseGraph(opts)
+	}
+	nodeMap, err := LoadGraphCache(path, key)
+	switch {
+	case err == nil:
+		logger.Debug("read transit graph from cache", "path", path)
+		return nodeMap, nil
+	case errors.Is(err, ErrStaleGraphCache):
+		logger.Info("graph cache is out of date, rebuilding it", "path", path)
+	case errors.Is(err, fs.ErrNotExist):
+		logger.Debug("no graph cache yet, building the graph", "path", path)
+	default:
+		logger.Warn("cannot read graph cache, rebuilding it", "path", path, "error", err)
+	}
+	if nodeMap, err = buildBaseGraph(opts); err != nil {
+		return nil, err
+	}
+	if err := SaveGraphCache(path, key, nodeMap); err != nil {
+		logger.Warn("cannot write graph cache", "path", path, "error", err)
+	}
+	return nodeMap, nil
+}
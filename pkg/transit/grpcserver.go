@@ -0,0 +1,319 @@
+package transit
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Name of the gRPC service defined in api/tubeplanner.proto, which prefixes
+// the path of each of its methods
+const grpcService = "/tubeplanner.v1.Planner/"
+
+// Status codes of gRPC calls, as the gRPC protocol defines them
+const (
+	grpcOK                = 0
+	grpcInvalidArgument   = 3
+	grpcResourceExhausted = 8
+	grpcUnimplemented     = 12
+	grpcInternal          = 13
+)
+
+// Largest request message accepted, far more than any request needs
+const maxGRPCMessage = 1 << 20
+
+// Routes an Alternatives call returns when it does not say
+const defaultGRPCAlternatives = 3
+
+// Describes why a gRPC call failed, with the status code to answer it with
+type grpcError struct {
+	code    int
+	message string
+}
+
+func (e *grpcError) Error() string {
+	return e.message
+}
+
+// Answers a call to a gRPC method, given its encoded request message, by
+// passing each encoded response message to send in turn: exactly one for a
+// unary method, or any number for a streaming one
+type grpcMethod func(request []byte, send func([]byte) error) error
+
+// Register the methods of the Planner service of api/tubeplanner.proto, so
+// the server answers gRPC calls as well as HTTP requests
+func (server *HTTPServer) registerGRPC() {
+	server.mux.HandleFunc("POST "+grpcService+"Plan", server.handleGRPC(server.grpcPlan))
+	server.mux.HandleFunc("POST "+grpcService+"Alternatives", server.handleGRPC(server.grpcAlternatives))
+	server.mux.HandleFunc("POST "+grpcService+"Stations", server.handleGRPC(server.grpcStations))
+}
+
+// Return an http.HandlerFunc answering calls to the specified gRPC method,
+// which must arrive over HTTP/2. The call's status is sent in the trailers,
+// after any response messages.
+func (server *HTTPServer) handleGRPC(method grpcMethod) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor != 2 || !strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+			http.Error(w, "gRPC calls must be made over HTTP/2 with content type application/grpc",
+				http.StatusUnsupportedMediaType)
+			return
+		}
+		w.Header().Set("Content-Type", "application/grpc")
+		w.Header().Set("Trailer", "Grpc-Status, Grpc-Message")
+		w.WriteHeader(http.StatusOK)
+		rc := http.NewResponseController(w)
+		send := func(msg []byte) error {
+			frame := binary.BigEndian.AppendUint32([]byte{0}, uint32(len(msg)))
+			if _, err := w.Write(append(frame, msg...)); err != nil {
+				return err
+			}
+			return rc.Flush()
+		}
+
+		request, err := readGRPCMessage(r.Body)
+		if err == nil {
+			err = method(request, send)
+		}
+		code, message := grpcOK, ""
+		var callErr *grpcError
+		if errors.As(err, &callErr) {
+			code, message = callErr.code, callErr.message
+		} else if err != nil {
+			code, message = grpcInternal, err.Error()
+		}
+		w.Header().Set("Grpc-Status", strconv.Itoa(code))
+		if message != "" {
+			w.Header().Set("Grpc-Message", url.PathEscape(message))
+		}
+	}
+}
+
+// Read the single, length-prefixed request message of a gRPC call from the
+// specified request body
+func readGRPCMessage(body io.Reader) ([]byte, error) {
+	var prefix [5]byte
+	if _, err := io.ReadFull(body, prefix[:]); err != nil {
+		return nil, &grpcError{grpcInvalidArgument, "missing request message"}
+	}
+	if prefix[0] != 0 {
+		return nil, &grpcError{grpcUnimplemented, "compressed messages are not supported"}
+	}
+	length := binary.BigEndian.Uint32(prefix[1:])
+	if length > maxGRPCMessage {
+		return nil, &grpcError{grpcResourceExhausted,
+			fmt.Sprintf("request message of %d bytes is more than the %d allowed", length, maxGRPCMessage)}
+	}
+	msg := make([]byte, length)
+	if _, err := io.ReadFull(body, msg); err != nil {
+		return nil, &grpcError{grpcInvalidArgument, "truncated request message"}
+	}
+	return msg, nil
+}
+
+// Answer a Plan call just as GET /route answers the same query
+func (server *HTTPServer) grpcPlan(request []byte, send func([]byte) error) error {
+	query, err := decodeRouteRequest(request)
+	if err != nil {
+		return &grpcError{grpcInvalidArgument, err.Error()}
+	}
+	response, err := server.answer(query)
+	if err != nil {
+		return err
+	}
+	return send(encodeRouteResponse(response))
+}
+
+// Answer an Alternatives call with up to the number of distinct routes it
+// asks for, fastest first, as for --alternatives. The request's closures,
+// avoided lines and stations, objective and step-free access are honoured,
+// while seat and accessibility preferences apply to Plan alone. A request
+// which cannot be answered gets a single response giving the reason.
+func (server *HTTPServer) grpcAlternatives(request []byte, send func([]byte) error) error {
+	var query JSONQuery
+	k := uint64(defaultGRPCAlternatives)
+	err := readProtoFields(request, func(field protoField) error {
+		switch {
+		case field.number == 1 && field.wireType == wireBytes:
+			var err error
+			query, err = decodeRouteRequest(field.bytes)
+			return err
+		case field.number == 2 && field.wireType == wireVarint && field.value > 0:
+			k = field.value
+		}
+		return nil
+	})
+	if err != nil {
+		return &grpcError{grpcInvalidArgument, err.Error()}
+	}
+	if k > MaxAlternatives {
+		return &grpcError{grpcInvalidArgument,
+			fmt.Sprintf("max_routes %d is more than the %d allowed", k, MaxAlternatives)}
+	}
+
+	graph := server.graph.Load()
+	graph.mu.Lock()
+	responses := alternativeResponses(graph.nodeMap, query, int(k), graph.DataVersion())
+	graph.mu.Unlock()
+	for _, response := range responses {
+		if err := send(encodeRouteResponse(response)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Return a response for each of up to k distinct routes for the specified
+// query, fastest first, or a single response saying why there are none
+func alternativeResponses(nodeMap NodeMap, query JSONQuery, k int, dataVersion string) []JSONResponse {
+	failure := func(err error) []JSONResponse {
+		return []JSONResponse{{Error: err.Error()}}
+	}
+	if err := checkQueryStations(nodeMap, query); err != nil {
+		return failure(err)
+	}
+	opts, err := queryScenario(nodeMap, query)
+	if err != nil {
+		return failure(err)
+	}
+	routes, err := KShortestPaths(nodeMap, query.From, query.To, k, opts)
+	if err != nil {
+		return failure(fmt.Errorf("No route available from %s to %s", query.From, query.To))
+	}
+	responses := make([]JSONResponse, 0, len(routes))
+	for _, route := range routes {
+		journey := markScenarioClosures(route.Journey(), opts)
+		journey.Stamp = NewStamp(journey, dataVersion, opts.Describe())
+		responses = append(responses, JSONResponse{Journey: journey})
+	}
+	return responses
+}
+
+// Answer a Stations call with every station of the graph, in alphabetical
+// order, with the lines serving it and where it is, if known
+func (server *HTTPServer) grpcStations(request []byte, send func([]byte) error) error {
+	if err := readProtoFields(request, func(protoField) error { return nil }); err != nil {
+		return &grpcError{grpcInvalidArgument, err.Error()}
+	}
+	graph := server.graph.Load()
+	coords := GetStationCoordinates()
+	for _, station := range graph.Stations() {
+		var enc protoEncoder
+		enc.string(1, station)
+		enc.strings(2, graph.Lines(station))
+		if c, known := coords[station]; known {
+			enc.optionalDouble(3, c.lat)
+			enc.optionalDouble(4, c.lon)
+		}
+		if err := send(enc.buf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Return the JSONQuery described by an encoded RouteRequest message
+func decodeRouteRequest(msg []byte) (JSONQuery, error) {
+	var query JSONQuery
+	err := readProtoFields(msg, func(field protoField) error {
+		if field.wireType == wireBytes {
+			value := string(field.bytes)
+			switch field.number {
+			case 1:
+				query.From = value
+			case 2:
+				query.To = value
+			case 3:
+				query.Closed = append(query.Closed, value)
+			case 4:
+				query.AvoidLine = append(query.AvoidLine, value)
+			case 5:
+				query.AvoidStation = append(query.AvoidStation, value)
+			case 6:
+				query.Optimize = value
+			case 9:
+				query.Accessibility = value
+			}
+		} else if field.wireType == wireVarint {
+			switch field.number {
+			case 7:
+				query.StepFree = field.value != 0
+			case 8:
+				if field.value > math.MaxUint16 {
+					return fmt.Errorf("invalid prefer_seat %d: must be a number of minutes", field.value)
+				}
+				query.PreferSeat = uint16(field.value)
+			case 10:
+				budgetMS := int32(field.value)
+				if budgetMS < 0 {
+					return fmt.Errorf("invalid budget_ms %d: must be a number of milliseconds", budgetMS)
+				}
+				query.BudgetMS = int(budgetMS)
+			}
+		}
+		return nil
+	})
+	if err == nil && (query.From == "" || query.To == "") {
+		err = errors.New("both from and to must be given")
+	}
+	return query, err
+}
+
+// Return the specified response encoded as a RouteResponse message
+func encodeRouteResponse(response JSONResponse) []byte {
+	var enc protoEncoder
+	if response.Journey != nil {
+		enc.message(1, encodeJourney(response.Journey))
+	}
+	enc.bool(2, response.Partial)
+	enc.string(3, response.Error)
+	return enc.buf
+}
+
+// Return the specified journey encoded as a Journey message
+func encodeJourney(journey *Journey) []byte {
+	var enc protoEncoder
+	enc.int(1, int64(journey.SchemaVersion))
+	enc.string(2, journey.From)
+	enc.string(3, journey.To)
+	enc.uint(4, uint64(journey.TotalMinutes))
+	for _, leg := range journey.Legs {
+		var legEnc protoEncoder
+		legEnc.string(1, leg.Type)
+		legEnc.string(2, leg.Line)
+		legEnc.string(3, leg.FromLine)
+		legEnc.string(4, leg.ToLine)
+		legEnc.string(5, leg.From)
+		legEnc.string(6, leg.To)
+		legEnc.uint(7, uint64(leg.Depart))
+		legEnc.uint(8, uint64(leg.Arrive))
+		legEnc.uint(9, uint64(leg.Minutes))
+		for _, stop := range leg.Stops {
+			var stopEnc protoEncoder
+			stopEnc.string(1, stop.Station)
+			stopEnc.uint(2, uint64(stop.Time))
+			stopEnc.bool(3, stop.Closed)
+			legEnc.message(10, stopEnc.buf)
+		}
+		legEnc.string(11, leg.Guidance)
+		legEnc.string(12, leg.LineDetails)
+		legEnc.uint(13, uint64(leg.WalkMinutes))
+		legEnc.bool(14, leg.BoardingRamp)
+		legEnc.bool(15, leg.AlightingRamp)
+		enc.message(5, legEnc.buf)
+	}
+	enc.strings(6, journey.DataWarnings)
+	if journey.Stamp != nil {
+		var stampEnc protoEncoder
+		stampEnc.string(1, journey.Stamp.RouteHash)
+		stampEnc.string(2, journey.Stamp.DataVersion)
+		stampEnc.string(3, journey.Stamp.Options)
+		enc.message(7, stampEnc.buf)
+	}
+	return enc.buf
+}
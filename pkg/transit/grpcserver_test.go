@@ -0,0 +1,143 @@
+package transit
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Make a gRPC call to the specified method of the server at the specified
+// URL over unencrypted HTTP/2, returning the response messages and the
+// call's status
+func callGRPC(t *testing.T, url, method string, request []byte) ([][]byte, string) {
+	t.Helper()
+	protocols := new(http.Protocols)
+	protocols.SetUnencryptedHTTP2(true)
+	client := &http.Client{Transport: &http.Transport{Protocols: protocols}}
+	body := binary.BigEndian.AppendUint32([]byte{0}, uint32(len(request)))
+	req, err := http.NewRequest("POST", url+grpcService+method, bytes.NewReader(append(body, request...)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/grpc")
+	req.Header.Set("TE", "trailers")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("calling %s: %v", method, err)
+	}
+	defer resp.Body.Close()
+	if resp.ProtoMajor != 2 {
+		t.Fatalf("%s answered over HTTP/%d", method, resp.ProtoMajor)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var messages [][]byte
+	for len(data) > 0 {
+		if len(data) < 5 || int(binary.BigEndian.Uint32(data[1:5])) > len(data)-5 {
+			t.Fatalf("%s sent a truncated message", method)
+		}
+		length := int(binary.BigEndian.Uint32(data[1:5]))
+		messages, data = append(messages, data[5:5+length]), data[5+length:]
+	}
+	return messages, resp.Trailer.Get("Grpc-Status")
+}
+
+// Return the journey's from, to and total minutes, and the error, of an
+// encoded RouteResponse
+func decodeTestResponse(t *testing.T, msg []byte) (string, string, uint64, string) {
+	t.Helper()
+	var from, to, errMsg string
+	var minutes uint64
+	err := readProtoFields(msg, func(field protoField) error {
+		switch field.number {
+		case 1:
+			return readProtoFields(field.bytes, func(field protoField) error {
+				switch field.number {
+				case 2:
+					from = string(field.bytes)
+				case 3:
+					to = string(field.bytes)
+				case 4:
+					minutes = field.value
+				}
+				return nil
+			})
+		case 3:
+			errMsg = string(field.bytes)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("decoding RouteResponse: %v", err)
+	}
+	return from, to, minutes, errMsg
+}
+
+func TestGRPCServer(t *testing.T) {
+	nodeMap := defaultNodeMap(t)
+	ts := httptest.NewUnstartedServer(NewHTTPServer(nodeMap, nil, 0))
+	ts.Config.Protocols = new(http.Protocols)
+	ts.Config.Protocols.SetHTTP1(true)
+	ts.Config.Protocols.SetUnencryptedHTTP2(true)
+	ts.Start()
+	defer ts.Close()
+
+	var request protoEncoder
+	request.string(1, "Brixton")
+	request.string(2, "Bank")
+	messages, status := callGRPC(t, ts.URL, "Plan", request.buf)
+	if status != "0" || len(messages) != 1 {
+		t.Fatalf("Plan answered %d messages with status %q, want 1 with status 0", len(messages), status)
+	}
+	want := AnswerJSONQuery(nodeMap, JSONQuery{From: "Brixton", To: "Bank"}, 0)
+	from, to, minutes, errMsg := decodeTestResponse(t, messages[0])
+	if from != "Brixton" || to != "Bank" || minutes != uint64(want.Journey.TotalMinutes) || errMsg != "" {
+		t.Errorf("Plan answered %s to %s in %d minutes (error %q), want Brixton to Bank in %d",
+			from, to, minutes, errMsg, want.Journey.TotalMinutes)
+	}
+
+	var alternatives protoEncoder
+	alternatives.message(1, request.buf)
+	alternatives.uint(2, 3)
+	messages, status = callGRPC(t, ts.URL, "Alternatives", alternatives.buf)
+	if status != "0" || len(messages) == 0 || len(messages) > 3 {
+		t.Fatalf("Alternatives answered %d messages with status %q, want 1 to 3 with status 0",
+			len(messages), status)
+	}
+	previous := uint64(0)
+	for _, msg := range messages {
+		if _, _, minutes, errMsg := decodeTestResponse(t, msg); errMsg != "" || minutes < previous {
+			t.Errorf("Alternatives answered a route of %d minutes (error %q) after one of %d",
+				minutes, errMsg, previous)
+		} else {
+			previous = minutes
+		}
+	}
+
+	var unknown protoEncoder
+	unknown.string(1, "Nowhere")
+	unknown.string(2, "Bank")
+	messages, status = callGRPC(t, ts.URL, "Plan", unknown.buf)
+	if status != "0" || len(messages) != 1 {
+		t.Fatalf("Plan from an unknown station answered %d messages with status %q", len(messages), status)
+	}
+	if _, _, _, errMsg := decodeTestResponse(t, messages[0]); errMsg == "" {
+		t.Error("Plan from an unknown station answered without an error")
+	}
+
+	if messages, status = callGRPC(t, ts.URL, "Plan", nil); status != "3" || len(messages) != 0 {
+		t.Errorf("Plan without stations answered %d messages with status %q, want none with status 3",
+			len(messages), status)
+	}
+
+	messages, status = callGRPC(t, ts.URL, "Stations", nil)
+	if status != "0" || len(messages) != len(nodeMap) {
+		t.Errorf("Stations answered %d stations with status %q, want %d with status 0",
+			len(messages), status, len(nodeMap))
+	}
+}
@@ -0,0 +1,318 @@
+package transit
+
+import (
+	"archive/zip"
+	"cmp"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Return whether routes of the specified GTFS route_type are rail services
+// of the kind the planner models: trams, metros, trains and monorails, in
+// either the basic or the extended route types
+func gtfsRailRouteType(routeType int) bool {
+	switch {
+	case routeType == 0 || routeType == 1 || routeType == 2 || routeType == 12:
+		return true
+	case routeType >= 100 && routeType < 200, routeType >= 400 && routeType < 500,
+		routeType >= 900 && routeType < 1000:
+		return true
+	}
+	return false
+}
+
+// Call the specified function on every record of the named file in a GTFS
+// feed, with the record's fields keyed by the column names of its header row
+func readGTFSFile(feed fs.FS, name string, visit func(record map[string]string) error) error {
+	file, err := feed.Open(name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+	header, err := reader.Read()
+	if err != nil {
+		return fmt.Errorf("%s: reading header: %v", name, err)
+	}
+	columns := make([]string, len(header))
+	for idx, column := range header {
+		columns[idx] = strings.TrimSpace(strings.TrimPrefix(column, "\ufeff"))
+	}
+	record := make(map[string]string, len(columns))
+	for {
+		fields, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("%s: %v", name, err)
+		}
+		clear(record)
+		for idx, field := range fields {
+			if idx < len(columns) {
+				record[columns[idx]] = strings.TrimSpace(field)
+			}
+		}
+		if err := visit(record); err != nil {
+			lineNum, _ := reader.FieldPos(0)
+			return fmt.Errorf("%s:%d: %v", name, lineNum, err)
+		}
+	}
+}
+
+// Parse a GTFS time of day ("HH:MM:SS", where hours may exceed 24 for trips
+// running past midnight) into seconds since midnight
+func parseGTFSTime(s string) (int, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+	seconds := 0
+	for _, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid time %q", s)
+		}
+		seconds = seconds*60 + n
+	}
+	return seconds, nil
+}
+
+// Open the GTFS feed at the specified path, which may be a directory or a
+// zip file, returning a function to close it when done
+func openGTFSFeed(path string) (fs.FS, func() error, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if info.IsDir() {
+		return os.DirFS(path), func() error { return nil }, nil
+	}
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return archive, archive.Close, nil
+}
+
+// Build rail links and interchanges from the GTFS feed at the specified path.
+// Stops are grouped into stations by their parent_station, and stations are
+// named after the stop_name of the station (suffixed with its stop_id where
+// two stations share a name). Rail links join consecutive stops of the trips
+// on rail routes, timed by the median run time over all trips between them
+// in either direction and named after the route's short name (or else its
+// long name). Interchanges come from transfers.txt, if the feed has one;
+// changes between lines at the same station without a transfer time are
+// left to GraphOptions.AssumedInterchangeMinutes.
+func LoadGTFS(path string) ([]RailLink, []Interchange, error) {
+	feed, closeFeed, err := openGTFSFeed(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer closeFeed()
+
+	// Map every stop to the station it belongs to, and name the stations
+	parents, names := make(map[string]string), make(map[string]string)
+	err = readGTFSFile(feed, "stops.txt", func(record map[string]string) error {
+		if record["stop_id"] == "" {
+			return errors.New("stop without a stop_id")
+		}
+		if parent := record["parent_station"]; parent != "" {
+			parents[record["stop_id"]] = parent
+		}
+		names[record["stop_id"]] = record["stop_name"]
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	stationOf := func(stopID string) string {
+		for depth := 0; depth < 4 && parents[stopID] != ""; depth++ {
+			stopID = parents[stopID]
+		}
+		return stopID
+	}
+	stationIDs := make(map[string][]string)
+	for stopID := range names {
+		if station := stationOf(stopID); station == stopID {
+			stationIDs[names[stopID]] = append(stationIDs[names[stopID]], stopID)
+		}
+	}
+	stationNames := make(map[string]string)
+	for name, ids := range stationIDs {
+		for _, id := range ids {
+			if len(ids) > 1 {
+				stationNames[id] = fmt.Sprintf("%s (%s)", name, id)
+			} else {
+				stationNames[id] = name
+			}
+		}
+	}
+
+	// Find the line name of every rail route, and the route of every trip
+	lineNames := make(map[string]string)
+	err = readGTFSFile(feed, "routes.txt", func(record map[string]string) error {
+		routeType, err := strconv.Atoi(record["route_type"])
+		if err != nil {
+			return fmt.Errorf("invalid route_type %q", record["route_type"])
+		}
+		if gtfsRailRouteType(routeType) {
+			lineNames[record["route_id"]] = cmp.Or(record["route_short_name"], record["route_long_name"],
+				record["route_id"])
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	tripLines := make(map[string]string)
+	err = readGTFSFile(feed, "trips.txt", func(record map[string]string) error {
+		if line, isRail := lineNames[record["route_id"]]; isRail {
+			tripLines[record["trip_id"]] = line
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Collect the calls of every rail trip, then time the runs between
+	// consecutive calls
+	type call struct {
+		sequence  int
+		station   string
+		arrival   int
+		departure int
+	}
+	tripCalls := make(map[string][]call)
+	err = readGTFSFile(feed, "stop_times.txt", func(record map[string]string) error {
+		if _, isRail := tripLines[record["trip_id"]]; !isRail {
+			return nil
+		}
+		sequence, err := strconv.Atoi(record["stop_sequence"])
+		if err != nil {
+			return fmt.Errorf("invalid stop_sequence %q", record["stop_sequence"])
+		}
+		// Stops between timepoints may leave their times blank, in which
+		// case they are skipped, merging the runs either side of them
+		if record["arrival_time"] == "" || record["departure_time"] == "" {
+			return nil
+		}
+		arrival, err := parseGTFSTime(record["arrival_time"])
+		if err != nil {
+			return err
+		}
+		departure, err := parseGTFSTime(record["departure_time"])
+		if err != nil {
+			return err
+		}
+		station := stationNames[stationOf(record["stop_id"])]
+		if station == "" {
+			return fmt.Errorf("unknown stop_id %q", record["stop_id"])
+		}
+		tripCalls[record["trip_id"]] = append(tripCalls[record["trip_id"]],
+			call{sequence, station, arrival, departure})
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	type linkKey struct{ stationA, stationB, line string }
+	runTimes := make(map[linkKey][]int)
+	for tripID, calls := range tripCalls {
+		slices.SortFunc(calls, func(a, b call) int { return a.sequence - b.sequence })
+		for idx := 1; idx < len(calls); idx++ {
+			from, to := calls[idx-1], calls[idx]
+			if from.station == to.station {
+				continue
+			}
+			key := linkKey{from.station, to.station, tripLines[tripID]}
+			if key.stationB < key.stationA {
+				key.stationA, key.stationB = key.stationB, key.stationA
+			}
+			runTimes[key] = append(runTimes[key], to.arrival-from.departure)
+		}
+	}
+	keys := make([]linkKey, 0, len(runTimes))
+	for key := range runTimes {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b linkKey) int {
+		return strings.Compare(a.line+"\x00"+a.stationA+"\x00"+a.stationB,
+			b.line+"\x00"+b.stationA+"\x00"+b.stationB)
+	})
+	railLinks := make([]RailLink, 0, len(keys))
+	served := make(map[string][]string)
+	for _, key := range keys {
+		times := runTimes[key]
+		slices.Sort(times)
+		minutes := max(1, int(math.Round(float64(times[len(times)/2])/60)))
+		railLinks = append(railLinks, RailLink{key.stationA, key.stationB, key.line,
+			uint16(min(minutes, math.MaxUint16-1))})
+		for _, station := range []string{key.stationA, key.stationB} {
+			if !slices.Contains(served[station], key.line) {
+				served[station] = append(served[station], key.line)
+			}
+		}
+	}
+	if len(railLinks) == 0 {
+		return nil, nil, errors.New("feed has no rail services")
+	}
+
+	interchanges, err := readGTFSTransfers(feed, stationOf, stationNames, served)
+	if err != nil {
+		return nil, nil, err
+	}
+	return railLinks, interchanges, nil
+}
+
+// Build interchanges from the transfers.txt file of a GTFS feed, if it has
+// one, between every line serving the two stations of each transfer with a
+// minimum transfer time. Transfers marked as impossible, and those involving
+// stations with no rail services, are skipped.
+func readGTFSTransfers(feed fs.FS, stationOf func(string) string, stationNames map[string]string,
+	served map[string][]string) ([]Interchange, error) {
+	interchanges := make([]Interchange, 0)
+	seen := make(map[Interchange]bool)
+	err := readGTFSFile(feed, "transfers.txt", func(record map[string]string) error {
+		if record["transfer_type"] != "2" || record["min_transfer_time"] == "" {
+			return nil
+		}
+		seconds, err := strconv.Atoi(record["min_transfer_time"])
+		if err != nil || seconds < 0 {
+			return fmt.Errorf("invalid min_transfer_time %q", record["min_transfer_time"])
+		}
+		minutes := uint16(min((seconds+59)/60, math.MaxUint16-1))
+		from := stationNames[stationOf(record["from_stop_id"])]
+		to := stationNames[stationOf(record["to_stop_id"])]
+		for _, fromLine := range served[from] {
+			for _, toLine := range served[to] {
+				if from == to && fromLine == toLine {
+					continue
+				}
+				ic := Interchange{from, fromLine, to, toLine, minutes}
+				reversed := Interchange{to, toLine, from, fromLine, minutes}
+				if !seen[ic] && !seen[reversed] {
+					seen[ic] = true
+					interchanges = append(interchanges, ic)
+				}
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return interchanges, nil
+	}
+	return interchanges, err
+}
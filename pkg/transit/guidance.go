@@ -0,0 +1,14 @@
+package transit
+
+// Return the walking guidance for changing from the first to the second of
+// the specified nodes, or "" if the transit data has none for that
+// interchange
+func InterchangeGuidanceText(from, to *Node) string {
+	for _, guidance := range GetInterchangeGuidance() {
+		if guidance.fromStation == from.station && guidance.fromLine == from.line &&
+			guidance.toStation == to.station && guidance.toLine == to.line {
+			return guidance.text
+		}
+	}
+	return ""
+}
@@ -0,0 +1,35 @@
+package transit
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+// Return a fresh transit graph built from the bundled transit data alone,
+// with no graph cache, station overrides, lift outages or link times, so
+// that tests do not depend on the machine running them
+func defaultNodeMap(t *testing.T) NodeMap {
+	t.Helper()
+	_, nodeMap, err := BuildTransitGraph(DefaultGraphOptions())
+	if err != nil {
+		t.Fatalf("BuildTransitGraph() failed: %v", err)
+	}
+	return nodeMap
+}
+
+// Make searches place the stations at the specified coordinates instead of
+// those built into the transit data until the test ends, so that A* can
+// search graphs made up for tests
+func useStationCoordinates(t *testing.T, coords map[string]Coordinates) {
+	t.Helper()
+	saved := stationPoints
+	points := mapPoints(coords)
+	stationPoints = func() map[string]mapPoint { return points }
+	t.Cleanup(func() { stationPoints = saved })
+}
+
+// Return the specified number of pairs of stations of the graph, chosen at
+// random but the same every run
+func samplePairs(nodeMap NodeMap, n int) [][2]string {
+	return SampleStationPairs(nodeMap, nodeMap, n, rand.New(rand.NewPCG(1, 2)))
+}
@@ -0,0 +1,357 @@
+package transit
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Represents the HTTP API serving routing queries against a transit graph
+// built once up front. Searching records travel times on the graph's Nodes,
+// so queries are answered one at a time, apart from queries setting no
+// options, which are answered from the graph's contraction hierarchy at the
+// same time as any others when it has one. Queries setting no options
+// between pairs of stations the server was warmed with (see Warm) are
+// answered from its route cache without searching at all.
+type HTTPServer struct {
+	graph     atomic.Pointer[Graph]
+	cache     atomic.Pointer[routeCache]
+	warmPairs [][2]string
+	logMu     sync.Mutex
+	queryLog  *QueryLog
+	budget    time.Duration
+	journeys  JourneyStore
+	mux       *http.ServeMux
+}
+
+// Holds the responses to queries setting no options between pairs of
+// stations, as answered from one graph. It is never changed once built, so
+// it can be read by any number of queries at once.
+type routeCache struct {
+	graph     *Graph
+	responses map[[2]string]JSONResponse
+}
+
+// Return an http.Handler answering GET /route?from=X&to=Y with the same JSON
+// responses as --stdio-json mode. The optional query parameters closed,
+// avoidLine and avoidStation may be repeated, and optimize, stepFree,
+// preferSeat, accessibility and budgetMs are as for a JSONQuery. A nil
+// queryLog disables logging, and the budget is the default for honouring
+// preferences, as for AnswerJSONQuery. GET /matrix?from=A&from=B&to=C&to=D,
+// with from and to each repeated as needed, answers with the travel times
+// from each origin to each destination as a TravelTimeMatrix. Calls to the
+// gRPC service of api/tubeplanner.proto made over HTTP/2 are answered too.
+func NewHTTPServer(nodeMap NodeMap, queryLog *QueryLog, budget time.Duration) *HTTPServer {
+	server := &HTTPServer{
+		queryLog: queryLog,
+		budget:   budget,
+		mux:      http.NewServeMux(),
+	}
+	server.graph.Store(NewGraph(nodeMap))
+	server.mux.HandleFunc("GET /route", server.handleRoute)
+	server.mux.HandleFunc("GET /matrix", server.handleMatrix)
+	server.registerGRPC()
+	return server
+}
+
+// Return the graph queries are currently answered from
+func (server *HTTPServer) Graph() *Graph {
+	return server.graph.Load()
+}
+
+// Replace the graph queries are answered from, as for Planner.SwapGraph,
+// without interrupting queries under way. If the server was warmed, the
+// route cache is warmed again from the new graph before it is swapped in,
+// so queries never find the cache cold.
+func (server *HTTPServer) SwapGraph(graph *Graph) {
+	var cache *routeCache
+	if server.warmPairs != nil {
+		cache = server.buildRouteCache(graph, server.warmPairs)
+	}
+	server.graph.Store(graph)
+	server.cache.Store(cache)
+}
+
+// Answer a query setting no options between each of the specified pairs of
+// stations up front, keeping the responses to answer the same queries with
+// later without searching, e.g. for the most popular trips, so the first
+// users after a deploy do not wait for cold searches. Responses saying there
+// is no route are kept as well. The pairs are remembered, so the cache is
+// warmed again whenever the graph is swapped (see SwapGraph). This must be
+// called before the server starts answering queries.
+func (server *HTTPServer) Warm(pairs [][2]string) {
+	server.warmPairs = slices.Clone(pairs)
+	server.cache.Store(server.buildRouteCache(server.graph.Load(), pairs))
+}
+
+// Build a route cache holding the responses from the specified graph to
+// queries setting no options between each of the specified pairs of stations
+func (server *HTTPServer) buildRouteCache(graph *Graph, pairs [][2]string) *routeCache {
+	cache := &routeCache{graph, make(map[[2]string]JSONResponse, len(pairs))}
+	ch := graph.hierarchy.Load()
+	graph.mu.Lock()
+	defer graph.mu.Unlock()
+	for _, pair := range pairs {
+		if _, cached := cache.responses[pair]; !cached {
+			cache.responses[pair] = answerJSONQuery(graph.nodeMap, ch, JSONQuery{From: pair[0], To: pair[1]},
+				server.budget, graph.DataVersion())
+		}
+	}
+	return cache
+}
+
+// Return the number of trips in the route cache, zero if the server was
+// never warmed
+func (server *HTTPServer) CachedRoutes() int {
+	if cache := server.cache.Load(); cache != nil {
+		return len(cache.responses)
+	}
+	return 0
+}
+
+// Keep the favourite trips and journey history of the server's users in the
+// specified store. GET /route requests naming a user with the user query
+// parameter add their journeys to that user's history, which GET
+// /history?user=X returns, most recent first, up to an optional limit. GET
+// /favourites?user=X returns the user's favourites, PUT
+// /favourites?user=X&name=N&from=A&to=B saves one, and DELETE
+// /favourites?user=X&name=N removes one. Users are taken at their word, so
+// the server should sit behind something that checks who they are. This
+// must be called before the server starts answering queries.
+func (server *HTTPServer) UseJourneyStore(store JourneyStore) {
+	server.journeys = store
+	server.mux.HandleFunc("GET /history", server.handleHistory)
+	server.mux.HandleFunc("GET /favourites", server.handleFavourites)
+	server.mux.HandleFunc("PUT /favourites", server.handleSaveFavourite)
+	server.mux.HandleFunc("DELETE /favourites", server.handleRemoveFavourite)
+}
+
+func (server *HTTPServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	server.mux.ServeHTTP(w, r)
+}
+
+func (server *HTTPServer) handleRoute(w http.ResponseWriter, r *http.Request) {
+	query, err := parseRouteQuery(r)
+	if err != nil {
+		writeJSONResponse(w, http.StatusBadRequest, JSONResponse{Error: err.Error()})
+		return
+	}
+	response, err := server.answer(query)
+	if err != nil {
+		writeJSONResponse(w, http.StatusInternalServerError, JSONResponse{Error: err.Error()})
+		return
+	}
+	if user := r.URL.Query().Get("user"); server.journeys != nil && user != "" && response.Journey != nil {
+		if err := server.journeys.RecordJourney(user, NewHistoryEntry(response.Journey, time.Now())); err != nil {
+			writeJSONResponse(w, http.StatusInternalServerError,
+				JSONResponse{Error: fmt.Sprintf("writing journey history: %v", err)})
+			return
+		}
+	}
+
+	status := http.StatusOK
+	if response.Journey == nil {
+		status = http.StatusUnprocessableEntity
+	}
+	writeJSONResponse(w, status, response)
+}
+
+// Answer the specified query from the route cache, the contraction
+// hierarchy or the graph itself, whichever can, and record it in the query
+// log, returning an error only if the log could not be written
+func (server *HTTPServer) answer(query JSONQuery) (JSONResponse, error) {
+	var response JSONResponse
+	graph := server.graph.Load()
+	cache := server.cache.Load()
+	cached, isCached := JSONResponse{}, false
+	if cache != nil && cache.graph == graph && query.unconstrained() {
+		cached, isCached = cache.responses[[2]string{query.From, query.To}]
+	}
+	if isCached {
+		response = cached
+		response.ID = query.ID
+	} else if ch := graph.hierarchy.Load(); ch != nil && query.unconstrained() {
+		response = answerJSONQuery(graph.nodeMap, ch, query, server.budget, graph.DataVersion())
+	} else {
+		graph.mu.Lock()
+		response = answerJSONQuery(graph.nodeMap, nil, query, server.budget, graph.DataVersion())
+		graph.mu.Unlock()
+	}
+	if server.queryLog != nil {
+		server.logMu.Lock()
+		err := server.queryLog.Record(query, response)
+		server.logMu.Unlock()
+		if err != nil {
+			return response, fmt.Errorf("writing query log: %v", err)
+		}
+	}
+	return response, nil
+}
+
+func (server *HTTPServer) handleMatrix(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	origins, dests := params["from"], params["to"]
+	if len(origins) == 0 || len(dests) == 0 {
+		writeJSONResponse(w, http.StatusBadRequest, JSONResponse{Error: "at least one from and one to must be given"})
+		return
+	}
+	if len(origins)*len(dests) > maxMatrixPairs {
+		writeJSONResponse(w, http.StatusBadRequest, JSONResponse{Error: fmt.Sprintf(
+			"%d origins by %d destinations is more than the %d pairs allowed", len(origins), len(dests),
+			maxMatrixPairs)})
+		return
+	}
+	graph := server.graph.Load()
+	graph.mu.Lock()
+	matrix, err := NewTravelTimeMatrix(graph.nodeMap, origins, dests, nil)
+	graph.mu.Unlock()
+	if err != nil {
+		writeJSONResponse(w, http.StatusUnprocessableEntity, JSONResponse{Error: err.Error()})
+		return
+	}
+	writeJSONResponse(w, http.StatusOK, matrix)
+}
+
+// Most pairs of stations a single /matrix request may ask for, which keeps
+// any one request from holding up the others for long
+const maxMatrixPairs = 100000
+
+// Return the JSONQuery described by a /route request's query parameters
+func parseRouteQuery(r *http.Request) (JSONQuery, error) {
+	params := r.URL.Query()
+	query := JSONQuery{
+		From:          params.Get("from"),
+		To:            params.Get("to"),
+		Closed:        params["closed"],
+		AvoidLine:     params["avoidLine"],
+		AvoidStation:  params["avoidStation"],
+		Optimize:      params.Get("optimize"),
+		Accessibility: params.Get("accessibility"),
+	}
+	if query.From == "" || query.To == "" {
+		return query, fmt.Errorf("both from and to must be given")
+	}
+	if value := params.Get("stepFree"); value != "" {
+		stepFree, err := strconv.ParseBool(value)
+		if err != nil {
+			return query, fmt.Errorf("invalid stepFree %q: must be true or false", value)
+		}
+		query.StepFree = stepFree
+	}
+	if value := params.Get("preferSeat"); value != "" {
+		minutes, err := strconv.ParseUint(value, 10, 16)
+		if err != nil {
+			return query, fmt.Errorf("invalid preferSeat %q: must be a number of minutes", value)
+		}
+		query.PreferSeat = uint16(minutes)
+	}
+	if value := params.Get("budgetMs"); value != "" {
+		budgetMS, err := strconv.Atoi(value)
+		if err != nil || budgetMS < 0 {
+			return query, fmt.Errorf("invalid budgetMs %q: must be a number of milliseconds", value)
+		}
+		query.BudgetMS = budgetMS
+	}
+	return query, nil
+}
+
+// Return the user named by a request's query parameters, writing an error
+// response and returning false if there is none
+func requestUser(w http.ResponseWriter, r *http.Request) (string, bool) {
+	user := r.URL.Query().Get("user")
+	if user == "" {
+		writeJSONResponse(w, http.StatusBadRequest, JSONResponse{Error: "user must be given"})
+	}
+	return user, user != ""
+}
+
+func (server *HTTPServer) handleHistory(w http.ResponseWriter, r *http.Request) {
+	user, ok := requestUser(w, r)
+	if !ok {
+		return
+	}
+	limit := 0
+	if value := r.URL.Query().Get("limit"); value != "" {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil || limit < 0 {
+			writeJSONResponse(w, http.StatusBadRequest,
+				JSONResponse{Error: fmt.Sprintf("invalid limit %q: must be a number of journeys", value)})
+			return
+		}
+	}
+	history, err := server.journeys.History(user, limit)
+	if err != nil {
+		writeJSONResponse(w, http.StatusInternalServerError, JSONResponse{Error: err.Error()})
+		return
+	}
+	writeJSONResponse(w, http.StatusOK, history)
+}
+
+func (server *HTTPServer) handleFavourites(w http.ResponseWriter, r *http.Request) {
+	user, ok := requestUser(w, r)
+	if !ok {
+		return
+	}
+	favourites, err := server.journeys.Favourites(user)
+	if err != nil {
+		writeJSONResponse(w, http.StatusInternalServerError, JSONResponse{Error: err.Error()})
+		return
+	}
+	writeJSONResponse(w, http.StatusOK, favourites)
+}
+
+func (server *HTTPServer) handleSaveFavourite(w http.ResponseWriter, r *http.Request) {
+	user, ok := requestUser(w, r)
+	if !ok {
+		return
+	}
+	params := r.URL.Query()
+	favourite := Favourite{params.Get("name"), params.Get("from"), params.Get("to")}
+	if favourite.Name == "" || favourite.From == "" || favourite.To == "" {
+		writeJSONResponse(w, http.StatusBadRequest, JSONResponse{Error: "name, from and to must all be given"})
+		return
+	}
+	nodeMap := server.graph.Load().nodeMap
+	for _, station := range []string{favourite.From, favourite.To} {
+		if _, exists := nodeMap[station]; !exists {
+			writeJSONResponse(w, http.StatusUnprocessableEntity,
+				JSONResponse{Error: fmt.Sprintf("%s is not a valid station", station)})
+			return
+		}
+	}
+	if err := server.journeys.SaveFavourite(user, favourite); err != nil {
+		writeJSONResponse(w, http.StatusInternalServerError, JSONResponse{Error: err.Error()})
+		return
+	}
+	writeJSONResponse(w, http.StatusOK, favourite)
+}
+
+func (server *HTTPServer) handleRemoveFavourite(w http.ResponseWriter, r *http.Request) {
+	user, ok := requestUser(w, r)
+	if !ok {
+		return
+	}
+	name := r.URL.Query().Get("name")
+	removed, err := server.journeys.RemoveFavourite(user, name)
+	switch {
+	case err != nil:
+		writeJSONResponse(w, http.StatusInternalServerError, JSONResponse{Error: err.Error()})
+	case !removed:
+		writeJSONResponse(w, http.StatusNotFound, JSONResponse{Error: fmt.Sprintf("no favourite named %q", name)})
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func writeJSONResponse(w http.ResponseWriter, status int, response any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.Encode(response)
+}
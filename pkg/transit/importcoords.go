@@ -0,0 +1,173 @@
+package transit
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Base URL of the TfL Unified API
+const tflAPIBase = "https://api.tfl.gov.uk"
+
+// Transport modes searched when looking stations up through the TfL API
+const tflStopPointModes = "tube,dlr,overground,elizabeth-line,tram"
+
+// Normalize a station name for matching against names from other sources,
+// which differ in case, punctuation and suffixes like "Underground Station"
+func normalizeStationName(name string) string {
+	name = strings.ToLower(name)
+	for _, suffix := range []string{" underground station", " dlr station", " rail station",
+		" tram stop", " station"} {
+		name = strings.TrimSuffix(name, suffix)
+	}
+	name = strings.ReplaceAll(name, "&", "and")
+	return strings.Map(func(r rune) rune {
+		if r == '.' || r == '\'' || r == ',' || r == '-' {
+			return -1
+		}
+		return r
+	}, strings.Join(strings.Fields(name), " "))
+}
+
+// Return the station names to try when matching a station from the dataset
+// against another source: the name as-is, then without any parenthesized
+// qualifier (e.g. "Bethnal Green (Central)")
+func StationNameCandidates(station string) []string {
+	candidates := []string{normalizeStationName(station)}
+	if idx := strings.Index(station, " ("); idx > 0 {
+		candidates = append(candidates, normalizeStationName(station[:idx]))
+	}
+	return candidates
+}
+
+// Read station coordinates from a reference CSV file with a header row naming
+// its columns, which must include a station name column ("name" or
+// "station") and latitude/longitude columns ("lat"/"latitude" and
+// "lon"/"lng"/"longitude"). Returned coordinates are keyed by normalized name.
+func ReadReferenceCoordinates(r io.Reader) (map[string]Coordinates, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading header: %v", err)
+	}
+	nameCol, latCol, lonCol := -1, -1, -1
+	for idx, column := range header {
+		switch strings.ToLower(strings.TrimSpace(column)) {
+		case "name", "station":
+			nameCol = idx
+		case "lat", "latitude":
+			latCol = idx
+		case "lon", "lng", "longitude":
+			lonCol = idx
+		}
+	}
+	if nameCol < 0 || latCol < 0 || lonCol < 0 {
+		return nil, fmt.Errorf("header must name station, latitude and longitude columns")
+	}
+
+	coords := make(map[string]Coordinates)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		lineNum, _ := reader.FieldPos(0)
+		lat, latErr := strconv.ParseFloat(strings.TrimSpace(record[latCol]), 64)
+		lon, lonErr := strconv.ParseFloat(strings.TrimSpace(record[lonCol]), 64)
+		if latErr != nil || lonErr != nil || lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+			return nil, fmt.Errorf("line %d: invalid coordinates %q, %q", lineNum, record[latCol], record[lonCol])
+		}
+		coords[normalizeStationName(record[nameCol])] = Coordinates{lat, lon}
+	}
+	return coords, nil
+}
+
+// Represents a stop point found through the TfL StopPoint search API
+type tflStopPoint struct {
+	ID   string  `json:"id"`
+	Name string  `json:"name"`
+	Lat  float64 `json:"lat"`
+	Lon  float64 `json:"lon"`
+}
+
+// Look the specified station up through the TfL StopPoint search API,
+// returning the best matching stop point
+func searchTfLStopPoint(client *http.Client, station string) (tflStopPoint, bool, error) {
+	candidates := StationNameCandidates(station)
+	query := url.Values{"modes": {tflStopPointModes}}
+	if key := os.Getenv("TFL_APP_KEY"); key != "" {
+		query.Set("app_key", key)
+	}
+	reqURL := fmt.Sprintf("%s/StopPoint/Search/%s?%s", tflAPIBase,
+		url.PathEscape(candidates[len(candidates)-1]), query.Encode())
+	resp, err := client.Get(reqURL)
+	if err != nil {
+		return tflStopPoint{}, false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return tflStopPoint{}, false, fmt.Errorf("TfL API returned %s", resp.Status)
+	}
+	var result struct {
+		Matches []tflStopPoint `json:"matches"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return tflStopPoint{}, false, err
+	}
+	// Only accept a match whose name agrees with the station's, since the
+	// search also returns partial matches (e.g. "Bank" finding "Bankside")
+	for _, candidate := range candidates {
+		for _, match := range result.Matches {
+			if normalizeStationName(match.Name) == candidate {
+				return match, true, nil
+			}
+		}
+	}
+	return tflStopPoint{}, false, nil
+}
+
+// Look the specified station up through the TfL StopPoint search API,
+// returning the coordinates of the best matching stop point
+func LookupTfLCoordinates(client *http.Client, station string) (Coordinates, bool, error) {
+	match, found, err := searchTfLStopPoint(client, station)
+	if !found || err != nil {
+		return Coordinates{}, found, err
+	}
+	return Coordinates{match.Lat, match.Lon}, true, nil
+}
+
+// Render the specified station coordinates as the Go source of
+// stationcoords.go, sorted by station name
+func GenerateCoordinatesSource(coords map[string]Coordinates) ([]byte, error) {
+	stations := make([]string, 0, len(coords))
+	for station := range coords {
+		stations = append(stations, station)
+	}
+	slices.Sort(stations)
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by \"tubeplanner import-coords\"; DO NOT EDIT.\n\n")
+	buf.WriteString("package transit\n\n")
+	buf.WriteString("// Return the known coordinates of stations in the transit map\n")
+	buf.WriteString("func GetStationCoordinates() map[string]Coordinates {\n")
+	buf.WriteString("\treturn map[string]Coordinates{\n")
+	for _, station := range stations {
+		fmt.Fprintf(&buf, "\t\t%q: {%s, %s},\n", station,
+			strconv.FormatFloat(coords[station].lat, 'f', 6, 64),
+			strconv.FormatFloat(coords[station].lon, 'f', 6, 64))
+	}
+	buf.WriteString("\t}\n}\n")
+	return format.Source(buf.Bytes())
+}
@@ -0,0 +1,285 @@
+package transit
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// Version of the JSON schema of Journey, to be increased whenever its
+// structure changes in a way existing consumers could notice. Decoding
+// structs for earlier versions are kept in schema.go.
+const JourneySchemaVersion = 3
+
+// Structured form of a planned trip, used for every kind of output. All
+// times are in minutes since the start of the journey, and the legs run on
+// from one another, so their Minutes add up to TotalMinutes. Any parts of
+// the trip relying on assumed rather than actual transit data are described
+// in DataWarnings.
+type Journey struct {
+	SchemaVersion int      `json:"schemaVersion"`
+	From          string   `json:"from"`
+	To            string   `json:"to"`
+	TotalMinutes  uint16   `json:"totalMinutes"`
+	Legs          []Leg    `json:"legs"`
+	DataWarnings  []string `json:"dataWarnings,omitempty"`
+	// Estimated fare, where the caller has one (see EstimateFare)
+	Fare *Fare `json:"fare,omitempty"`
+	// What the trip was planned from, where the caller gives it (see
+	// NewStamp)
+	Stamp *Stamp `json:"stamp,omitempty"`
+}
+
+// Types of the Legs which begin and end every Journey that goes anywhere.
+// The legs between are rides, of type "rail", and interchanges, of the type
+// of the interchange: "line interchange" or "station interchange".
+const (
+	// Entering the station, reaching the platform of the first line and
+	// waiting for its train
+	LegBoard = "board"
+	// Leaving the last train and making for the way out
+	LegAlight = "alight"
+)
+
+// A single part of a Journey: boarding the first train, a ride along one
+// line through one or more stops, an interchange to another line or a
+// nearby station (including the wait for the next train), or alighting at
+// the end, along with any walking guidance the transit data has for it
+type Leg struct {
+	Type     string `json:"type"`
+	Line     string `json:"line,omitempty"`
+	FromLine string `json:"fromLine,omitempty"`
+	ToLine   string `json:"toLine,omitempty"`
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Depart   uint16 `json:"depart"`
+	Arrive   uint16 `json:"arrive"`
+	// Minutes the leg takes, from Depart to Arrive
+	Minutes  uint16 `json:"minutes"`
+	Stops    []Stop `json:"stops,omitempty"`
+	Guidance string `json:"guidance,omitempty"`
+	// How deep the line of a ride runs and whether its trains are
+	// air-conditioned, where known (see LineDetailsText)
+	LineDetails string `json:"lineDetails,omitempty"`
+	// Minutes on foot of an interchange, as the transit data gives them,
+	// leaving out the wait for the next train
+	WalkMinutes uint16 `json:"walkMinutes,omitempty"`
+	// Whether getting on and off the train of a ride needs a manual
+	// boarding ramp from staff, on step-free journeys (see GetBoardingRamps)
+	BoardingRamp  bool `json:"boardingRamp,omitempty"`
+	AlightingRamp bool `json:"alightingRamp,omitempty"`
+
+	// The Node the leg begins at and each link followed from there, as
+	// planned, for the analyses of this package which need more than the
+	// fields above. Boarding and alighting follow no links. These are unset
+	// in a decoded Journey.
+	start *Node
+	hops  []hop
+}
+
+// A link followed during a Leg, between copies of the Nodes at either end
+// holding the travel times they were reached at
+type hop struct {
+	from, to *Node
+	link     *Link
+}
+
+// A station passed during a rail Leg, along with the time it is reached and
+// whether the train runs through without stopping because it is closed
+type Stop struct {
+	Station string `json:"station"`
+	Time    uint16 `json:"time"`
+	Closed  bool   `json:"closed,omitempty"`
+}
+
+// Return whether the leg is a ride on a train
+func (leg *Leg) IsRide() bool {
+	return leg.Type == string(ModeRail)
+}
+
+// Return whether the leg is an interchange, whether between lines or on
+// foot to another station
+func (leg *Leg) IsInterchange() bool {
+	return leg.Type == string(ModeLineInterchange) || leg.Type == string(ModeStationInterchange)
+}
+
+// Return a Journey for a trip which begins at its destination, so has no
+// legs
+func arrivedJourney(station string) *Journey {
+	return &Journey{SchemaVersion: JourneySchemaVersion, From: station, To: station, Legs: make([]Leg, 0)}
+}
+
+// Return a copy of the specified Node holding only the fields describing it,
+// as searches under way elsewhere may be changing the rest
+func detachedNode(node *Node) *Node {
+	return &Node{station: node.station, line: node.line, adj: node.adj, closed: node.closed,
+		closure: node.closure, boardTime: node.boardTime, accessTime: node.accessTime, service: node.service, zone: node.zone,
+		stepFree: node.stepFree, liftTime: node.liftTime, lifts: node.lifts,
+		outages: node.outages, ramp: node.ramp}
+}
+
+// Return the Journey following the specified links from the first of the
+// specified Nodes, as found by a search under the specified search options
+// (which may be nil). Each part is timed afresh from the links and waits
+// alone, so that boarding penalties are not reported as time actually
+// travelled. The Nodes and links are those of the graph, which is left
+// untouched: the Journey keeps copies of the Nodes, so that later searches
+// leave it unchanged. Consecutive rail links are grouped into a single
+// ride, just as the directions group them into a single step. Rides on
+// step-free journeys say where they need a boarding ramp. Journeys setting
+// off on foot to another station leave without a boarding leg, as they
+// neither reach a platform nor wait for a train at the origin.
+func newJourney(route []*Node, links []*Link, opts *SearchOptions) *Journey {
+	first, last := detachedNode(route[0]), detachedNode(route[len(route)-1])
+	journey := arrivedJourney(first.station)
+	journey.To = last.station
+	if len(links) > 0 && links[0].attrs.Mode != ModeStationInterchange {
+		first.totalTime = opts.originBoarding(first).elapsed
+		journey.Legs = append(journey.Legs, Leg{Type: LegBoard, Line: first.line, From: first.station,
+			To: first.station, Arrive: first.totalTime, Minutes: first.totalTime, start: first})
+	}
+	stepFree := opts != nil && opts.StepFree
+
+	from := first
+	for idx, link := range links {
+		to := last
+		if idx < len(links)-1 {
+			to = detachedNode(route[idx+1])
+		}
+		runTime := opts.linkTime(link, from.totalTime)
+		to.totalTime = AddTime(from.totalTime, runTime)
+		if link.attrs.Mode != ModeRail {
+			to.totalTime = AddTime(to.totalTime, opts.changeWait(link, to.totalTime, runTime))
+		}
+		stop := Stop{to.station, to.totalTime, to.closed}
+		if link.attrs.Mode == ModeRail && journey.Legs[len(journey.Legs)-1].IsRide() {
+			prev := &journey.Legs[len(journey.Legs)-1]
+			prev.To, prev.Arrive, prev.Minutes = to.station, to.totalTime, to.totalTime-prev.Depart
+			prev.AlightingRamp = stepFree && to.ramp
+			prev.Stops = append(prev.Stops, stop)
+			prev.hops = append(prev.hops, hop{from, to, link})
+			from = to
+			continue
+		}
+		leg := Leg{Type: string(link.attrs.Mode), From: from.station, To: to.station, Depart: from.totalTime,
+			Arrive: to.totalTime, Minutes: to.totalTime - from.totalTime, start: from,
+			hops: []hop{{from, to, link}}}
+		if link.attrs.Mode == ModeRail {
+			leg.Line = to.line
+			leg.Stops = []Stop{stop}
+			leg.LineDetails = LineDetailsText(to.line)
+			leg.BoardingRamp, leg.AlightingRamp = stepFree && from.ramp, stepFree && to.ramp
+		} else {
+			leg.FromLine, leg.ToLine = from.line, to.line
+			leg.Guidance = InterchangeGuidanceText(from, to)
+			leg.WalkMinutes = link.time
+		}
+		journey.Legs = append(journey.Legs, leg)
+		from = to
+	}
+
+	journey.TotalMinutes = AddTime(last.totalTime, opts.accessTime(last))
+	journey.Legs = append(journey.Legs, Leg{Type: LegAlight, Line: last.line, From: last.station,
+		To: last.station, Depart: last.totalTime, Arrive: journey.TotalMinutes,
+		Minutes: journey.TotalMinutes - last.totalTime, start: last})
+	if flags := DataCoverageFlags(journey); len(flags) > 0 {
+		journey.DataWarnings = flags
+	}
+	return journey
+}
+
+// Return each link followed during the journey in order, as planned, or
+// none if it was decoded rather than planned
+func (journey *Journey) hops() []hop {
+	hops := make([]hop, 0)
+	for _, leg := range journey.Legs {
+		hops = append(hops, leg.hops...)
+	}
+	return hops
+}
+
+// Return each Node visited during the journey in order, as planned, or none
+// if it was decoded rather than planned
+func (journey *Journey) nodes() []*Node {
+	if len(journey.Legs) == 0 || journey.Legs[0].start == nil {
+		return nil
+	}
+	nodes := []*Node{journey.Legs[0].start}
+	for _, hop := range journey.hops() {
+		nodes = append(nodes, hop.to)
+	}
+	return nodes
+}
+
+// Represents a criterion to order journeys by, such as alternative routes
+type JourneyOrder string
+
+const (
+	// Total travel time, quickest first
+	OrderByTime JourneyOrder = "time"
+	// Number of interchanges, fewest first
+	OrderByChanges JourneyOrder = "changes"
+	// Minutes on foot at interchanges, least first
+	OrderByWalk JourneyOrder = "walk"
+	// Estimated fare, cheapest first, with journeys of unknown fare last
+	OrderByFare JourneyOrder = "fare"
+)
+
+// Parse the name of a criterion to order journeys by
+func ParseJourneyOrder(s string) (JourneyOrder, error) {
+	switch order := JourneyOrder(s); order {
+	case OrderByTime, OrderByChanges, OrderByWalk, OrderByFare:
+		return order, nil
+	}
+	return "", fmt.Errorf("unknown sort order %q, expected time, changes, walk or fare", s)
+}
+
+// Return the number of interchanges the journey makes, whether between
+// lines or on foot to another station
+func (journey *Journey) Changes() int {
+	changes := 0
+	for _, leg := range journey.Legs {
+		if leg.IsInterchange() {
+			changes++
+		}
+	}
+	return changes
+}
+
+// Return the minutes the journey spends on foot at interchanges
+func (journey *Journey) WalkingMinutes() uint16 {
+	var walking uint16
+	for _, leg := range journey.Legs {
+		walking = AddTime(walking, leg.WalkMinutes)
+	}
+	return walking
+}
+
+// Compare two journeys by the specified criterion, returning a negative
+// number when the first comes first, as for slices.SortFunc. Ties are broken
+// on total travel time, then on the number of changes.
+func CompareJourneys(a, b *Journey, order JourneyOrder) int {
+	var byOrder int
+	switch order {
+	case OrderByChanges:
+		byOrder = cmp.Compare(a.Changes(), b.Changes())
+	case OrderByWalk:
+		byOrder = cmp.Compare(a.WalkingMinutes(), b.WalkingMinutes())
+	case OrderByFare:
+		fare := func(journey *Journey) int {
+			if journey.Fare == nil {
+				return math.MaxInt
+			}
+			return int(journey.Fare.Pence)
+		}
+		byOrder = cmp.Compare(fare(a), fare(b))
+	}
+	return cmp.Or(byOrder, cmp.Compare(a.TotalMinutes, b.TotalMinutes), cmp.Compare(a.Changes(), b.Changes()))
+}
+
+// Sort the specified journeys by the specified criterion (see
+// CompareJourneys), keeping the order of journeys that tie
+func SortJourneys(journeys []*Journey, order JourneyOrder) {
+	slices.SortStableFunc(journeys, func(a, b *Journey) int { return CompareJourneys(a, b, order) })
+}
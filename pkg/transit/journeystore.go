@@ -0,0 +1,375 @@
+package transit
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Most journeys kept in the history of each user, the oldest being dropped
+// first once there are more
+const MaxJourneyHistory = 500
+
+// Returned by OpenJourneyStore for a kind of store that is planned but not
+// built yet
+var ErrUnsupportedStore = errors.New("unsupported journey store")
+
+// A trip saved under a name, e.g. "home", so that it can be planned again
+// without naming the stations
+type Favourite struct {
+	Name string `json:"name"`
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// A journey planned, as remembered in the journey history
+type HistoryEntry struct {
+	PlannedAt time.Time `json:"plannedAt"`
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	Minutes   uint16    `json:"minutes"`
+	Changes   int       `json:"changes"`
+	Lines     []string  `json:"lines"`
+	RouteHash string    `json:"routeHash"`
+}
+
+// Return the history entry for the specified journey, planned at the
+// specified time
+func NewHistoryEntry(journey *Journey, plannedAt time.Time) HistoryEntry {
+	return HistoryEntry{plannedAt, journey.From, journey.To, journey.TotalMinutes, journey.Changes(),
+		JourneyLines(journey), journey.RouteHash()}
+}
+
+// Keeps the favourite trips and journey history of each of any number of
+// users, identified by name, so the same store can serve one person at the
+// command line (as the user "") or everyone using a server. Implementations
+// must be safe to use from several goroutines at once.
+type JourneyStore interface {
+	// Save the specified favourite, replacing any of the user's favourites
+	// with the same name
+	SaveFavourite(user string, favourite Favourite) error
+	// Remove the user's favourite with the specified name, returning
+	// whether there was one
+	RemoveFavourite(user, name string) (bool, error)
+	// Return the user's favourites, sorted by name
+	Favourites(user string) ([]Favourite, error)
+	// Add the specified journey to the user's history, dropping the oldest
+	// once there are more than MaxJourneyHistory
+	RecordJourney(user string, entry HistoryEntry) error
+	// Return up to the specified number of the user's most recent journeys,
+	// most recent first, or all of them if the number is zero
+	History(user string, limit int) ([]HistoryEntry, error)
+}
+
+// Return the default location of the journey store, inside the user's
+// cache directory (falling back to the working directory)
+func DefaultJourneyStorePath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "tubeplanner-journeys.json"
+	}
+	return filepath.Join(dir, "tubeplanner", "journeys.json")
+}
+
+// Return the journey store at the specified location: a SQLiteJourneyStore
+// for a path ending in .db, .sqlite or .sqlite3, and otherwise a
+// JSONJourneyStore. Stores are created on first write if they do not exist
+// already. A redis:// URL is reserved for a store shared by several
+// servers, which is not supported yet.
+func OpenJourneyStore(location string) (JourneyStore, error) {
+	if strings.HasPrefix(location, "redis://") {
+		return nil, fmt.Errorf("%w: Redis stores are not built in yet", ErrUnsupportedStore)
+	}
+	switch strings.ToLower(filepath.Ext(location)) {
+	case ".db", ".sqlite", ".sqlite3":
+		return &SQLiteJourneyStore{Path: location}, nil
+	}
+	return &JSONJourneyStore{Path: location}, nil
+}
+
+// Return the specified history entries, most recent first, cut down to the
+// specified number unless it is zero
+func recentHistory(entries []HistoryEntry, limit int) []HistoryEntry {
+	slices.SortStableFunc(entries, func(a, b HistoryEntry) int {
+		return b.PlannedAt.Compare(a.PlannedAt)
+	})
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries
+}
+
+// A JourneyStore kept as a single JSON file, rewritten whole on every
+// change, which suits one person at the command line. Changes made by other
+// processes at the same moment may be lost.
+type JSONJourneyStore struct {
+	Path string
+	mu   sync.Mutex
+}
+
+// Represents the contents of a JSONJourneyStore file, by user
+type journeyStoreFile struct {
+	Users map[string]*userJourneys `json:"users"`
+}
+
+// Represents the favourites and history of one user in a JSONJourneyStore
+type userJourneys struct {
+	Favourites []Favourite    `json:"favourites"`
+	History    []HistoryEntry `json:"history"`
+}
+
+// Read the store's file, treating a file that does not exist yet as empty
+func (store *JSONJourneyStore) load() (*journeyStoreFile, error) {
+	contents := &journeyStoreFile{Users: make(map[string]*userJourneys)}
+	data, err := os.ReadFile(store.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return contents, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, contents); err != nil {
+		return nil, fmt.Errorf("%s: %v", store.Path, err)
+	}
+	if contents.Users == nil {
+		contents.Users = make(map[string]*userJourneys)
+	}
+	return contents, nil
+}
+
+// Read the store's file, call the specified function on the specified
+// user's favourites and history, and write the file back if it returns true
+func (store *JSONJourneyStore) update(user string, change func(*userJourneys) bool) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	contents, err := store.load()
+	if err != nil {
+		return err
+	}
+	journeys := contents.Users[user]
+	if journeys == nil {
+		journeys = &userJourneys{Favourites: make([]Favourite, 0), History: make([]HistoryEntry, 0)}
+		contents.Users[user] = journeys
+	}
+	if !change(journeys) {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(store.Path), 0o755); err != nil {
+		return err
+	}
+	return WriteFileAtomic(store.Path, func(w io.Writer) error {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(contents)
+	})
+}
+
+// Return a copy of the specified user's favourites and history
+func (store *JSONJourneyStore) read(user string) (userJourneys, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	contents, err := store.load()
+	if err != nil || contents.Users[user] == nil {
+		return userJourneys{}, err
+	}
+	return *contents.Users[user], nil
+}
+
+// Save the specified favourite in the user's list, kept sorted by name,
+// replacing any with the same name
+func (store *JSONJourneyStore) SaveFavourite(user string, favourite Favourite) error {
+	return store.update(user, func(journeys *userJourneys) bool {
+		journeys.Favourites = slices.DeleteFunc(journeys.Favourites, func(f Favourite) bool {
+			return f.Name == favourite.Name
+		})
+		journeys.Favourites = append(journeys.Favourites, favourite)
+		slices.SortFunc(journeys.Favourites, func(a, b Favourite) int { return cmp.Compare(a.Name, b.Name) })
+		return true
+	})
+}
+
+// Remove the user's favourite with the specified name, writing the file
+// back only if there was one
+func (store *JSONJourneyStore) RemoveFavourite(user, name string) (bool, error) {
+	removed := false
+	err := store.update(user, func(journeys *userJourneys) bool {
+		before := len(journeys.Favourites)
+		journeys.Favourites = slices.DeleteFunc(journeys.Favourites, func(f Favourite) bool {
+			return f.Name == name
+		})
+		removed = len(journeys.Favourites) < before
+		return removed
+	})
+	return removed, err
+}
+
+// Return a copy of the user's favourites, sorted by name
+func (store *JSONJourneyStore) Favourites(user string) ([]Favourite, error) {
+	journeys, err := store.read(user)
+	if err != nil {
+		return nil, err
+	}
+	return append(make([]Favourite, 0), journeys.Favourites...), nil
+}
+
+// Record the specified journey at the end of the user's history, trimming
+// the oldest beyond MaxJourneyHistory
+func (store *JSONJourneyStore) RecordJourney(user string, entry HistoryEntry) error {
+	return store.update(user, func(journeys *userJourneys) bool {
+		journeys.History = append(journeys.History, entry)
+		if excess := len(journeys.History) - MaxJourneyHistory; excess > 0 {
+			journeys.History = slices.Delete(journeys.History, 0, excess)
+		}
+		return true
+	})
+}
+
+// Return up to the specified number of the user's most recent journeys,
+// most recent first, or all of them if the number is zero
+func (store *JSONJourneyStore) History(user string, limit int) ([]HistoryEntry, error) {
+	journeys, err := store.read(user)
+	if err != nil {
+		return nil, err
+	}
+	return recentHistory(append(make([]HistoryEntry, 0), journeys.History...), limit), nil
+}
+
+// Layout of the times journeys were planned in a SQLiteJourneyStore, in UTC
+// with every digit given, so that they sort as text in time order
+const historyTimeLayout = "2006-01-02T15:04:05.000000000Z"
+
+// Tables of a SQLiteJourneyStore, created on first use. The timeout has a
+// write wait up to five seconds for any other process writing to the store,
+// e.g. another server, rather than fail straight away.
+const journeyStoreSchema = `.timeout 5000
+CREATE TABLE IF NOT EXISTS favourites (
+	user TEXT NOT NULL,
+	name TEXT NOT NULL,
+	from_station TEXT NOT NULL,
+	to_station TEXT NOT NULL,
+	PRIMARY KEY (user, name)
+);
+CREATE TABLE IF NOT EXISTS history (
+	id INTEGER PRIMARY KEY,
+	user TEXT NOT NULL,
+	planned_at TEXT NOT NULL,
+	from_station TEXT NOT NULL,
+	to_station TEXT NOT NULL,
+	minutes INTEGER NOT NULL,
+	changes INTEGER NOT NULL,
+	lines TEXT NOT NULL,
+	route_hash TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS history_by_user ON history (user, planned_at);
+`
+
+// A JourneyStore kept in a SQLite database, which several processes can
+// share safely, e.g. a server and the command line. The database is read
+// and written with the sqlite3 command, which must be installed. Lines
+// ridden are stored separated by "|".
+type SQLiteJourneyStore struct {
+	Path string
+}
+
+// Run the specified SQL against the store, creating the database and its
+// tables first if needed
+func (store *SQLiteJourneyStore) exec(sql string) ([][]string, error) {
+	if err := os.MkdirAll(filepath.Dir(store.Path), 0o755); err != nil {
+		return nil, err
+	}
+	return runSQLite(store.Path, journeyStoreSchema+sql, true)
+}
+
+// Save the specified favourite, replacing any row for the user with the
+// same name
+func (store *SQLiteJourneyStore) SaveFavourite(user string, favourite Favourite) error {
+	_, err := store.exec(fmt.Sprintf("INSERT OR REPLACE INTO favourites VALUES (%s, %s, %s, %s);\n",
+		sqlQuote(user), sqlQuote(favourite.Name), sqlQuote(favourite.From), sqlQuote(favourite.To)))
+	return err
+}
+
+// Remove the user's favourite with the specified name, returning whether a
+// row was deleted
+func (store *SQLiteJourneyStore) RemoveFavourite(user, name string) (bool, error) {
+	rows, err := store.exec(fmt.Sprintf("DELETE FROM favourites WHERE user = %s AND name = %s;\n"+
+		"SELECT changes();\n", sqlQuote(user), sqlQuote(name)))
+	return err == nil && len(rows) == 1 && rows[0][0] != "0", err
+}
+
+// Return the user's favourites, sorted by name by the database
+func (store *SQLiteJourneyStore) Favourites(user string) ([]Favourite, error) {
+	rows, err := store.exec(fmt.Sprintf("SELECT name, from_station, to_station FROM favourites "+
+		"WHERE user = %s ORDER BY name;\n", sqlQuote(user)))
+	if err != nil {
+		return nil, err
+	}
+	favourites := make([]Favourite, 0, len(rows))
+	for _, row := range rows {
+		if len(row) != 3 {
+			return nil, fmt.Errorf("%s: malformed favourite %q", store.Path, row)
+		}
+		favourites = append(favourites, Favourite{row[0], row[1], row[2]})
+	}
+	return favourites, nil
+}
+
+// Record the specified journey in the user's history, deleting the oldest
+// beyond MaxJourneyHistory in the same transaction
+func (store *SQLiteJourneyStore) RecordJourney(user string, entry HistoryEntry) error {
+	_, err := store.exec(fmt.Sprintf("BEGIN;\n"+
+		"INSERT INTO history (user, planned_at, from_station, to_station, minutes, changes, lines, route_hash) "+
+		"VALUES (%[1]s, %[2]s, %[3]s, %[4]s, %[5]d, %[6]d, %[7]s, %[8]s);\n"+
+		"DELETE FROM history WHERE user = %[1]s AND id NOT IN "+
+		"(SELECT id FROM history WHERE user = %[1]s ORDER BY planned_at DESC, id DESC LIMIT %[9]d);\n"+
+		"COMMIT;\n", sqlQuote(user), sqlQuote(entry.PlannedAt.UTC().Format(historyTimeLayout)),
+		sqlQuote(entry.From), sqlQuote(entry.To), entry.Minutes, entry.Changes,
+		sqlQuote(strings.Join(entry.Lines, "|")), sqlQuote(entry.RouteHash), MaxJourneyHistory))
+	return err
+}
+
+// Return up to the specified number of the user's most recent journeys, or
+// all of them if the number is zero, in the order the database sorts them:
+// most recent first
+func (store *SQLiteJourneyStore) History(user string, limit int) ([]HistoryEntry, error) {
+	sql := fmt.Sprintf("SELECT planned_at, from_station, to_station, minutes, changes, lines, route_hash "+
+		"FROM history WHERE user = %s ORDER BY planned_at DESC, id DESC", sqlQuote(user))
+	if limit > 0 {
+		sql += fmt.Sprintf(" LIMIT %d", limit)
+	}
+	rows, err := store.exec(sql + ";\n")
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]HistoryEntry, 0, len(rows))
+	for _, row := range rows {
+		if len(row) != 7 {
+			return nil, fmt.Errorf("%s: malformed history entry %q", store.Path, row)
+		}
+		plannedAt, err := time.Parse(historyTimeLayout, row[0])
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid time of history entry: %v", store.Path, err)
+		}
+		minutes, err := strconv.ParseUint(row[3], 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid minutes of history entry: %v", store.Path, err)
+		}
+		changes, err := strconv.Atoi(row[4])
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid changes of history entry: %v", store.Path, err)
+		}
+		lines := make([]string, 0)
+		if row[5] != "" {
+			lines = strings.Split(row[5], "|")
+		}
+		entries = append(entries, HistoryEntry{plannedAt, row[1], row[2], uint16(minutes), changes, lines, row[6]})
+	}
+	return entries, nil
+}
@@ -0,0 +1,72 @@
+package transit
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Column headings of a journey's leg table
+var LegTableHeader = []string{"Leg", "From", "To", "Line/walk", "Depart", "Arrive", "Minutes"}
+
+// Return one row of the leg table of the specified journey for each of its
+// legs, as laid out by LegTableHeader, for people who plan trips in
+// spreadsheets. Times are clock times counting from the specified departure
+// time, or minutes into the journey if it is zero.
+func JourneyLegRows(journey *Journey, departAt time.Time) [][]string {
+	clock := func(minutes uint16) string {
+		if departAt.IsZero() {
+			return strconv.Itoa(int(minutes))
+		}
+		return departAt.Add(time.Duration(minutes) * time.Minute).Format("15:04")
+	}
+	rows := make([][]string, 0, len(journey.Legs))
+	for idx, leg := range journey.Legs {
+		var mode string
+		switch leg.Type {
+		case LegBoard:
+			mode = fmt.Sprintf("Wait for the %s line", leg.Line)
+		case string(ModeRail):
+			mode = leg.Line
+		case string(ModeLineInterchange):
+			mode = fmt.Sprintf("Change to the %s line", leg.ToLine)
+		case LegAlight:
+			mode = "Leave the station"
+		default:
+			mode = "Walk"
+		}
+		rows = append(rows, []string{strconv.Itoa(idx + 1), leg.From, leg.To, mode, clock(leg.Depart),
+			clock(leg.Arrive), strconv.Itoa(int(leg.Minutes))})
+	}
+	return rows
+}
+
+// Return the leg table of the specified journey as a Markdown table, with
+// times as for JourneyLegRows
+func JourneyMarkdown(journey *Journey, departAt time.Time) string {
+	var sb strings.Builder
+	writeRow := func(cells []string) {
+		for _, cell := range cells {
+			sb.WriteString("| " + strings.ReplaceAll(cell, "|", `\|`) + " ")
+		}
+		sb.WriteString("|\n")
+	}
+	writeRow(LegTableHeader)
+	sb.WriteString(strings.Repeat("| --- ", len(LegTableHeader)) + "|\n")
+	for _, row := range JourneyLegRows(journey, departAt) {
+		writeRow(row)
+	}
+	return sb.String()
+}
+
+// Write the leg table of the specified journey as CSV to the specified
+// writer, with a header row and times as for JourneyLegRows
+func WriteJourneyCSV(w io.Writer, journey *Journey, departAt time.Time) error {
+	cw := csv.NewWriter(w)
+	cw.Write(LegTableHeader)
+	cw.WriteAll(JourneyLegRows(journey, departAt))
+	return cw.Error()
+}
@@ -0,0 +1,160 @@
+package transit
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Represents a lift out of service, breaking the step-free ways through its
+// station which depend on it
+type LiftOutage struct {
+	Lift   Lift
+	Until  time.Time
+	Reason string
+}
+
+// Return the default location of the lift outages file, inside the user's
+// config directory (falling back to the working directory)
+func DefaultLiftOutagesPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "lift-outages.csv"
+	}
+	return filepath.Join(dir, "tubeplanner", "lift-outages.csv")
+}
+
+// Return whether the outage is still in effect at the specified time. An
+// outage without an end date lasts until removed from the file, and one
+// with an end date lasts until the end of that day.
+func (lo LiftOutage) ActiveAt(t time.Time) bool {
+	return lo.Until.IsZero() || t.Before(lo.Until.AddDate(0, 0, 1))
+}
+
+// Describe a lift outage for messages, e.g. "the Jubilee lift at Green Park
+// is out of service until 2026-11-30 (refurbishment)"
+func (lo LiftOutage) Describe() string {
+	desc := fmt.Sprintf("the %s at %s is out of service", lo.Lift.Name, lo.Lift.Station)
+	if !lo.Until.IsZero() {
+		desc += " until " + lo.Until.Format("2006-01-02")
+	}
+	if lo.Reason != "" {
+		desc += " (" + lo.Reason + ")"
+	}
+	return desc
+}
+
+// Read lift outages from the specified CSV file, one per line in the form
+// "station,lift[,until[,reason]]", where lift is the lift's name at the
+// station as listed in GetLiftDependencies and until is a date in YYYY-MM-DD
+// form. Lines starting with # are ignored.
+func LoadLiftOutages(path string) ([]LiftOutage, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	outages := make([]LiftOutage, 0)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		lineNum, _ := reader.FieldPos(0)
+		if len(record) < 2 || len(record) > 4 {
+			return nil, fmt.Errorf("%s:%d: expected station,lift[,until[,reason]]", path, lineNum)
+		}
+		outage := LiftOutage{Lift: Lift{strings.TrimSpace(record[0]), strings.TrimSpace(record[1])}}
+		if len(record) > 2 && strings.TrimSpace(record[2]) != "" {
+			outage.Until, err = time.ParseInLocation("2006-01-02", strings.TrimSpace(record[2]), time.Local)
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: invalid date %q", path, lineNum, record[2])
+			}
+		}
+		if len(record) > 3 {
+			outage.Reason = strings.TrimSpace(record[3])
+		}
+		outages = append(outages, outage)
+	}
+	return outages, nil
+}
+
+// Apply the lift outages in effect at the specified time to the graph: the
+// platforms reached from the street by an affected lift lose their step-free
+// access, as do the interchanges depending on it, while other ways through
+// the same station stay step-free
+func ApplyLiftOutages(nodeMap NodeMap, outages []LiftOutage, now time.Time) error {
+	known := make(map[Lift]bool)
+	for _, ld := range GetLiftDependencies() {
+		for _, name := range ld.lifts {
+			known[Lift{ld.station, name}] = true
+		}
+	}
+	broken := make(map[Lift]bool)
+	active := make([]LiftOutage, 0)
+	for _, outage := range outages {
+		if !known[outage.Lift] {
+			return fmt.Errorf("%s has no lift called %q", outage.Lift.Station, outage.Lift.Name)
+		}
+		if outage.ActiveAt(now) {
+			broken[outage.Lift] = true
+			active = append(active, outage)
+		}
+	}
+	if len(broken) == 0 {
+		return nil
+	}
+	dependsOnBroken := func(lifts []Lift) bool {
+		return slices.ContainsFunc(lifts, func(lift Lift) bool { return broken[lift] })
+	}
+	for _, nodes := range nodeMap {
+		for _, node := range nodes {
+			if dependsOnBroken(node.lifts) {
+				node.stepFree = false
+				node.outages = slices.DeleteFunc(slices.Clone(active), func(outage LiftOutage) bool {
+					return !slices.Contains(node.lifts, outage.Lift)
+				})
+			}
+			for _, link := range node.adj {
+				if link.attrs.Mode != ModeRail && dependsOnBroken(link.attrs.Lifts) {
+					link.attrs.StepFree = false
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// If the step-free access to the specified station's platforms has been
+// broken by lift outages, return a description of them suitable for error
+// messages
+func StepFreeOutage(nodeMap NodeMap, station string) (string, bool) {
+	descs := make([]string, 0)
+	for _, node := range nodeMap[station] {
+		for _, outage := range node.outages {
+			if desc := outage.Describe(); !slices.Contains(descs, desc) {
+				descs = append(descs, desc)
+			}
+		}
+	}
+	if len(descs) == 0 {
+		return "", false
+	}
+	return fmt.Sprintf("%s has no step-free access to its platforms while %s",
+		station, strings.Join(descs, " and ")), true
+}
@@ -0,0 +1,68 @@
+package transit
+
+import (
+	"fmt"
+	"os"
+)
+
+// Whether PrintDirections and DirectionLines show each line's name in the
+// line's colour (see GetLineColours), using ANSI escape codes. Off unless
+// set, e.g. when writing to a terminal.
+var ColourDirections bool
+
+// ANSI escape code turning colours off again
+const ansiReset = "\x1b[0m"
+
+// Represents the colour of a line on the Tube map
+type LineColour struct {
+	R, G, B uint8
+}
+
+// Return whether text on the colour is easier to read in black than white
+func (c LineColour) light() bool {
+	return 299*int(c.R)+587*int(c.G)+114*int(c.B) > 150_000
+}
+
+// Return the specified line name as it appears on a terminal in its colour,
+// as a label in the line's colour with black or white text, whichever reads
+// better, or just the name for lines with no known colour
+func ColourLineName(line string) string {
+	c, known := GetLineColours()[line]
+	if !known {
+		return line
+	}
+	text := "97"
+	if c.light() {
+		text = "30"
+	}
+	return fmt.Sprintf("\x1b[48;2;%d;%d;%dm\x1b[%sm%s%s", c.R, c.G, c.B, text, line, ansiReset)
+}
+
+// Return whether colours should be used when writing to the specified file:
+// only if it is a terminal, and the NO_COLOR environment variable is unset
+// (see https://no-color.org) and TERM does not name a dumb terminal
+func ColourSupported(file *os.File) bool {
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// Words directions as the DirectionPhrases embedded do, with each line's
+// name shown in its colour (see ColourLineName)
+type ColourPhrases struct {
+	DirectionPhrases
+}
+
+func (p ColourPhrases) Board(step int, line string) string {
+	return p.DirectionPhrases.Board(step, ColourLineName(line))
+}
+
+func (p ColourPhrases) RequestRamp(step int, station, line string, boarding bool, alightAt string) string {
+	return p.DirectionPhrases.RequestRamp(step, station, ColourLineName(line), boarding, alightAt)
+}
+
+func (p ColourPhrases) ChangeLines(step int, station, line string, minutes uint16) string {
+	return p.DirectionPhrases.ChangeLines(step, station, ColourLineName(line), minutes)
+}
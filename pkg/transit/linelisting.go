@@ -0,0 +1,108 @@
+package transit
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Return the names of every line in the graph, sorted
+func (g *Graph) LineNames() []string {
+	seen := make(map[string]bool)
+	for _, lines := range g.nodeMap {
+		for line := range lines {
+			seen[line] = true
+		}
+	}
+	names := make([]string, 0, len(seen))
+	for line := range seen {
+		names = append(names, line)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Return the names of the stations served by the specified line, sorted, or
+// nil if no station in the graph is
+func (g *Graph) StationsOn(line string) []string {
+	var stations []string
+	for station, lines := range g.nodeMap {
+		if _, exists := lines[line]; exists {
+			stations = append(stations, station)
+		}
+	}
+	slices.Sort(stations)
+	return stations
+}
+
+// Return the stations of the specified line in the order its trains call at
+// them, as sections running between the line's termini and the junctions
+// where it branches. A line without branches is a single section from the
+// terminus first by name to the other, and a loop, such as the Circle line's,
+// is a section beginning and ending at the same station. Returns nil if no
+// station in the graph is served by the line.
+func (g *Graph) LineSections(line string) [][]string {
+	neighbours := make(map[string][]string)
+	for _, station := range g.StationsOn(line) {
+		next := make([]string, 0, 2)
+		for _, link := range g.nodeMap[station][line].adj {
+			if link.attrs.Mode == ModeRail && link.endNode.line == line &&
+				!slices.Contains(next, link.endNode.station) {
+				next = append(next, link.endNode.station)
+			}
+		}
+		slices.Sort(next)
+		neighbours[station] = next
+	}
+	// Sections begin at the termini, then at the junctions, each in order by
+	// name, with whatever is left after them being loops
+	starts := make([]string, 0)
+	for station, next := range neighbours {
+		if len(next) != 2 {
+			starts = append(starts, station)
+		}
+	}
+	slices.SortFunc(starts, func(a, b string) int {
+		return cmp.Or(cmp.Compare(min(len(neighbours[a]), 2), min(len(neighbours[b]), 2)), cmp.Compare(a, b))
+	})
+	type track struct{ from, to string }
+	travelled := make(map[track]bool)
+	follow := func(from, to string) []string {
+		section := []string{from}
+		for {
+			travelled[track{from, to}], travelled[track{to, from}] = true, true
+			section = append(section, to)
+			if len(neighbours[to]) != 2 {
+				return section
+			}
+			from, to = to, neighbours[to][0]
+			if to == section[len(section)-2] {
+				to = neighbours[from][1]
+			}
+			if travelled[track{from, to}] {
+				return section
+			}
+		}
+	}
+	sections := make([][]string, 0)
+	for _, station := range starts {
+		if len(neighbours[station]) == 0 {
+			sections = append(sections, []string{station})
+		}
+		for _, next := range neighbours[station] {
+			if !travelled[track{station, next}] {
+				sections = append(sections, follow(station, next))
+			}
+		}
+	}
+	for _, station := range g.StationsOn(line) {
+		for _, next := range neighbours[station] {
+			if !travelled[track{station, next}] {
+				sections = append(sections, follow(station, next))
+			}
+		}
+	}
+	if len(sections) == 0 {
+		return nil
+	}
+	return sections
+}
@@ -0,0 +1,119 @@
+package transit
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Represents the user's own time for a rail link or an interchange, such as
+// a change they know always takes them longer than the transit data says,
+// replacing its time in both directions. A rail link has the same line at
+// both ends.
+type LinkTimeOverride struct {
+	From     string
+	FromLine string
+	To       string
+	ToLine   string
+	Minutes  uint16
+}
+
+// Return the default location of the link times file, inside the user's
+// config directory (falling back to the working directory)
+func DefaultLinkTimesPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "link-times.csv"
+	}
+	return filepath.Join(dir, "tubeplanner", "link-times.csv")
+}
+
+// Read link time overrides from the specified CSV file, one per line in the
+// form "from,to,line,minutes" for a rail link, or
+// "from,fromLine,to,toLine,minutes" for an interchange, between lines at one
+// station or on foot to another. Lines starting with # are ignored.
+func LoadLinkTimes(path string) ([]LinkTimeOverride, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	overrides := make([]LinkTimeOverride, 0)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		lineNum, _ := reader.FieldPos(0)
+		for idx := range record {
+			record[idx] = strings.TrimSpace(record[idx])
+		}
+		var override LinkTimeOverride
+		switch len(record) {
+		case 4:
+			override = LinkTimeOverride{record[0], record[2], record[1], record[2], 0}
+		case 5:
+			override = LinkTimeOverride{record[0], record[1], record[2], record[3], 0}
+		default:
+			return nil, fmt.Errorf("%s:%d: expected from,to,line,minutes or from,fromLine,to,toLine,minutes",
+				path, lineNum)
+		}
+		if override.Minutes, err = parseCSVMinutes(record[len(record)-1]); err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", path, lineNum, err)
+		}
+		overrides = append(overrides, override)
+	}
+	return overrides, nil
+}
+
+// Replace the times of the links between the ends of each of the specified
+// overrides, in both directions, with later overrides of the same link
+// taking precedence. The override's time is all the link takes, so any run
+// times by time band or extra dwell time it had are dropped, and the time
+// no longer counts as assumed. It is an error for an override to name a
+// station, or line at a station, not in the graph, or two ends with no link
+// between them.
+func ApplyLinkTimes(nodeMap NodeMap, overrides []LinkTimeOverride) error {
+	for _, override := range overrides {
+		from, to := nodeMap[override.From][override.FromLine], nodeMap[override.To][override.ToLine]
+		for _, end := range []struct {
+			node          *Node
+			station, line string
+		}{{from, override.From, override.FromLine}, {to, override.To, override.ToLine}} {
+			if _, exists := nodeMap[end.station]; !exists {
+				return fmt.Errorf("%s is not a valid station", end.station)
+			}
+			if end.node == nil {
+				return fmt.Errorf("the %s line does not serve %s", end.line, end.station)
+			}
+		}
+		found := false
+		for _, pair := range [][2]*Node{{from, to}, {to, from}} {
+			for _, link := range pair[0].adj {
+				if link.endNode == pair[1] {
+					link.time = override.Minutes
+					link.attrs.BandTimes, link.attrs.Dwell, link.attrs.Assumed = nil, 0, false
+					found = true
+				}
+			}
+		}
+		if !found {
+			return fmt.Errorf("no link between %s (%s) and %s (%s)", override.From, override.FromLine,
+				override.To, override.ToLine)
+		}
+	}
+	return nil
+}
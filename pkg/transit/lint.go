@@ -0,0 +1,208 @@
+package transit
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// Represents a problem found in a dataset by LintDataset, along with how it
+// was fixed, or an empty Fix if it could not safely be fixed and is left for
+// the dataset's editor
+type LintIssue struct {
+	Message string
+	Fix     string
+}
+
+// Return the form of the specified name compared when looking for names
+// which differ only in case or spacing
+func foldName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// Return the spelling each name should be normalized to, for names which
+// differ only in case or spacing from others used more often (or as often,
+// but earlier), along with issues describing each renaming. The names are
+// given in the order they are used in the dataset, once per use.
+func normalizeNames(kind string, uses []string) (map[string]string, []LintIssue) {
+	counts, order := make(map[string]int), make([]string, 0)
+	for _, name := range uses {
+		if counts[name] == 0 {
+			order = append(order, name)
+		}
+		counts[name]++
+	}
+	best := make(map[string]string)
+	for _, name := range order {
+		key := foldName(name)
+		if known, exists := best[key]; !exists || counts[name] > counts[known] {
+			best[key] = name
+		}
+	}
+	renames, issues := make(map[string]string), make([]LintIssue, 0)
+	for _, name := range order {
+		canonical := strings.Join(strings.Fields(best[foldName(name)]), " ")
+		if name == canonical {
+			continue
+		}
+		renames[name] = canonical
+		issues = append(issues, LintIssue{
+			fmt.Sprintf("%s %q differs only in case or spacing from %q", kind, name, canonical),
+			fmt.Sprintf("renamed in %d %s", counts[name], plural(counts[name], "entry", "entries")),
+		})
+	}
+	return renames, issues
+}
+
+// Return the singular or plural form of a noun to go with the specified count
+func plural(n int, singular, plural string) string {
+	if n == 1 {
+		return singular
+	}
+	return plural
+}
+
+// Return the times in the specified list as text, e.g. "2 and 3 minutes"
+func describeTimes(times []uint16) string {
+	strs := make([]string, len(times))
+	for idx, t := range times {
+		strs[idx] = fmt.Sprint(t)
+	}
+	return strings.Join(strs[:len(strs)-1], ", ") + " and " + strs[len(strs)-1] + " minutes"
+}
+
+// Remove connections listed more than once, in either direction (as every
+// connection runs both ways), with the same time from the specified list,
+// given the key identifying each connection regardless of direction or time
+// and its description. Connections listed more than once with different
+// times are kept as they are, and reported. Returns the connections kept and
+// issues describing each duplicate.
+func dedupeConnections[T any](conns []T, key func(T) string, describe func(T) string,
+	transitTime func(T) uint16) ([]T, []LintIssue) {
+	times, order := make(map[string][]uint16), make([]string, 0)
+	first := make(map[string]T)
+	kept := make([]T, 0, len(conns))
+	for _, conn := range conns {
+		k := key(conn)
+		if _, seen := times[k]; !seen {
+			order = append(order, k)
+			first[k] = conn
+		}
+		if slices.Contains(times[k], transitTime(conn)) {
+			times[k] = append(times[k], transitTime(conn))
+			continue
+		}
+		times[k] = append(times[k], transitTime(conn))
+		kept = append(kept, conn)
+	}
+	issues := make([]LintIssue, 0)
+	for _, k := range order {
+		if len(times[k]) == 1 {
+			continue
+		}
+		distinct := slices.Compact(slices.Sorted(slices.Values(times[k])))
+		if len(distinct) > 1 {
+			issues = append(issues, LintIssue{fmt.Sprintf("%s is listed %d times, with %s",
+				describe(first[k]), len(times[k]), describeTimes(distinct)), ""})
+			continue
+		}
+		removed := len(times[k]) - 1
+		issues = append(issues, LintIssue{fmt.Sprintf("%s is listed %d times", describe(first[k]), len(times[k])),
+			fmt.Sprintf("removed %d %s", removed, plural(removed, "duplicate", "duplicates"))})
+	}
+	return kept, issues
+}
+
+// Check the specified rail links and interchanges for problems which the
+// strict checks of ParseYAMLDataset do not catch, fixing those which can be
+// fixed safely. Station and line names differing only in case or spacing are
+// normalized to the spelling used most, and connections listed more than
+// once with the same time, in either direction, are removed. Connections
+// listed more than once with different times, and interchanges at
+// station/line pairs no rail link serves, are reported but left as they are.
+// Returns the fixed rail links and interchanges, and every issue found.
+func LintDataset(railLinks []RailLink, interchanges []Interchange) ([]RailLink, []Interchange, []LintIssue) {
+	stationUses, lineUses := make([]string, 0), make([]string, 0)
+	for _, rl := range railLinks {
+		stationUses = append(stationUses, rl.fromStation, rl.toStation)
+		lineUses = append(lineUses, rl.line)
+	}
+	for _, ic := range interchanges {
+		stationUses = append(stationUses, ic.fromStation, ic.toStation)
+		lineUses = append(lineUses, ic.fromLine, ic.toLine)
+	}
+	stationRenames, issues := normalizeNames("station", stationUses)
+	lineRenames, lineIssues := normalizeNames("line", lineUses)
+	issues = append(issues, lineIssues...)
+	rename := func(name string, renames map[string]string) string {
+		if canonical, exists := renames[name]; exists {
+			return canonical
+		}
+		return name
+	}
+
+	fixedLinks := make([]RailLink, len(railLinks))
+	for idx, rl := range railLinks {
+		fixedLinks[idx] = RailLink{rename(rl.fromStation, stationRenames), rename(rl.toStation, stationRenames),
+			rename(rl.line, lineRenames), rl.transitTime}
+	}
+	fixedInterchanges := make([]Interchange, len(interchanges))
+	for idx, ic := range interchanges {
+		fixedInterchanges[idx] = Interchange{rename(ic.fromStation, stationRenames), rename(ic.fromLine, lineRenames),
+			rename(ic.toStation, stationRenames), rename(ic.toLine, lineRenames), ic.transitTime}
+	}
+
+	fixedLinks, linkIssues := dedupeConnections(fixedLinks,
+		func(rl RailLink) string {
+			ends := []string{rl.fromStation, rl.toStation}
+			slices.Sort(ends)
+			return strings.Join(append(ends, rl.line), "\x00")
+		},
+		func(rl RailLink) string {
+			return fmt.Sprintf("rail link %s to %s (%s)", rl.fromStation, rl.toStation, rl.line)
+		},
+		func(rl RailLink) uint16 { return rl.transitTime })
+	issues = append(issues, linkIssues...)
+	fixedInterchanges, interchangeIssues := dedupeConnections(fixedInterchanges,
+		func(ic Interchange) string {
+			ends := []string{ic.fromStation + "\x00" + ic.fromLine, ic.toStation + "\x00" + ic.toLine}
+			slices.Sort(ends)
+			return strings.Join(ends, "\x00")
+		},
+		func(ic Interchange) string {
+			return fmt.Sprintf("interchange from %s (%s) to %s (%s)", ic.fromStation, ic.fromLine,
+				ic.toStation, ic.toLine)
+		},
+		func(ic Interchange) uint16 { return ic.transitTime })
+	issues = append(issues, interchangeIssues...)
+
+	served := make(map[[2]string]bool)
+	for _, rl := range fixedLinks {
+		served[[2]string{rl.fromStation, rl.line}] = true
+		served[[2]string{rl.toStation, rl.line}] = true
+	}
+	reported := make(map[[2]string]bool)
+	for _, ic := range fixedInterchanges {
+		for _, end := range [][2]string{{ic.fromStation, ic.fromLine}, {ic.toStation, ic.toLine}} {
+			if !served[end] && !reported[end] {
+				reported[end] = true
+				issues = append(issues, LintIssue{
+					fmt.Sprintf("no rail link serves %s on the %s line, which an interchange uses", end[0], end[1]), ""})
+			}
+		}
+	}
+	return fixedLinks, fixedInterchanges, issues
+}
+
+// Read a YAML dataset as ParseYAMLDataset does, but without rejecting
+// interchanges at station/line pairs no rail link serves, as those may only
+// be misspelt, and lint it with LintDataset
+func LintYAMLDataset(r io.Reader) ([]RailLink, []Interchange, []LintIssue, error) {
+	railLinks, interchanges, err := parseYAMLDataset(r, false)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	railLinks, interchanges, issues := LintDataset(railLinks, interchanges)
+	return railLinks, interchanges, issues, nil
+}
@@ -0,0 +1,168 @@
+package transit
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Names of the lines in the transit data for TfL line ids which differ from
+// the line's name. The Overground's named lines are reported separately by
+// TfL, but are a single line in the transit data.
+var tflLineNames = map[string]string{
+	"dlr":         "Docklands Light Railway",
+	"elizabeth":   "Elizabeth",
+	"tram":        "Tramlink",
+	"liberty":     "Overground",
+	"lioness":     "Overground",
+	"mildmay":     "Overground",
+	"suffragette": "Overground",
+	"weaver":      "Overground",
+	"windrush":    "Overground",
+}
+
+// Severity recorded for a line made up of several TfL lines when only some
+// of them are closed, so that the line as a whole is not treated as closed
+const partClosureSeverity = "Part Suspended"
+
+// Fetch the current status of every line from the TfL Unified API, as of the
+// specified time. Where TfL reports several statuses for a line, the most
+// severe is kept, and the Overground's named lines are merged into one.
+func FetchLineStatuses(client *http.Client, now time.Time) ([]LineStatus, error) {
+	query := url.Values{}
+	if key := os.Getenv("TFL_APP_KEY"); key != "" {
+		query.Set("app_key", key)
+	}
+	reqURL := fmt.Sprintf("%s/Line/Mode/%s/Status?%s", tflAPIBase, tflStopPointModes, query.Encode())
+	resp, err := client.Get(reqURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("TfL API returned %s", resp.Status)
+	}
+	var result []struct {
+		ID           string `json:"id"`
+		Name         string `json:"name"`
+		LineStatuses []struct {
+			// Lower severities are worse, with 10 a good service
+			StatusSeverity            int    `json:"statusSeverity"`
+			StatusSeverityDescription string `json:"statusSeverityDescription"`
+			Reason                    string `json:"reason"`
+			ValidityPeriods           []struct {
+				ToDate time.Time `json:"toDate"`
+				IsNow  bool      `json:"isNow"`
+			} `json:"validityPeriods"`
+		} `json:"lineStatuses"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	byLine := make(map[string][]LineStatus)
+	for _, tflLine := range result {
+		if len(tflLine.LineStatuses) == 0 {
+			continue
+		}
+		worst := tflLine.LineStatuses[0]
+		for _, ls := range tflLine.LineStatuses[1:] {
+			if ls.StatusSeverity < worst.StatusSeverity {
+				worst = ls
+			}
+		}
+		line, renamed := tflLineNames[tflLine.ID]
+		if !renamed {
+			line = tflLine.Name
+		}
+		status := LineStatus{Line: line, Severity: worst.StatusSeverityDescription,
+			Reason: strings.TrimSpace(worst.Reason), FetchedAt: now}
+		for _, period := range worst.ValidityPeriods {
+			if period.IsNow {
+				status.ValidUntil = period.ToDate
+			}
+		}
+		byLine[line] = append(byLine[line], status)
+	}
+
+	statuses := make([]LineStatus, 0, len(byLine))
+	for _, line := range slices.Sorted(maps.Keys(byLine)) {
+		statuses = append(statuses, mergeLineStatuses(byLine[line]))
+	}
+	return statuses, nil
+}
+
+// Return a single status for a line reported by TfL as several: the most
+// delayed of them, except that the line is only closed if all of them are
+func mergeLineStatuses(parts []LineStatus) LineStatus {
+	merged, closures := parts[0], 0
+	for _, part := range parts {
+		if part.IsClosure() {
+			closures++
+		}
+		if severityDelays[part.Severity] > severityDelays[merged.Severity] {
+			merged = part
+		}
+	}
+	if merged.IsClosure() && closures < len(parts) {
+		merged.Severity = partClosureSeverity
+	}
+	return merged
+}
+
+// Apply the specified line statuses to the search options as of time t:
+// lines closed then are not used at all, and the search weighs in the delays
+// to expect on other disrupted lines (see SearchOptions.LineDelays)
+func ApplyLineStatuses(opts *SearchOptions, statuses map[string]LineStatus, t time.Time) {
+	closedLines := maps.Clone(opts.ClosedLines)
+	if closedLines == nil {
+		closedLines = make(map[string]bool)
+	}
+	maps.Copy(closedLines, ClosedLinesAt(statuses, t))
+	opts.ClosedLines = closedLines
+
+	delays := make(map[string]float64)
+	for line, status := range statuses {
+		if !status.ClosedAt(t) && severityDelays[status.Severity] > 0 {
+			delays[line] = severityDelays[status.Severity]
+		}
+	}
+	opts.AddLineDelays(delays)
+}
+
+// Add the specified expected delays to those the options weigh in, keeping
+// the greater of the two for lines with both
+func (opts *SearchOptions) AddLineDelays(delays map[string]float64) {
+	lineDelays := maps.Clone(opts.LineDelays)
+	if lineDelays == nil {
+		lineDelays = make(map[string]float64)
+	}
+	for line, delay := range delays {
+		lineDelays[line] = max(lineDelays[line], delay)
+	}
+	opts.LineDelays = lineDelays
+}
+
+// Return a description of each disrupted line among the specified statuses,
+// in line order, e.g. "Victoria line: Suspended (signal failure)"
+func DescribeDisruptions(statuses map[string]LineStatus) []string {
+	disruptions := make([]string, 0)
+	for _, line := range slices.Sorted(maps.Keys(statuses)) {
+		status := statuses[line]
+		if status.Severity == "Good Service" {
+			continue
+		}
+		description := fmt.Sprintf("%s line: %s", line, status.Severity)
+		if status.Reason != "" {
+			description += fmt.Sprintf(" (%s)", status.Reason)
+		}
+		disruptions = append(disruptions, description)
+	}
+	return disruptions
+}
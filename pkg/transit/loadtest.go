@@ -0,0 +1,237 @@
+package transit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Chooses the origin and destination of each trip asked for by a load
+// test, using the specified random number generator
+type ODDistribution func(rng *rand.Rand) [2]string
+
+// Return an ODDistribution choosing every trip between two different
+// stations of those specified as likely as any other
+func UniformOD(stations []string) ODDistribution {
+	return func(rng *rand.Rand) [2]string {
+		from := rng.IntN(len(stations))
+		to := rng.IntN(len(stations) - 1)
+		if to >= from {
+			to++
+		}
+		return [2]string{stations[from], stations[to]}
+	}
+}
+
+// Return an ODDistribution choosing each end of a trip in proportion to the
+// number of lines serving the station in the specified graph, as a rough
+// stand-in for real demand, which gathers at the big interchanges
+func HubWeightedOD(nodeMap NodeMap) ODDistribution {
+	// Sorted for the same trips from the same seed on every run
+	stations := slices.Sorted(maps.Keys(nodeMap))
+	weights := make([]int, len(stations))
+	total := 0
+	for idx, station := range stations {
+		total += len(nodeMap[station])
+		weights[idx] = total
+	}
+	pick := func(rng *rand.Rand) string {
+		idx, _ := slices.BinarySearch(weights, rng.IntN(total)+1)
+		return stations[idx]
+	}
+	return func(rng *rand.Rand) [2]string {
+		from := pick(rng)
+		for {
+			if to := pick(rng); to != from {
+				return [2]string{from, to}
+			}
+		}
+	}
+}
+
+// Return an ODDistribution choosing each of the specified trips as likely
+// as any other, so a trip listed more than once is chosen more often
+func PairsOD(pairs [][2]string) ODDistribution {
+	return func(rng *rand.Rand) [2]string {
+		return pairs[rng.IntN(len(pairs))]
+	}
+}
+
+// Describes a load test of a server started with NewHTTPServer: how many
+// workers send it GET /route requests at once, each waiting for its answer
+// before sending the next, and for how long or how many requests
+type LoadTest struct {
+	// Base URL of the server, e.g. "http://localhost:8080"
+	URL    string
+	Client *http.Client
+	// Number of requests under way at once
+	Concurrency int
+	// Total number of requests to send, or zero to keep sending them until
+	// Duration has passed
+	Requests int
+	Duration time.Duration
+	// Trips to ask for, and further query parameters to send with each,
+	// e.g. stepFree=true
+	Trips ODDistribution
+	Query url.Values
+	// Seed of the random choice of trips, each worker drawing from its own
+	// generator seeded from it
+	Seed uint64
+}
+
+// Outcome of a LoadTest. Responses with a journey count as OK, and those
+// saying there is no route (status 422) as NoRoute, as the server answered
+// them properly. Anything else counts as Failed, with the number of each
+// kind of failure, such as "status 500" or a connection error, in Failures.
+type LoadTestResult struct {
+	Requests int
+	OK       int
+	NoRoute  int
+	Failed   int
+	Failures map[string]int
+	Elapsed  time.Duration
+	// Time taken to answer each request, shortest first
+	Latencies []time.Duration
+}
+
+// Return the requests answered a second over the test, including failures
+func (result *LoadTestResult) Throughput() float64 {
+	if result.Elapsed <= 0 {
+		return 0
+	}
+	return float64(result.Requests) / result.Elapsed.Seconds()
+}
+
+// Return the share of requests which failed, from 0 to 1
+func (result *LoadTestResult) ErrorRate() float64 {
+	if result.Requests == 0 {
+		return 0
+	}
+	return float64(result.Failed) / float64(result.Requests)
+}
+
+// Return the latency the specified percentage of requests were answered
+// within, e.g. 99 for the 99th percentile
+func (result *LoadTestResult) Percentile(p float64) time.Duration {
+	if len(result.Latencies) == 0 {
+		return 0
+	}
+	idx := int(float64(len(result.Latencies))*p/100+0.5) - 1
+	return result.Latencies[max(0, min(idx, len(result.Latencies)-1))]
+}
+
+// Run the load test until it has sent all its requests, its duration has
+// passed or the specified context is done, whichever comes first, and
+// return the outcome
+func RunLoadTest(ctx context.Context, test *LoadTest) (*LoadTestResult, error) {
+	if test.Concurrency < 1 {
+		return nil, errors.New("a load test needs at least one worker")
+	}
+	if test.Requests <= 0 && test.Duration <= 0 {
+		return nil, errors.New("a load test needs a number of requests or a duration")
+	}
+	base, err := url.Parse(strings.TrimSuffix(test.URL, "/") + "/route")
+	if err != nil {
+		return nil, err
+	}
+	client := test.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	if test.Requests <= 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, test.Duration)
+		defer cancel()
+	}
+
+	var sent atomic.Int64
+	var mu sync.Mutex
+	result := &LoadTestResult{Failures: make(map[string]int)}
+	var wg sync.WaitGroup
+	began := time.Now()
+	for worker := range test.Concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rng := rand.New(rand.NewPCG(test.Seed, uint64(worker)))
+			for ctx.Err() == nil && (test.Requests <= 0 || sent.Add(1) <= int64(test.Requests)) {
+				trip := test.Trips(rng)
+				query := url.Values{"from": {trip[0]}, "to": {trip[1]}}
+				for key, values := range test.Query {
+					query[key] = values
+				}
+				reqURL := *base
+				reqURL.RawQuery = query.Encode()
+				sentAt := time.Now()
+				failure := sendLoadTestRequest(ctx, client, reqURL.String())
+				latency := time.Since(sentAt)
+				if failure != "" && failure != noRouteFailure && ctx.Err() != nil {
+					// Cut off at the end of the test, so neither answered nor
+					// failed
+					return
+				}
+				mu.Lock()
+				result.Requests++
+				result.Latencies = append(result.Latencies, latency)
+				switch failure {
+				case "":
+					result.OK++
+				case noRouteFailure:
+					result.NoRoute++
+				default:
+					result.Failed++
+					result.Failures[failure]++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	result.Elapsed = time.Since(began)
+	slices.Sort(result.Latencies)
+	return result, nil
+}
+
+// Returned by sendLoadTestRequest for a response saying there is no route
+const noRouteFailure = "no route"
+
+// Send one request of a load test to the specified URL, returning what went
+// wrong, noRouteFailure if the server found no route, or "" if it answered
+// with a journey
+func sendLoadTestRequest(ctx context.Context, client *http.Client, reqURL string) string {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return err.Error()
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		// The URL differs for every trip, so leave it out for errors to add up
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			return urlErr.Err.Error()
+		}
+		return err.Error()
+	}
+	defer resp.Body.Close()
+	// Reading the whole body lets the connection be reused
+	if _, err := io.Copy(io.Discard, resp.Body); err != nil {
+		return fmt.Sprintf("reading response: %v", err)
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return ""
+	case http.StatusUnprocessableEntity:
+		return noRouteFailure
+	}
+	return fmt.Sprintf("status %d", resp.StatusCode)
+}
@@ -0,0 +1,62 @@
+package transit
+
+import (
+	"fmt"
+	"net/url"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Return the query used to look a station up in an online map, dropping any
+// parenthesized qualifier used to tell apart same-named stations in the
+// dataset (e.g. "Bethnal Green (Central)") since map providers don't know it
+func mapsStationQuery(station string) string {
+	if idx := strings.Index(station, " ("); idx > 0 {
+		station = station[:idx]
+	}
+	return station + " Station, London"
+}
+
+// Return a URL requesting public transport directions between the specified
+// stations from the given maps provider: "google", "apple", or "maps" for
+// whichever of the two is native to the current platform
+func MapsDirectionsURL(provider, start, dest string) (string, error) {
+	if provider == "maps" {
+		provider = "google"
+		if runtime.GOOS == "darwin" || runtime.GOOS == "ios" {
+			provider = "apple"
+		}
+	}
+	query := url.Values{}
+	switch provider {
+	case "google":
+		query.Set("api", "1")
+		query.Set("origin", mapsStationQuery(start))
+		query.Set("destination", mapsStationQuery(dest))
+		query.Set("travelmode", "transit")
+		return "https://www.google.com/maps/dir/?" + query.Encode(), nil
+	case "apple":
+		query.Set("saddr", mapsStationQuery(start))
+		query.Set("daddr", mapsStationQuery(dest))
+		query.Set("dirflg", "r")
+		return "https://maps.apple.com/?" + query.Encode(), nil
+	default:
+		return "", fmt.Errorf("unknown maps provider: %s (expected maps, google or apple)", provider)
+	}
+}
+
+// Open the specified URL with the platform's default handler, e.g. a browser
+// or the native maps application
+func OpenURL(link string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", link)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", link)
+	default:
+		cmd = exec.Command("xdg-open", link)
+	}
+	return cmd.Start()
+}
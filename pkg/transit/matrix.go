@@ -0,0 +1,65 @@
+package transit
+
+import "math"
+
+// Represents the travel times in minutes from each of a list of origins to
+// each of a list of destinations, as served by the HTTP API. Minutes is
+// indexed by origin then destination, with null where there is no route.
+type TravelTimeMatrix struct {
+	Origins      []string    `json:"origins"`
+	Destinations []string    `json:"destinations"`
+	Minutes      [][]*uint16 `json:"minutes"`
+}
+
+// Return the travel time in minutes from each of the specified origins to
+// each of the specified destinations, indexed by origin then destination,
+// under the specified search options (which may be nil). Rather than
+// searching for each pair, each distinct origin takes one sweep of the graph
+// finding the times to every station at once (see TravelTimesFrom), so this
+// suits many destinations as well as many origins. Pairs with no route are
+// given math.MaxUint16. An UnknownStationError is returned for any station
+// not in the graph.
+func TravelTimes(nodeMap NodeMap, origins, dests []string, opts *SearchOptions) ([][]uint16, error) {
+	for _, station := range append(append([]string{}, origins...), dests...) {
+		if _, exists := nodeMap[station]; !exists {
+			return nil, &UnknownStationError{station}
+		}
+	}
+	swept := make(map[string]map[string]uint16, len(origins))
+	matrix := make([][]uint16, len(origins))
+	for i, origin := range origins {
+		times, done := swept[origin]
+		if !done {
+			times = TravelTimesFrom(nodeMap, origin, opts)
+			swept[origin] = times
+		}
+		matrix[i] = make([]uint16, len(dests))
+		for j, dest := range dests {
+			if t, reached := times[dest]; reached {
+				matrix[i][j] = t
+			} else {
+				matrix[i][j] = math.MaxUint16
+			}
+		}
+	}
+	return matrix, nil
+}
+
+// Return the travel times between the specified origins and destinations as
+// TravelTimes does, in the form served by the HTTP API
+func NewTravelTimeMatrix(nodeMap NodeMap, origins, dests []string, opts *SearchOptions) (*TravelTimeMatrix, error) {
+	times, err := TravelTimes(nodeMap, origins, dests, opts)
+	if err != nil {
+		return nil, err
+	}
+	matrix := &TravelTimeMatrix{origins, dests, make([][]*uint16, len(origins))}
+	for i, row := range times {
+		matrix.Minutes[i] = make([]*uint16, len(dests))
+		for j, minutes := range row {
+			if minutes != math.MaxUint16 {
+				matrix.Minutes[i][j] = &minutes
+			}
+		}
+	}
+	return matrix, nil
+}
@@ -0,0 +1,151 @@
+package transit
+
+import (
+	"cmp"
+	"maps"
+	"math"
+	"math/rand/v2"
+	"slices"
+)
+
+// How the fastest trip between two stations changes from one version of
+// the transit data to another, in minutes, math.MaxUint16 meaning there is
+// no route in that version
+type TripChange struct {
+	From, To      string
+	Before, After uint16
+}
+
+// Return how many minutes longer the trip takes after the change than
+// before, negative if it is faster. Trips without a route on either side
+// have no change in time.
+func (change TripChange) Minutes() int {
+	if change.Before == math.MaxUint16 || change.After == math.MaxUint16 {
+		return 0
+	}
+	return int(change.After) - int(change.Before)
+}
+
+// Summarises how the trips between a set of station pairs change between
+// two versions of the transit data. Changes lists every pair whose trip
+// changed, biggest changes first, with trips made possible or impossible
+// before any others.
+type NetworkDiff struct {
+	Trips     int
+	Faster    int
+	Slower    int
+	Unchanged int
+	// Trips only possible after the change, and only before it
+	Connected    int
+	Disconnected int
+	Changes      []TripChange
+}
+
+// Return every ordered pair of different stations of the specified
+// networks, taken together, sorted
+func AllStationPairs(before, after NodeMap) [][2]string {
+	stations := slices.Sorted(maps.Keys(UnionStations(before, after)))
+	pairs := make([][2]string, 0, len(stations)*(len(stations)-1))
+	for _, from := range stations {
+		for _, to := range stations {
+			if from != to {
+				pairs = append(pairs, [2]string{from, to})
+			}
+		}
+	}
+	return pairs
+}
+
+// Return the specified number of ordered pairs of different stations of
+// the specified networks, taken together, chosen at random with the
+// specified random number generator without repeating any, or every pair
+// if there are no more than that
+func SampleStationPairs(before, after NodeMap, n int, rng *rand.Rand) [][2]string {
+	pairs := AllStationPairs(before, after)
+	if n >= len(pairs) {
+		return pairs
+	}
+	rng.Shuffle(len(pairs), func(i, j int) { pairs[i], pairs[j] = pairs[j], pairs[i] })
+	pairs = pairs[:n]
+	slices.SortFunc(pairs, func(a, b [2]string) int {
+		return cmp.Or(cmp.Compare(a[0], b[0]), cmp.Compare(a[1], b[1]))
+	})
+	return pairs
+}
+
+// Return a map holding the stations of both networks, for looking up
+// whether either has a station, e.g. with LoadStationPairs. Stations in
+// both have the Nodes of the first.
+func UnionStations(before, after NodeMap) NodeMap {
+	merged := maps.Clone(before)
+	for station, nodes := range after {
+		if _, exists := merged[station]; !exists {
+			merged[station] = nodes
+		}
+	}
+	return merged
+}
+
+// Compare the fastest trips between each of the specified pairs of stations
+// over two versions of the transit data, before and after a change such as
+// a proposed new link or a closure, under the specified search options
+// (which may be nil). A station missing from one version cannot be reached
+// in it. Each origin takes one sweep of each graph (see TravelTimesFrom), so
+// comparing every pair of stations is practical.
+func CompareNetworks(before, after NodeMap, pairs [][2]string, opts *SearchOptions) *NetworkDiff {
+	sweep := func(nodeMap NodeMap, origin string) map[string]uint16 {
+		if _, exists := nodeMap[origin]; !exists {
+			return nil
+		}
+		return TravelTimesFrom(nodeMap, origin, opts)
+	}
+	lookup := func(times map[string]uint16, dest string) uint16 {
+		if t, reached := times[dest]; reached {
+			return t
+		}
+		return math.MaxUint16
+	}
+
+	diff := &NetworkDiff{Trips: len(pairs), Changes: make([]TripChange, 0)}
+	sweptBefore := make(map[string]map[string]uint16)
+	sweptAfter := make(map[string]map[string]uint16)
+	for _, pair := range pairs {
+		origin := pair[0]
+		if _, done := sweptBefore[origin]; !done {
+			sweptBefore[origin], sweptAfter[origin] = sweep(before, origin), sweep(after, origin)
+		}
+		change := TripChange{origin, pair[1], lookup(sweptBefore[origin], pair[1]),
+			lookup(sweptAfter[origin], pair[1])}
+		switch {
+		case change.Before == change.After:
+			diff.Unchanged++
+			continue
+		case change.Before == math.MaxUint16:
+			diff.Connected++
+		case change.After == math.MaxUint16:
+			diff.Disconnected++
+		case change.After < change.Before:
+			diff.Faster++
+		default:
+			diff.Slower++
+		}
+		diff.Changes = append(diff.Changes, change)
+	}
+	slices.SortStableFunc(diff.Changes, func(a, b TripChange) int {
+		// Trips made possible or impossible matter most
+		aRoute := a.Before == math.MaxUint16 || a.After == math.MaxUint16
+		bRoute := b.Before == math.MaxUint16 || b.After == math.MaxUint16
+		if aRoute != bRoute {
+			if aRoute {
+				return -1
+			}
+			return 1
+		}
+		return cmp.Compare(abs(b.Minutes()), abs(a.Minutes()))
+	})
+	return diff
+}
+
+func abs(n int) int {
+	return max(n, -n)
+}
@@ -0,0 +1,82 @@
+package transit
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// Name of the network described by the built-in transit data
+const BuiltinNetworkName = "London"
+
+// Represents a transit network loaded for searching across several at once,
+// labelled with a name such as the city it serves
+type Network struct {
+	Name      string
+	RailLinks []RailLink
+}
+
+// Represents a station found by SearchStations, along with the network it
+// belongs to and the lines serving it there
+type StationMatch struct {
+	Network string
+	Station string
+	Lines   []string
+}
+
+// Return the network described by the built-in transit data
+func BuiltinNetwork() Network {
+	return Network{BuiltinNetworkName, GetRailLinks()}
+}
+
+// Load the network with the specified name from the transit data at the
+// specified path, in any of the forms DataSourceAt recognises
+func LoadNetwork(name, path string) (Network, error) {
+	railLinks, err := DataSourceAt(path).GetRailLinks()
+	if err != nil {
+		return Network{}, err
+	}
+	return Network{name, railLinks}, nil
+}
+
+// Return the stations of the specified networks whose names contain the
+// specified query, ignoring case, punctuation and "&" versus "and". Exact
+// matches come first, then names starting with the query, then the rest,
+// each in alphabetical order.
+func SearchStations(networks []Network, query string) []StationMatch {
+	query = normalizeStationName(query)
+	matches := make([]StationMatch, 0)
+	for _, network := range networks {
+		lines := make(map[string][]string)
+		for _, rl := range network.RailLinks {
+			for _, station := range []string{rl.fromStation, rl.toStation} {
+				if !slices.Contains(lines[station], rl.line) {
+					lines[station] = append(lines[station], rl.line)
+				}
+			}
+		}
+		for station, stationLines := range lines {
+			name := normalizeStationName(station)
+			if !strings.Contains(name, query) {
+				continue
+			}
+			slices.Sort(stationLines)
+			matches = append(matches, StationMatch{network.Name, station, stationLines})
+		}
+	}
+	matchRank := func(match StationMatch) int {
+		switch name := normalizeStationName(match.Station); {
+		case name == query:
+			return 0
+		case strings.HasPrefix(name, query):
+			return 1
+		default:
+			return 2
+		}
+	}
+	slices.SortFunc(matches, func(a, b StationMatch) int {
+		return cmp.Or(cmp.Compare(matchRank(a), matchRank(b)), strings.Compare(a.Station, b.Station),
+			strings.Compare(a.Network, b.Network))
+	})
+	return matches
+}
@@ -0,0 +1,300 @@
+package transit
+
+import (
+	"bufio"
+	"cmp"
+	"compress/bzip2"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"os"
+	"slices"
+	"strings"
+)
+
+// OpenStreetMap has no timetables, so run times are estimated from the
+// straight-line distance between stations at this average speed, including
+// the time spent accelerating and braking, plus a dwell at each station
+const (
+	osmAverageSpeedKMH = 35
+	osmDwellMinutes    = 0.5
+)
+
+// Values of the route tag of the route relations the importer reads: those
+// of the rail services the planner models
+var osmRailRoutes = map[string]bool{
+	"subway": true, "light_rail": true, "tram": true, "monorail": true, "train": true,
+}
+
+// An element of an OSM extract, as much of it as the importer needs: a
+// node's position, a relation's members and any element's tags
+type osmElement struct {
+	ID      int64       `xml:"id,attr"`
+	Lat     float64     `xml:"lat,attr"`
+	Lon     float64     `xml:"lon,attr"`
+	Members []osmMember `xml:"member"`
+	Tags    []osmTag    `xml:"tag"`
+}
+
+type osmMember struct {
+	Type string `xml:"type,attr"`
+	Ref  int64  `xml:"ref,attr"`
+	Role string `xml:"role,attr"`
+}
+
+type osmTag struct {
+	Key   string `xml:"k,attr"`
+	Value string `xml:"v,attr"`
+}
+
+// Return the element's tags as a map
+func (element *osmElement) tags() map[string]string {
+	tags := make(map[string]string, len(element.Tags))
+	for _, tag := range element.Tags {
+		tags[tag.Key] = tag.Value
+	}
+	return tags
+}
+
+// Represents a relation of an OSM extract with its tags looked up
+type osmRelation struct {
+	members []osmMember
+	tags    map[string]string
+}
+
+// Read the named nodes and the relations of the OSM XML extract at the
+// specified path, decompressing it first if its name ends in .bz2. Ways are
+// skipped, as stations are found from the nodes of routes' stops.
+func readOSM(path string) (map[int64]*osmElement, map[int64]*osmRelation, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+	var r io.Reader = bufio.NewReader(file)
+	if strings.HasSuffix(strings.ToLower(path), ".bz2") {
+		r = bzip2.NewReader(r)
+	}
+
+	nodes, relations := make(map[int64]*osmElement), make(map[int64]*osmRelation)
+	decoder := xml.NewDecoder(r)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, nil, err
+		}
+		start, isStart := token.(xml.StartElement)
+		if !isStart || (start.Name.Local != "node" && start.Name.Local != "relation") {
+			continue
+		}
+		var element osmElement
+		if err := decoder.DecodeElement(&element, &start); err != nil {
+			return nil, nil, err
+		}
+		if start.Name.Local == "relation" {
+			relations[element.ID] = &osmRelation{element.Members, element.tags()}
+		} else if slices.ContainsFunc(element.Tags, func(tag osmTag) bool { return tag.Key == "name" }) {
+			nodes[element.ID] = &element
+		}
+	}
+	if len(nodes) == 0 && len(relations) == 0 {
+		return nil, nil, errors.New("no OpenStreetMap data found (only OSM XML is supported)")
+	}
+	return nodes, relations, nil
+}
+
+// Return the estimated minutes a train takes between stations the specified
+// straight-line distance apart
+func osmRunTime(km float64) uint16 {
+	minutes := math.Round(km/osmAverageSpeedKMH*60 + osmDwellMinutes)
+	return uint16(max(1, min(minutes, math.MaxUint16-1)))
+}
+
+// Build rail links, interchanges and stations from the OpenStreetMap extract
+// at the specified path, in OSM XML. Lines are the route relations of rail
+// services (route=subway, light_rail, tram, monorail or train), named after
+// their route_master (or else their ref or name), and rail links join the
+// consecutive stops of each route. Stops are grouped into stations by the
+// stop_area relations containing them, or else by their names, with each
+// station placed at the average position of its stops. Run times are
+// estimated from the distances between stations, as OSM has no timetables.
+// Stations in the same stop_area_group are joined by interchanges on foot,
+// timed by their distance apart, while OSM has no times for changing
+// between lines at a station (see GraphOptions.AssumedInterchangeMinutes).
+func LoadOSM(path string) ([]RailLink, []Interchange, []Station, error) {
+	nodes, relations, err := readOSM(path)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	// Name every stop after the stop area it belongs to, where it has one,
+	// and the lines after the route masters of their routes
+	stopAreas := make(map[int64]string)
+	lineNames := make(map[int64]string)
+	for _, id := range slices.Sorted(maps.Keys(relations)) {
+		relation := relations[id]
+		switch {
+		case relation.tags["public_transport"] == "stop_area" && relation.tags["name"] != "":
+			for _, member := range relation.members {
+				if member.Type == "node" {
+					stopAreas[member.Ref] = relation.tags["name"]
+				}
+			}
+		case relation.tags["type"] == "route_master":
+			for _, member := range relation.members {
+				if member.Type == "relation" {
+					lineNames[member.Ref] = cmp.Or(relation.tags["name"], relation.tags["ref"])
+				}
+			}
+		}
+	}
+	stationOf := func(id int64) string {
+		if name := stopAreas[id]; name != "" {
+			return name
+		}
+		if node := nodes[id]; node != nil {
+			return node.tags()["name"]
+		}
+		return ""
+	}
+
+	// Follow the stops of every rail route, placing stations as they are met
+	type linkKey struct{ stationA, stationB, line string }
+	runTimes := make(map[linkKey]uint16)
+	positions := make(map[string][]Coordinates)
+	served := make(map[string][]string)
+	for _, id := range slices.Sorted(maps.Keys(relations)) {
+		relation := relations[id]
+		if relation.tags["type"] != "route" || !osmRailRoutes[relation.tags["route"]] {
+			continue
+		}
+		line := cmp.Or(lineNames[id], relation.tags["ref"], relation.tags["name"])
+		if line == "" {
+			continue
+		}
+		var prev string
+		var prevAt Coordinates
+		for _, member := range relation.members {
+			if member.Type != "node" || !strings.HasPrefix(member.Role, "stop") || nodes[member.Ref] == nil {
+				continue
+			}
+			station := stationOf(member.Ref)
+			at := NewCoordinates(nodes[member.Ref].Lat, nodes[member.Ref].Lon)
+			positions[station] = append(positions[station], at)
+			if !slices.Contains(served[station], line) {
+				served[station] = append(served[station], line)
+			}
+			if prev != "" && prev != station {
+				key := linkKey{prev, station, line}
+				if key.stationB < key.stationA {
+					key.stationA, key.stationB = key.stationB, key.stationA
+				}
+				minutes := osmRunTime(DistanceKM(prevAt, at))
+				if known, exists := runTimes[key]; !exists || minutes < known {
+					runTimes[key] = minutes
+				}
+			}
+			prev, prevAt = station, at
+		}
+	}
+	if len(runTimes) == 0 {
+		return nil, nil, nil, errors.New("no rail route relations with stops found (e.g. route=subway)")
+	}
+	keys := slices.SortedFunc(maps.Keys(runTimes), func(a, b linkKey) int {
+		return cmp.Or(strings.Compare(a.line, b.line), strings.Compare(a.stationA, b.stationA),
+			strings.Compare(a.stationB, b.stationB))
+	})
+	railLinks := make([]RailLink, 0, len(keys))
+	for _, key := range keys {
+		railLinks = append(railLinks, RailLink{key.stationA, key.stationB, key.line, runTimes[key]})
+	}
+
+	stations := make([]Station, 0, len(positions))
+	located := make(map[string]Coordinates, len(positions))
+	for _, name := range slices.Sorted(maps.Keys(positions)) {
+		var lat, lon float64
+		for _, at := range positions[name] {
+			lat, lon = lat+at.lat, lon+at.lon
+		}
+		c := NewCoordinates(lat/float64(len(positions[name])), lon/float64(len(positions[name])))
+		located[name] = c
+		stations = append(stations, Station{name, &c})
+	}
+
+	// Stop area groups join stop areas close enough to change between,
+	// such as the platforms of two stations sharing a concourse
+	areaNames := make(map[int64]string)
+	for id, relation := range relations {
+		if relation.tags["public_transport"] == "stop_area" {
+			areaNames[id] = relation.tags["name"]
+		}
+	}
+	interchanges := make([]Interchange, 0)
+	seen := make(map[[2]string]bool)
+	for _, id := range slices.Sorted(maps.Keys(relations)) {
+		relation := relations[id]
+		if relation.tags["public_transport"] != "stop_area_group" {
+			continue
+		}
+		group := make([]string, 0)
+		for _, member := range relation.members {
+			name := areaNames[member.Ref]
+			if member.Type == "relation" && len(served[name]) > 0 && !slices.Contains(group, name) {
+				group = append(group, name)
+			}
+		}
+		slices.Sort(group)
+		for i, from := range group {
+			for _, to := range group[i+1:] {
+				if seen[[2]string{from, to}] {
+					continue
+				}
+				seen[[2]string{from, to}] = true
+				minutes := walkingTime(DistanceKM(located[from], located[to]))
+				for _, fromLine := range served[from] {
+					for _, toLine := range served[to] {
+						interchanges = append(interchanges, Interchange{from, fromLine, to, toLine, minutes})
+					}
+				}
+			}
+		}
+	}
+	return railLinks, interchanges, stations, nil
+}
+
+// Supplies the rail network of an OpenStreetMap extract, along with where
+// its stations are
+type osmDataSource struct {
+	loadedDataSource
+	stations []Station
+}
+
+func (source *osmDataSource) GetStations() ([]Station, error) {
+	if _, _, err := source.loaded(); err != nil {
+		return nil, err
+	}
+	return source.stations, nil
+}
+
+// Return the DataSource supplying the rail network of the OpenStreetMap
+// extract at the specified path (see LoadOSM), with its stations located
+// where the extract puts them
+func OSMDataSource(path string) DataSource {
+	source := &osmDataSource{}
+	source.kind = "osm"
+	source.files = func(stamp func(path string) error) error { return stamp(path) }
+	source.load = func() ([]RailLink, []Interchange, error) {
+		railLinks, interchanges, stations, err := LoadOSM(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %v", path, err)
+		}
+		source.stations = stations
+		return railLinks, interchanges, nil
+	}
+	return source
+}
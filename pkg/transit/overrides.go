@@ -0,0 +1,176 @@
+package transit
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Represents a temporary change to a station's status, such as a closure for
+// long-term works, recorded separately from the main transit data
+type StationOverride struct {
+	Station string
+	Closed  bool
+	Until   time.Time
+	Reason  string
+}
+
+// Return the default location of the station overrides file, inside the
+// user's config directory (falling back to the working directory)
+func DefaultStationOverridesPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "station-overrides.csv"
+	}
+	return filepath.Join(dir, "tubeplanner", "station-overrides.csv")
+}
+
+// Return whether the override is still in effect at the specified time. An
+// override without an end date stays in effect until removed from the file,
+// and one with an end date lasts until the end of that day.
+func (so StationOverride) ActiveAt(t time.Time) bool {
+	return so.Until.IsZero() || t.Before(so.Until.AddDate(0, 0, 1))
+}
+
+// Read station overrides from the specified CSV file, one per line in the
+// form "station,status[,until[,reason]]", where status is "closed" or "open"
+// and until is a date in YYYY-MM-DD form. Lines starting with # are ignored.
+func LoadStationOverrides(path string) ([]StationOverride, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	overrides := make([]StationOverride, 0)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		lineNum, _ := reader.FieldPos(0)
+		if len(record) < 2 || len(record) > 4 {
+			return nil, fmt.Errorf("%s:%d: expected station,status[,until[,reason]]", path, lineNum)
+		}
+		override := StationOverride{Station: strings.TrimSpace(record[0])}
+		switch strings.ToLower(strings.TrimSpace(record[1])) {
+		case "closed":
+			override.Closed = true
+		case "open":
+			override.Closed = false
+		default:
+			return nil, fmt.Errorf("%s:%d: status must be closed or open, not %q",
+				path, lineNum, record[1])
+		}
+		if len(record) > 2 && strings.TrimSpace(record[2]) != "" {
+			override.Until, err = time.ParseInLocation("2006-01-02", strings.TrimSpace(record[2]), time.Local)
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: invalid date %q", path, lineNum, record[2])
+			}
+		}
+		if len(record) > 3 {
+			override.Reason = strings.TrimSpace(record[3])
+		}
+		overrides = append(overrides, override)
+	}
+	return overrides, nil
+}
+
+// Apply the station overrides in effect at the specified time to the graph,
+// with later overrides of the same station taking precedence. Trains keep
+// running through a closed station, but nobody can interchange there, so all
+// interchange links to and from its Nodes are removed. Overrides of stations
+// the graph does not have, e.g. one built from other data or misspelt, are
+// skipped rather than failing the build, and the stations they name are
+// returned. Overrides no longer in effect are skipped before the station is
+// looked up, so old entries never matter.
+func ApplyStationOverrides(nodeMap NodeMap, overrides []StationOverride, now time.Time) []string {
+	latest := make(map[string]StationOverride)
+	unknown := make([]string, 0)
+	for _, override := range overrides {
+		if !override.ActiveAt(now) {
+			continue
+		}
+		if _, exists := nodeMap[override.Station]; !exists {
+			unknown = append(unknown, override.Station)
+			continue
+		}
+		latest[override.Station] = override
+	}
+	for station, override := range latest {
+		if !override.Closed {
+			continue
+		}
+		for _, node := range nodeMap[station] {
+			node.closed = true
+			node.closure = &override
+			for _, link := range node.adj {
+				if link.attrs.Mode != ModeRail {
+					link.endNode.adj = slices.DeleteFunc(link.endNode.adj, func(back *Link) bool {
+						return back.endNode == node && back.attrs.Mode != ModeRail
+					})
+				}
+			}
+			node.adj = slices.DeleteFunc(node.adj, func(link *Link) bool {
+				return link.attrs.Mode != ModeRail
+			})
+		}
+	}
+	return unknown
+}
+
+// Return a warning for each of the specified overrides naming a station the
+// graph does not have, which ApplyStationOverrides skips
+func CheckStationOverrides(nodeMap NodeMap, overrides []StationOverride) []GraphIssue {
+	issues := make([]GraphIssue, 0)
+	for _, override := range overrides {
+		if _, exists := nodeMap[override.Station]; !exists {
+			issues = append(issues, GraphIssue{Check: CheckOverride, Warning: true,
+				Message: fmt.Sprintf("%s is not a valid station, so its override is ignored", override.Station)})
+		}
+	}
+	return issues
+}
+
+// If the specified station has been closed by an override, return a
+// description of the closure suitable for error messages
+func StationClosure(nodeMap NodeMap, station string) (string, bool) {
+	var closure *StationOverride
+	for _, node := range nodeMap[station] {
+		if !node.closed {
+			return "", false
+		}
+		closure = node.closure
+	}
+	if closure == nil {
+		return "", false
+	}
+	return closure.Describe(), true
+}
+
+// Describe a station closure for error messages, e.g. "Kentish Town is
+// closed until 2026-12-31 (lift replacement works)"
+func (so StationOverride) Describe() string {
+	desc := so.Station + " is closed"
+	if !so.Until.IsZero() {
+		desc += " until " + so.Until.Format("2006-01-02")
+	}
+	if so.Reason != "" {
+		desc += " (" + so.Reason + ")"
+	}
+	return desc
+}
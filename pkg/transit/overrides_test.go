@@ -0,0 +1,33 @@
+package transit
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestApplyStationOverrides(t *testing.T) {
+	nodeMap := defaultNodeMap(t)
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.Local)
+	overrides := []StationOverride{
+		{Station: "Old Street", Closed: true, Until: now.AddDate(0, 0, 7)},
+		{Station: "Nowhere Central", Closed: true, Until: now.AddDate(0, -1, 0)},
+		{Station: "Nowhere Parkway", Closed: true},
+		{Station: "Angel", Closed: true, Until: now.AddDate(0, 0, -2)},
+	}
+	unknown := ApplyStationOverrides(nodeMap, overrides, now)
+	if !slices.Equal(unknown, []string{"Nowhere Parkway"}) {
+		t.Errorf("skipped overrides of %v, want only the one in effect, [Nowhere Parkway]", unknown)
+	}
+	if _, closed := StationClosure(nodeMap, "Old Street"); !closed {
+		t.Error("Old Street is open, want it closed by its override")
+	}
+	if _, closed := StationClosure(nodeMap, "Angel"); closed {
+		t.Error("Angel is closed, though its override has expired")
+	}
+
+	issues := CheckStationOverrides(nodeMap, overrides)
+	if len(issues) != 2 || !issues[0].Warning || !issues[1].Warning {
+		t.Errorf("CheckStationOverrides() = %v, want a warning for each unknown station", issues)
+	}
+}
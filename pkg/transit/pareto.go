@@ -0,0 +1,175 @@
+package transit
+
+import (
+	"cmp"
+	"container/heap"
+	"slices"
+	"time"
+)
+
+// Represents a trip in a set of Pareto-optimal trips, along with the
+// criteria besides travel time that it was judged by: the number of
+// interchanges, whether between lines or on foot to another station, and
+// the minutes spent walking, including between the gates and the platforms
+type Tradeoff struct {
+	*Route
+	Changes        int
+	WalkingMinutes uint16
+}
+
+// Represents a partial trip reaching a Node during a multi-criteria search,
+// with its search cost, number of changes and walking minutes so far,
+// whether it is still at the origin, and the label and link it was reached
+// from
+type paretoLabel struct {
+	node    *Node
+	cost    uint16
+	elapsed uint16
+	origin  bool
+	changes int
+	walking uint16
+	prev    *paretoLabel
+	link    *Link
+	dead    bool
+}
+
+// Return whether the label is at least as good as the other on every
+// criterion
+func (l *paretoLabel) dominates(other *paretoLabel) bool {
+	return l.cost <= other.cost && l.changes <= other.changes && l.walking <= other.walking
+}
+
+// Min heap of labels by search cost, then number of changes, then walking
+// minutes
+type labelQueue []*paretoLabel
+
+func (lq labelQueue) Len() int { return len(lq) }
+
+func (lq labelQueue) Less(i, j int) bool {
+	return cmp.Or(cmp.Compare(lq[i].cost, lq[j].cost), cmp.Compare(lq[i].changes, lq[j].changes),
+		cmp.Compare(lq[i].walking, lq[j].walking)) < 0
+}
+
+func (lq labelQueue) Swap(i, j int) { lq[i], lq[j] = lq[j], lq[i] }
+
+func (lq *labelQueue) Push(x any) { *lq = append(*lq, x.(*paretoLabel)) }
+
+func (lq *labelQueue) Pop() any {
+	old := *lq
+	label := old[len(old)-1]
+	old[len(old)-1] = nil
+	*lq = old[:len(old)-1]
+	return label
+}
+
+// Return every Pareto-optimal trip between the specified stations across
+// travel time, number of changes and minutes spent walking: those for which
+// no other trip is at least as good on all three and better on one. The
+// trips are found by a multi-criteria label-setting search, in which each
+// Node keeps every partial trip reaching it that no other beats, and are
+// returned fastest first. Search options (which may be nil) apply as for
+// RunShortestPaths, with any penalties counting towards travel time.
+func ParetoRoutes(nodeMap NodeMap, start, dest string, opts *SearchOptions) ([]Tradeoff, error) {
+	if start == dest {
+		return []Tradeoff{{Route: &Route{arrivedJourney(start)}}}, nil
+	}
+	labels := make(map[*Node][]*paretoLabel)
+	lq := make(labelQueue, 0)
+	// Add a label unless a label already at its Node beats it, setting aside
+	// those it beats in turn
+	add := func(label *paretoLabel) {
+		for _, other := range labels[label.node] {
+			if other.dominates(label) {
+				return
+			}
+		}
+		labels[label.node] = slices.DeleteFunc(labels[label.node], func(other *paretoLabel) bool {
+			if label.dominates(other) {
+				other.dead = true
+				return true
+			}
+			return false
+		})
+		labels[label.node] = append(labels[label.node], label)
+		heap.Push(&lq, label)
+	}
+	for node, start := range startCosts(nodeMap, start, opts) {
+		add(&paretoLabel{node: node, cost: start.cost, elapsed: start.elapsed, origin: start.origin})
+	}
+
+	// Trips reaching the destination, counting the walk out of the station
+	arrivals := make([]*paretoLabel, 0)
+	beaten := func(label *paretoLabel) bool {
+		return slices.ContainsFunc(arrivals, func(arrival *paretoLabel) bool {
+			return arrival.dominates(label)
+		})
+	}
+	for len(lq) > 0 {
+		if opts != nil && !opts.Deadline.IsZero() && time.Now().After(opts.Deadline) {
+			return nil, ErrDeadlineExceeded
+		}
+		label := heap.Pop(&lq).(*paretoLabel)
+		if label.dead || beaten(label) {
+			continue
+		}
+		if label.node.station == dest {
+			if opts.accessible(label.node) {
+				access := opts.accessTime(label.node)
+				arrival := *label
+				arrival.prev, arrival.link = label, nil
+				arrival.cost = AddTime(label.cost, access)
+				arrival.elapsed = AddTime(label.elapsed, access)
+				arrival.walking = AddTime(label.walking, access)
+				if !beaten(&arrival) {
+					arrivals = slices.DeleteFunc(arrivals, arrival.dominates)
+					arrivals = append(arrivals, &arrival)
+				}
+			}
+			continue
+		}
+		// Searches check whether links can be followed against the time
+		// recorded on the Node they leave
+		label.node.totalTime, label.node.elapsed = label.cost, label.elapsed
+		for _, link := range label.node.adj {
+			if opts.blocked(label.node, link) {
+				continue
+			}
+			reached := opts.linkCost(label.node, link, progress{label.cost, label.elapsed, label.origin})
+			next := &paretoLabel{node: link.endNode, cost: reached.cost, elapsed: reached.elapsed,
+				changes: label.changes, walking: label.walking, prev: label, link: link}
+			if label.origin && link.attrs.Mode != ModeStationInterchange {
+				next.walking = AddTime(next.walking, opts.accessTime(label.node))
+			}
+			if link.attrs.Mode != ModeRail {
+				next.changes++
+				next.walking = AddTime(next.walking, opts.linkTime(link, label.elapsed))
+			}
+			if !beaten(next) {
+				add(next)
+			}
+		}
+	}
+	if len(arrivals) == 0 {
+		return nil, ErrNoRoute
+	}
+
+	slices.SortFunc(arrivals, func(a, b *paretoLabel) int {
+		return cmp.Or(cmp.Compare(a.cost, b.cost), cmp.Compare(a.changes, b.changes),
+			cmp.Compare(a.walking, b.walking))
+	})
+	tradeoffs := make([]Tradeoff, 0, len(arrivals))
+	for _, arrival := range arrivals {
+		route, links := make([]*Node, 0), make([]*Link, 0)
+		for label := arrival.prev; label != nil; label = label.prev {
+			route = append(route, label.node)
+			if label.link != nil {
+				links = append(links, label.link)
+			}
+		}
+		slices.Reverse(route)
+		slices.Reverse(links)
+		tradeoffs = append(tradeoffs, Tradeoff{&Route{newJourney(route, links, opts)},
+			arrival.changes, arrival.walking})
+	}
+	return tradeoffs, nil
+}
@@ -0,0 +1,74 @@
+package transit
+
+import (
+	"fmt"
+	"time"
+)
+
+// Return, for each line with a peak flow centre, the number of stops from
+// each of its stations to the centre along the line's own rail links
+func peakFlowDistances(nodeMap NodeMap) map[string]map[string]int {
+	distances := make(map[string]map[string]int)
+	for line, centre := range GetPeakFlowCentres() {
+		node, exists := nodeMap[centre][line]
+		if !exists {
+			continue
+		}
+		distances[line] = map[string]int{centre: 0}
+		frontier := []*Node{node}
+		for stops := 1; len(frontier) > 0; stops++ {
+			next := make([]*Node, 0)
+			for _, node := range frontier {
+				for _, link := range node.adj {
+					if _, seen := distances[line][link.endNode.station]; link.attrs.Mode == ModeRail && !seen {
+						distances[line][link.endNode.station] = stops
+						next = append(next, link.endNode)
+					}
+				}
+			}
+			frontier = next
+		}
+	}
+	return distances
+}
+
+// Return a hint for each rail leg of the journey ridden during a peak, saying
+// whether it runs with the peak flow, when trains are crowded, or against
+// it, when a seat is likely. The morning peak flows towards the centre given
+// for each line in transitdata.go and the evening peak away from it. Legs
+// outside the peaks, or on lines without a clear peak direction, get none.
+func PeakFlowHints(nodeMap NodeMap, journey *Journey, departAt time.Time) []string {
+	distances := peakFlowDistances(nodeMap)
+	hints := make([]string, 0)
+	for _, leg := range journey.Legs {
+		if leg.Type != string(ModeRail) {
+			continue
+		}
+		boarding := departAt.Add(time.Duration(leg.Depart) * time.Minute)
+		fromDist, fromKnown := distances[leg.Line][leg.From]
+		toDist, toKnown := distances[leg.Line][leg.To]
+		if TimeBand(boarding) != PeakBand || !fromKnown || !toKnown || fromDist == toDist {
+			continue
+		}
+		inbound := toDist < fromDist
+		morning := boarding.Hour() < 12
+		hint := "against the peak flow, likely to get a seat"
+		if inbound == morning {
+			hint = "with the peak flow, expect crowded trains"
+		}
+		hints = append(hints, fmt.Sprintf("%s line from %s to %s: %s", leg.Line, leg.From, leg.To, hint))
+	}
+	return hints
+}
+
+// Print the peak flow hints for the journey's legs, if any
+func PrintPeakFlowHints(nodeMap NodeMap, journey *Journey, departAt time.Time) {
+	hints := PeakFlowHints(nodeMap, journey, departAt)
+	if len(hints) == 0 {
+		return
+	}
+	fmt.Println("Peak travel:")
+	for _, hint := range hints {
+		fmt.Printf("- %s\n", hint)
+	}
+}
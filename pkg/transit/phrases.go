@@ -0,0 +1,124 @@
+package transit
+
+import "fmt"
+
+// Generates the wording of each kind of step in a trip's directions, so that
+// embedders can reword them, or inject details of their own, without
+// changing how directions are put together. Each method returns one line of
+// text; times are in minutes since the start of the trip.
+type DirectionPhrases interface {
+	// The whole of the directions for a trip which starts at its destination
+	AlreadyThere() string
+	// The first step, at the start station
+	Begin(station string) string
+	// A step asking staff at the specified station, before boarding a train
+	// on the specified line, for a manual ramp onto the train if boarding
+	// needs one, and for one off it at alightAt unless that is empty
+	RequestRamp(step int, station, line string, boarding bool, alightAt string) string
+	// A step boarding a train on the specified line
+	Board(step int, line string) string
+	// A station passed on the train boarded in the previous Board step,
+	// which may be closed, in which case the train runs through it
+	Stop(station string, minutes uint16, closed bool) string
+	// A step changing to the specified line at the same station
+	ChangeLines(step int, station, line string, minutes uint16) string
+	// A step walking from one station to another nearby
+	Walk(step int, from, to string, minutes uint16) string
+	// Walking guidance following a ChangeLines or Walk step, or details of
+	// the line following a Board step, in detailed directions
+	Guidance(text string) string
+	// The final step, at the destination
+	Arrive(step int, station string, minutes uint16) string
+}
+
+// The standard wording of directions, as printed by PrintDirections
+type StandardPhrases struct{}
+
+func (StandardPhrases) AlreadyThere() string { return "Already at destination!" }
+
+func (StandardPhrases) Begin(station string) string {
+	return fmt.Sprintf("1) Begin journey at %s station. (0 minutes)", station)
+}
+
+func (StandardPhrases) Board(step int, line string) string {
+	return fmt.Sprintf("%d) Travel on the %s line, through station stops:", step, line)
+}
+
+func (StandardPhrases) RequestRamp(step int, station, line string, boarding bool, alightAt string) string {
+	switch {
+	case boarding && alightAt != "":
+		return fmt.Sprintf("%d) Ask staff at %s for a ramp onto the %s line train, and for one to meet you at %s.",
+			step, station, line, alightAt)
+	case boarding:
+		return fmt.Sprintf("%d) Ask staff at %s for a ramp onto the %s line train.", step, station, line)
+	default:
+		return fmt.Sprintf("%d) Ask staff at %s for a ramp to meet the %s line train at %s.",
+			step, station, line, alightAt)
+	}
+}
+
+func (StandardPhrases) Stop(station string, minutes uint16, closed bool) string {
+	if closed {
+		return fmt.Sprintf("- %s (%d minutes, station closed - train does not stop)", station, minutes)
+	}
+	return fmt.Sprintf("- %s (%d minutes)", station, minutes)
+}
+
+func (StandardPhrases) ChangeLines(step int, station, line string, minutes uint16) string {
+	return fmt.Sprintf("%d) Get off at %s and interchange to the %s line. (%d minutes)",
+		step, station, line, minutes)
+}
+
+func (StandardPhrases) Walk(step int, from, to string, minutes uint16) string {
+	return fmt.Sprintf("%d) From %s, interchange on foot to nearby %s station. (%d minutes)",
+		step, from, to, minutes)
+}
+
+func (StandardPhrases) Guidance(text string) string { return "   " + text }
+
+func (StandardPhrases) Arrive(step int, station string, minutes uint16) string {
+	return fmt.Sprintf("%d) Reach destination at %s station. (%d minutes)", step, station, minutes)
+}
+
+// A terser wording of directions suited to narrow displays
+type CompactPhrases struct {
+	StandardPhrases
+}
+
+func (CompactPhrases) Begin(station string) string {
+	return fmt.Sprintf("1) Start: %s", station)
+}
+
+func (CompactPhrases) Board(step int, line string) string {
+	return fmt.Sprintf("%d) %s line:", step, line)
+}
+
+func (CompactPhrases) RequestRamp(step int, station, line string, boarding bool, alightAt string) string {
+	switch {
+	case boarding && alightAt != "":
+		return fmt.Sprintf("%d) Ask staff: ramp on, and off at %s", step, alightAt)
+	case boarding:
+		return fmt.Sprintf("%d) Ask staff: ramp on", step)
+	default:
+		return fmt.Sprintf("%d) Ask staff: ramp off at %s", step, alightAt)
+	}
+}
+
+func (CompactPhrases) Stop(station string, minutes uint16, closed bool) string {
+	if closed {
+		return fmt.Sprintf("- %s %dm (closed)", station, minutes)
+	}
+	return fmt.Sprintf("- %s %dm", station, minutes)
+}
+
+func (CompactPhrases) ChangeLines(step int, station, line string, minutes uint16) string {
+	return fmt.Sprintf("%d) At %s change to %s %dm", step, station, line, minutes)
+}
+
+func (CompactPhrases) Walk(step int, from, to string, minutes uint16) string {
+	return fmt.Sprintf("%d) Walk %s to %s %dm", step, from, to, minutes)
+}
+
+func (CompactPhrases) Arrive(step int, station string, minutes uint16) string {
+	return fmt.Sprintf("%d) Arrive: %s %dm", step, station, minutes)
+}
@@ -0,0 +1,143 @@
+package transit
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Mean radius of the Earth in kilometres, for distances between coordinates
+const earthRadiusKM = 6371
+
+// Represents a place of the user's, such as a flat, an office or a venue,
+// along with the station it is reached from and how far away that is (zero
+// when the place was given as a station)
+type Place struct {
+	Name       string
+	Station    string
+	DistanceKM float64
+}
+
+// Return the great-circle distance between the specified coordinates in
+// kilometres
+func DistanceKM(a, b Coordinates) float64 {
+	toRadians := func(deg float64) float64 { return deg * math.Pi / 180 }
+	dLat, dLon := toRadians(b.lat-a.lat), toRadians(b.lon-a.lon)
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRadians(a.lat))*math.Cos(toRadians(b.lat))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKM * math.Asin(math.Sqrt(h))
+}
+
+// Return the station in the specified graph nearest to the specified
+// coordinates, and its distance in kilometres, among the stations with known
+// coordinates
+func NearestStation(nodeMap NodeMap, c Coordinates) (string, float64, bool) {
+	nearest, nearestKM := "", math.Inf(1)
+	for station, sc := range GetStationCoordinates() {
+		if _, exists := nodeMap[station]; !exists {
+			continue
+		}
+		if km := DistanceKM(c, sc); km < nearestKM || (km == nearestKM && station < nearest) {
+			nearest, nearestKM = station, km
+		}
+	}
+	return nearest, nearestKM, nearest != ""
+}
+
+// Read a list of places from a CSV file with one "name,station" or
+// "name,latitude,longitude" record per line, resolving places given by
+// coordinates to their nearest station in the specified graph. Given a
+// Geocoder, "name,address" records whose address is not a station are
+// looked up with it and resolved likewise; otherwise the geocoder may be
+// nil. Lines starting with "#" are comments.
+func LoadPlaces(path string, nodeMap NodeMap, geocoder Geocoder) ([]Place, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	places := make([]Place, 0)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		lineNum, _ := reader.FieldPos(0)
+		for idx := range record {
+			record[idx] = strings.TrimSpace(record[idx])
+		}
+		// Coordinates given directly or found by geocoding are both resolved
+		// to the nearest station
+		var coords Coordinates
+		switch len(record) {
+		case 2:
+			if _, exists := nodeMap[record[1]]; exists {
+				places = append(places, Place{record[0], record[1], 0})
+				continue
+			}
+			if geocoder == nil {
+				return nil, fmt.Errorf("%s:%d: %s is not a valid station", path, lineNum, record[1])
+			}
+			if coords, err = geocoder.Geocode(record[1]); err != nil {
+				return nil, fmt.Errorf("%s:%d: looking up %s: %v", path, lineNum, record[1], err)
+			}
+		case 3:
+			lat, latErr := strconv.ParseFloat(record[1], 64)
+			lon, lonErr := strconv.ParseFloat(record[2], 64)
+			if latErr != nil || lonErr != nil || math.Abs(lat) > 90 || math.Abs(lon) > 180 {
+				return nil, fmt.Errorf("%s:%d: invalid coordinates %s,%s", path, lineNum, record[1], record[2])
+			}
+			coords = Coordinates{lat, lon}
+		default:
+			return nil, fmt.Errorf("%s:%d: expected name,station or name,latitude,longitude", path, lineNum)
+		}
+		station, km, found := NearestStation(nodeMap, coords)
+		if !found {
+			return nil, fmt.Errorf("%s:%d: no station coordinates are known to find the nearest "+
+				"station, import them with \"./tubeplanner import-coords\" or give a station", path, lineNum)
+		}
+		places = append(places, Place{record[0], station, km})
+	}
+	return places, nil
+}
+
+// Return the travel time in minutes from each of the specified places to
+// each other, indexed by origin then destination, running one search per
+// origin. Pairs with no route are given math.MaxUint16.
+func PlaceMatrix(nodeMap NodeMap, places []Place, opts *SearchOptions) [][]uint16 {
+	matrix, _ := PlaceZoneMatrix(nodeMap, places, opts)
+	return matrix
+}
+
+// Return the travel times between the specified places as PlaceMatrix does,
+// along with how the trip between each pair crosses the fare zones (see
+// ZoneCrossings), indexed the same way. Pairs with no route, and each place
+// to itself, cross no zones.
+func PlaceZoneMatrix(nodeMap NodeMap, places []Place, opts *SearchOptions) ([][]uint16, [][]ZoneCrossings) {
+	matrix := make([][]uint16, len(places))
+	zones := make([][]ZoneCrossings, len(places))
+	for i, origin := range places {
+		times, crossings := ZoneCrossingsFrom(nodeMap, origin.Station, opts)
+		matrix[i] = make([]uint16, len(places))
+		zones[i] = make([]ZoneCrossings, len(places))
+		for j, dest := range places {
+			if t, reached := times[dest.Station]; reached {
+				matrix[i][j], zones[i][j] = t, crossings[dest.Station]
+			} else {
+				matrix[i][j] = math.MaxUint16
+			}
+		}
+	}
+	return matrix, zones
+}
@@ -0,0 +1,359 @@
+package transit
+
+import (
+	"errors"
+	"io/fs"
+	"log/slog"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Represents a transit graph built from the transit data, ready to be
+// searched by a Planner. Searches record their progress on the graph, so
+// Planners searching the same graph take turns, apart from searches answered
+// from a contraction hierarchy (see UseContractionHierarchy).
+type Graph struct {
+	nodeMap   NodeMap
+	mu        sync.Mutex
+	logger    *slog.Logger
+	hierarchy atomic.Pointer[ContractionHierarchy]
+	// Version of the network, once worked out by DataVersion
+	versionOnce sync.Once
+	version     string
+}
+
+// Build the transit graph as the specified options say (see
+// BuildTransitGraph), logging how it was built to their logger, which
+// Planners searching the graph log to as well unless given their own
+func LoadGraph(opts GraphOptions) (*Graph, error) {
+	logger := loggerOrDiscard(opts.Logger)
+	opts.Logger = logger
+	began := time.Now()
+	_, nodeMap, err := BuildTransitGraph(opts)
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("built transit graph", "stations", len(nodeMap), "duration", time.Since(began))
+	return &Graph{nodeMap: nodeMap, logger: logger}, nil
+}
+
+// Wrap an existing map of graph nodes, e.g. one returned by
+// BuildTransitGraph, as a Graph
+func NewGraph(nodeMap NodeMap) *Graph {
+	return &Graph{nodeMap: nodeMap}
+}
+
+// Answer searches without search options from a contraction hierarchy over
+// the graph, read from the file at the specified path if it was built for
+// this graph, or else built afresh and written there for next time (unless
+// the path is empty), returning any error writing it. Searches answered from
+// the hierarchy need not take turns with other searches of the graph.
+func (g *Graph) UseContractionHierarchy(path string) error {
+	logger := loggerOrDiscard(g.logger)
+	if path != "" {
+		ch, err := LoadContractionHierarchy(path, g.nodeMap)
+		if err == nil {
+			logger.Debug("read contraction hierarchy", "path", path)
+			g.hierarchy.Store(ch)
+			return nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			logger.Info("rebuilding contraction hierarchy", "path", path, "reason", err)
+		}
+	}
+	began := time.Now()
+	ch := BuildContractionHierarchy(g.nodeMap)
+	logger.Info("built contraction hierarchy", "nodes", len(ch.nodes), "edges", len(ch.edges),
+		"duration", time.Since(began))
+	g.hierarchy.Store(ch)
+	if path == "" {
+		return nil
+	}
+	return ch.Save(path)
+}
+
+// Return the underlying map of graph nodes, for use with the lower-level
+// functions of this package
+func (g *Graph) NodeMap() NodeMap {
+	return g.nodeMap
+}
+
+// Return the names of every station in the graph, sorted
+func (g *Graph) Stations() []string {
+	stations := make([]string, 0, len(g.nodeMap))
+	for station := range g.nodeMap {
+		stations = append(stations, station)
+	}
+	slices.Sort(stations)
+	return stations
+}
+
+// Return the names of the lines serving the specified station, sorted, or
+// nil if it is not in the graph
+func (g *Graph) Lines(station string) []string {
+	if _, exists := g.nodeMap[station]; !exists {
+		return nil
+	}
+	lines := make([]string, 0, len(g.nodeMap[station]))
+	for line := range g.nodeMap[station] {
+		lines = append(lines, line)
+	}
+	slices.Sort(lines)
+	return lines
+}
+
+// Return whether the specified station is in the graph
+func (g *Graph) HasStation(station string) bool {
+	_, exists := g.nodeMap[station]
+	return exists
+}
+
+// Represents a trip planned by a Planner, as the Journey taken. The Journey
+// keeps copies of the Nodes visited taken when the trip was planned, so
+// later searches of the graph leave it unchanged.
+type Route struct {
+	journey *Journey
+}
+
+// Return the station the trip begins at
+func (r *Route) Start() string {
+	return r.journey.From
+}
+
+// Return the station the trip ends at
+func (r *Route) Destination() string {
+	return r.journey.To
+}
+
+// Return the total travel time of the trip in minutes, which is zero when
+// the trip begins at its destination
+func (r *Route) TotalMinutes() uint16 {
+	return r.journey.TotalMinutes
+}
+
+// Return the trip as a Journey, for output in any format. The Journey is a
+// copy, which the caller may change (e.g. to add its Fare) without changing
+// the Route.
+func (r *Route) Journey() *Journey {
+	journey := *r.journey
+	return &journey
+}
+
+// Return directions for the trip as individual lines of text, optionally in
+// compact form and with walking guidance for interchanges
+func (r *Route) Directions(compact, detailed bool) []string {
+	return DirectionLines(r.journey, compact, detailed)
+}
+
+// Represents a trip planner searching a Graph with a set of search options.
+// A Planner may be used from several goroutines at once, with searches of
+// the same graph taking turns, though its Options should not be changed
+// while it is in use.
+type Planner struct {
+	graph   atomic.Pointer[Graph]
+	logger  *slog.Logger
+	Options SearchOptions
+}
+
+// Return a Planner searching the specified graph with no search options set
+func NewPlanner(graph *Graph) *Planner {
+	return NewPlannerWithLogger(graph, nil)
+}
+
+// Return a Planner searching the specified graph with no search options set,
+// logging each plan to the specified logger at debug level, along with any
+// searches abandoned at their deadline and any swaps of the graph. A nil
+// logger means the graph's own logger, if it has one, or else logging
+// nothing.
+func NewPlannerWithLogger(graph *Graph, logger *slog.Logger) *Planner {
+	if logger == nil && graph != nil {
+		logger = graph.logger
+	}
+	p := &Planner{logger: loggerOrDiscard(logger)}
+	p.graph.Store(graph)
+	return p
+}
+
+// Log the outcome of a search by the Planner which began at the specified
+// time, with the specified attributes identifying it
+func (p *Planner) logSearch(began time.Time, err error, msg string, args ...any) {
+	args = append(args, "duration", time.Since(began))
+	switch {
+	case errors.Is(err, ErrDeadlineExceeded):
+		p.logger.Warn(msg+" abandoned at its deadline", args...)
+	case err != nil:
+		p.logger.Debug(msg+" failed", append(args, "error", err)...)
+	default:
+		p.logger.Debug(msg, args...)
+	}
+}
+
+// Return the graph the Planner currently searches
+func (p *Planner) Graph() *Graph {
+	return p.graph.Load()
+}
+
+// Replace the graph the Planner searches, e.g. with one built from updated
+// transit data, without waiting for plans under way. Those finish on the old
+// graph, while plans begun afterwards use the new one.
+func (p *Planner) SwapGraph(graph *Graph) {
+	p.graph.Store(graph)
+	p.logger.Info("swapped transit graph", "stations", len(graph.nodeMap))
+}
+
+// Plan the fastest trip between the specified stations, returning ErrNoRoute
+// if there is none
+func (p *Planner) Plan(start, dest string) (*Route, error) {
+	return p.PlanToAny(start, []string{dest})
+}
+
+// Plan the fastest trip from the specified station to whichever of the
+// specified destinations can be reached soonest, returning ErrNoRoute if none
+// can be reached
+func (p *Planner) PlanToAny(start string, dests []string) (*Route, error) {
+	graph := p.graph.Load()
+	for _, station := range append([]string{start}, dests...) {
+		if !graph.HasStation(station) {
+			return nil, &UnknownStationError{station}
+		}
+	}
+	began := time.Now()
+	var journey *Journey
+	var err error
+	if ch := graph.hierarchy.Load(); ch != nil && p.Options.unconstrained() {
+		journey, err = ch.ShortestPath(start, dests)
+	} else {
+		graph.mu.Lock()
+		defer graph.mu.Unlock()
+		npq := ResetGraph(graph.nodeMap)
+		journey, err = RunShortestPathsToAny(&npq, graph.nodeMap, start, dests, &p.Options)
+	}
+	if err != nil {
+		p.logSearch(began, err, "planned trip", "start", start, "destinations", dests)
+		return nil, err
+	}
+	p.logSearch(began, nil, "planned trip", "start", start, "destination", journey.To,
+		"minutes", journey.TotalMinutes)
+	return &Route{journey}, nil
+}
+
+// Plan the trip from the specified station to whichever of the specified
+// destinations lets it set off latest while still arriving by the specified
+// time (see PlanArriveBy), returning it along with the time it sets off.
+// The Planner's departure time is ignored.
+func (p *Planner) PlanArriveBy(start string, dests []string, arriveBy time.Time) (*Route, time.Time, error) {
+	graph := p.graph.Load()
+	for _, station := range append([]string{start}, dests...) {
+		if !graph.HasStation(station) {
+			return nil, time.Time{}, &UnknownStationError{station}
+		}
+	}
+	graph.mu.Lock()
+	defer graph.mu.Unlock()
+	began := time.Now()
+	route, departAt, err := PlanArriveBy(graph.nodeMap, start, dests, arriveBy, &p.Options)
+	if err != nil {
+		p.logSearch(began, err, "planned trip arriving by", "start", start, "destinations", dests,
+			"arriveBy", arriveBy)
+		return nil, time.Time{}, err
+	}
+	p.logSearch(began, nil, "planned trip arriving by", "start", start, "destination", route.Destination(),
+		"arriveBy", arriveBy, "departAt", departAt)
+	return route, departAt, nil
+}
+
+// Plan the quickest trip between the specified stations allowing the
+// specified taxi for the first part, the last part or all of it (see
+// PlanWithTaxi), between stations whose coordinates are known
+func (p *Planner) PlanWithTaxi(start, dest string, taxi Taxi) (*TaxiPlan, error) {
+	graph := p.graph.Load()
+	for _, station := range []string{start, dest} {
+		if !graph.HasStation(station) {
+			return nil, &UnknownStationError{station}
+		}
+	}
+	graph.mu.Lock()
+	defer graph.mu.Unlock()
+	began := time.Now()
+	plan, err := PlanWithTaxi(graph.nodeMap, start, dest, taxi, GetStationCoordinates(), &p.Options)
+	if err != nil {
+		p.logSearch(began, err, "planned trip with taxi", "start", start, "destination", dest)
+		return nil, err
+	}
+	p.logSearch(began, nil, "planned trip with taxi", "start", start, "destination", dest,
+		"minutes", plan.TotalMinutes())
+	return plan, nil
+}
+
+// Most distinct routes a caller should ask Alternatives for, as the gRPC API
+// and the command allow, which keeps any one request from holding up the
+// others for long
+const MaxAlternatives = 10
+
+// Plan up to k distinct trips between the specified stations, fastest first
+// (see KShortestPaths), returning ErrNoRoute if there are none
+func (p *Planner) Alternatives(start, dest string, k int) ([]*Route, error) {
+	graph := p.graph.Load()
+	for _, station := range []string{start, dest} {
+		if !graph.HasStation(station) {
+			return nil, &UnknownStationError{station}
+		}
+	}
+	graph.mu.Lock()
+	defer graph.mu.Unlock()
+	began := time.Now()
+	routes, err := KShortestPaths(graph.nodeMap, start, dest, k, &p.Options)
+	p.logSearch(began, err, "planned alternative trips", "start", start, "destination", dest,
+		"routes", len(routes))
+	return routes, err
+}
+
+// Plan every Pareto-optimal trip between the specified stations across
+// travel time, changes and walking, fastest first (see ParetoRoutes),
+// returning ErrNoRoute if there are none
+func (p *Planner) Tradeoffs(start, dest string) ([]Tradeoff, error) {
+	graph := p.graph.Load()
+	for _, station := range []string{start, dest} {
+		if !graph.HasStation(station) {
+			return nil, &UnknownStationError{station}
+		}
+	}
+	graph.mu.Lock()
+	defer graph.mu.Unlock()
+	began := time.Now()
+	tradeoffs, err := ParetoRoutes(graph.nodeMap, start, dest, &p.Options)
+	p.logSearch(began, err, "planned trade-offs", "start", start, "destination", dest,
+		"routes", len(tradeoffs))
+	return tradeoffs, err
+}
+
+// Plan every trip between the specified stations taking at most slack
+// minutes longer than the fastest, fastest first (see RoutesWithin),
+// returning ErrNoRoute if there are none
+func (p *Planner) RoutesWithin(start, dest string, slack uint16) ([]*Route, error) {
+	graph := p.graph.Load()
+	for _, station := range []string{start, dest} {
+		if !graph.HasStation(station) {
+			return nil, &UnknownStationError{station}
+		}
+	}
+	graph.mu.Lock()
+	defer graph.mu.Unlock()
+	began := time.Now()
+	routes, err := RoutesWithin(graph.nodeMap, start, dest, slack, &p.Options)
+	p.logSearch(began, err, "planned trips within slack", "start", start, "destination", dest,
+		"slack", slack, "routes", len(routes))
+	return routes, err
+}
+
+// Returned when planning a trip from or to a station not in the graph
+type UnknownStationError struct {
+	Station string
+}
+
+// Return a description of the error
+func (e *UnknownStationError) Error() string {
+	return e.Station + " is not a valid station"
+}
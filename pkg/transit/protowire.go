@@ -0,0 +1,130 @@
+package transit
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+// Wire types of the protocol buffer encoding used by the gRPC server, which
+// writes and reads the few messages of api/tubeplanner.proto by hand rather
+// than with generated code, so that no modules beyond the standard library
+// are needed
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// Builds an encoded protocol buffer message one field at a time. Fields
+// holding their type's zero value are left out, as proto3 does for fields
+// without explicit presence.
+type protoEncoder struct {
+	buf []byte
+}
+
+func (enc *protoEncoder) tag(field int, wireType int) {
+	enc.buf = binary.AppendUvarint(enc.buf, uint64(field)<<3|uint64(wireType))
+}
+
+func (enc *protoEncoder) uint(field int, value uint64) {
+	if value != 0 {
+		enc.tag(field, wireVarint)
+		enc.buf = binary.AppendUvarint(enc.buf, value)
+	}
+}
+
+func (enc *protoEncoder) int(field int, value int64) {
+	enc.uint(field, uint64(value))
+}
+
+func (enc *protoEncoder) bool(field int, value bool) {
+	if value {
+		enc.uint(field, 1)
+	}
+}
+
+func (enc *protoEncoder) string(field int, value string) {
+	if value != "" {
+		enc.tag(field, wireBytes)
+		enc.buf = binary.AppendUvarint(enc.buf, uint64(len(value)))
+		enc.buf = append(enc.buf, value...)
+	}
+}
+
+func (enc *protoEncoder) strings(field int, values []string) {
+	for _, value := range values {
+		enc.tag(field, wireBytes)
+		enc.buf = binary.AppendUvarint(enc.buf, uint64(len(value)))
+		enc.buf = append(enc.buf, value...)
+	}
+}
+
+// Add an optional double, which is written even when zero
+func (enc *protoEncoder) optionalDouble(field int, value float64) {
+	enc.tag(field, wireFixed64)
+	enc.buf = binary.LittleEndian.AppendUint64(enc.buf, math.Float64bits(value))
+}
+
+// Add an embedded message, which is written even when empty
+func (enc *protoEncoder) message(field int, msg []byte) {
+	enc.tag(field, wireBytes)
+	enc.buf = binary.AppendUvarint(enc.buf, uint64(len(msg)))
+	enc.buf = append(enc.buf, msg...)
+}
+
+var errMalformedProto = errors.New("malformed protocol buffer message")
+
+// A single field read from an encoded protocol buffer message, holding its
+// value as a number for varint and fixed-size fields, or as the raw bytes of
+// a string or embedded message
+type protoField struct {
+	number   int
+	wireType int
+	value    uint64
+	bytes    []byte
+}
+
+// Call the specified function with each field of the encoded message in
+// turn, stopping at the first error. Fields of wire types no message here
+// uses (the deprecated groups) make the message malformed.
+func readProtoFields(msg []byte, fn func(protoField) error) error {
+	for len(msg) > 0 {
+		tag, n := binary.Uvarint(msg)
+		if n <= 0 || tag>>3 == 0 || tag>>3 > math.MaxInt32 {
+			return errMalformedProto
+		}
+		msg = msg[n:]
+		field := protoField{number: int(tag >> 3), wireType: int(tag & 7)}
+		switch field.wireType {
+		case wireVarint:
+			if field.value, n = binary.Uvarint(msg); n <= 0 {
+				return errMalformedProto
+			}
+			msg = msg[n:]
+		case wireFixed64:
+			if len(msg) < 8 {
+				return errMalformedProto
+			}
+			field.value, msg = binary.LittleEndian.Uint64(msg), msg[8:]
+		case wireFixed32:
+			if len(msg) < 4 {
+				return errMalformedProto
+			}
+			field.value, msg = uint64(binary.LittleEndian.Uint32(msg)), msg[4:]
+		case wireBytes:
+			length, n := binary.Uvarint(msg)
+			if n <= 0 || length > uint64(len(msg)-n) {
+				return errMalformedProto
+			}
+			field.bytes, msg = msg[n:n+int(length)], msg[n+int(length):]
+		default:
+			return errMalformedProto
+		}
+		if err := fn(field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
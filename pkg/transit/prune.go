@@ -0,0 +1,111 @@
+package transit
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Represents a geographic area bounded by lines of latitude and longitude
+type BoundingBox struct {
+	minLat float64
+	minLon float64
+	maxLat float64
+	maxLon float64
+}
+
+// Return whether the specified coordinates lie within the bounding box
+func (box BoundingBox) Contains(c Coordinates) bool {
+	return c.lat >= box.minLat && c.lat <= box.maxLat && c.lon >= box.minLon && c.lon <= box.maxLon
+}
+
+// Parse a bounding box given as "minLat,minLon,maxLat,maxLon"
+func ParseBoundingBox(s string) (BoundingBox, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 4 {
+		return BoundingBox{}, fmt.Errorf("invalid bounding box %q, expected minLat,minLon,maxLat,maxLon", s)
+	}
+	values := make([]float64, 4)
+	for idx, part := range parts {
+		value, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return BoundingBox{}, fmt.Errorf("invalid bounding box %q: %q is not a number", s, part)
+		}
+		values[idx] = value
+	}
+	box := BoundingBox{values[0], values[1], values[2], values[3]}
+	if box.minLat > box.maxLat || box.minLon > box.maxLon {
+		return BoundingBox{}, fmt.Errorf("invalid bounding box %q, minimums exceed maximums", s)
+	}
+	return box, nil
+}
+
+// Parse a range of fare zones given as a single zone ("2") or an inclusive
+// range ("1-2")
+func ParseZoneRange(s string) (uint8, uint8, error) {
+	lowStr, highStr, isRange := strings.Cut(s, "-")
+	if !isRange {
+		highStr = lowStr
+	}
+	low, lowErr := strconv.ParseUint(strings.TrimSpace(lowStr), 10, 8)
+	high, highErr := strconv.ParseUint(strings.TrimSpace(highStr), 10, 8)
+	if lowErr != nil || highErr != nil || low == 0 || low > high {
+		return 0, 0, fmt.Errorf("invalid zone range %q, expected e.g. 2 or 1-2", s)
+	}
+	return uint8(low), uint8(high), nil
+}
+
+// Return a predicate selecting the stations in any of the fare zones from
+// low to high. Stations on a zone boundary are selected if either of their
+// zones is in range; stations with no known zone are never selected.
+func ZoneArea(low, high uint8) func(station string) bool {
+	zones := GetStationZones()
+	return func(station string) bool {
+		zone, exists := zones[station]
+		return exists && zone.low <= high && zone.high >= low
+	}
+}
+
+// Return a predicate selecting the stations whose coordinates lie within the
+// specified bounding box. Stations with no known coordinates are never
+// selected.
+func BoundingBoxArea(box BoundingBox) func(station string) bool {
+	coords := GetStationCoordinates()
+	return func(station string) bool {
+		c, exists := coords[station]
+		return exists && box.Contains(c)
+	}
+}
+
+// Remove every station not selected by the specified predicate from the
+// graph, along with all links leading to it, returning the number of
+// stations removed
+func PruneGraph(nodeMap NodeMap, keep func(station string) bool) int {
+	removed := 0
+	for station := range nodeMap {
+		if !keep(station) {
+			delete(nodeMap, station)
+			removed++
+		}
+	}
+	for _, lines := range nodeMap {
+		for _, node := range lines {
+			node.adj = slices.DeleteFunc(node.adj, func(link *Link) bool {
+				_, exists := nodeMap[link.endNode.station]
+				return !exists
+			})
+		}
+	}
+	return removed
+}
+
+// Return whether the specified station appears anywhere in the transit data
+// the specified DataSource supplies (the built-in data if nil), regardless
+// of any pruning
+func StationInDataset(source DataSource, station string) bool {
+	railLinks, _, _ := LoadDataset(source)
+	return slices.ContainsFunc(railLinks, func(rl RailLink) bool {
+		return rl.fromStation == station || rl.toStation == station
+	})
+}
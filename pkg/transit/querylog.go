@@ -0,0 +1,60 @@
+package transit
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+)
+
+// A single query answered in --stdio-json mode, as recorded in the query log
+// along with the response given at the time
+type QueryLogEntry struct {
+	LoggedAt time.Time    `json:"loggedAt"`
+	Query    JSONQuery    `json:"query"`
+	Response JSONResponse `json:"response"`
+}
+
+// Opt-in append-only log of the queries answered in --stdio-json mode, kept
+// as one JSON object per line in a plain text file so that real usage can be
+// replayed against later versions of the planner or its data
+type QueryLog struct {
+	path string
+}
+
+// Return a handle on the query log at the specified path, which is created
+// on first write if it does not exist already
+func OpenQueryLog(path string) *QueryLog {
+	return &QueryLog{path}
+}
+
+// Append the specified query and the response given to it to the log
+func (ql *QueryLog) Record(query JSONQuery, response JSONResponse) error {
+	line, err := json.Marshal(QueryLogEntry{time.Now(), query, response})
+	if err != nil {
+		return err
+	}
+	return appendLines(ql.path, append(line, '\n'))
+}
+
+// Return every entry in the log, in the order they were recorded
+func (ql *QueryLog) Entries() ([]QueryLogEntry, error) {
+	file, err := os.Open(ql.path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	entries := make([]QueryLogEntry, 0)
+	err = scanJSONLines(file, ql.path, func(line []byte) error {
+		var entry QueryLogEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return err
+		}
+		entries = append(entries, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
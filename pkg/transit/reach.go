@@ -0,0 +1,157 @@
+package transit
+
+import (
+	"container/heap"
+	"math"
+	"time"
+)
+
+// Represents a link of the transit graph followed backwards, from the Node
+// it leads to back to the Node it leaves
+type reverseLink struct {
+	fromNode *Node
+	link     *Link
+}
+
+// Return the links leading to each Node of the graph, followed backwards
+func reverseLinks(nodeMap NodeMap) map[*Node][]reverseLink {
+	reverse := make(map[*Node][]reverseLink)
+	for _, lines := range nodeMap {
+		for _, node := range lines {
+			for _, link := range node.adj {
+				reverse[link.endNode] = append(reverse[link.endNode], reverseLink{node, link})
+			}
+		}
+	}
+	return reverse
+}
+
+// Return, for every station from which the specified target station can be
+// reached within the specified number of minutes, the time the trip takes.
+// This runs Dijkstra's algorithm backwards from the target over the reversed
+// graph, so all origins are found in a single search. Run times are the
+// all-day ones, as the time each link is reached depends on the origin.
+func StationsReaching(nodeMap NodeMap, target string, within uint16, opts *SearchOptions) map[string]uint16 {
+	reverse := reverseLinks(nodeMap)
+	var reverseOpts SearchOptions
+	if opts != nil {
+		reverseOpts = *opts
+	}
+	reverseOpts.DepartAt = time.Time{}
+
+	// Arriving at the target ends with the walk out of the station, which
+	// is where the backwards search begins
+	npq := ResetGraph(nodeMap)
+	for _, node := range nodeMap[target] {
+		if !reverseOpts.closed(node) && reverseOpts.accessible(node) {
+			npq.update(node, reverseOpts.accessTime(node))
+		}
+	}
+	for len(npq) > 0 {
+		curNode := heap.Pop(&npq).(*Node)
+		if curNode.totalTime > within {
+			break
+		}
+		for _, rl := range reverse[curNode] {
+			if reverseOpts.closed(rl.fromNode) || reverseOpts.blocked(rl.fromNode, rl.link) {
+				continue
+			}
+			// An interchange into the current Node's line means waiting for
+			// one of its trains there
+			altDistance := AddTime(curNode.totalTime, reverseOpts.linkTime(rl.link, 0))
+			if rl.link.attrs.Mode != ModeRail {
+				altDistance = AddTime(altDistance, curNode.boardTime)
+				altDistance = AddTime(altDistance, reverseOpts.boardingPenalty(curNode))
+				altDistance = AddTime(altDistance, reverseOpts.interchangePenalty(rl.link))
+			}
+			if altDistance < rl.fromNode.totalTime {
+				rl.fromNode.totalTime = altDistance
+				npq.update(rl.fromNode, altDistance)
+			}
+		}
+	}
+
+	// Starting a trip means walking in to the platform and waiting for a
+	// train, on whichever line gets there soonest
+	reaching := make(map[string]uint16)
+	for station, lines := range nodeMap {
+		if station == target {
+			continue
+		}
+		best := uint16(math.MaxUint16)
+		for _, node := range lines {
+			if node.closed || node.totalTime == math.MaxUint16 || !reverseOpts.accessible(node) {
+				continue
+			}
+			best = min(best, AddTime(node.totalTime, AddTime(reverseOpts.accessTime(node), node.boardTime)))
+		}
+		if best <= within {
+			reaching[station] = best
+		}
+	}
+	return reaching
+}
+
+// Return the time taken to travel from the specified start station to every
+// station reachable from it, in a single search of the graph. Times count
+// from entering the start station to leaving the destination, as for
+// RunShortestPaths, though any boarding penalties are included. Closed
+// stations are left out.
+func TravelTimesFrom(nodeMap NodeMap, start string, opts *SearchOptions) map[string]uint16 {
+	times, _ := ZoneCrossingsFrom(nodeMap, start, opts)
+	return times
+}
+
+// Return the travel time from the specified start station to every station
+// reachable from it, as TravelTimesFrom does, along with how the quickest
+// trip to each crosses the fare zones (see RouteZoneCrossings)
+func ZoneCrossingsFrom(nodeMap NodeMap, start string, opts *SearchOptions) (map[string]uint16,
+	map[string]ZoneCrossings) {
+	npq := ResetGraph(nodeMap)
+	starts := startCosts(nodeMap, start, opts)
+	for node, start := range starts {
+		npq.update(node, start.cost)
+		node.elapsed = start.elapsed
+	}
+	nodePrev := make(map[*Node]*Node)
+	times := map[string]uint16{start: 0}
+	arrivals := make(map[string]*Node)
+	for len(npq) > 0 {
+		curNode := heap.Pop(&npq).(*Node)
+		if curNode.totalTime == math.MaxUint16 {
+			break
+		}
+		// Trains run through closed stations without stopping, so the trip
+		// cannot end at one
+		if !curNode.closed && (opts == nil || !opts.ClosedStations[curNode.station]) && opts.accessible(curNode) {
+			arrival := AddTime(curNode.totalTime, opts.accessTime(curNode))
+			if best, reached := times[curNode.station]; !reached || arrival < best {
+				times[curNode.station] = arrival
+				arrivals[curNode.station] = curNode
+			}
+		}
+		for _, link := range curNode.adj {
+			if opts.blocked(curNode, link) {
+				continue
+			}
+			if alt := opts.linkCost(curNode, link, curNode.progress(starts)); alt.cost < link.endNode.totalTime {
+				npq.update(link.endNode, alt.cost)
+				link.endNode.elapsed = alt.elapsed
+				nodePrev[link.endNode] = curNode
+			}
+		}
+	}
+
+	crossings := map[string]ZoneCrossings{start: {}}
+	for station, node := range arrivals {
+		if station == start {
+			continue
+		}
+		var route []*Node
+		for ; node != nil; node = nodePrev[node] {
+			route = append(route, node)
+		}
+		crossings[station] = RouteZoneCrossings(route)
+	}
+	return times, crossings
+}
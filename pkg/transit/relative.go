@@ -0,0 +1,71 @@
+package transit
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Whether PrintDirections and DirectionLines give times as countdowns from
+// now (see RelativePhrases) rather than minutes into the trip, and how many
+// minutes from now the trip sets off if so
+var (
+	RelativeDirections bool
+	RelativeLead       uint16
+)
+
+// Return how many whole minutes from now the specified departure time is,
+// as a lead for RelativePhrases, or zero if it has passed
+func LeadMinutes(departAt, now time.Time) uint16 {
+	minutes := math.Round(departAt.Sub(now).Minutes())
+	return uint16(max(0, min(minutes, math.MaxUint16-1)))
+}
+
+// Words directions tersely with each time given as a countdown from now,
+// e.g. "in 12 min", suited to following along on a phone when setting off
+// at once. Lead is how many minutes from now the trip sets off, so that the
+// countdowns still hold for a later departure.
+type RelativePhrases struct {
+	StandardPhrases
+	Lead uint16
+}
+
+// Return the time the specified number of minutes into the trip as a
+// countdown from now
+func (p RelativePhrases) countdown(minutes uint16) string {
+	switch minutes = AddTime(minutes, p.Lead); minutes {
+	case 0:
+		return "now"
+	case 1:
+		return "in 1 min"
+	default:
+		return fmt.Sprintf("in %d min", minutes)
+	}
+}
+
+func (p RelativePhrases) Begin(station string) string {
+	return fmt.Sprintf("1) Leave from %s %s", station, p.countdown(0))
+}
+
+func (p RelativePhrases) Board(step int, line string) string {
+	return fmt.Sprintf("%d) Take the %s line:", step, line)
+}
+
+func (p RelativePhrases) Stop(station string, minutes uint16, closed bool) string {
+	if closed {
+		return fmt.Sprintf("- %s %s (closed, not stopping)", station, p.countdown(minutes))
+	}
+	return fmt.Sprintf("- %s %s", station, p.countdown(minutes))
+}
+
+func (p RelativePhrases) ChangeLines(step int, station, line string, minutes uint16) string {
+	return fmt.Sprintf("%d) At %s change to the %s line, boarding %s", step, station, line, p.countdown(minutes))
+}
+
+func (p RelativePhrases) Walk(step int, from, to string, minutes uint16) string {
+	return fmt.Sprintf("%d) Walk from %s to %s, there %s", step, from, to, p.countdown(minutes))
+}
+
+func (p RelativePhrases) Arrive(step int, station string, minutes uint16) string {
+	return fmt.Sprintf("%d) Arrive at %s %s", step, station, p.countdown(minutes))
+}
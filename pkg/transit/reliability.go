@@ -0,0 +1,120 @@
+package transit
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Share of the usual run time by which a line's trains are expected to be
+// delayed while it has each reported severity. Closures count as doubling
+// the trip, as the line has to be avoided altogether once they happen.
+// Severities not listed mean a good service.
+var severityDelays = map[string]float64{
+	"Minor Delays":        0.2,
+	"Reduced Service":     0.25,
+	"Severe Delays":       0.5,
+	"Part Suspended":      0.5,
+	"Part Closure":        0.5,
+	"Suspended":           1,
+	"Closed":              1,
+	"Planned Closure":     1,
+	"Not Running":         1,
+	"Service Closed":      1,
+	"Special Service":     0.1,
+	"Change of Frequency": 0.1,
+}
+
+// Represents how dependable a line has been, judged from its stored statuses
+type LineReliability struct {
+	Line string
+	// Number of statuses the judgement is based on
+	Samples int
+	// Share of the statuses reporting anything other than a good service
+	DisruptedShare float64
+	// Expected delay as a share of the usual run time, averaged over the
+	// statuses
+	ExpectedDelay float64
+}
+
+// Return the reliability of every line with statuses stored since the
+// specified time
+func (sh *StatusHistory) Reliability(since time.Time) (map[string]LineReliability, error) {
+	reliability := make(map[string]LineReliability)
+	err := sh.scan(func(status LineStatus) {
+		if status.FetchedAt.Before(since) {
+			return
+		}
+		lr := reliability[status.Line]
+		lr.Line = status.Line
+		lr.Samples++
+		if status.Severity != "Good Service" {
+			lr.DisruptedShare++
+		}
+		lr.ExpectedDelay += severityDelays[status.Severity]
+		reliability[status.Line] = lr
+	})
+	if err != nil {
+		return nil, err
+	}
+	for line, lr := range reliability {
+		lr.DisruptedShare /= float64(lr.Samples)
+		lr.ExpectedDelay /= float64(lr.Samples)
+		reliability[line] = lr
+	}
+	return reliability, nil
+}
+
+// Return the expected delay of each line as a share of its run times, as
+// used by SearchOptions.LineDelays
+func LineDelays(reliability map[string]LineReliability) map[string]float64 {
+	delays := make(map[string]float64, len(reliability))
+	for line, lr := range reliability {
+		delays[line] = lr.ExpectedDelay
+	}
+	return delays
+}
+
+// Return the search cost added for the delays to expect along the specified
+// rail link, if the options give any for its line
+func (opts *SearchOptions) delayPenalty(link *Link, runTime uint16) uint16 {
+	if opts == nil || link.attrs.Mode != ModeRail {
+		return 0
+	}
+	return uint16(min(math.Round(float64(runTime)*opts.LineDelays[link.endNode.line]), MaxTravelTime))
+}
+
+// Return the delay in minutes to expect along the rides of the specified
+// journey, given the expected delay of each line as a share of its run times
+func ExpectedRouteDelay(journey *Journey, lineDelays map[string]float64) uint16 {
+	delay := 0.0
+	for _, leg := range journey.Legs {
+		if leg.IsRide() {
+			delay += float64(leg.Minutes) * lineDelays[leg.Line]
+		}
+	}
+	return uint16(min(math.Round(delay), math.MaxUint16))
+}
+
+// Print the reliability of each line, most dependable first
+func PrintLineReliability(since time.Time, reliability map[string]LineReliability) {
+	fmt.Printf("Line reliability since %s:\n", since.Local().Format("2006-01-02 15:04"))
+	if len(reliability) == 0 {
+		fmt.Println("No statuses recorded.")
+		return
+	}
+	lines := make([]LineReliability, 0, len(reliability))
+	for _, lr := range reliability {
+		lines = append(lines, lr)
+	}
+	slices.SortFunc(lines, func(a, b LineReliability) int {
+		return cmp.Or(cmp.Compare(a.ExpectedDelay, b.ExpectedDelay), strings.Compare(a.Line, b.Line))
+	})
+	for _, lr := range lines {
+		fmt.Printf("- %s: disrupted %.0f%% of the time, usual delays add %.0f%% to run times "+
+			"(%d statuses)\n", lr.Line, 100*lr.DisruptedShare, 100*lr.ExpectedDelay, lr.Samples)
+	}
+}
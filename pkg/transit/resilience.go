@@ -0,0 +1,102 @@
+package transit
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+	"sync"
+)
+
+// Represents how trips across the network fare without one of its lines:
+// how many trips between pairs of stations can no longer be made at all,
+// and how many of the rest take longer than allowed, out of those which can
+// be made with every line. Trips count in each direction. Stranded counts
+// the stations served by no other line, whose trips are all cut off.
+type LineImpact struct {
+	Line         string `json:"line"`
+	Disconnected int    `json:"disconnected"`
+	Slower       int    `json:"slower"`
+	Stranded     int    `json:"stranded"`
+}
+
+// Return the impact on trips between every pair of stations of each line of
+// the graph vanishing in turn, under the specified search options (which may
+// be nil), counting trips as slower if they take more than the specified
+// number of minutes longer than with every line. Lines the options close
+// already are left out. The lines are ranked by how many trips they cut
+// off, then by how many they slow down, most first, and returned with the
+// number of trips which can be made with every line. Each line takes one
+// sweep of the graph from every station, so lines are searched at once on
+// copies of the graph.
+func LineRemovalImpacts(nodeMap NodeMap, slowerBy uint16, opts *SearchOptions) ([]LineImpact, int) {
+	stations := slices.Sorted(maps.Keys(nodeMap))
+	lines := make([]string, 0)
+	for _, stationLines := range nodeMap {
+		for line := range stationLines {
+			if !slices.Contains(lines, line) && !opts.closed(stationLines[line]) {
+				lines = append(lines, line)
+			}
+		}
+	}
+	slices.Sort(lines)
+
+	baseline := make(map[string]map[string]uint16, len(stations))
+	trips := 0
+	for _, station := range stations {
+		baseline[station] = TravelTimesFrom(nodeMap, station, opts)
+		trips += len(baseline[station]) - 1
+	}
+
+	impacts := make([]LineImpact, len(lines))
+	var wg sync.WaitGroup
+	for idx, line := range lines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			graph := CloneGraph(nodeMap)
+			var without SearchOptions
+			if opts != nil {
+				without = *opts
+			}
+			without.ClosedLines = maps.Clone(without.ClosedLines)
+			if without.ClosedLines == nil {
+				without.ClosedLines = make(map[string]bool)
+			}
+			without.ClosedLines[line] = true
+
+			impact := LineImpact{Line: line}
+			for _, station := range stations {
+				served, stranded := false, true
+				for _, node := range graph[station] {
+					if !opts.closed(node) {
+						served, stranded = true, stranded && node.line == line
+					}
+				}
+				if served && stranded {
+					impact.Stranded++
+				}
+				times := TravelTimesFrom(graph, station, &without)
+				for dest, before := range baseline[station] {
+					if dest == station {
+						continue
+					}
+					after, reached := times[dest]
+					switch {
+					case !reached:
+						impact.Disconnected++
+					case after > AddTime(before, slowerBy):
+						impact.Slower++
+					}
+				}
+			}
+			impacts[idx] = impact
+		}()
+	}
+	wg.Wait()
+
+	slices.SortStableFunc(impacts, func(a, b LineImpact) int {
+		return cmp.Or(cmp.Compare(b.Disconnected, a.Disconnected), cmp.Compare(b.Slower, a.Slower),
+			cmp.Compare(a.Line, b.Line))
+	})
+	return impacts, trips
+}
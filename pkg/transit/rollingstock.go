@@ -0,0 +1,26 @@
+package transit
+
+import "slices"
+
+// Return a description of how deep the specified line runs and whether its
+// trains are air-conditioned, printed under each ride in detailed directions,
+// e.g. for riders choosing between comparable routes in summer or who find
+// narrow tunnels uncomfortable, or "" for lines the transit data says
+// nothing about
+func LineDetailsText(line string) string {
+	var depth string
+	switch {
+	case slices.Contains(GetDeepLevelLines(), line):
+		depth = "Deep-level tube in narrow tunnels"
+	case slices.Contains(GetSubSurfaceLines(), line):
+		depth = "Sub-surface line in shallow tunnels"
+	case slices.Contains(GetSurfaceLines(), line):
+		depth = "Mostly above ground"
+	default:
+		return ""
+	}
+	if slices.Contains(GetAirConditionedLines(), line) {
+		return depth + ", air-conditioned trains"
+	}
+	return depth + ", trains without air conditioning"
+}
@@ -0,0 +1,174 @@
+package transit
+
+import (
+	"container/heap"
+	"errors"
+	"math"
+	"slices"
+	"time"
+)
+
+// Number of paths through the graph RoutesWithin may find before giving up,
+// as the number of routes grows quickly with the slack allowed
+const maxPathsWithin = 10000
+
+// Returned by RoutesWithin when more routes are within the slack allowed
+// than it is prepared to enumerate
+var ErrTooManyRoutes = errors.New("too many routes within the slack allowed")
+
+// Return the least time the specified link could take at any time of day
+func (opts *SearchOptions) leastLinkTime(link *Link) uint16 {
+	least := opts.linkTime(link, 0)
+	for _, bandTime := range link.attrs.BandTimes {
+		least = min(least, bandTime)
+	}
+	return least
+}
+
+// Return the least wait for a train on the line at the end of the specified
+// interchange, at any time of day, after taking the specified minutes to
+// follow it. A change taking longer only ever ends later, so with the least
+// time for the interchange this bounds the two together.
+func leastChangeWait(link *Link, runTime uint16) uint16 {
+	allowance, shortfall := connectionAllowance(link, runTime)
+	least := link.endNode.boardTime
+	if service := link.endNode.service; service != nil {
+		wait := (min(service.peakHeadway, service.offPeakHeadway) + 1) / 2
+		least = min(least, wait-min(wait, allowance))
+	}
+	return AddTime(least, shortfall)
+}
+
+// Return, for every Node from which the specified destination station can
+// be reached, a lower bound on the search cost of getting there and walking
+// out of it. This runs Dijkstra's algorithm backwards from the destination
+// with the least run time of each link and the least wait for each train, so
+// the bound holds whatever time the trip is made at.
+func costsToDestination(nodeMap NodeMap, dest string, opts *SearchOptions) map[*Node]uint16 {
+	reverse := reverseLinks(nodeMap)
+	var boundOpts SearchOptions
+	if opts != nil {
+		boundOpts = *opts
+	}
+	boundOpts.DepartAt = time.Time{}
+
+	npq := ResetGraph(nodeMap)
+	for _, node := range nodeMap[dest] {
+		if !boundOpts.closed(node) && boundOpts.accessible(node) {
+			npq.update(node, boundOpts.accessTime(node))
+		}
+	}
+	remaining := make(map[*Node]uint16)
+	for len(npq) > 0 {
+		curNode := heap.Pop(&npq).(*Node)
+		if curNode.totalTime == math.MaxUint16 {
+			break
+		}
+		remaining[curNode] = curNode.totalTime
+		for _, rl := range reverse[curNode] {
+			if boundOpts.closed(rl.fromNode) || boundOpts.blocked(rl.fromNode, rl.link) {
+				continue
+			}
+			runTime := boundOpts.leastLinkTime(rl.link)
+			altDistance := AddTime(curNode.totalTime, runTime)
+			if rl.link.attrs.Mode != ModeRail {
+				altDistance = AddTime(altDistance, leastChangeWait(rl.link, runTime))
+				altDistance = AddTime(altDistance, boundOpts.boardingPenalty(curNode))
+				altDistance = AddTime(altDistance, boundOpts.interchangePenalty(rl.link))
+			}
+			if altDistance < rl.fromNode.totalTime {
+				npq.update(rl.fromNode, altDistance)
+			}
+		}
+	}
+	return remaining
+}
+
+// Return every route between the specified stations whose search cost (the
+// travel time, unless the options add penalties) is within slack minutes of
+// the fastest route's, fastest first. Routes never pass through the same
+// station twice. As for KShortestPaths, routes through the same stations as
+// a faster route, only on a different line sharing its tracks, are skipped.
+// The routes are enumerated by a depth-first search from the start, cut short
+// wherever even the quickest way on to the destination would exceed the
+// slack. ErrTooManyRoutes is returned if the slack allows too many to list.
+func RoutesWithin(nodeMap NodeMap, start, dest string, slack uint16, opts *SearchOptions) ([]*Route, error) {
+	if start == dest {
+		return []*Route{{arrivedJourney(start)}}, nil
+	}
+	starts := startCosts(nodeMap, start, opts)
+	npq := ResetGraph(nodeMap)
+	nodes, links, err := shortestPath(&npq, starts, map[string]bool{dest: true}, opts)
+	if err != nil {
+		return nil, err
+	}
+	limit := AddTime(pathCost(starts, nodes, links, opts), slack)
+	remaining := costsToDestination(nodeMap, dest, opts)
+
+	found := make([]candidatePath, 0)
+	visitedStations := make(map[string]bool)
+	visitedNodes := make(map[*Node]bool)
+	var pathNodes []*Node
+	var pathLinks []*Link
+	var explore func(node *Node, at progress) error
+	explore = func(node *Node, at progress) error {
+		if bound, reachable := remaining[node]; !reachable || AddTime(at.cost, bound) > limit {
+			return nil
+		}
+		if opts != nil && !opts.Deadline.IsZero() && time.Now().After(opts.Deadline) {
+			return ErrDeadlineExceeded
+		}
+		pathNodes = append(pathNodes, node)
+		defer func() { pathNodes = pathNodes[:len(pathNodes)-1] }()
+		if node.station == dest {
+			if !opts.accessible(node) {
+				return nil
+			}
+			if len(found) == maxPathsWithin {
+				return ErrTooManyRoutes
+			}
+			found = append(found, candidatePath{slices.Clone(pathNodes), slices.Clone(pathLinks),
+				AddTime(at.cost, opts.accessTime(node))})
+			return nil
+		}
+		visitedNodes[node] = true
+		defer delete(visitedNodes, node)
+		if !visitedStations[node.station] {
+			visitedStations[node.station] = true
+			defer delete(visitedStations, node.station)
+		}
+
+		// The validity of links is checked at the time they are reached
+		node.totalTime, node.elapsed = at.cost, at.elapsed
+		for _, link := range node.adj {
+			next := link.endNode
+			if opts.blocked(node, link) || visitedNodes[next] ||
+				(next.station != node.station && visitedStations[next.station]) {
+				continue
+			}
+			pathLinks = append(pathLinks, link)
+			err := explore(next, opts.linkCost(node, link, at))
+			pathLinks = pathLinks[:len(pathLinks)-1]
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for node, at := range starts {
+		if err := explore(node, at); err != nil {
+			return nil, err
+		}
+	}
+
+	slices.SortStableFunc(found, candidatePath.compare)
+	routes := make([]*Route, 0)
+	seenRoutes := make(map[string]bool)
+	for _, path := range found {
+		if key := pathKey(path.nodes, true); !seenRoutes[key] {
+			seenRoutes[key] = true
+			routes = append(routes, &Route{newJourney(path.nodes, path.links, opts)})
+		}
+	}
+	return routes, nil
+}
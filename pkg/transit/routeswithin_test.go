@@ -0,0 +1,52 @@
+package transit
+
+import (
+	"errors"
+	"testing"
+)
+
+// RoutesWithin begins with the fastest route and lists every route within
+// the slack of it, so includes each of the k shortest routes that is
+func TestRoutesWithinMatchKShortestPaths(t *testing.T) {
+	nodeMap := defaultNodeMap(t)
+	const slack = 4
+	for _, pair := range samplePairs(nodeMap, 30) {
+		within, err := RoutesWithin(nodeMap, pair[0], pair[1], slack, nil)
+		if errors.Is(err, ErrTooManyRoutes) {
+			continue
+		} else if err != nil {
+			t.Fatalf("%s to %s: RoutesWithin failed: %v", pair[0], pair[1], err)
+		}
+		npq := ResetGraph(nodeMap)
+		fastest, err := RunShortestPaths(&npq, nodeMap, pair[0], pair[1], nil)
+		if err != nil {
+			t.Fatalf("%s to %s: %v", pair[0], pair[1], err)
+		}
+		if got, want := within[0].Journey().RouteHash(), fastest.RouteHash(); got != want {
+			t.Errorf("%s to %s: first route within the slack is %s, the shortest path %s",
+				pair[0], pair[1], got, want)
+		}
+		listed := make(map[string]bool)
+		for idx, route := range within {
+			listed[route.Journey().RouteHash()] = true
+			if route.TotalMinutes() > fastest.TotalMinutes+slack {
+				t.Errorf("%s to %s: route %d takes %d minutes, more than %d past the fastest %d",
+					pair[0], pair[1], idx+1, route.TotalMinutes(), slack, fastest.TotalMinutes)
+			}
+			if idx > 0 && route.TotalMinutes() < within[idx-1].TotalMinutes() {
+				t.Errorf("%s to %s: route %d is faster than the one before", pair[0], pair[1], idx+1)
+			}
+		}
+
+		routes, err := KShortestPaths(nodeMap, pair[0], pair[1], 4, nil)
+		if err != nil {
+			t.Fatalf("%s to %s: KShortestPaths failed: %v", pair[0], pair[1], err)
+		}
+		for _, route := range routes {
+			if route.TotalMinutes() <= fastest.TotalMinutes+slack && !listed[route.Journey().RouteHash()] {
+				t.Errorf("%s to %s: a route of %d minutes from KShortestPaths is missing from RoutesWithin",
+					pair[0], pair[1], route.TotalMinutes())
+			}
+		}
+	}
+}
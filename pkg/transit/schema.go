@@ -0,0 +1,177 @@
+package transit
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// Journey as output before the JSON schema was versioned, which counts as
+// version 1. Kept unchanged so that output saved by older versions can still
+// be decoded after Journey evolves.
+type JourneyV1 struct {
+	From         string   `json:"from"`
+	To           string   `json:"to"`
+	TotalMinutes uint16   `json:"totalMinutes"`
+	Legs         []LegV1  `json:"legs"`
+	DataWarnings []string `json:"dataWarnings,omitempty"`
+}
+
+// Leg of a JourneyV1
+type LegV1 struct {
+	Type     string   `json:"type"`
+	Line     string   `json:"line,omitempty"`
+	FromLine string   `json:"fromLine,omitempty"`
+	ToLine   string   `json:"toLine,omitempty"`
+	From     string   `json:"from"`
+	To       string   `json:"to"`
+	Depart   uint16   `json:"depart"`
+	Arrive   uint16   `json:"arrive"`
+	Stops    []StopV1 `json:"stops,omitempty"`
+	Guidance string   `json:"guidance,omitempty"`
+}
+
+// Stop of a LegV1
+type StopV1 struct {
+	Station string `json:"station"`
+	Time    uint16 `json:"time"`
+	Closed  bool   `json:"closed,omitempty"`
+}
+
+// Convert the version 1 journey to the current schema
+func (v1 *JourneyV1) Upgrade() *Journey {
+	journey := &Journey{SchemaVersion: JourneySchemaVersion, From: v1.From, To: v1.To,
+		TotalMinutes: v1.TotalMinutes, Legs: make([]Leg, 0, len(v1.Legs)), DataWarnings: v1.DataWarnings}
+	for _, legV1 := range v1.Legs {
+		leg := Leg{Type: legV1.Type, Line: legV1.Line, FromLine: legV1.FromLine, ToLine: legV1.ToLine,
+			From: legV1.From, To: legV1.To, Depart: legV1.Depart, Arrive: legV1.Arrive,
+			Guidance: legV1.Guidance}
+		for _, stop := range legV1.Stops {
+			leg.Stops = append(leg.Stops, Stop{stop.Station, stop.Time, stop.Closed})
+		}
+		journey.Legs = append(journey.Legs, leg)
+	}
+	journey.addEndLegs()
+	return journey
+}
+
+// Journey as output in version 2 of the schema, before legs for boarding
+// and alighting were added and each leg given its Minutes
+type JourneyV2 struct {
+	SchemaVersion int      `json:"schemaVersion"`
+	From          string   `json:"from"`
+	To            string   `json:"to"`
+	TotalMinutes  uint16   `json:"totalMinutes"`
+	Legs          []LegV2  `json:"legs"`
+	DataWarnings  []string `json:"dataWarnings,omitempty"`
+	Fare          *Fare    `json:"fare,omitempty"`
+}
+
+// Leg of a JourneyV2, whose stops are as in version 1
+type LegV2 struct {
+	Type        string   `json:"type"`
+	Line        string   `json:"line,omitempty"`
+	FromLine    string   `json:"fromLine,omitempty"`
+	ToLine      string   `json:"toLine,omitempty"`
+	From        string   `json:"from"`
+	To          string   `json:"to"`
+	Depart      uint16   `json:"depart"`
+	Arrive      uint16   `json:"arrive"`
+	Stops       []StopV1 `json:"stops,omitempty"`
+	Guidance    string   `json:"guidance,omitempty"`
+	LineDetails string   `json:"lineDetails,omitempty"`
+	WalkMinutes uint16   `json:"walkMinutes,omitempty"`
+}
+
+// Convert the version 2 journey to the current schema
+func (v2 *JourneyV2) Upgrade() *Journey {
+	journey := &Journey{SchemaVersion: JourneySchemaVersion, From: v2.From, To: v2.To,
+		TotalMinutes: v2.TotalMinutes, Legs: make([]Leg, 0, len(v2.Legs)+2), DataWarnings: v2.DataWarnings,
+		Fare: v2.Fare}
+	for _, legV2 := range v2.Legs {
+		leg := Leg{Type: legV2.Type, Line: legV2.Line, FromLine: legV2.FromLine, ToLine: legV2.ToLine,
+			From: legV2.From, To: legV2.To, Depart: legV2.Depart, Arrive: legV2.Arrive,
+			Guidance: legV2.Guidance, LineDetails: legV2.LineDetails, WalkMinutes: legV2.WalkMinutes}
+		for _, stop := range legV2.Stops {
+			leg.Stops = append(leg.Stops, Stop{stop.Station, stop.Time, stop.Closed})
+		}
+		journey.Legs = append(journey.Legs, leg)
+	}
+	journey.addEndLegs()
+	return journey
+}
+
+// Complete the legs of a journey upgraded from before version 3 by working
+// out each one's Minutes and adding the boarding and alighting legs around
+// them. Those versions counted the walk out at the destination as part of
+// the last leg, so the alighting leg takes no time.
+func (journey *Journey) addEndLegs() {
+	if len(journey.Legs) == 0 {
+		return
+	}
+	for idx := range journey.Legs {
+		leg := &journey.Legs[idx]
+		leg.Minutes = leg.Arrive - leg.Depart
+	}
+	first, last := journey.Legs[0], journey.Legs[len(journey.Legs)-1]
+	board := Leg{Type: LegBoard, Line: cmp.Or(first.Line, first.FromLine), From: first.From, To: first.From,
+		Arrive: first.Depart, Minutes: first.Depart}
+	alight := Leg{Type: LegAlight, Line: cmp.Or(last.Line, last.ToLine), From: last.To, To: last.To,
+		Depart: last.Arrive, Arrive: journey.TotalMinutes, Minutes: journey.TotalMinutes - last.Arrive}
+	journey.Legs = slices.Concat([]Leg{board}, journey.Legs, []Leg{alight})
+}
+
+// Decode a Journey from JSON output of any schema version up to the current
+// one, upgrading earlier versions to the current schema. Output without a
+// schemaVersion is version 1.
+func DecodeJourney(data []byte) (*Journey, error) {
+	var header struct {
+		SchemaVersion int `json:"schemaVersion"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, err
+	}
+	switch header.SchemaVersion {
+	case 0, 1:
+		var v1 JourneyV1
+		if err := json.Unmarshal(data, &v1); err != nil {
+			return nil, err
+		}
+		return v1.Upgrade(), nil
+	case 2:
+		var v2 JourneyV2
+		if err := json.Unmarshal(data, &v2); err != nil {
+			return nil, err
+		}
+		return v2.Upgrade(), nil
+	case JourneySchemaVersion:
+		var journey Journey
+		if err := json.Unmarshal(data, &journey); err != nil {
+			return nil, err
+		}
+		return &journey, nil
+	default:
+		return nil, fmt.Errorf("unsupported journey schema version %d, newest supported is %d",
+			header.SchemaVersion, JourneySchemaVersion)
+	}
+}
+
+// Convert the journey to the version 1 schema, for consumers not yet
+// migrated to the current one, leaving out the boarding and alighting legs
+func (journey *Journey) Downgrade() *JourneyV1 {
+	v1 := &JourneyV1{From: journey.From, To: journey.To, TotalMinutes: journey.TotalMinutes,
+		Legs: make([]LegV1, 0, len(journey.Legs)), DataWarnings: journey.DataWarnings}
+	for _, leg := range journey.Legs {
+		if leg.Type == LegBoard || leg.Type == LegAlight {
+			continue
+		}
+		legV1 := LegV1{Type: leg.Type, Line: leg.Line, FromLine: leg.FromLine, ToLine: leg.ToLine,
+			From: leg.From, To: leg.To, Depart: leg.Depart, Arrive: leg.Arrive, Guidance: leg.Guidance}
+		for _, stop := range leg.Stops {
+			legV1.Stops = append(legV1.Stops, StopV1{stop.Station, stop.Time, stop.Closed})
+		}
+		v1.Legs = append(v1.Legs, legV1)
+	}
+	return v1
+}
@@ -0,0 +1,480 @@
+package transit
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Represents what a search of the graph minimizes
+type Objective string
+
+const (
+	// The total travel time
+	OptimizeTime Objective = "time"
+	// The number of interchanges, whether between lines or on foot to
+	// another station, breaking ties on total travel time
+	OptimizeChanges Objective = "changes"
+	// The total travel time plus the delays to expect on each line, given
+	// by SearchOptions.LineDelays from each line's past reliability
+	OptimizeReliable Objective = "reliable"
+	// The estimated fare, breaking ties on total travel time. Searches
+	// themselves minimize time, and PlanCheapestRoute picks between them.
+	OptimizeCheapest Objective = "cheapest"
+	// Experimental: the total travel time plus a weighting for the minutes
+	// spent on deep-level lines and for every train boarded, as a rough proxy
+	// for the energy a trip uses
+	OptimizeEnergy Objective = "energy"
+)
+
+// Search cost in minutes charged for every boarding when minimizing changes,
+// longer than any trip across the network takes so that one fewer change
+// always outweighs any difference in travel time
+const changePenalty = 1000
+
+// Parse the name of a search objective
+func ParseObjective(s string) (Objective, error) {
+	switch objective := Objective(s); objective {
+	case OptimizeTime, OptimizeChanges, OptimizeReliable, OptimizeCheapest, OptimizeEnergy:
+		return objective, nil
+	}
+	return "", fmt.Errorf("unknown objective %q, expected time, changes, reliable, cheapest or energy", s)
+}
+
+// Optional constraints and preferences applied while searching the graph
+type SearchOptions struct {
+	// Lines which may not be boarded at all, e.g. because they are closed
+	ClosedLines map[string]bool
+	// Stations at which the trip may neither begin, end nor interchange,
+	// though trains still run through them without stopping
+	ClosedStations map[string]bool
+	// Stations the trip may not pass through at all, not even on a train
+	// running through without stopping
+	AvoidStations map[string]bool
+	// Extra cost in minutes of boarding the specified line at the specified
+	// station, either at the start of the trip or after an interchange. This
+	// steers the choice of route, but is not included in its reported times.
+	BoardingPenalty func(station, line string) uint16
+	// Extra cost in minutes of each interchange, on top of the walking time
+	// and wait the transit data gives it, by mode: ModeLineInterchange for
+	// changing lines within a station and ModeStationInterchange for walking
+	// along the street to a nearby station. Like boarding penalties, it
+	// steers the choice of route towards staying on one line, but is not
+	// included in reported times.
+	InterchangePenalty map[LinkMode]uint16
+	// Walking speeds relative to those assumed by the transit data, for
+	// interchanges within a station and on-foot interchanges along the street
+	// to nearby stations respectively (e.g. 0.5 takes twice as long). Zero
+	// means the speed assumed by the data.
+	InterchangeSpeed float64
+	StreetSpeed      float64
+	// Time at which the trip begins, used to pick the time band of rail
+	// links with banded run times and the expected wait for each train, and
+	// to keep to each line's operating hours. Zero means every link takes its
+	// all-day time, with the all-day waits.
+	DepartAt time.Time
+	// Time by which the search must finish, after which it is abandoned with
+	// ErrDeadlineExceeded. Zero means no deadline.
+	Deadline time.Time
+	// What the search minimizes. Zero means the total travel time.
+	Optimize Objective
+	// Expected delay of each line as a share of its run times, e.g. from
+	// StatusHistory.Reliability or the latest line statuses, weighed in when
+	// choosing the route. Like boarding penalties, it is not included in
+	// reported times.
+	LineDelays map[string]float64
+	// Whether the trip must be step-free: starting, ending and interchanging
+	// only where lifts or ramps reach the platforms, with the extra time the
+	// lifts take added
+	StepFree bool
+	// Whether the search, when made with A* or from both ends at once, is
+	// checked against a plain run of Dijkstra's algorithm, failing with
+	// ErrCrossCheckFailed if they disagree on how good the best trip is. This
+	// is for debugging the quicker searches, and roughly doubles the work
+	// done.
+	CrossCheck bool
+	// Nodes and links set aside while searching for alternative routes
+	excludedNodes map[*Node]bool
+	excludedLinks map[*Link]bool
+}
+
+// Return the time taken to traverse the specified link when setting off
+// along it the specified number of minutes into the trip, using the run time
+// for the time band of the moment it is reached plus any time trains stand at
+// the station it leaves, and with walking times scaled according to the
+// options' walking speeds and any lifts needed
+func (opts *SearchOptions) linkTime(link *Link, elapsed uint16) uint16 {
+	speed := 0.0
+	if opts != nil {
+		if link.attrs.BandTimes != nil && !opts.DepartAt.IsZero() {
+			at := opts.DepartAt.Add(time.Duration(elapsed) * time.Minute)
+			if bandTime, exists := link.attrs.BandTimes[TimeBand(at)]; exists {
+				return AddTime(bandTime, link.attrs.Dwell)
+			}
+		}
+		switch link.attrs.Mode {
+		case ModeLineInterchange:
+			speed = opts.InterchangeSpeed
+		case ModeStationInterchange:
+			speed = opts.StreetSpeed
+		}
+	}
+	walkTime := link.time
+	if speed > 0 && speed != 1 {
+		walkTime = uint16(min(math.Ceil(float64(link.time)/speed), MaxTravelTime))
+	}
+	return AddTime(AddTime(walkTime, link.attrs.Dwell), opts.liftTime(link))
+}
+
+// Return the penalty for boarding the line of the specified Node at its
+// station, if the options define one, plus the penalty for each change when
+// minimizing changes or for each train boarded when minimizing energy
+func (opts *SearchOptions) boardingPenalty(node *Node) uint16 {
+	if opts == nil {
+		return 0
+	}
+	var penalty uint16
+	switch opts.Optimize {
+	case OptimizeChanges:
+		penalty = changePenalty
+	case OptimizeEnergy:
+		penalty = boardingEnergyPenalty
+	}
+	if opts.BoardingPenalty != nil {
+		penalty = AddTime(penalty, opts.BoardingPenalty(node.station, node.line))
+	}
+	return penalty
+}
+
+// Return the penalty the options define for following the specified
+// interchange link, if any
+func (opts *SearchOptions) interchangePenalty(link *Link) uint16 {
+	if opts == nil {
+		return 0
+	}
+	return opts.InterchangePenalty[link.attrs.Mode]
+}
+
+// Parse an interchange penalty given either as a number of minutes for
+// every interchange, e.g. "5", or as minutes by kind of interchange, e.g.
+// "line=3,street=10", where line is a change of lines within a station and
+// street a walk to a nearby station. Kinds left out have no penalty.
+func ParseInterchangePenalty(s string) (map[LinkMode]uint16, error) {
+	errInvalid := fmt.Errorf("invalid interchange penalty %q, expected minutes, e.g. 5, or line=3,street=10", s)
+	if minutes, err := strconv.ParseUint(strings.TrimSpace(s), 10, 16); err == nil {
+		return map[LinkMode]uint16{ModeLineInterchange: uint16(minutes),
+			ModeStationInterchange: uint16(minutes)}, nil
+	}
+	penalty := make(map[LinkMode]uint16)
+	for _, part := range strings.Split(s, ",") {
+		kind, minutesStr, found := strings.Cut(part, "=")
+		minutes, err := strconv.ParseUint(strings.TrimSpace(minutesStr), 10, 16)
+		if !found || err != nil {
+			return nil, errInvalid
+		}
+		switch strings.TrimSpace(kind) {
+		case "line":
+			penalty[ModeLineInterchange] = uint16(minutes)
+		case "street":
+			penalty[ModeStationInterchange] = uint16(minutes)
+		default:
+			return nil, errInvalid
+		}
+	}
+	return penalty, nil
+}
+
+// Return the wait for a train on the line of the specified Node, boarded the
+// specified number of minutes into the trip. Given a departure time and the
+// line's frequency, this is the expected wait at that time of day, less the
+// specified minutes of it already allowed for, e.g. by an interchange;
+// otherwise it is the all-day extra wait.
+func (opts *SearchOptions) boardWait(node *Node, elapsed, allowance uint16) uint16 {
+	if opts == nil || opts.DepartAt.IsZero() || node.service == nil {
+		return node.boardTime
+	}
+	wait := node.service.waitAt(opts.DepartAt.Add(time.Duration(elapsed) * time.Minute))
+	return wait - min(wait, allowance)
+}
+
+// Return the wait for a train on the line at the end of the specified
+// interchange, reached the specified number of minutes into the trip after
+// taking the specified minutes to follow it: the wait beyond the part the
+// interchange time allows for, and long enough for the change to take its
+// minimum connection time (see connectionAllowance)
+func (opts *SearchOptions) changeWait(link *Link, elapsed, runTime uint16) uint16 {
+	allowance, shortfall := connectionAllowance(link, runTime)
+	return AddTime(opts.boardWait(link.endNode, elapsed, allowance), shortfall)
+}
+
+// Return whether the line of the specified Node is running at the time it is
+// boarded, the specified number of minutes into the trip, which is always
+// assumed to be so without a departure time or the line's operating hours
+func (opts *SearchOptions) running(node *Node, elapsed uint16) bool {
+	return opts == nil || opts.DepartAt.IsZero() || node.service == nil ||
+		node.service.runningAt(opts.DepartAt.Add(time.Duration(elapsed)*time.Minute))
+}
+
+// Return whether the options forbid boarding the line of the specified Node
+func (opts *SearchOptions) closed(node *Node) bool {
+	return opts != nil && opts.ClosedLines[node.line]
+}
+
+// Return whether the options forbid following the specified link from the
+// specified Node, either because it boards a closed line or one which is not
+// running by then, because it leads to an avoided station, because it is an
+// interchange at, to or from a closed station or one without step-free
+// access when it is needed, because the link is not valid at the time it is
+// reached, or because it has been set aside
+func (opts *SearchOptions) blocked(from *Node, link *Link) bool {
+	if opts.closed(link.endNode) || (opts != nil && opts.AvoidStations[link.endNode.station]) ||
+		opts.stepsRequired(link) {
+		return true
+	}
+	if link.attrs.Mode != ModeRail && !opts.timeIndependent() &&
+		!opts.running(link.endNode, AddTime(from.elapsed, opts.linkTime(link, from.elapsed))) {
+		return true
+	}
+	if opts != nil && (opts.excludedNodes[link.endNode] || opts.excludedLinks[link]) {
+		return true
+	}
+	if opts != nil && !opts.DepartAt.IsZero() &&
+		!link.attrs.ValidAt(opts.DepartAt.Add(time.Duration(from.elapsed)*time.Minute)) {
+		return true
+	}
+	return opts != nil && link.attrs.Mode != ModeRail &&
+		(opts.ClosedStations[from.station] || opts.ClosedStations[link.endNode.station])
+}
+
+// Run a binary heap variation of Dijkstra's shortest paths algorithm on the
+// completed transit graph (or A* where station coordinates are known) to
+// calculate the shortest possible trip between the provided start and end
+// stations, subject to the specified search options (which may be nil)
+func RunShortestPaths(npq *NodePriorityQueue, nodeMap NodeMap,
+	start, dest string, opts *SearchOptions) (*Journey, error) {
+	return RunShortestPathsToAny(npq, nodeMap, start, []string{dest}, opts)
+}
+
+// Calculate the shortest possible trip from the provided start station to
+// whichever of the provided destinations can be reached soonest, in a single
+// search, the Journey's To being the destination chosen
+func RunShortestPathsToAny(npq *NodePriorityQueue, nodeMap NodeMap,
+	start string, dests []string, opts *SearchOptions) (*Journey, error) {
+	if slices.Contains(dests, start) {
+		return arrivedJourney(start), nil
+	}
+	isDest := make(map[string]bool)
+	for _, dest := range dests {
+		isDest[dest] = true
+	}
+	// Searches whose costs do not depend on the time of day can run from
+	// both ends at once, which is much quicker for long trips
+	var route []*Node
+	var links []*Link
+	var err error
+	if opts.timeIndependent() {
+		starts := startCosts(nodeMap, start, opts)
+		search := func() ([]*Node, []*Link, error) { return bidirectionalPath(nodeMap, starts, isDest, opts) }
+		if opts != nil && opts.CrossCheck {
+			route, links, err = crossChecked("bidirectional search", starts, isDest, opts, search)
+		} else {
+			route, links, err = search()
+		}
+	} else {
+		route, links, err = shortestPath(npq, startCosts(nodeMap, start, opts), isDest, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return newJourney(route, links, opts), nil
+}
+
+// Where a search stands on reaching a Node: the search cost of getting
+// there, including any penalties steering the choice of route, and the
+// minutes actually taken, which leave them out. The time of day is told from
+// the minutes taken alone. A trip still at its origin has yet to board a
+// train, so has yet to reach the platform or wait for one.
+type progress struct {
+	cost    uint16
+	elapsed uint16
+	origin  bool
+}
+
+// Return where a search stands on beginning a trip on each open line at the
+// specified station: at the origin, with nothing spent yet. Reaching the
+// platform and waiting for a train are counted on boarding one (see
+// linkCost), so a trip setting off on foot counts neither.
+func startCosts(nodeMap NodeMap, start string, opts *SearchOptions) map[*Node]progress {
+	starts := make(map[*Node]progress)
+	for _, node := range nodeMap[start] {
+		if !opts.closed(node) && (opts == nil || !opts.excludedNodes[node]) &&
+			opts.accessible(node) && opts.running(node, opts.accessTime(node)) {
+			starts[node] = progress{origin: true}
+		}
+	}
+	return starts
+}
+
+// Return where a search stands on boarding a train at the specified Node at
+// the origin of the trip: the time taken to reach the platform and wait for
+// the train, plus any penalty for boarding there in the search cost
+func (opts *SearchOptions) originBoarding(node *Node) progress {
+	accessTime := opts.accessTime(node)
+	elapsed := AddTime(accessTime, opts.boardWait(node, accessTime, 0))
+	return progress{AddTime(elapsed, opts.boardingPenalty(node)), elapsed, false}
+}
+
+// Return where a search stands on reaching the end of the specified link
+// from the specified Node, having stood as specified on setting off along
+// it. The search cost and the minutes taken both grow by the link's time
+// and, when it leads onto another line or station, the wait for a train
+// there. Any link but a walk to another station first adds boarding at the
+// origin (see originBoarding), so that changing lines there never beats
+// starting on the other line. The search cost also grows by any delay to
+// expect along the link when optimizing for reliability, any weighting for
+// a deep-level line when minimizing energy, and any penalty for boarding.
+func (opts *SearchOptions) linkCost(from *Node, link *Link, at progress) progress {
+	if at.origin && link.attrs.Mode != ModeStationInterchange {
+		boarding := opts.originBoarding(from)
+		at.cost, at.elapsed = AddTime(at.cost, boarding.cost), AddTime(at.elapsed, boarding.elapsed)
+	}
+	runTime := opts.linkTime(link, at.elapsed)
+	elapsed := AddTime(at.elapsed, runTime)
+	cost := AddTime(AddTime(at.cost, runTime), opts.delayPenalty(link, runTime))
+	cost = AddTime(cost, opts.energyPenalty(link, runTime))
+	if link.attrs.Mode != ModeRail {
+		wait := opts.changeWait(link, elapsed, runTime)
+		elapsed, cost = AddTime(elapsed, wait), AddTime(cost, wait)
+		cost = AddTime(cost, opts.boardingPenalty(link.endNode))
+		cost = AddTime(cost, opts.interchangePenalty(link))
+	}
+	return progress{cost, elapsed, false}
+}
+
+// Return where the search stands on reaching the specified Node by the best
+// path found so far. A search never finds a better way to the specified
+// start Nodes than beginning there, so those are still at the origin.
+func (node *Node) progress(starts map[*Node]progress) progress {
+	return progress{node.totalTime, node.elapsed, starts[node].origin}
+}
+
+// Run Dijkstra's algorithm from the specified start Nodes, each beginning
+// with the specified search cost, to the station among the specified
+// destinations which can be reached soonest (counting the walk out of it),
+// returning the Nodes visited and the links followed between them. Travel
+// times left on the Nodes are search costs, including any penalties. Where
+// station coordinates are known, an A* search finds the same trip sooner
+// (see astarPath).
+func shortestPath(npq *NodePriorityQueue, starts map[*Node]progress, isDest map[string]bool,
+	opts *SearchOptions) ([]*Node, []*Link, error) {
+	if dh := newDistanceHeuristic(starts, isDest, opts); dh != nil {
+		if opts != nil && opts.CrossCheck {
+			return crossChecked("A*", starts, isDest, opts, func() ([]*Node, []*Link, error) {
+				return astarPath(starts, isDest, dh, opts)
+			})
+		}
+		return astarPath(starts, isDest, dh, opts)
+	}
+	return dijkstraPath(npq, starts, isDest, opts)
+}
+
+// Run Dijkstra's algorithm itself for shortestPath, visiting Nodes in order
+// of their search cost alone
+func dijkstraPath(npq *NodePriorityQueue, starts map[*Node]progress, isDest map[string]bool,
+	opts *SearchOptions) ([]*Node, []*Link, error) {
+	nodePrev := make(map[*Node]*Node)
+	linkPrev := make(map[*Node]*Link)
+	for node, start := range starts {
+		node.elapsed = start.elapsed
+		npq.update(node, start.cost)
+		nodePrev[node] = nil
+		linkPrev[node] = nil
+	}
+	prev := func(node *Node) *Node { return nodePrev[node] }
+	// Arriving on different lines means a different walk out of the
+	// destination station, so track the best arrival found so far
+	var curNode, bestNode *Node = nil, nil
+	var bestTime uint16 = math.MaxUint16
+	for len(*npq) > 0 {
+		if opts != nil && !opts.Deadline.IsZero() && time.Now().After(opts.Deadline) {
+			return nil, nil, ErrDeadlineExceeded
+		}
+		// Retrieve the Node of minimum established travel time from the heap
+		curNode = heap.Pop(npq).(*Node)
+		// Once no remaining Node can improve on the best arrival, or match
+		// it to break the tie, we are done
+		if curNode.totalTime > bestTime || (bestNode == nil && curNode.totalTime == bestTime) {
+			break
+		}
+		// If even the closest remaining Node was never reached, neither was
+		// the destination
+		if curNode.totalTime == math.MaxUint16 {
+			return nil, nil, ErrNoRoute
+		}
+		// If this Node represents a desired destination, there is no need to
+		// travel on from it
+		if isDest[curNode.station] {
+			if !opts.accessible(curNode) {
+				continue
+			}
+			arrival := AddTime(curNode.totalTime, opts.accessTime(curNode))
+			if arrival < bestTime || (bestNode != nil && arrival == bestTime &&
+				preferTie(curNode.changes, bestNode.changes,
+					func() tieKey { return tieKeyTo(curNode, prev) },
+					func() tieKey { return tieKeyTo(bestNode, prev) })) {
+				bestNode, bestTime = curNode, arrival
+			}
+			continue
+		}
+		// For every node directly reachable from the current node, update the
+		// travel time to that node if the path to it from the current node is
+		// an improvement on its previously established travel time, or ties
+		// with it but is preferable (see preferTie) and the node is still
+		// waiting in the heap
+		for _, link := range curNode.adj {
+			if opts.blocked(curNode, link) {
+				continue
+			}
+			endNode := link.endNode
+			alt := opts.linkCost(curNode, link, curNode.progress(starts))
+			altDistance := alt.cost
+			altChanges := AddTime(curNode.changes, linkChanges(link))
+			if altDistance < endNode.totalTime || (altDistance == endNode.totalTime && endNode.index >= 0 &&
+				altDistance != math.MaxUint16 &&
+				preferTie(altChanges, endNode.changes,
+					func() tieKey { return tieKeyTo(curNode, prev).then(endNode) },
+					func() tieKey { return tieKeyTo(endNode, prev) })) {
+				endNode.totalTime, endNode.elapsed, endNode.changes = altDistance, alt.elapsed, altChanges
+				nodePrev[endNode] = curNode
+				linkPrev[endNode] = link
+				npq.update(endNode, altDistance)
+			}
+		}
+	}
+	if bestNode == nil {
+		return nil, nil, ErrNoRoute
+	}
+	route, links := tracePath(bestNode, nodePrev, linkPrev)
+	return route, links, nil
+}
+
+// Construct the route from the start to the specified ending Node by
+// continually following pointers to the previous node in the path until the
+// start is reached, tracking the link taken at each step as well
+func tracePath(end *Node, nodePrev map[*Node]*Node, linkPrev map[*Node]*Link) ([]*Node, []*Link) {
+	curNode := end
+	route, links := make([]*Node, 0), make([]*Link, 0)
+	for linkPrev[curNode] != nil {
+		route = append(route, curNode)
+		links = append(links, linkPrev[curNode])
+		curNode = nodePrev[curNode]
+	}
+	route = append(route, curNode)
+	slices.Reverse(links)
+	slices.Reverse(route)
+	return route, links
+}
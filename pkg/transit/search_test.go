@@ -0,0 +1,248 @@
+package transit
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Penalties for changing lines are part of the search cost but not of the
+// time taken, so must not move the clock used to tell whether trains are
+// running and which run times apply
+func TestOptimizeChangesWithDepartureTime(t *testing.T) {
+	planner := NewPlanner(NewGraph(defaultNodeMap(t)))
+	trips := []struct {
+		from, to string
+		departAt time.Time
+	}{
+		{"Uxbridge", "Bank", time.Date(2026, 10, 19, 9, 2, 0, 0, time.UTC)},
+		{"Brixton", "Epping", time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)},
+	}
+	for _, trip := range trips {
+		planner.Options = SearchOptions{DepartAt: trip.departAt}
+		fastest, err := planner.Plan(trip.from, trip.to)
+		if err != nil {
+			t.Fatalf("%s to %s: fastest route failed: %v", trip.from, trip.to, err)
+		}
+		planner.Options.Optimize = OptimizeChanges
+		fewest, err := planner.Plan(trip.from, trip.to)
+		if err != nil {
+			t.Fatalf("%s to %s at %v with fewest changes failed: %v", trip.from, trip.to, trip.departAt, err)
+		}
+		if got, limit := fewest.Journey().Changes(), fastest.Journey().Changes(); got > limit {
+			t.Errorf("%s to %s: fewest changes route has %d changes, fastest has %d", trip.from, trip.to, got, limit)
+		}
+		if got, least := fewest.TotalMinutes(), fastest.TotalMinutes(); got < least || got > least+120 {
+			t.Errorf("%s to %s: fewest changes route takes %d minutes, fastest %d", trip.from, trip.to, got, least)
+		}
+	}
+}
+
+// A trip found by one of the searches, described by the platforms it
+// visits, for comparing the searches with one another
+type searchResult struct {
+	route string
+	cost  uint16
+	err   error
+}
+
+// Return the trip from the specified start station to the specified
+// destination found by each search able to make it under the specified
+// options (which may be nil), by name: Dijkstra's algorithm always, A* where
+// the stations have coordinates, the bidirectional search where costs do
+// not depend on the time of day, and the specified contraction hierarchy,
+// unless nil, where no options are set
+func searchAll(nodeMap NodeMap, ch *ContractionHierarchy, start, dest string,
+	opts *SearchOptions) map[string]searchResult {
+	isDest := map[string]bool{dest: true}
+	results := make(map[string]searchResult)
+	run := func(router string, search func(starts map[*Node]progress) ([]*Node, []*Link, error)) {
+		starts := startCosts(nodeMap, start, opts)
+		nodes, links, err := search(starts)
+		result := searchResult{err: err}
+		if err == nil {
+			parts := make([]string, len(nodes))
+			for idx, node := range nodes {
+				parts[idx] = fmt.Sprintf("%s (%s)", node.station, node.line)
+			}
+			result.route = strings.Join(parts, ", ")
+			result.cost = pathCost(starts, nodes, links, opts)
+		}
+		results[router] = result
+	}
+
+	run("Dijkstra", func(starts map[*Node]progress) ([]*Node, []*Link, error) {
+		npq := ResetGraph(nodeMap)
+		return dijkstraPath(&npq, starts, isDest, opts)
+	})
+	if dh := newDistanceHeuristic(startCosts(nodeMap, start, opts), isDest, opts); dh != nil {
+		run("A*", func(starts map[*Node]progress) ([]*Node, []*Link, error) {
+			ResetGraph(nodeMap)
+			return astarPath(starts, isDest, dh, opts)
+		})
+	}
+	if opts.timeIndependent() {
+		run("bidirectional", func(starts map[*Node]progress) ([]*Node, []*Link, error) {
+			ResetGraph(nodeMap)
+			return bidirectionalPath(nodeMap, starts, isDest, opts)
+		})
+	}
+	if ch != nil && opts.unconstrained() {
+		run("contraction hierarchy", func(starts map[*Node]progress) ([]*Node, []*Link, error) {
+			nodes, links, err := ch.search(start, isDest)
+			if errors.Is(err, errHierarchyDetour) {
+				return bidirectionalPath(nodeMap, starts, isDest, nil)
+			}
+			return nodes, links, err
+		})
+	}
+	return results
+}
+
+// Report any search whose trip differs from the one Dijkstra's algorithm
+// finds in the specified results, whether in route, search cost or failure
+func checkSearchesAgree(t *testing.T, start, dest string, results map[string]searchResult) {
+	t.Helper()
+	want := results["Dijkstra"]
+	for _, router := range slices.Sorted(maps.Keys(results)) {
+		got := results[router]
+		if (got.err == nil) != (want.err == nil) || got.cost != want.cost || got.route != want.route {
+			t.Errorf("%s to %s: %s found %q costing %d (error %v), Dijkstra's algorithm %q costing %d (error %v)",
+				start, dest, router, got.route, got.cost, got.err, want.route, want.cost, want.err)
+		}
+	}
+}
+
+// Where several routes are equally fast, every search picks the one making
+// the fewest changes, then the one whose lines come first by name, however
+// the graph happens to be built
+func TestSearchesBreakTiesAlike(t *testing.T) {
+	rail := LinkAttributes{Mode: ModeRail}
+	interchange := LinkAttributes{Mode: ModeLineInterchange}
+	// Three routes of 10 minutes from Oxford Circus to Bank: on Bravo via
+	// Holborn, on Delta via Chancery Lane, and on Alpha then Charlie with a
+	// change at Tottenham Court Road
+	railLinks := []RailLink{
+		NewRailLink("Oxford Circus", "Holborn", "Bravo", 5),
+		NewRailLink("Holborn", "Bank", "Bravo", 5),
+		NewRailLink("Oxford Circus", "Chancery Lane", "Delta", 5),
+		NewRailLink("Chancery Lane", "Bank", "Delta", 5),
+		NewRailLink("Oxford Circus", "Tottenham Court Road", "Alpha", 3),
+		NewRailLink("Tottenham Court Road", "Bank", "Charlie", 5),
+	}
+	interchanges := []Interchange{
+		NewInterchange("Tottenham Court Road", "Alpha", "Tottenham Court Road", "Charlie", 2),
+	}
+	want := "Oxford Circus (Bravo), Holborn (Bravo), Bank (Bravo)"
+	useStationCoordinates(t, map[string]Coordinates{
+		"Oxford Circus":        NewCoordinates(51.5152, -0.1418),
+		"Tottenham Court Road": NewCoordinates(51.5165, -0.1310),
+		"Holborn":              NewCoordinates(51.5174, -0.1201),
+		"Chancery Lane":        NewCoordinates(51.5185, -0.1111),
+		"Bank":                 NewCoordinates(51.5133, -0.0886),
+	})
+
+	for round := range 10 {
+		npq := make(NodePriorityQueue, 0)
+		nodeMap := make(NodeMap)
+		order := slices.Clone(railLinks)
+		if round%2 == 1 {
+			slices.Reverse(order)
+		}
+		for _, link := range order {
+			AddConnection(&npq, nodeMap, &link, rail)
+		}
+		for _, ic := range interchanges {
+			AddConnection(&npq, nodeMap, &ic, interchange)
+		}
+		ch := BuildContractionHierarchy(nodeMap)
+		results := searchAll(nodeMap, ch, "Oxford Circus", "Bank", nil)
+		for _, router := range []string{"Dijkstra", "A*", "bidirectional", "contraction hierarchy"} {
+			if _, ran := results[router]; !ran {
+				t.Fatalf("%s did not search the graph", router)
+			}
+		}
+		checkSearchesAgree(t, "Oxford Circus", "Bank", results)
+		if got := results["Dijkstra"].route; got != want {
+			t.Fatalf("picked %q, want %q", got, want)
+		}
+	}
+}
+
+// A trip setting off on foot to another station neither reaches a platform
+// nor waits for a train at its origin, so has no boarding leg there, and
+// every search counts it alike
+func TestWalkFromOriginHasNoBoardingWait(t *testing.T) {
+	nodeMap := defaultNodeMap(t)
+	journey, err := NewPlanner(NewGraph(nodeMap)).Plan("Bank", "Victoria")
+	if err != nil {
+		t.Fatalf("Bank to Victoria failed: %v", err)
+	}
+	first := journey.Journey().Legs[0]
+	if first.Type != string(ModeStationInterchange) {
+		t.Fatalf("Bank to Victoria begins with a %q leg, want a walk to another station", first.Type)
+	}
+	walked := first.hops[0]
+	want := AddTime(walked.link.time, (*SearchOptions)(nil).changeWait(walked.link, walked.link.time, walked.link.time))
+	if first.Depart != 0 || first.Arrive != want {
+		t.Errorf("walk from Bank runs from minute %d to %d, want 0 to %d", first.Depart, first.Arrive, want)
+	}
+
+	results := searchAll(nodeMap, BuildContractionHierarchy(nodeMap), "Bank", "Victoria", nil)
+	checkSearchesAgree(t, "Bank", "Victoria", results)
+	if got := results["Dijkstra"].cost; got != journey.TotalMinutes() {
+		t.Errorf("Dijkstra's algorithm costs Bank to Victoria at %d, the journey takes %d minutes",
+			got, journey.TotalMinutes())
+	}
+}
+
+// The wait after changing lines counts any interchange time beyond the
+// change's minimum connection time, and the change always takes at least
+// that long
+func TestMinimumConnectionTimes(t *testing.T) {
+	departAt := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		connection uint16
+		want       uint16
+	}{
+		// Half the 10 minute gap between trains, less the 2 minutes the 4
+		// minute interchange allows for waiting
+		{0, 7},
+		// A train a minute after arriving can be caught, so 3 minutes of the
+		// interchange count towards the wait
+		{1, 6},
+		// The change takes 6 minutes before the wait begins
+		{6, 11},
+	}
+	for _, c := range cases {
+		npq, nodeMap := make(NodePriorityQueue, 0), make(NodeMap)
+		for _, link := range []RailLink{
+			NewRailLink("Piccadilly Circus", "Oxford Circus", "Bakerloo", 2),
+			NewRailLink("Oxford Circus", "Warren Street", "Victoria", 2),
+		} {
+			AddConnection(&npq, nodeMap, &link, LinkAttributes{Mode: ModeRail})
+		}
+		ic := NewInterchange("Oxford Circus", "Bakerloo", "Oxford Circus", "Victoria", 4)
+		AddConnection(&npq, nodeMap, &ic, LinkAttributes{Mode: ModeLineInterchange})
+		ApplyLineServices(nodeMap, []LineService{{"Victoria", clockTime(5, 30), clockTime(24, 30), 10, 10}})
+		ApplyConnectionTimes(nodeMap, []ConnectionTime{{"Oxford Circus", "Bakerloo", "Victoria", c.connection}})
+
+		planner := NewPlanner(NewGraph(nodeMap))
+		planner.Options = SearchOptions{DepartAt: departAt}
+		route, err := planner.Plan("Piccadilly Circus", "Warren Street")
+		if err != nil {
+			t.Fatalf("minimum connection time %d: planning failed: %v", c.connection, err)
+		}
+		idx := slices.IndexFunc(route.Journey().Legs, func(leg Leg) bool { return leg.IsInterchange() })
+		if idx < 0 {
+			t.Fatalf("minimum connection time %d: no change at Oxford Circus", c.connection)
+		}
+		if got := route.Journey().Legs[idx].Minutes; got != c.want {
+			t.Errorf("minimum connection time %d: change takes %d minutes, want %d", c.connection, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package transit
+
+import "errors"
+
+// Number of stops from one of a line's service origins within which boarding
+// is still considered likely to find a seat
+const seatNearStops = 2
+
+// Return, for each line, the set of stations within seatNearStops stops of
+// one of the line's service origins (as defined in transitdata.go)
+func SeatFriendlyStations(nodeMap NodeMap) map[string]map[string]bool {
+	friendly := make(map[string]map[string]bool)
+	for line, origins := range GetServiceOrigins() {
+		friendly[line] = make(map[string]bool)
+		// Breadth-first search outwards from the origins along the line's
+		// own rail links, one stop per round
+		frontier := make([]*Node, 0)
+		for _, origin := range origins {
+			if node, exists := nodeMap[origin][line]; exists {
+				friendly[line][origin] = true
+				frontier = append(frontier, node)
+			}
+		}
+		for stops := 0; stops < seatNearStops; stops++ {
+			next := make([]*Node, 0)
+			for _, node := range frontier {
+				for _, link := range node.adj {
+					if link.attrs.Mode == ModeRail && !friendly[line][link.endNode.station] {
+						friendly[line][link.endNode.station] = true
+						next = append(next, link.endNode)
+					}
+				}
+			}
+			frontier = next
+		}
+	}
+	return friendly
+}
+
+// Return a boarding penalty which charges the specified number of minutes
+// for every boarding of a line away from its service origins, where trains
+// are likely to be full already
+func SeatBoardingPenalty(nodeMap NodeMap, tolerance uint16) func(station, line string) uint16 {
+	friendly := SeatFriendlyStations(nodeMap)
+	return func(station, line string) uint16 {
+		if friendly[line][station] {
+			return 0
+		}
+		return tolerance
+	}
+}
+
+// Plan the trip between the specified stations preferring to board lines at
+// or near their service origins, as long as the resulting route is no more
+// than tolerance minutes slower than the fastest one; otherwise plan the
+// fastest route as usual. Also return how many minutes slower than the
+// fastest route the chosen one is. Any other search options (which may be
+// nil), including any other boarding penalty, apply to both routes.
+func PlanSeatFriendlyRoute(nodeMap NodeMap, start, dest string, tolerance uint16,
+	opts *SearchOptions) (*Journey, uint16, error) {
+	npq := ResetGraph(nodeMap)
+	fastest, err := RunShortestPaths(&npq, nodeMap, start, dest, opts)
+	if err != nil || len(fastest.Legs) == 0 {
+		return fastest, 0, err
+	}
+
+	npq = ResetGraph(nodeMap)
+	seatOpts := SearchOptions{}
+	if opts != nil {
+		seatOpts = *opts
+	}
+	seatOpts.BoardingPenalty = CombinePenalties(seatOpts.BoardingPenalty,
+		SeatBoardingPenalty(nodeMap, tolerance))
+	journey, err := RunShortestPaths(&npq, nodeMap, start, dest, &seatOpts)
+	if err == nil && journey.TotalMinutes <= AddTime(fastest.TotalMinutes, tolerance) {
+		return journey, journey.TotalMinutes - fastest.TotalMinutes, nil
+	} else if errors.Is(err, ErrDeadlineExceeded) {
+		return nil, 0, err
+	}
+	return fastest, 0, nil
+}
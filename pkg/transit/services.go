@@ -0,0 +1,129 @@
+package transit
+
+import "time"
+
+// Record on each Node of the graph the extra wait for a train on its line,
+// for lines listed in the specified waits, and the time to walk between the
+// gates and its platforms, for the stations and lines listed in the specified
+// access times. Entries for stations or lines not in the graph, e.g. when
+// built from a dataset covering less of the network, are ignored.
+func ApplyServiceTimes(nodeMap NodeMap, waits []LineWait, accessTimes []PlatformAccess) {
+	for _, lw := range waits {
+		for _, lines := range nodeMap {
+			if node, exists := lines[lw.line]; exists {
+				node.boardTime = lw.wait
+			}
+		}
+	}
+	for _, pa := range accessTimes {
+		if node, exists := nodeMap[pa.station][pa.line]; exists {
+			node.accessTime = pa.transitTime
+		}
+	}
+}
+
+// Record on each rail link leaving a station listed in the specified dwell
+// times how much longer trains stand there, so that a ride through or from
+// the station takes that much longer. Stations not in the graph are ignored.
+func ApplyDwellTimes(nodeMap NodeMap, dwells []StationDwell) {
+	for _, sd := range dwells {
+		for _, node := range nodeMap[sd.station] {
+			for _, link := range node.adj {
+				if link.attrs.Mode == ModeRail {
+					link.attrs.Dwell = sd.dwell
+				}
+			}
+		}
+	}
+}
+
+// Return the specified time of day in minutes after midnight, with hours from
+// 24 on for times after midnight at the end of a day's service
+func clockTime(hours, minutes uint16) uint16 {
+	return hours*60 + minutes
+}
+
+// Minutes of waiting for a train already allowed for in the interchange
+// times of changes without a minimum connection time, so only waits beyond
+// this are added after such an interchange
+const interchangeWaitAllowance = 2
+
+// Record on each interchange between lines at a station listed in the
+// specified connection times the least time the change takes, in the
+// direction given. Stations and lines not in the graph, or without an
+// interchange between them, are ignored.
+func ApplyConnectionTimes(nodeMap NodeMap, connections []ConnectionTime) {
+	for _, ct := range connections {
+		from, to := nodeMap[ct.station][ct.fromLine], nodeMap[ct.station][ct.toLine]
+		if from == nil || to == nil {
+			continue
+		}
+		for _, link := range from.adj {
+			if link.endNode == to && link.attrs.Mode == ModeLineInterchange {
+				link.attrs.MinConnection = ct.minutes
+			}
+		}
+	}
+}
+
+// Return how the wait for a train after the specified interchange, taking
+// the specified minutes to follow, compares with the interchange time: the
+// minutes of the wait it already allows for, and the minutes it falls short
+// of the least time the change takes. With a minimum connection time, the
+// first train that time allows for after arriving can be boarded, so any
+// interchange time beyond it counts towards the wait; without one, the
+// interchange time allows interchangeWaitAllowance minutes for waiting.
+func connectionAllowance(link *Link, runTime uint16) (allowance, shortfall uint16) {
+	connection := link.attrs.MinConnection
+	if connection == 0 {
+		return interchangeWaitAllowance, 0
+	}
+	return runTime - min(runTime, connection), connection - min(connection, runTime)
+}
+
+// Record on each Node of the graph the operating hours and frequency of its
+// line, for lines listed in the specified services. Entries for lines not in
+// the graph are ignored.
+func ApplyLineServices(nodeMap NodeMap, services []LineService) {
+	for idx := range services {
+		for _, lines := range nodeMap {
+			if node, exists := lines[services[idx].line]; exists {
+				node.service = &services[idx]
+			}
+		}
+	}
+}
+
+// Return whether trains of the line can be boarded at the specified time.
+// A time before the first train counts as part of the previous day's service
+// if that runs on past midnight.
+func (ls *LineService) runningAt(t time.Time) bool {
+	minutes := uint16(t.Hour()*60 + t.Minute())
+	return (minutes >= ls.firstTrain && minutes <= ls.lastTrain) || minutes+24*60 <= ls.lastTrain
+}
+
+// Return the gap in minutes between trains of the line at the specified
+// time, in that time band
+func (ls *LineService) headwayAt(t time.Time) uint16 {
+	if TimeBand(t) == PeakBand {
+		return ls.peakHeadway
+	}
+	return ls.offPeakHeadway
+}
+
+// Return the expected wait for a train of the line boarded at the specified
+// time, half the gap between trains in that time band, rounded up
+func (ls *LineService) waitAt(t time.Time) uint16 {
+	return (ls.headwayAt(t) + 1) / 2
+}
+
+// Return when the last train of the line leaves on the day's service running
+// at the specified time, which counts a time before the first train as part
+// of the previous day's service if that runs on past midnight
+func (ls *LineService) lastTrainAfter(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	if minutes := uint16(t.Hour()*60 + t.Minute()); minutes < ls.firstTrain && minutes+24*60 <= ls.lastTrain {
+		day = day.AddDate(0, 0, -1)
+	}
+	return day.Add(time.Duration(ls.lastTrain) * time.Minute)
+}
@@ -0,0 +1,214 @@
+package transit
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"time"
+)
+
+// Represents the spread of journey times found by simulating a route many
+// times over, with the wait for each train drawn at random from the gaps
+// between trains on its line rather than taken as the expected wait
+type Simulation struct {
+	// Number of simulated journeys
+	Runs int
+	// Travel time of the route as planned, in minutes
+	PlannedMinutes uint16
+	// Share of simulated journeys arriving later than planned
+	LateShare float64
+	// Share of simulated journeys in which at least one change took longer
+	// than planned
+	AnyChangeLongerShare float64
+	// Share of simulated journeys stranded by reaching a line after its last
+	// train, which are left out of the journey times
+	StrandedShare float64
+	// How the wait for each train boarded compared with the plan, in the
+	// order they are boarded
+	Boardings []BoardingOdds
+	// Travel times of the journeys completed, in minutes, quickest first
+	minutes []float64
+}
+
+// Represents how often the wait for a train in simulated journeys was longer
+// than the wait allowed for it in the plan
+type BoardingOdds struct {
+	Station     string
+	Line        string
+	Interchange bool
+	// Wait allowed for the train in the plan, in minutes
+	PlannedWait uint16
+	// Share of simulated journeys in which the wait was longer
+	LongerShare float64
+}
+
+// Return a random wait in minutes for a train of the line of the specified
+// Node, reached the specified number of minutes into the trip: anywhere up to
+// the gap between trains at that time of day, less the specified minutes of
+// the wait already allowed for, e.g. by an interchange (see
+// connectionAllowance). Lines without a known frequency always take their
+// usual extra wait.
+func (opts *SearchOptions) sampleWait(node *Node, elapsed float64, allowance uint16,
+	rng *rand.Rand) float64 {
+	if node.service == nil {
+		return float64(node.boardTime)
+	}
+	headway := node.service.offPeakHeadway
+	if opts != nil && !opts.DepartAt.IsZero() {
+		headway = node.service.headwayAt(opts.DepartAt.Add(time.Duration(elapsed * float64(time.Minute))))
+	}
+	return max(rng.Float64()*float64(headway)-float64(allowance), 0)
+}
+
+// Simulate the specified journey the specified number of times, drawing the
+// wait for every train boarded at random from the gaps between trains on its
+// line, with run times and walks as planned under the specified search
+// options (which may be nil). The journey must be a planned one, carrying
+// the travel times it was planned with.
+func SimulateRoute(journey *Journey, opts *SearchOptions, runs int, rng *rand.Rand) *Simulation {
+	sim := &Simulation{Runs: runs}
+	hops := journey.hops()
+	if len(hops) == 0 || runs <= 0 {
+		return sim
+	}
+	sim.PlannedMinutes = journey.TotalMinutes
+
+	// Trains are boarded at the start, unless the journey sets off on foot to
+	// another station, and after each change leading onto a rail link, while
+	// a change into the destination boards nothing. Boardings are numbered by
+	// the link they begin.
+	boards := make([]int, 0)
+	var accessTime uint16
+	if hops[0].link.attrs.Mode != ModeStationInterchange {
+		boards = append(boards, 0)
+		accessTime = opts.accessTime(hops[0].from)
+	}
+	for idx, hop := range hops {
+		if hop.link.attrs.Mode != ModeRail && idx+1 < len(hops) && hops[idx+1].link.attrs.Mode == ModeRail {
+			boards = append(boards, idx+1)
+		}
+	}
+	planned := make(map[int]uint16, len(boards))
+	for _, idx := range boards {
+		if idx == 0 {
+			planned[idx] = opts.boardWait(hops[0].from, accessTime, 0)
+			continue
+		}
+		before := hops[idx-1]
+		runTime := opts.linkTime(before.link, before.from.totalTime)
+		planned[idx] = opts.changeWait(before.link, before.from.totalTime+runTime, runTime)
+	}
+	longer := make(map[int]int, len(boards))
+
+	late, anyLonger, stranded := 0, 0, 0
+	for range runs {
+		elapsed, changeLonger, isStranded := float64(accessTime), false, false
+		// Board the train beginning the specified link, after following the
+		// specified interchange taking the specified minutes, or at the
+		// start of the trip if nil
+		board := func(idx int, via *Link, runTime uint16) {
+			var allowance, shortfall uint16
+			if via != nil {
+				allowance, shortfall = connectionAllowance(via, runTime)
+			}
+			if !opts.running(hops[idx].from, uint16(min(elapsed, math.MaxUint16))) {
+				isStranded = true
+			}
+			wait := float64(shortfall) + opts.sampleWait(hops[idx].from, elapsed, allowance, rng)
+			if wait > float64(planned[idx]) {
+				longer[idx]++
+				changeLonger = changeLonger || via != nil
+			}
+			elapsed += wait
+		}
+		if _, boarding := planned[0]; boarding {
+			board(0, nil, 0)
+		}
+		for idx, hop := range hops {
+			runTime := opts.linkTime(hop.link, uint16(min(elapsed, math.MaxUint16)))
+			elapsed += float64(runTime)
+			if hop.link.attrs.Mode == ModeRail {
+				continue
+			}
+			if _, boarding := planned[idx+1]; boarding {
+				board(idx+1, hop.link, runTime)
+			} else {
+				elapsed += float64(opts.changeWait(hop.link, uint16(min(elapsed, math.MaxUint16)), runTime))
+			}
+		}
+		elapsed += float64(opts.accessTime(hops[len(hops)-1].to))
+		if changeLonger {
+			anyLonger++
+		}
+		if isStranded {
+			stranded++
+			continue
+		}
+		if math.Round(elapsed) > float64(sim.PlannedMinutes) {
+			late++
+		}
+		sim.minutes = append(sim.minutes, elapsed)
+	}
+	slices.Sort(sim.minutes)
+
+	share := func(count int) float64 { return float64(count) / float64(runs) }
+	sim.LateShare, sim.AnyChangeLongerShare, sim.StrandedShare = share(late), share(anyLonger), share(stranded)
+	for _, idx := range boards {
+		sim.Boardings = append(sim.Boardings, BoardingOdds{hops[idx].from.station, hops[idx].from.line, idx > 0,
+			planned[idx], share(longer[idx])})
+	}
+	return sim
+}
+
+// Return the travel time in minutes within which the specified share of the
+// completed simulated journeys arrived, e.g. 0.9 for nine in ten, or zero if
+// none were completed
+func (sim *Simulation) Percentile(share float64) uint16 {
+	if len(sim.minutes) == 0 {
+		return 0
+	}
+	idx := int(math.Ceil(share*float64(len(sim.minutes)))) - 1
+	idx = max(0, min(idx, len(sim.minutes)-1))
+	return uint16(min(math.Round(sim.minutes[idx]), math.MaxUint16))
+}
+
+// Return the mean travel time in minutes of the completed simulated journeys
+func (sim *Simulation) MeanMinutes() float64 {
+	if len(sim.minutes) == 0 {
+		return 0
+	}
+	total := 0.0
+	for _, minutes := range sim.minutes {
+		total += minutes
+	}
+	return total / float64(len(sim.minutes))
+}
+
+// Print a summary of the specified simulation: the spread of journey times,
+// the chance of arriving later than planned, and the chance of waiting
+// longer than planned for each train
+func PrintSimulation(sim *Simulation) {
+	if len(sim.Boardings) == 0 {
+		return
+	}
+	percent := func(share float64) int { return int(math.Round(share * 100)) }
+	fmt.Printf("\nSimulated %d journeys with random waits for each train (planned: %d minutes):\n",
+		sim.Runs, sim.PlannedMinutes)
+	if len(sim.minutes) > 0 {
+		fmt.Printf("- Journey time: %.1f minutes on average, %d at best, half within %d, "+
+			"nine in ten within %d, %d at worst\n", sim.MeanMinutes(), sim.Percentile(0),
+			sim.Percentile(0.5), sim.Percentile(0.9), sim.Percentile(1))
+	}
+	fmt.Printf("- Arriving later than planned: %d%%\n", percent(sim.LateShare))
+	for _, odds := range sim.Boardings {
+		fmt.Printf("- Waiting longer than the %d minutes planned for the %s line at %s: %d%%\n",
+			odds.PlannedWait, odds.Line, odds.Station, percent(odds.LongerShare))
+	}
+	if len(sim.Boardings) > 2 {
+		fmt.Printf("- Any change taking longer than planned: %d%%\n", percent(sim.AnyChangeLongerShare))
+	}
+	if sim.StrandedShare > 0 {
+		fmt.Printf("- Missing the last train of a line: %d%%\n", percent(sim.StrandedShare))
+	}
+}
@@ -0,0 +1,231 @@
+package transit
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// Tables of a network database, created by NetworkDatabase.Create. Every
+// connection runs both ways, so each is stored once, in either direction.
+const networkDatabaseSchema = `
+CREATE TABLE IF NOT EXISTS rail_links (
+	from_station TEXT NOT NULL,
+	to_station TEXT NOT NULL,
+	line TEXT NOT NULL,
+	minutes INTEGER NOT NULL,
+	PRIMARY KEY (from_station, to_station, line)
+);
+CREATE TABLE IF NOT EXISTS interchanges (
+	from_station TEXT NOT NULL,
+	from_line TEXT NOT NULL,
+	to_station TEXT NOT NULL,
+	to_line TEXT NOT NULL,
+	minutes INTEGER NOT NULL,
+	PRIMARY KEY (from_station, from_line, to_station, to_line)
+);
+CREATE TABLE IF NOT EXISTS stations (
+	name TEXT PRIMARY KEY,
+	latitude REAL,
+	longitude REAL
+);
+`
+
+// A transit network stored in a SQLite database, which can be updated a
+// link at a time without rebuilding the program, and built into a graph as
+// a DataSource. The database is read and written with the sqlite3 command,
+// which must be installed.
+type NetworkDatabase struct {
+	Path string
+}
+
+// Identify the database for the graph cache by its file
+func (db NetworkDatabase) identify(hash io.Writer, stamp func(path string) error) error {
+	fmt.Fprintln(hash, "database")
+	return stamp(db.Path)
+}
+
+// Quote the specified string as a SQL string literal
+func sqlQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// Run the specified SQL against the database with the sqlite3 command,
+// returning the rows of any results. The database must already exist unless
+// it is being created.
+func (db NetworkDatabase) exec(sql string, create bool) ([][]string, error) {
+	return runSQLite(db.Path, sql, create)
+}
+
+// Run the specified SQL against the SQLite database at the specified path
+// with the sqlite3 command, returning the rows of any results. The database
+// must already exist unless it is being created.
+func runSQLite(path, sql string, create bool) ([][]string, error) {
+	if !create {
+		if _, err := os.Stat(path); err != nil {
+			return nil, err
+		}
+	}
+	args := []string{"-bail", "-batch", "-csv", "-noheader", path}
+	cmd := exec.Command("sqlite3", args...)
+	cmd.Stdin = strings.NewReader(sql)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); errors.Is(err, exec.ErrNotFound) {
+		return nil, fmt.Errorf("the sqlite3 command is needed to use %s", path)
+	} else if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s: %s", path, msg)
+		}
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	reader := csv.NewReader(&stdout)
+	reader.FieldsPerRecord = -1
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%s: reading sqlite3 output: %v", path, err)
+	}
+	return rows, nil
+}
+
+// Create the network database, or add any missing tables to an existing
+// one, filling it with the specified rail links, interchanges and station
+// coordinates in place of anything already there
+func (db NetworkDatabase) Create(railLinks []RailLink, interchanges []Interchange,
+	coords map[string]Coordinates) error {
+	var sql strings.Builder
+	sql.WriteString("BEGIN;\n" + networkDatabaseSchema)
+	sql.WriteString("DELETE FROM rail_links; DELETE FROM interchanges; DELETE FROM stations;\n")
+	for _, rl := range railLinks {
+		fmt.Fprintf(&sql, "INSERT OR REPLACE INTO rail_links VALUES (%s, %s, %s, %d);\n",
+			sqlQuote(rl.fromStation), sqlQuote(rl.toStation), sqlQuote(rl.line), rl.transitTime)
+	}
+	for _, ic := range interchanges {
+		fmt.Fprintf(&sql, "INSERT OR REPLACE INTO interchanges VALUES (%s, %s, %s, %s, %d);\n",
+			sqlQuote(ic.fromStation), sqlQuote(ic.fromLine), sqlQuote(ic.toStation), sqlQuote(ic.toLine),
+			ic.transitTime)
+	}
+	for _, station := range knownStations(railLinks, coords) {
+		if station.Location != nil {
+			fmt.Fprintf(&sql, "INSERT INTO stations VALUES (%s, %f, %f);\n", sqlQuote(station.Name),
+				station.Location.lat, station.Location.lon)
+		}
+	}
+	sql.WriteString("COMMIT;\n")
+	_, err := db.exec(sql.String(), true)
+	return err
+}
+
+// Add the specified rail link to the database, replacing the time of the
+// link between the same stations on the same line if it is there already,
+// in either direction
+func (db NetworkDatabase) AddRailLink(rl RailLink) error {
+	_, err := db.exec(fmt.Sprintf("BEGIN;\n"+
+		"DELETE FROM rail_links WHERE from_station = %[2]s AND to_station = %[1]s AND line = %[3]s;\n"+
+		"INSERT OR REPLACE INTO rail_links VALUES (%[1]s, %[2]s, %[3]s, %[4]d);\nCOMMIT;\n",
+		sqlQuote(rl.fromStation), sqlQuote(rl.toStation), sqlQuote(rl.line), rl.transitTime), false)
+	return err
+}
+
+// Remove the rail link between the specified stations on the specified line
+// from the database, in either direction, returning whether it was there
+func (db NetworkDatabase) RemoveRailLink(from, to, line string) (bool, error) {
+	rows, err := db.exec(fmt.Sprintf("DELETE FROM rail_links WHERE line = %[3]s AND "+
+		"((from_station = %[1]s AND to_station = %[2]s) OR (from_station = %[2]s AND to_station = %[1]s));\n"+
+		"SELECT changes();\n", sqlQuote(from), sqlQuote(to), sqlQuote(line)), false)
+	return err == nil && len(rows) == 1 && rows[0][0] != "0", err
+}
+
+// Add the specified interchange to the database, replacing the time of the
+// interchange between the same ends if it is there already, in either
+// direction
+func (db NetworkDatabase) AddInterchange(ic Interchange) error {
+	_, err := db.exec(fmt.Sprintf("BEGIN;\n"+
+		"DELETE FROM interchanges WHERE from_station = %[3]s AND from_line = %[4]s AND "+
+		"to_station = %[1]s AND to_line = %[2]s;\n"+
+		"INSERT OR REPLACE INTO interchanges VALUES (%[1]s, %[2]s, %[3]s, %[4]s, %[5]d);\nCOMMIT;\n",
+		sqlQuote(ic.fromStation), sqlQuote(ic.fromLine), sqlQuote(ic.toStation), sqlQuote(ic.toLine),
+		ic.transitTime), false)
+	return err
+}
+
+// Remove the interchange between the specified ends from the database, in
+// either direction, returning whether it was there
+func (db NetworkDatabase) RemoveInterchange(from, fromLine, to, toLine string) (bool, error) {
+	rows, err := db.exec(fmt.Sprintf("DELETE FROM interchanges WHERE "+
+		"(from_station = %[1]s AND from_line = %[2]s AND to_station = %[3]s AND to_line = %[4]s) OR "+
+		"(from_station = %[3]s AND from_line = %[4]s AND to_station = %[1]s AND to_line = %[2]s);\n"+
+		"SELECT changes();\n", sqlQuote(from), sqlQuote(fromLine), sqlQuote(to), sqlQuote(toLine)), false)
+	return err == nil && len(rows) == 1 && rows[0][0] != "0", err
+}
+
+// Parse the minutes column of a row read from the database
+func (db NetworkDatabase) rowMinutes(table string, row []string) (uint16, error) {
+	minutes, err := parseCSVMinutes(row[len(row)-1])
+	if err != nil {
+		return 0, fmt.Errorf("%s: %s %s: %v", db.Path, table, strings.Join(row[:len(row)-1], ", "), err)
+	}
+	return minutes, nil
+}
+
+func (db NetworkDatabase) GetRailLinks() ([]RailLink, error) {
+	rows, err := db.exec("SELECT from_station, to_station, line, minutes FROM rail_links "+
+		"ORDER BY line, from_station, to_station;\n", false)
+	if err != nil {
+		return nil, err
+	}
+	railLinks := make([]RailLink, 0, len(rows))
+	for _, row := range rows {
+		minutes, err := db.rowMinutes("rail link", row)
+		if err != nil {
+			return nil, err
+		}
+		railLinks = append(railLinks, RailLink{row[0], row[1], row[2], minutes})
+	}
+	if len(railLinks) == 0 {
+		return nil, fmt.Errorf("%s: no rail links", db.Path)
+	}
+	return railLinks, nil
+}
+
+func (db NetworkDatabase) GetInterchanges() ([]Interchange, error) {
+	rows, err := db.exec("SELECT from_station, from_line, to_station, to_line, minutes FROM interchanges "+
+		"ORDER BY from_station, from_line, to_station, to_line;\n", false)
+	if err != nil {
+		return nil, err
+	}
+	interchanges := make([]Interchange, 0, len(rows))
+	for _, row := range rows {
+		minutes, err := db.rowMinutes("interchange", row)
+		if err != nil {
+			return nil, err
+		}
+		interchanges = append(interchanges, Interchange{row[0], row[1], row[2], row[3], minutes})
+	}
+	return interchanges, nil
+}
+
+func (db NetworkDatabase) GetStations() ([]Station, error) {
+	rows, err := db.exec("SELECT name, latitude, longitude FROM stations "+
+		"WHERE latitude IS NOT NULL AND longitude IS NOT NULL ORDER BY name;\n", false)
+	if err != nil {
+		return nil, err
+	}
+	stations := make([]Station, 0, len(rows))
+	for _, row := range rows {
+		lat, latErr := strconv.ParseFloat(row[1], 64)
+		lon, lonErr := strconv.ParseFloat(row[2], 64)
+		if latErr != nil || lonErr != nil {
+			return nil, fmt.Errorf("%s: station %s has invalid coordinates %q, %q", db.Path, row[0], row[1], row[2])
+		}
+		c := NewCoordinates(lat, lon)
+		stations = append(stations, Station{row[0], &c})
+	}
+	return stations, nil
+}
@@ -0,0 +1,166 @@
+package transit
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Hex digits kept of the hashes identifying routes and networks, enough to
+// tell apart any two that are likely to be compared
+const stampHashDigits = 12
+
+// Identifies exactly what a Journey was planned from, so that two people can
+// confirm they got the same answer and a bug report can give the inputs
+// behind it: a hash of the route itself, a hash of the network searched
+// (see NetworkVersion) and the options that shaped the search (see
+// SearchOptions.Describe)
+type Stamp struct {
+	RouteHash   string `json:"routeHash"`
+	DataVersion string `json:"dataVersion"`
+	Options     string `json:"options"`
+}
+
+// Return the Stamp of the specified journey, planned over the network with
+// the specified version using the specified options
+func NewStamp(journey *Journey, dataVersion, options string) *Stamp {
+	return &Stamp{journey.RouteHash(), dataVersion, options}
+}
+
+// Return the stamp as a single line, e.g. "route 1f0c9a7e52b4, data
+// 8d2e61c0a93f, options optimize=time; step-free"
+func (stamp *Stamp) String() string {
+	return fmt.Sprintf("route %s, data %s, options %s", stamp.RouteHash, stamp.DataVersion, stamp.Options)
+}
+
+// Return the first hex digits of the specified hash's sum
+func stampDigits(h hash.Hash) string {
+	return hex.EncodeToString(h.Sum(nil))[:stampHashDigits]
+}
+
+// Return a hash of the way the journey goes: the stations, lines and times
+// of its every leg. It depends on nothing else, so the same route planned on
+// two machines, or decoded from JSON, has the same hash. Fares, warnings and
+// walking guidance are left out.
+func (journey *Journey) RouteHash() string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%d\n", journey.From, journey.To, journey.TotalMinutes)
+	for _, leg := range journey.Legs {
+		fmt.Fprintf(h, "%s\x00%s\x00%s\x00%s\x00%s\x00%s\x00%d\x00%d", leg.Type, leg.Line, leg.FromLine,
+			leg.ToLine, leg.From, leg.To, leg.Depart, leg.Arrive)
+		for _, stop := range leg.Stops {
+			fmt.Fprintf(h, "\x00%s\x00%d\x00%t", stop.Station, stop.Time, stop.Closed)
+		}
+		h.Write([]byte("\n"))
+	}
+	return stampDigits(h)
+}
+
+// Return a version identifying the network of the specified graph nodes: a
+// hash of every station and line, its links and their times (including
+// those of time bands, dwells and minimum connections), and whether the station is closed,
+// step-free or needs boarding ramps. It changes whenever the transit data,
+// station overrides or anything else changing what a search could find
+// does, whichever source the graph was built from, and is the same on any
+// machine building the same graph. It takes a few milliseconds to work out
+// for the whole network, so Graph.DataVersion keeps it.
+func NetworkVersion(nodeMap NodeMap) string {
+	h := sha256.New()
+	for _, station := range slices.Sorted(maps.Keys(nodeMap)) {
+		for _, line := range slices.Sorted(maps.Keys(nodeMap[station])) {
+			node := nodeMap[station][line]
+			fmt.Fprintf(h, "%s\x00%s\x00%t\x00%d\x00%d\x00%t\x00%d\x00%t\x00%v\n", station, line, node.closed,
+				node.boardTime, node.accessTime, node.stepFree, node.liftTime, node.ramp, node.zone)
+			links := slices.SortedFunc(slices.Values(node.adj), func(a, b *Link) int {
+				return cmp.Or(strings.Compare(a.endNode.station, b.endNode.station),
+					strings.Compare(a.endNode.line, b.endNode.line), strings.Compare(string(a.attrs.Mode),
+						string(b.attrs.Mode)), cmp.Compare(a.time, b.time))
+			})
+			for _, link := range links {
+				fmt.Fprintf(h, "\t%s\x00%s\x00%s\x00%d\x00%d\x00%t", link.endNode.station, link.endNode.line,
+					link.attrs.Mode, link.time, link.attrs.Dwell, link.attrs.StepFree)
+				for _, band := range slices.Sorted(maps.Keys(link.attrs.BandTimes)) {
+					fmt.Fprintf(h, "\x00%s=%d", band, link.attrs.BandTimes[band])
+				}
+				if link.attrs.MinConnection > 0 {
+					fmt.Fprintf(h, "\x00connection=%d", link.attrs.MinConnection)
+				}
+				h.Write([]byte("\n"))
+			}
+		}
+	}
+	return stampDigits(h)
+}
+
+// Return the version of the graph's network (see NetworkVersion), worked
+// out the first time it is asked for
+func (g *Graph) DataVersion() string {
+	g.versionOnce.Do(func() {
+		g.version = NetworkVersion(g.nodeMap)
+	})
+	return g.version
+}
+
+// Describe the options that shape a search, in a fixed order, as settings
+// separated by semicolons, e.g. "optimize=time; depart=2026-03-02T08:30Z;
+// step-free; avoid=Bank". The objective is always given. The deadline is
+// left out, as it only decides whether a search finishes, and boarding
+// penalties, which are code rather than settings, are only noted as being
+// there.
+func (opts *SearchOptions) Describe() string {
+	settings := []string{"optimize=" + string(cmp.Or(opts.Optimize, OptimizeTime))}
+	if !opts.DepartAt.IsZero() {
+		settings = append(settings, "depart="+opts.DepartAt.Format("2006-01-02T15:04Z07:00"))
+	}
+	if opts.StepFree {
+		settings = append(settings, "step-free")
+	}
+	for _, set := range []struct {
+		name     string
+		included map[string]bool
+	}{{"closed-lines", opts.ClosedLines}, {"closed-stations", opts.ClosedStations},
+		{"avoid", opts.AvoidStations}} {
+		names := make([]string, 0)
+		for name, included := range set.included {
+			if included {
+				names = append(names, name)
+			}
+		}
+		if len(names) > 0 {
+			slices.Sort(names)
+			settings = append(settings, set.name+"="+strings.Join(names, ","))
+		}
+	}
+	for _, speed := range []struct {
+		name  string
+		speed float64
+	}{{"interchange-speed", opts.InterchangeSpeed}, {"street-speed", opts.StreetSpeed}} {
+		if speed.speed > 0 && speed.speed != 1 {
+			settings = append(settings, speed.name+"="+strconv.FormatFloat(speed.speed, 'g', -1, 64))
+		}
+	}
+	if len(opts.InterchangePenalty) > 0 {
+		penalties := make([]string, 0, len(opts.InterchangePenalty))
+		for _, mode := range slices.Sorted(maps.Keys(opts.InterchangePenalty)) {
+			penalties = append(penalties, fmt.Sprintf("%s:%d", mode, opts.InterchangePenalty[mode]))
+		}
+		settings = append(settings, "interchange-penalty="+strings.Join(penalties, ","))
+	}
+	if len(opts.LineDelays) > 0 {
+		delays := make([]string, 0, len(opts.LineDelays))
+		for _, line := range slices.Sorted(maps.Keys(opts.LineDelays)) {
+			delays = append(delays, line+":"+strconv.FormatFloat(opts.LineDelays[line], 'f', 3, 64))
+		}
+		settings = append(settings, "line-delays="+strings.Join(delays, ","))
+	}
+	if opts.BoardingPenalty != nil {
+		settings = append(settings, "boarding-penalties")
+	}
+	return strings.Join(settings, "; ")
+}
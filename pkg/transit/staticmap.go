@@ -0,0 +1,244 @@
+package transit
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// URL template of the OpenStreetMap standard tile layer, with {z}, {x} and
+// {y} standing for the zoom level and tile column and row
+const OSMTileURL = "https://tile.openstreetmap.org/{z}/{x}/{y}.png"
+
+// Size of a map tile in pixels
+const tileSize = 256
+
+// Zoom levels the map may be drawn at, the closest fitting the whole route
+const (
+	minMapZoom = 10
+	maxMapZoom = 16
+)
+
+// Margin in pixels left around the route when choosing the zoom level
+const mapMargin = 40
+
+// Attribution the OpenStreetMap tile usage policy requires on every map
+const osmAttribution = "(c) OpenStreetMap contributors"
+
+// Colours the route is drawn in
+var (
+	routeColour       = color.RGBA{0x00, 0x19, 0xa8, 0xff}
+	walkColour        = color.RGBA{0x55, 0x55, 0x55, 0xff}
+	stopColour        = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	startColour       = color.RGBA{0x00, 0x78, 0x2a, 0xff}
+	destinationColour = color.RGBA{0xdc, 0x24, 0x1f, 0xff}
+)
+
+// Return the position of the specified coordinates in pixels on the Web
+// Mercator projection of the whole world at the specified zoom level
+func worldPixel(c Coordinates, zoom int) (float64, float64) {
+	scale := float64(tileSize) * math.Exp2(float64(zoom))
+	latRad := c.lat * math.Pi / 180
+	x := (c.lon + 180) / 360 * scale
+	y := (1 - math.Log(math.Tan(latRad)+1/math.Cos(latRad))/math.Pi) / 2 * scale
+	return x, y
+}
+
+// Render a map of the specified journey over map tiles fetched from the
+// specified URL template (e.g. OSMTileURL) through the specified client, as
+// an image of the specified size in pixels. Every station along the journey
+// needs known coordinates (see GetStationCoordinates).
+func RenderRouteMap(client *http.Client, journey *Journey, tileURL string,
+	width, height int) (image.Image, error) {
+	if len(journey.Legs) == 0 {
+		return nil, fmt.Errorf("no route to draw")
+	}
+	// The stations along the journey in order, and whether each was reached
+	// by train (the start was not)
+	stations, byTrain := []string{journey.From}, []bool{false}
+	for _, leg := range journey.Legs {
+		if leg.IsRide() {
+			for _, stop := range leg.Stops {
+				stations, byTrain = append(stations, stop.Station), append(byTrain, true)
+			}
+		} else if leg.IsInterchange() {
+			stations, byTrain = append(stations, leg.To), append(byTrain, false)
+		}
+	}
+	stationCoords := GetStationCoordinates()
+	coords := make([]Coordinates, len(stations))
+	for idx, station := range stations {
+		c, known := stationCoords[station]
+		if !known {
+			return nil, fmt.Errorf("no coordinates are known for %s, import them with "+
+				"\"./tubeplanner import-coords\"", station)
+		}
+		coords[idx] = c
+	}
+
+	// Choose the closest zoom at which the whole route fits in the image
+	zoom := minMapZoom
+	for z := maxMapZoom; z > minMapZoom; z-- {
+		minX, minY, maxX, maxY := routeBounds(coords, z)
+		if maxX-minX <= float64(width-2*mapMargin) && maxY-minY <= float64(height-2*mapMargin) {
+			zoom = z
+			break
+		}
+	}
+	minX, minY, maxX, maxY := routeBounds(coords, zoom)
+	left := int(math.Round((minX+maxX)/2)) - width/2
+	top := int(math.Round((minY+maxY)/2)) - height/2
+
+	canvas := image.NewRGBA(image.Rect(0, 0, width, height))
+	tileCount := 1 << zoom
+	for tileY := top / tileSize; tileY*tileSize < top+height; tileY++ {
+		for tileX := left / tileSize; tileX*tileSize < left+width; tileX++ {
+			if tileX < 0 || tileY < 0 || tileX >= tileCount || tileY >= tileCount {
+				continue
+			}
+			tile, err := fetchTile(client, tileURL, zoom, tileX, tileY)
+			if err != nil {
+				return nil, err
+			}
+			at := image.Pt(tileX*tileSize-left, tileY*tileSize-top)
+			draw.Draw(canvas, tile.Bounds().Sub(tile.Bounds().Min).Add(at), tile, tile.Bounds().Min, draw.Src)
+		}
+	}
+
+	points := make([]image.Point, len(coords))
+	for idx, c := range coords {
+		x, y := worldPixel(c, zoom)
+		points[idx] = image.Pt(int(math.Round(x))-left, int(math.Round(y))-top)
+	}
+	// Walks between stations are dotted, and interchanges within a station
+	// have nothing to draw
+	for idx := 1; idx < len(points); idx++ {
+		if byTrain[idx] {
+			drawLine(canvas, points[idx-1], points[idx], 3, 1, routeColour)
+		} else {
+			drawLine(canvas, points[idx-1], points[idx], 2, 6, walkColour)
+		}
+	}
+	for _, point := range points {
+		drawDisc(canvas, point, 5, routeColour)
+		drawDisc(canvas, point, 3, stopColour)
+	}
+	drawDisc(canvas, points[0], 8, startColour)
+	drawDisc(canvas, points[len(points)-1], 8, destinationColour)
+	drawAttribution(canvas, osmAttribution)
+	return canvas, nil
+}
+
+// Return the extent of the specified coordinates in world pixels at the
+// specified zoom level
+func routeBounds(coords []Coordinates, zoom int) (minX, minY, maxX, maxY float64) {
+	minX, minY = math.Inf(1), math.Inf(1)
+	maxX, maxY = math.Inf(-1), math.Inf(-1)
+	for _, c := range coords {
+		x, y := worldPixel(c, zoom)
+		minX, minY, maxX, maxY = min(minX, x), min(minY, y), max(maxX, x), max(maxY, y)
+	}
+	return minX, minY, maxX, maxY
+}
+
+// Fetch and decode the specified map tile
+func fetchTile(client *http.Client, tileURL string, zoom, x, y int) (image.Image, error) {
+	reqURL := strings.NewReplacer("{z}", strconv.Itoa(zoom), "{x}", strconv.Itoa(x),
+		"{y}", strconv.Itoa(y)).Replace(tileURL)
+	req, err := http.NewRequest(http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	// The OpenStreetMap tile servers refuse requests which do not identify
+	// the application
+	req.Header.Set("User-Agent", httpUserAgent)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching map tile: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching map tile %s: server returned %s", reqURL, resp.Status)
+	}
+	tile, err := png.Decode(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("decoding map tile %s: %v", reqURL, err)
+	}
+	return tile, nil
+}
+
+// Draw a filled disc of the specified radius centred on the specified point
+func drawDisc(img draw.Image, centre image.Point, radius int, c color.Color) {
+	for dy := -radius; dy <= radius; dy++ {
+		for dx := -radius; dx <= radius; dx++ {
+			if dx*dx+dy*dy <= radius*radius {
+				img.Set(centre.X+dx, centre.Y+dy, c)
+			}
+		}
+	}
+}
+
+// Draw a line of the specified half-width between two points, as a disc
+// every step pixels along it, so that a step of 1 draws a solid line and
+// longer ones a dotted line
+func drawLine(img draw.Image, from, to image.Point, halfWidth, step int, c color.Color) {
+	dx, dy := float64(to.X-from.X), float64(to.Y-from.Y)
+	length := math.Hypot(dx, dy)
+	for dist := 0.0; dist <= length; dist += float64(step) {
+		t := 0.0
+		if length > 0 {
+			t = dist / length
+		}
+		drawDisc(img, image.Pt(from.X+int(math.Round(dx*t)), from.Y+int(math.Round(dy*t))), halfWidth, c)
+	}
+}
+
+// Glyphs of the bitmap font used for the attribution, 5 pixels wide and 7
+// tall, covering only the characters it needs
+var attributionGlyphs = map[rune][7]string{
+	' ': {".....", ".....", ".....", ".....", ".....", ".....", "....."},
+	'(': {"...#.", "..#..", ".#...", ".#...", ".#...", "..#..", "...#."},
+	')': {".#...", "..#..", "...#.", "...#.", "...#.", "..#..", ".#..."},
+	'M': {"#...#", "##.##", "#.#.#", "#.#.#", "#...#", "#...#", "#...#"},
+	'O': {".###.", "#...#", "#...#", "#...#", "#...#", "#...#", ".###."},
+	'S': {".####", "#....", "#....", ".###.", "....#", "....#", "####."},
+	'a': {".....", ".....", ".###.", "....#", ".####", "#...#", ".####"},
+	'b': {"#....", "#....", "####.", "#...#", "#...#", "#...#", "####."},
+	'c': {".....", ".....", ".###.", "#....", "#....", "#....", ".###."},
+	'e': {".....", ".....", ".###.", "#...#", "#####", "#....", ".###."},
+	'i': {"..#..", ".....", ".##..", "..#..", "..#..", "..#..", ".###."},
+	'n': {".....", ".....", "####.", "#...#", "#...#", "#...#", "#...#"},
+	'o': {".....", ".....", ".###.", "#...#", "#...#", "#...#", ".###."},
+	'p': {".....", ".....", "####.", "#...#", "####.", "#....", "#...."},
+	'r': {".....", ".....", "#.##.", "##..#", "#....", "#....", "#...."},
+	's': {".....", ".....", ".####", "#....", ".###.", "....#", "####."},
+	't': {"..#..", "..#..", "#####", "..#..", "..#..", "..#..", "...##"},
+	'u': {".....", ".....", "#...#", "#...#", "#...#", "#..##", ".##.#"},
+}
+
+// Draw the specified text in the bottom right corner of the image, in dark
+// grey on a white box
+func drawAttribution(img draw.Image, text string) {
+	const glyphWidth, glyphHeight, padding = 6, 7, 3
+	bounds := img.Bounds()
+	box := image.Rect(bounds.Max.X-len(text)*glyphWidth-2*padding, bounds.Max.Y-glyphHeight-2*padding,
+		bounds.Max.X, bounds.Max.Y)
+	draw.Draw(img, box, image.NewUniform(color.White), image.Point{}, draw.Src)
+	ink := color.RGBA{0x33, 0x33, 0x33, 0xff}
+	for idx, r := range text {
+		glyph := attributionGlyphs[r]
+		for row, pixels := range glyph {
+			for col, pixel := range pixels {
+				if pixel == '#' {
+					img.Set(box.Min.X+padding+idx*glyphWidth+col, box.Min.Y+padding+row, ink)
+				}
+			}
+		}
+	}
+}
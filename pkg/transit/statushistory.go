@@ -0,0 +1,176 @@
+package transit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Represents the reported service status of a single transit line at the
+// moment it was fetched (e.g. "Good Service", "Minor Delays", "Suspended"),
+// along with when the reported disruption is expected to end, if known
+type LineStatus struct {
+	Line       string    `json:"line"`
+	Severity   string    `json:"severity"`
+	Reason     string    `json:"reason,omitempty"`
+	FetchedAt  time.Time `json:"fetchedAt"`
+	ValidUntil time.Time `json:"validUntil,omitempty"`
+}
+
+// Return whether the status means no trains are running on the line at all
+func (ls LineStatus) IsClosure() bool {
+	switch ls.Severity {
+	case "Closed", "Suspended", "Planned Closure", "Not Running", "Service Closed":
+		return true
+	}
+	return false
+}
+
+// Return whether the status means the line is closed at the specified time,
+// treating closures without a known end as lasting indefinitely
+func (ls LineStatus) ClosedAt(t time.Time) bool {
+	return ls.IsClosure() && (ls.ValidUntil.IsZero() || t.Before(ls.ValidUntil))
+}
+
+// Local append-only store of previously fetched line statuses, kept as one
+// JSON object per line in a plain text file
+type StatusHistory struct {
+	path string
+}
+
+// Return the default location of the status history store, inside the
+// user's cache directory (falling back to the working directory)
+func DefaultStatusHistoryPath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "tubeplanner-status-history.jsonl"
+	}
+	return filepath.Join(dir, "tubeplanner", "status-history.jsonl")
+}
+
+// Return a handle on the status history store at the specified path, which
+// is created on first write if it does not exist already
+func OpenStatusHistory(path string) *StatusHistory {
+	return &StatusHistory{path}
+}
+
+// Append the specified batch of fetched line statuses to the store
+func (sh *StatusHistory) Record(statuses []LineStatus) error {
+	var lines bytes.Buffer
+	enc := json.NewEncoder(&lines)
+	for _, status := range statuses {
+		if err := enc.Encode(status); err != nil {
+			return err
+		}
+	}
+	return appendLines(sh.path, lines.Bytes())
+}
+
+// Call the specified function on every status in the store, in the order
+// they were recorded. A store that does not exist yet is treated as empty
+// rather than as an error.
+func (sh *StatusHistory) scan(visit func(LineStatus)) error {
+	file, err := os.Open(sh.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return scanJSONLines(file, sh.path, func(line []byte) error {
+		var status LineStatus
+		if err := json.Unmarshal(line, &status); err != nil {
+			return err
+		}
+		visit(status)
+		return nil
+	})
+}
+
+// Return every stored status of the specified line fetched at or after the
+// given time, oldest first
+func (sh *StatusHistory) Query(line string, since time.Time) ([]LineStatus, error) {
+	statuses := make([]LineStatus, 0)
+	err := sh.scan(func(status LineStatus) {
+		if status.Line == line && !status.FetchedAt.Before(since) {
+			statuses = append(statuses, status)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(statuses, func(i, j int) bool {
+		return statuses[i].FetchedAt.Before(statuses[j].FetchedAt)
+	})
+	return statuses, nil
+}
+
+// Return the most recently fetched status of each line, ignoring any
+// statuses fetched before the given time as too stale to act on
+func (sh *StatusHistory) Latest(since time.Time) (map[string]LineStatus, error) {
+	latest := make(map[string]LineStatus)
+	err := sh.scan(func(status LineStatus) {
+		if status.FetchedAt.Before(since) {
+			return
+		}
+		if prev, exists := latest[status.Line]; !exists || !status.FetchedAt.Before(prev.FetchedAt) {
+			latest[status.Line] = status
+		}
+	})
+	return latest, err
+}
+
+// Parse a look-back window such as "7d", "36h" or "90m". Go duration syntax
+// is accepted as-is, with the addition of a "d" suffix for whole days.
+func ParseSince(s string) (time.Duration, error) {
+	if days, found := strings.CutSuffix(s, "d"); found {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid number of days: %s", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid time window: %s", s)
+	}
+	return d, nil
+}
+
+// Print the recorded statuses of a line, followed by a summary of how often
+// each severity was reported over the window
+func PrintStatusHistory(line string, since time.Time, statuses []LineStatus) {
+	fmt.Printf("Status history for the %s line since %s:\n",
+		line, since.Local().Format("2006-01-02 15:04"))
+	if len(statuses) == 0 {
+		fmt.Println("No statuses recorded.")
+		return
+	}
+	counts := make(map[string]int)
+	severities := make([]string, 0)
+	for _, status := range statuses {
+		if counts[status.Severity] == 0 {
+			severities = append(severities, status.Severity)
+		}
+		counts[status.Severity]++
+		if status.Reason != "" {
+			fmt.Printf("- %s %s: %s\n", status.FetchedAt.Local().Format("2006-01-02 15:04"),
+				status.Severity, status.Reason)
+		} else {
+			fmt.Printf("- %s %s\n", status.FetchedAt.Local().Format("2006-01-02 15:04"),
+				status.Severity)
+		}
+	}
+	fmt.Printf("%d statuses recorded:\n", len(statuses))
+	for _, severity := range severities {
+		fmt.Printf("- %s: %.0f%%\n", severity, 100*float64(counts[severity])/float64(len(statuses)))
+	}
+}
@@ -0,0 +1,258 @@
+package transit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// A single routing query read in --stdio-json mode. The optional ID is
+// echoed back unchanged so callers can match responses to queries. Closed
+// and AvoidLine describe a scenario applying to this query alone: stations
+// treated as closed (trains run through without stopping) and lines not to
+// be used at all. AvoidStation lists stations not to pass through at all.
+type JSONQuery struct {
+	ID           json.RawMessage `json:"id,omitempty"`
+	From         string          `json:"from"`
+	To           string          `json:"to"`
+	Closed       []string        `json:"closed,omitempty"`
+	AvoidLine    []string        `json:"avoidLine,omitempty"`
+	AvoidStation []string        `json:"avoidStation,omitempty"`
+	// Objective as for the --optimize option, "time" (the default) or
+	// "changes"
+	Optimize string `json:"optimize,omitempty"`
+	// Whether the trip must be step-free, as for the --step-free option
+	StepFree bool `json:"stepFree,omitempty"`
+	// Preferences as for the --prefer-seat and --accessibility options,
+	// and the time budget for honouring them in milliseconds
+	PreferSeat    uint16 `json:"preferSeat,omitempty"`
+	Accessibility string `json:"accessibility,omitempty"`
+	BudgetMS      int    `json:"budgetMs,omitempty"`
+}
+
+// The response written for each JSONQuery, holding either the planned
+// journey or a description of why the query could not be answered. Partial
+// marks a journey which is only the fastest route, because the query's
+// preferences could not be honoured within its time budget.
+type JSONResponse struct {
+	ID      json.RawMessage `json:"id,omitempty"`
+	Journey *Journey        `json:"journey,omitempty"`
+	Partial bool            `json:"partial,omitempty"`
+	Error   string          `json:"error,omitempty"`
+}
+
+// Answer a single query against the already-built transit graph. The
+// fastest route is always planned in full; if the query also asks for a
+// preference such as a seat, the route honouring it is planned within the
+// query's time budget (or else the specified default budget, where zero means
+// no limit), falling back to the fastest route marked as partial if the
+// budget runs out first. The journey is stamped with the version of the
+// network (see NetworkVersion), worked out afresh for every query.
+func AnswerJSONQuery(nodeMap NodeMap, query JSONQuery, defaultBudget time.Duration) JSONResponse {
+	return answerJSONQuery(nodeMap, nil, query, defaultBudget, NetworkVersion(nodeMap))
+}
+
+// Answer a single query as AnswerJSONQuery does, planning the fastest route
+// from the specified contraction hierarchy, if any, when the query sets no
+// search options, and stamping the journey with the specified version of
+// the network
+func answerJSONQuery(nodeMap NodeMap, ch *ContractionHierarchy, query JSONQuery,
+	defaultBudget time.Duration, dataVersion string) JSONResponse {
+	began := time.Now()
+	response := JSONResponse{ID: query.ID}
+	if err := checkQueryStations(nodeMap, query); err != nil {
+		response.Error = err.Error()
+		return response
+	}
+	opts, err := queryScenario(nodeMap, query)
+	if err != nil {
+		response.Error = err.Error()
+		return response
+	}
+	prefOpts := *opts
+	if query.Accessibility != "" {
+		needs, err := ParseAccessibilityNeeds(query.Accessibility)
+		if err != nil {
+			response.Error = err.Error()
+			return response
+		}
+		prefOpts.BoardingPenalty = AccessibilityBoardingPenalty(needs)
+	}
+
+	var journey *Journey
+	if ch != nil && opts.unconstrained() {
+		journey, err = ch.ShortestPath(query.From, []string{query.To})
+	} else {
+		npq := ResetGraph(nodeMap)
+		journey, err = RunShortestPaths(&npq, nodeMap, query.From, query.To, opts)
+	}
+	if err != nil {
+		response.Error = fmt.Sprintf("No route available from %s to %s", query.From, query.To)
+		return response
+	}
+	options := opts.Describe()
+	if query.Accessibility != "" {
+		options += "; accessibility=" + query.Accessibility
+	}
+	if query.PreferSeat > 0 {
+		options += fmt.Sprintf("; prefer-seat=%d", query.PreferSeat)
+	}
+	response.Journey = markScenarioClosures(journey, opts)
+	response.Journey.Stamp = NewStamp(journey, dataVersion, options)
+	if query.PreferSeat == 0 && query.Accessibility == "" {
+		return response
+	}
+
+	budget := defaultBudget
+	if query.BudgetMS > 0 {
+		budget = time.Duration(query.BudgetMS) * time.Millisecond
+	}
+	if budget > 0 {
+		prefOpts.Deadline = began.Add(budget)
+	}
+	if query.PreferSeat > 0 {
+		journey, _, err = PlanSeatFriendlyRoute(nodeMap, query.From, query.To, query.PreferSeat, &prefOpts)
+	} else {
+		npq := ResetGraph(nodeMap)
+		journey, err = RunShortestPaths(&npq, nodeMap, query.From, query.To, &prefOpts)
+	}
+	if errors.Is(err, ErrDeadlineExceeded) {
+		response.Partial = true
+	} else if err == nil {
+		response.Journey = markScenarioClosures(journey, opts)
+		response.Journey.Stamp = NewStamp(journey, dataVersion, options)
+	}
+	return response
+}
+
+// Return an error if the trip of the specified query cannot begin or end
+// where it asks to, because either station is unknown or closed
+func checkQueryStations(nodeMap NodeMap, query JSONQuery) error {
+	if _, startExists := nodeMap[query.From]; !startExists {
+		return fmt.Errorf("%s is not a valid initial station", query.From)
+	}
+	if _, destExists := nodeMap[query.To]; !destExists {
+		return fmt.Errorf("%s is not a valid destination", query.To)
+	}
+	for _, station := range []string{query.From, query.To} {
+		if closure, isClosed := StationClosure(nodeMap, station); isClosed {
+			return errors.New(closure)
+		}
+	}
+	return nil
+}
+
+// Mark the stations closed by a query's scenario, as given by the specified
+// search options, as such along the specified journey planned for it,
+// returning the journey
+func markScenarioClosures(journey *Journey, opts *SearchOptions) *Journey {
+	for idx := range journey.Legs {
+		for stopIdx, stop := range journey.Legs[idx].Stops {
+			if opts.ClosedStations[stop.Station] {
+				journey.Legs[idx].Stops[stopIdx].Closed = true
+			}
+		}
+	}
+	return journey
+}
+
+// Return whether the query sets no search options or preferences at all, so
+// that it can be answered from a contraction hierarchy without searching the
+// graph itself
+func (query JSONQuery) unconstrained() bool {
+	return len(query.Closed) == 0 && len(query.AvoidLine) == 0 && len(query.AvoidStation) == 0 &&
+		(query.Optimize == "" || query.Optimize == string(OptimizeTime)) && !query.StepFree &&
+		query.PreferSeat == 0 && query.Accessibility == ""
+}
+
+// Return the search options for the closures, avoided lines and stations and
+// objective of the specified query. These are applied by the search itself,
+// so the shared graph is left untouched for other queries.
+func queryScenario(nodeMap NodeMap, query JSONQuery) (*SearchOptions, error) {
+	opts := &SearchOptions{ClosedLines: make(map[string]bool),
+		ClosedStations: make(map[string]bool), AvoidStations: make(map[string]bool),
+		StepFree: query.StepFree}
+	if query.Optimize != "" {
+		var err error
+		if opts.Optimize, err = ParseObjective(query.Optimize); err != nil {
+			return nil, err
+		}
+		if opts.Optimize == OptimizeReliable {
+			return nil, fmt.Errorf("optimizing for reliability is only available from the command line")
+		}
+		if opts.Optimize == OptimizeCheapest {
+			return nil, fmt.Errorf("optimizing for fares is only available from the command line")
+		}
+	}
+	if query.StepFree {
+		for _, station := range []string{query.From, query.To} {
+			if !HasStepFreeAccess(nodeMap, station) {
+				return nil, fmt.Errorf("%s has no step-free access to its platforms", station)
+			}
+		}
+	}
+	for _, station := range query.Closed {
+		if _, exists := nodeMap[station]; !exists {
+			return nil, fmt.Errorf("%s is not a valid station", station)
+		}
+		if station == query.From || station == query.To {
+			return nil, fmt.Errorf("%s is closed", station)
+		}
+		opts.ClosedStations[station] = true
+	}
+	for _, line := range query.AvoidLine {
+		if !LineExists(nodeMap, line) {
+			return nil, fmt.Errorf("%s is not a valid line", line)
+		}
+		opts.ClosedLines[line] = true
+	}
+	for _, station := range query.AvoidStation {
+		if _, exists := nodeMap[station]; !exists {
+			return nil, fmt.Errorf("%s is not a valid station", station)
+		}
+		if station == query.From || station == query.To {
+			return nil, fmt.Errorf("cannot avoid %s, where the trip begins or ends", station)
+		}
+		opts.AvoidStations[station] = true
+	}
+	return opts, nil
+}
+
+// Read one JSON query per line from the input until it is exhausted, writing
+// one JSON response per line to the output for each, all answered from the
+// specified graph. Malformed queries produce an error response rather than
+// ending the session. Answered queries are recorded in the specified query
+// log, unless it is nil. Queries without a time budget of their own get the
+// specified default budget.
+func RunStdioJSON(nodeMap NodeMap, in io.Reader, out io.Writer, queryLog *QueryLog,
+	budget time.Duration) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	enc := json.NewEncoder(out)
+	enc.SetEscapeHTML(false)
+	version := NetworkVersion(nodeMap)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var query JSONQuery
+		var response JSONResponse
+		if err := json.Unmarshal(scanner.Bytes(), &query); err != nil {
+			response.Error = fmt.Sprintf("invalid query: %v", err)
+		} else {
+			response = answerJSONQuery(nodeMap, nil, query, budget, version)
+			if queryLog != nil {
+				if err := queryLog.Record(query, response); err != nil {
+					return fmt.Errorf("writing query log: %v", err)
+				}
+			}
+		}
+		if err := enc.Encode(response); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
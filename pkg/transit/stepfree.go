@@ -0,0 +1,112 @@
+package transit
+
+import "slices"
+
+// Record on each Node of the graph whether its platforms have step-free
+// access, for the stations and lines listed in the specified access and for
+// every stop of the specified lines, along with the extra time the lifts
+// take. Each interchange link is then marked step-free when its platforms
+// at both ends are, along with the lifts it depends on: those listed in the
+// specified lift dependencies, or else the street lifts of both Nodes.
+// Entries for stations or lines not in the graph are ignored.
+func ApplyStepFreeAccess(nodeMap NodeMap, access []StepFreeAccess, lines []string, lifts []LiftDependency) {
+	for _, line := range lines {
+		for _, nodes := range nodeMap {
+			if node, exists := nodes[line]; exists {
+				node.stepFree = true
+			}
+		}
+	}
+	for _, sfa := range access {
+		if node, exists := nodeMap[sfa.station][sfa.line]; exists {
+			node.stepFree, node.liftTime = true, sfa.liftTime
+		}
+	}
+
+	interchangeLifts := make(map[[2]*Node][]Lift)
+	for _, ld := range lifts {
+		deps := make([]Lift, len(ld.lifts))
+		for idx, name := range ld.lifts {
+			deps[idx] = Lift{ld.station, name}
+		}
+		to, exists := nodeMap[ld.station][ld.toLine]
+		if !exists {
+			continue
+		}
+		if ld.fromLine == "" {
+			to.lifts = deps
+		} else if from, exists := nodeMap[ld.station][ld.fromLine]; exists {
+			interchangeLifts[[2]*Node{from, to}] = deps
+			interchangeLifts[[2]*Node{to, from}] = deps
+		}
+	}
+	for _, nodes := range nodeMap {
+		for _, node := range nodes {
+			for _, link := range node.adj {
+				if link.attrs.Mode == ModeRail || !node.stepFree || !link.endNode.stepFree {
+					continue
+				}
+				deps, listed := interchangeLifts[[2]*Node{node, link.endNode}]
+				if !listed {
+					deps = slices.Concat(node.lifts, link.endNode.lifts)
+				}
+				link.attrs.StepFree, link.attrs.Lifts = true, deps
+			}
+		}
+	}
+}
+
+// Record on each Node of the graph listed in the specified boarding ramps
+// that its trains need a manual ramp to get on or off. Entries for stations
+// or lines not in the graph are ignored.
+func ApplyBoardingRamps(nodeMap NodeMap, ramps []BoardingRamp) {
+	for _, br := range ramps {
+		if node, exists := nodeMap[br.station][br.line]; exists {
+			node.ramp = true
+		}
+	}
+}
+
+// Return whether the specified station has step-free access to the
+// platforms of any of its lines
+func HasStepFreeAccess(nodeMap NodeMap, station string) bool {
+	for _, node := range nodeMap[station] {
+		if node.stepFree {
+			return true
+		}
+	}
+	return false
+}
+
+// Return whether the options allow starting or ending a trip on the platforms
+// of the specified Node, which must be step-free if the options ask for it
+func (opts *SearchOptions) accessible(node *Node) bool {
+	return opts == nil || !opts.StepFree || node.stepFree
+}
+
+// Return the time taken to walk between the gates and the platforms of the
+// specified Node, by lift if the options ask for step-free access
+func (opts *SearchOptions) accessTime(node *Node) uint16 {
+	if opts != nil && opts.StepFree {
+		return AddTime(node.accessTime, node.liftTime)
+	}
+	return node.accessTime
+}
+
+// Return the extra time taken by lifts along the specified link if the
+// options ask for step-free access: interchanges end with the way down to
+// the new platform by lift
+func (opts *SearchOptions) liftTime(link *Link) uint16 {
+	if opts != nil && opts.StepFree && link.attrs.Mode != ModeRail {
+		return link.endNode.liftTime
+	}
+	return 0
+}
+
+// Return whether the options forbid following the specified link for want
+// of step-free access: interchanges, whether within
+// a station or on foot to another, need step-free access to both platforms,
+// with every lift along the way in service
+func (opts *SearchOptions) stepsRequired(link *Link) bool {
+	return opts != nil && opts.StepFree && link.attrs.Mode != ModeRail && !link.attrs.StepFree
+}
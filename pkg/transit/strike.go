@@ -0,0 +1,84 @@
+package transit
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Extra cost in minutes of boarding any line still running on a strike day,
+// when crowds at stations make trains slower to board. This tips the balance
+// towards walking along the street between nearby stations instead of making
+// another change.
+const strikeCrowdingPenalty = 5
+
+// Represents a strike affecting one or more lines, such as the one described
+// by "RMT on Central,Victoria"
+type Strike struct {
+	union string
+	lines []string
+}
+
+// Parse a strike description: a comma-separated list of the lines on strike,
+// optionally preceded by the name of the union calling it and "on"
+func ParseStrike(s string) (Strike, error) {
+	var strike Strike
+	lines := s
+	if union, rest, found := strings.Cut(s, " on "); found {
+		strike.union, lines = strings.TrimSpace(union), rest
+	}
+	for _, line := range strings.Split(lines, ",") {
+		if line = strings.TrimSpace(line); line != "" {
+			strike.lines = append(strike.lines, line)
+		}
+	}
+	if len(strike.lines) == 0 {
+		return Strike{}, errors.New("strike names no lines")
+	}
+	return strike, nil
+}
+
+// Check that every line on strike is in the specified transit graph
+func (strike Strike) Validate(nodeMap NodeMap) error {
+	for _, line := range strike.lines {
+		found := false
+		for _, lines := range nodeMap {
+			if _, found = lines[line]; found {
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%s is not a valid line", line)
+		}
+	}
+	return nil
+}
+
+// Return the lines on strike
+func (strike Strike) Lines() []string {
+	return strike.lines
+}
+
+// Adjust the specified search options for the strike, closing the lines on
+// strike and adding the crowding penalty for boarding the others
+func (strike Strike) Apply(opts *SearchOptions) {
+	closedLines := make(map[string]bool, len(opts.ClosedLines)+len(strike.lines))
+	for line, closed := range opts.ClosedLines {
+		closedLines[line] = closed
+	}
+	for _, line := range strike.lines {
+		closedLines[line] = true
+	}
+	opts.ClosedLines = closedLines
+	opts.BoardingPenalty = CombinePenalties(opts.BoardingPenalty, func(station, line string) uint16 {
+		return strikeCrowdingPenalty
+	})
+}
+
+// Return a one-line description of the strike for the directions
+func (strike Strike) String() string {
+	if strike.union == "" {
+		return "Strike on " + strings.Join(strike.lines, ", ")
+	}
+	return fmt.Sprintf("%s strike on %s", strike.union, strings.Join(strike.lines, ", "))
+}
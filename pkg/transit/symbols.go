@@ -0,0 +1,41 @@
+package transit
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Return the symbol for the kind of train running on the specified line
+func lineSymbol(line string) string {
+	switch line {
+	case "Docklands Light Railway":
+		return "🚈"
+	case "Tramlink":
+		return "🚊"
+	case "Elizabeth", "Overground":
+		return "🚆"
+	default:
+		return "🚇"
+	}
+}
+
+// Render the specified journey as a single line of route symbols suited to
+// chat messages, e.g. "Uxbridge 🚇 Metropolitan → Farringdon 🔁 🚆 Elizabeth
+// → Woolwich 🚶 Woolwich Arsenal (78 min)"
+func JourneySymbols(journey *Journey) string {
+	if len(journey.Legs) == 0 {
+		return fmt.Sprintf("📍 %s (already there)", journey.From)
+	}
+	parts := []string{journey.From}
+	for _, leg := range journey.Legs {
+		switch leg.Type {
+		case string(ModeRail):
+			parts = append(parts, fmt.Sprintf("%s %s → %s", lineSymbol(leg.Line), leg.Line, leg.To))
+		case string(ModeLineInterchange):
+			parts = append(parts, "🔁")
+		case string(ModeStationInterchange):
+			parts = append(parts, "🚶 "+leg.To)
+		}
+	}
+	return fmt.Sprintf("%s (%d min)", strings.Join(parts, " "), journey.TotalMinutes)
+}
@@ -0,0 +1,226 @@
+package transit
+
+import (
+	"container/heap"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Ratio of the distance driven along roads to the straight-line distance
+// between two places
+const taxiDetourFactor = 1.4
+
+// Minutes allowed on top of the drive itself for hailing or booking a taxi
+// and getting in
+const taxiPickupMinutes = 5
+
+// Represents a taxi or minicab which can take the first or last part of a
+// trip, or all of it, with its average speed and a fare of a flat charge
+// plus a charge for each kilometre driven
+type Taxi struct {
+	SpeedKMH   float64
+	FlatPence  uint16
+	PencePerKM uint16
+}
+
+// A taxi through London at night: 20 km/h on average, with a fare of £3.80
+// plus £2.20 a kilometre
+var DefaultTaxi = Taxi{SpeedKMH: 20, FlatPence: 380, PencePerKM: 220}
+
+// Parse a taxi fare given as a flat charge plus a charge per kilometre, in
+// pounds, e.g. "3.80+2.20", returning both in pence
+func ParseTaxiFare(s string) (uint16, uint16, error) {
+	flatStr, perKMStr, found := strings.Cut(s, "+")
+	if !found {
+		return 0, 0, fmt.Errorf("invalid taxi fare %q, expected a flat fare plus a fare per km, e.g. 3.80+2.20", s)
+	}
+	pence := make([]uint16, 2)
+	for idx, part := range []string{flatStr, perKMStr} {
+		pounds, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil || pounds < 0 || pounds*100 >= math.MaxUint16 {
+			return 0, 0, fmt.Errorf("invalid taxi fare %q: %q is not an amount in pounds", s, part)
+		}
+		pence[idx] = uint16(math.Round(pounds * 100))
+	}
+	return pence[0], pence[1], nil
+}
+
+// Represents a ride by taxi between two stations, with the distance driven
+// in kilometres, how many minutes it takes including the pickup, and its
+// fare in pence
+type TaxiLeg struct {
+	From    string
+	To      string
+	KM      float64
+	Minutes uint16
+	Pence   uint16
+}
+
+// Return the ride by taxi between the specified coordinates of the specified
+// stations
+func (taxi Taxi) leg(from, to string, a, b Coordinates) TaxiLeg {
+	km := DistanceKM(a, b) * taxiDetourFactor
+	minutes := math.Ceil(km/taxi.SpeedKMH*60) + taxiPickupMinutes
+	pence := math.Round(float64(taxi.FlatPence) + km*float64(taxi.PencePerKM))
+	return TaxiLeg{from, to, km, uint16(min(minutes, math.MaxUint16-1)), uint16(min(pence, math.MaxUint16))}
+}
+
+// Represents a trip taking a taxi for the first part, the last part or all
+// of it. First and Last are nil where the trip goes by train, and Route is
+// nil if it goes by taxi all the way.
+type TaxiPlan struct {
+	First *TaxiLeg
+	Route *Route
+	Last  *TaxiLeg
+}
+
+// Return the number of minutes the whole trip takes
+func (tp *TaxiPlan) TotalMinutes() uint16 {
+	var total uint16
+	for _, leg := range []*TaxiLeg{tp.First, tp.Last} {
+		if leg != nil {
+			total = AddTime(total, leg.Minutes)
+		}
+	}
+	if tp.Route != nil {
+		total = AddTime(total, tp.Route.TotalMinutes())
+	}
+	return total
+}
+
+// Return the total fare of the trip's taxi rides in pence, leaving out any
+// fare for the part by train
+func (tp *TaxiPlan) TaxiPence() uint16 {
+	var pence uint16
+	for _, leg := range []*TaxiLeg{tp.First, tp.Last} {
+		if leg != nil {
+			pence = AddTime(pence, leg.Pence)
+		}
+	}
+	return pence
+}
+
+// Plan the quickest trip between the specified stations allowing a taxi for
+// the first part, the last part or all of it, as well as trains, under the
+// specified search options (which may be nil). Taxis may go between any
+// stations with known coordinates, as given, which the start and destination
+// must both have. A taxi is taken wherever it gets there sooner, so this
+// suits trips which cannot be made by train alone, such as those after the
+// last trains; the trip is made by train alone where that is quickest. With
+// a departure time, the part by train is planned for when the first taxi
+// drops off, so it may only ride the trains still running then.
+func PlanWithTaxi(nodeMap NodeMap, start, dest string, taxi Taxi, coords map[string]Coordinates,
+	opts *SearchOptions) (*TaxiPlan, error) {
+	for _, station := range []string{start, dest} {
+		if _, known := coords[station]; !known {
+			return nil, fmt.Errorf("no coordinates are known for %s, to take a taxi from or to", station)
+		}
+	}
+	if taxi.SpeedKMH <= 0 {
+		return nil, fmt.Errorf("taxi speed must be greater than zero")
+	}
+	if start == dest {
+		return &TaxiPlan{}, nil
+	}
+
+	// Begin on any line at the start station, or on any line at another
+	// station after taking a taxi there
+	firstLegs := make(map[*Node]*TaxiLeg)
+	npq := ResetGraph(nodeMap)
+	nodePrev, linkPrev := make(map[*Node]*Node), make(map[*Node]*Link)
+	starts := startCosts(nodeMap, start, opts)
+	for node, start := range starts {
+		npq.update(node, start.cost)
+		node.elapsed = start.elapsed
+	}
+	for _, station := range slices.Sorted(maps.Keys(nodeMap)) {
+		c, known := coords[station]
+		if !known || station == start || station == dest {
+			continue
+		}
+		leg := taxi.leg(start, station, coords[start], c)
+		for _, node := range nodeMap[station] {
+			elapsed := AddTime(leg.Minutes, opts.accessTime(node))
+			if opts.closed(node) || (opts != nil && (opts.excludedNodes[node] || opts.AvoidStations[station])) ||
+				!opts.accessible(node) || !opts.running(node, elapsed) {
+				continue
+			}
+			elapsed = AddTime(elapsed, opts.boardWait(node, elapsed, 0))
+			if cost := AddTime(elapsed, opts.boardingPenalty(node)); cost < node.totalTime {
+				firstLegs[node] = &leg
+				npq.update(node, cost)
+				node.elapsed = elapsed
+			}
+		}
+	}
+
+	// Search the whole graph, as the trip may leave the trains anywhere and
+	// take a taxi the rest of the way, unless going by taxi all the way is
+	// quicker still
+	direct := taxi.leg(start, dest, coords[start], coords[dest])
+	var bestNode *Node
+	var bestLast *TaxiLeg
+	bestTime := direct.Minutes
+	for len(npq) > 0 {
+		if opts != nil && !opts.Deadline.IsZero() && time.Now().After(opts.Deadline) {
+			return nil, ErrDeadlineExceeded
+		}
+		curNode := heap.Pop(&npq).(*Node)
+		if curNode.totalTime >= bestTime {
+			break
+		}
+		if opts.accessible(curNode) {
+			arrival := AddTime(curNode.totalTime, opts.accessTime(curNode))
+			var last *TaxiLeg
+			if curNode.station != dest {
+				if c, known := coords[curNode.station]; known {
+					leg := taxi.leg(curNode.station, dest, c, coords[dest])
+					arrival, last = AddTime(arrival, leg.Minutes), &leg
+				} else {
+					arrival = math.MaxUint16
+				}
+			}
+			if arrival < bestTime {
+				bestNode, bestLast, bestTime = curNode, last, arrival
+			}
+		}
+		if curNode.station == dest {
+			continue
+		}
+		for _, link := range curNode.adj {
+			if opts.blocked(curNode, link) {
+				continue
+			}
+			if alt := opts.linkCost(curNode, link, curNode.progress(starts)); alt.cost < link.endNode.totalTime {
+				nodePrev[link.endNode], linkPrev[link.endNode] = curNode, link
+				npq.update(link.endNode, alt.cost)
+				link.endNode.elapsed = alt.elapsed
+			}
+		}
+	}
+	if bestNode == nil {
+		return &TaxiPlan{First: &direct}, nil
+	}
+
+	route, links := []*Node{bestNode}, make([]*Link, 0)
+	for node := bestNode; linkPrev[node] != nil; node = nodePrev[node] {
+		route = append(route, nodePrev[node])
+		links = append(links, linkPrev[node])
+	}
+	slices.Reverse(route)
+	slices.Reverse(links)
+	// Time the part by train from when the first taxi drops off
+	first := firstLegs[route[0]]
+	railOpts := opts
+	if first != nil && !opts.timeIndependent() {
+		shifted := *opts
+		shifted.DepartAt = opts.DepartAt.Add(time.Duration(first.Minutes) * time.Minute)
+		railOpts = &shifted
+	}
+	return &TaxiPlan{First: first, Route: &Route{newJourney(route, links, railOpts)}, Last: bestLast}, nil
+}
@@ -0,0 +1,88 @@
+package transit
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// Return the number of interchanges made by following the specified link:
+// one if it leads to another line or station, and none along a line
+func linkChanges(link *Link) uint16 {
+	if link.attrs.Mode == ModeRail {
+		return 0
+	}
+	return 1
+}
+
+// Describes a trip for telling it apart from others of equal search cost
+// making as many interchanges: the lines it rides, in order, then the
+// stations it passes through
+type tieKey struct {
+	lines    []string
+	stations []string
+}
+
+// Return the key of the trip to the specified Node, following the specified
+// function back to the Node before each until it returns nil at the start
+func tieKeyTo(node *Node, prev func(*Node) *Node) tieKey {
+	key := tieKeyFrom(node, prev)
+	slices.Reverse(key.lines)
+	slices.Reverse(key.stations)
+	return key
+}
+
+// Return the key of the trip on from the specified Node, following the
+// specified function on to the Node after each until it returns nil at the
+// end
+func tieKeyFrom(node *Node, next func(*Node) *Node) tieKey {
+	var key tieKey
+	for ; node != nil; node = next(node) {
+		key = key.then(node)
+	}
+	return key
+}
+
+// Return the key extended by travelling on to the specified Node
+func (key tieKey) then(node *Node) tieKey {
+	if len(key.lines) == 0 || key.lines[len(key.lines)-1] != node.line {
+		key.lines = append(key.lines, node.line)
+	}
+	key.stations = append(key.stations, node.station)
+	return key
+}
+
+// Return the key of the trip made up of the one described by the key
+// followed by the one described by the specified key, which begins at the
+// Node where the first ends
+func (key tieKey) join(next tieKey) tieKey {
+	return tieKey{append(slices.Clip(key.lines), next.lines[1:]...),
+		append(slices.Clip(key.stations), next.stations[1:]...)}
+}
+
+// Compare two keys by lines, then by stations, each in order by name
+func (key tieKey) compare(other tieKey) int {
+	return cmp.Or(slices.Compare(key.lines, other.lines), slices.Compare(key.stations, other.stations))
+}
+
+// Return whether a trip making the first specified number of interchanges
+// and described by the first specified key is preferable to one of the same
+// search cost making the second number and described by the second key.
+// Every search breaks ties this way, fewest interchanges first and then the
+// lines in the order they are ridden by name, so that the same query always
+// plans the same trip rather than whichever the order of map iteration
+// happened to find first. Trips riding the same lines are told apart by the
+// stations they pass through. Keys are only worked out if the interchanges
+// are the same.
+func preferTie(changes, otherChanges uint16, key, otherKey func() tieKey) bool {
+	if changes != otherChanges {
+		return changes < otherChanges
+	}
+	return key().compare(otherKey()) < 0
+}
+
+// Compare two Nodes by station, then line, for queueing Nodes of equal
+// search cost and interchanges in the same order every time
+func compareNodes(a, b *Node) int {
+	return cmp.Or(strings.Compare(a.station, b.station), strings.Compare(a.line, b.line))
+}
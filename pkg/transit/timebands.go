@@ -0,0 +1,71 @@
+package transit
+
+import (
+	"fmt"
+	"time"
+)
+
+// Names of the time bands in which rail links may have their own run times
+const (
+	PeakBand    = "peak"
+	OffPeakBand = "off-peak"
+)
+
+// Return the time band of the specified moment: weekday mornings from 06:30
+// to 09:30 and evenings from 16:00 to 19:00 are peak, as on TfL, and all
+// other times are off-peak
+func TimeBand(t time.Time) string {
+	if t.Weekday() == time.Saturday || t.Weekday() == time.Sunday {
+		return OffPeakBand
+	}
+	minutes := t.Hour()*60 + t.Minute()
+	if (minutes >= 6*60+30 && minutes < 9*60+30) || (minutes >= 16*60 && minutes < 19*60) {
+		return PeakBand
+	}
+	return OffPeakBand
+}
+
+// Attach the specified banded run times to the rail links they describe, in
+// both directions
+func ApplyBandedRunTimes(nodeMap NodeMap, runTimes []BandedRunTime) error {
+	for _, rt := range runTimes {
+		if rt.band != PeakBand && rt.band != OffPeakBand {
+			return fmt.Errorf("%s to %s (%s): unknown time band %q",
+				rt.fromStation, rt.toStation, rt.line, rt.band)
+		}
+		nodeA, nodeB := nodeMap[rt.fromStation][rt.line], nodeMap[rt.toStation][rt.line]
+		found := false
+		if nodeA != nil && nodeB != nil {
+			for _, pair := range [][2]*Node{{nodeA, nodeB}, {nodeB, nodeA}} {
+				for _, link := range pair[0].adj {
+					if link.endNode == pair[1] && link.attrs.Mode == ModeRail {
+						if link.attrs.BandTimes == nil {
+							link.attrs.BandTimes = make(map[string]uint16)
+						}
+						link.attrs.BandTimes[rt.band] = rt.transitTime
+						found = true
+					}
+				}
+			}
+		}
+		if !found {
+			return fmt.Errorf("no %s line rail link between %s and %s",
+				rt.line, rt.fromStation, rt.toStation)
+		}
+	}
+	return nil
+}
+
+// Parse a departure time given either as a clock time ("08:15"), meaning
+// that time today, or as a full RFC 3339 timestamp
+func ParseDepartAt(s string, now time.Time) (time.Time, error) {
+	if clock, err := time.ParseInLocation("15:04", s, now.Location()); err == nil {
+		return time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(),
+			0, 0, now.Location()), nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid departure time %q (expected HH:MM or RFC 3339)", s)
+	}
+	return t, nil
+}
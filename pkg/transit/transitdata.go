@@ -0,0 +1,2127 @@
+package transit
+
+// Represents a rail connection between two stations
+type RailLink struct {
+	fromStation string
+	toStation   string
+	line        string
+	transitTime uint16
+}
+
+// Represents an on-foot interchange opportunity, either to a nearby station
+// or to a different transit line from the same station
+type Interchange struct {
+	fromStation string
+	fromLine    string
+	toStation   string
+	toLine      string
+	transitTime uint16
+}
+
+// Represents walking guidance for following an interchange in one direction,
+// printed alongside the interchange in detailed directions
+type InterchangeGuidance struct {
+	fromStation string
+	fromLine    string
+	toStation   string
+	toLine      string
+	text        string
+}
+
+// Represents a run time along a rail link which differs from its all-day
+// transit time during a particular time band ("peak" or "off-peak")
+type BandedRunTime struct {
+	fromStation string
+	toStation   string
+	line        string
+	band        string
+	transitTime uint16
+}
+
+// Represents the average wait for a train on a line running less often than
+// the Underground, in minutes beyond the waits already allowed for in the
+// interchange times
+type LineWait struct {
+	line string
+	wait uint16
+}
+
+// Represents the hours during which a line runs and how often its trains come
+// in each time band, in minutes. Times of day are in minutes after midnight,
+// with last trains after midnight counted on past 24:00 (so 00:30 is 1470).
+type LineService struct {
+	line           string
+	firstTrain     uint16
+	lastTrain      uint16
+	peakHeadway    uint16
+	offPeakHeadway uint16
+}
+
+// Represents the kinds of day on which a line's trains run, either along the
+// whole line or, when stations are listed, only at the line's platforms at
+// those stations. An entry listing stations takes precedence there over one
+// for the whole line.
+type ServiceDays struct {
+	line     string
+	days     DayType
+	stations []string
+}
+
+// Represents the time taken to walk between the ticket gates and the
+// platforms of a line at a station whose platforms are unusually deep or far
+// from the entrance, in minutes
+type PlatformAccess struct {
+	station     string
+	line        string
+	transitTime uint16
+}
+
+// Represents how long trains stand at a station, in minutes beyond the
+// usual stop already allowed for in the run times between stations
+type StationDwell struct {
+	station string
+	dwell   uint16
+}
+
+// Represents the least time in minutes it takes to change from one line to
+// another at a station, from arriving on the first to being able to board
+// the second
+type ConnectionTime struct {
+	station  string
+	fromLine string
+	toLine   string
+	minutes  uint16
+}
+
+// Represents step-free access, by lifts or ramps, between the street and the
+// platforms of a line at a station, along with the extra time in minutes the
+// lifts take over the usual way to and from those platforms
+type StepFreeAccess struct {
+	station  string
+	line     string
+	liftTime uint16
+}
+
+// Represents the platforms of a line at a station where the step or gap
+// between train and platform is too large for a wheelchair, even though the
+// platforms are step-free from the street, so staff put down a manual ramp
+// for passengers getting on or off
+type BoardingRamp struct {
+	station string
+	line    string
+}
+
+// Represents the lifts a step-free way through a station depends on, either
+// between the street and the platforms of a line (with fromLine empty) or
+// between the platforms of two lines, in either direction. The way is only
+// step-free while every one of its lifts is in service.
+type LiftDependency struct {
+	station  string
+	fromLine string
+	toLine   string
+	lifts    []string
+}
+
+// Represents the set of aids for passengers with hearing or visual
+// impairments which a station provides, as a combination of the
+// AccessibilityAids constants
+type AccessibilityAids uint8
+
+const (
+	TactilePaving AccessibilityAids = 1 << iota
+	HearingLoop
+	AudioAnnouncements
+	VisualDisplays
+)
+
+// Represents an amenity passengers may need to break their journey for
+type Facility string
+
+const (
+	FacilityToilet     Facility = "toilet"
+	FacilityBabyChange Facility = "baby-change"
+)
+
+// Represents a facility provided at a station, along with where in the
+// station it is found
+type StationFacility struct {
+	station  string
+	facility Facility
+	location string
+}
+
+// Represents the geographic location of a station, in decimal degrees
+type Coordinates struct {
+	lat float64
+	lon float64
+}
+
+// Represents the fare zone of a station, as a range of zones to allow for
+// stations on the boundary between two zones
+type FareZone struct {
+	low  uint8
+	high uint8
+}
+
+// Represents a pay as you go fare for rail journeys within a range of fare
+// zones, in pence. Fares for ranges including zone 1 depend on the furthest
+// zone reached, and others on the number of zones travelled in.
+type ZoneFare struct {
+	viaZone1 bool
+	zones    uint8
+	peak     uint16
+	offPeak  uint16
+}
+
+// Return list of all rail links in the transit map
+func GetRailLinks() []RailLink {
+	return []RailLink{
+		// BAKERLOO LINE
+		{"Harrow & Wealdstone", "Kenton", "Bakerloo", 3},
+		{"Kenton", "South Kenton", "Bakerloo", 2},
+		{"South Kenton", "North Wembley", "Bakerloo", 2},
+		{"North Wembley", "Wembley Central", "Bakerloo", 2},
+		{"Wembley Central", "Stonebridge Park", "Bakerloo", 3},
+		{"Stonebridge Park", "Harlesden", "Bakerloo", 2},
+		{"Harlesden", "Willesden Junction", "Bakerloo", 2},
+		{"Willesden Junction", "Kensal Green", "Bakerloo", 3},
+		{"Kensal Green", "Queen's Park", "Bakerloo", 4},
+		{"Queen's Park", "Kilburn Park", "Bakerloo", 1},
+		{"Kilburn Park", "Maida Vale", "Bakerloo", 2},
+		{"Maida Vale", "Warwick Avenue", "Bakerloo", 2},
+		{"Warwick Avenue", "Paddington", "Bakerloo", 2},
+		{"Paddington", "Edgware Road", "Bakerloo", 1},
+		{"Edgware Road", "Marylebone", "Bakerloo", 2},
+		{"Marylebone", "Baker Street", "Bakerloo", 1},
+		{"Baker Street", "Regent's Park", "Bakerloo", 2},
+		{"Regent's Park", "Oxford Circus", "Bakerloo", 2},
+		{"Oxford Circus", "Piccadilly Circus", "Bakerloo", 2},
+		{"Piccadilly Circus", "Charing Cross", "Bakerloo", 2},
+		{"Charing Cross", "Embankment", "Bakerloo", 1},
+		{"Embankment", "Waterloo", "Bakerloo", 2},
+		{"Waterloo", "Lambeth North", "Bakerloo", 1},
+		{"Lambeth North", "Elephant & Castle", "Bakerloo", 4},
+
+		// CENTRAL LINE
+		{"West Ruislip", "Ruislip Gardens", "Central", 3},
+		{"Ruislip Gardens", "South Ruislip", "Central", 2},
+		{"South Ruislip", "Northolt", "Central", 3},
+		{"Northolt", "Greenford", "Central", 2},
+		{"Greenford", "Perivale", "Central", 2},
+		{"Perivale", "Hanger Lane", "Central", 2},
+		{"Hanger Lane", "North Acton", "Central", 4},
+		{"North Acton", "East Acton", "Central", 2},
+		{"East Acton", "White City", "Central", 4},
+		{"White City", "Shepherd's Bush", "Central", 3},
+		{"Shepherd's Bush", "Holland Park", "Central", 1},
+		{"Holland Park", "Notting Hill Gate", "Central", 2},
+		{"Notting Hill Gate", "Queensway", "Central", 1},
+		{"Queensway", "Lancaster Gate", "Central", 2},
+		{"Lancaster Gate", "Marble Arch", "Central", 2},
+		{"Marble Arch", "Bond Street", "Central", 2},
+		{"Bond Street", "Oxford Circus", "Central", 1},
+		{"Oxford Circus", "Tottenham Court Road", "Central", 1},
+		{"Tottenham Court Road", "Holborn", "Central", 3},
+		{"Holborn", "Chancery Lane", "Central", 1},
+		{"Chancery Lane", "St. Paul's", "Central", 2},
+		{"St. Paul's", "Bank", "Central", 2},
+		{"Bank", "Liverpool Street", "Central", 2},
+		{"Liverpool Street", "Bethnal Green (Central)", "Central", 3},
+		{"Bethnal Green (Central)", "Mile End", "Central", 3},
+		{"Mile End", "Stratford", "Central", 3},
+		{"Stratford", "Leyton", "Central", 3},
+		{"Leyton", "Leytonstone", "Central", 3},
+		{"Leytonstone", "Wanstead", "Central", 3},
+		{"Wanstead", "Redbridge", "Central", 2},
+		{"Redbridge", "Gants Hill", "Central", 2},
+		{"Gants Hill", "Newbury Park", "Central", 4},
+		{"Newbury Park", "Barkingside", "Central", 1},
+		{"Barkingside", "Fairlop", "Central", 2},
+		{"Fairlop", "Hainault", "Central", 4},
+		{"Hainault", "Grange Hill", "Central", 3},
+		{"Grange Hill", "Chigwell", "Central", 2},
+		{"Chigwell", "Roding Valley", "Central", 3},
+		{"Roding Valley", "Woodford", "Central", 2},
+		{"Ealing Broadway", "West Acton", "Central", 2},
+		{"West Acton", "North Acton", "Central", 3},
+		{"Leytonstone", "Snaresbrook", "Central", 2},
+		{"Snaresbrook", "South Woodford", "Central", 2},
+		{"South Woodford", "Woodford", "Central", 3},
+		{"Woodford", "Buckhurst Hill", "Central", 2},
+		{"Buckhurst Hill", "Loughton", "Central", 3},
+		{"Loughton", "Debden", "Central", 3},
+		{"Debden", "Theydon Bois", "Central", 3},
+		{"Theydon Bois", "Epping", "Central", 3},
+
+		// CIRCLE LINE
+		{"Hammersmith", "Goldhawk Road", "Circle", 4},
+		{"Goldhawk Road", "Shepherd's Bush Market", "Circle", 1},
+		{"Shepherd's Bush Market", "Wood Lane", "Circle", 1},
+		{"Wood Lane", "Latimer Road", "Circle", 2},
+		{"Latimer Road", "Ladbroke Grove", "Circle", 1},
+		{"Ladbroke Grove", "Westbourne Park", "Circle", 2},
+		{"Westbourne Park", "Royal Oak", "Circle", 2},
+		{"Royal Oak", "Paddington", "Circle", 2},
+		{"Paddington", "Edgware Road", "Circle", 3},
+		{"Edgware Road", "Baker Street", "Circle", 3},
+		{"Baker Street", "Great Portland Street", "Circle", 2},
+		{"Great Portland Street", "Euston Square", "Circle", 2},
+		{"Euston Square", "King's Cross St. Pancras", "Circle", 2},
+		{"King's Cross St. Pancras", "Farringdon", "Circle", 3},
+		{"Farringdon", "Barbican", "Circle", 1},
+		{"Barbican", "Moorgate", "Circle", 2},
+		{"Moorgate", "Liverpool Street", "Circle", 2},
+		{"Liverpool Street", "Aldgate", "Circle", 3},
+		{"Aldgate", "Tower Hill", "Circle", 2},
+		{"Tower Hill", "Monument", "Circle", 2},
+		{"Monument", "Cannon Street", "Circle", 1},
+		{"Cannon Street", "Mansion House", "Circle", 1},
+		{"Mansion House", "Blackfriars", "Circle", 2},
+		{"Blackfriars", "Temple", "Circle", 2},
+		{"Temple", "Embankment", "Circle", 1},
+		{"Embankment", "Westminster", "Circle", 2},
+		{"Westminster", "St. James's Park", "Circle", 2},
+		{"St. James's Park", "Victoria", "Circle", 1},
+		{"Victoria", "Sloane Square", "Circle", 2},
+		{"Sloane Square", "South Kensington", "Circle", 3},
+		{"South Kensington", "Gloucester Road", "Circle", 4},
+		{"Gloucester Road", "High Street Kensington", "Circle", 2},
+		{"High Street Kensington", "Notting Hill Gate", "Circle", 2},
+		{"Notting Hill Gate", "Bayswater", "Circle", 2},
+		{"Bayswater", "Paddington", "Circle", 2},
+
+		// DISTRICT LINE
+		{"Ealing Broadway", "Ealing Common", "District", 4},
+		{"Ealing Common", "Acton Town", "District", 4},
+		{"Acton Town", "Chiswick Park", "District", 2},
+		{"Chiswick Park", "Turnham Green", "District", 4},
+		{"Turnham Green", "Stamford Park", "District", 1},
+		{"Stamford Park", "Ravenscourt Park", "District", 2},
+		{"Ravenscourt Park", "Hammersmith", "District", 2},
+		{"Hammersmith", "Barons Court", "District", 2},
+		{"Barons Court", "West Kensington", "District", 1},
+		{"West Kensington", "Earl's Court", "District", 3},
+		{"Earl's Court", "Gloucester Road", "District", 3},
+		{"Gloucester Road", "South Kensington", "District", 1},
+		{"South Kensington", "Sloane Square", "District", 3},
+		{"Sloane Square", "Victoria", "District", 2},
+		{"Victoria", "St. James's Park", "District", 1},
+		{"St. James's Park", "Westminster", "District", 2},
+		{"Westminster", "Embankment", "District", 2},
+		{"Embankment", "Temple", "District", 1},
+		{"Temple", "Blackfriars", "District", 2},
+		{"Blackfriars", "Mansion House", "District", 2},
+		{"Mansion House", "Cannon Street", "District", 1},
+		{"Cannon Street", "Monument", "District", 1},
+		{"Monument", "Tower Hill", "District", 2},
+		{"Tower Hill", "Aldgate East", "District", 2},
+		{"Aldgate East", "Whitechapel", "District", 3},
+		{"Whitechapel", "Stepney Green", "District", 2},
+		{"Stepney Green", "Mile End", "District", 2},
+		{"Mile End", "Bow Road", "District", 1},
+		{"Bow Road", "Bromley-by-Bow", "District", 2},
+		{"Bromley-by-Bow", "West Ham", "District", 3},
+		{"West Ham", "Plaistow", "District", 2},
+		{"Plaistow", "Upton Park", "District", 2},
+		{"Upton Park", "East Ham", "District", 2},
+		{"East Ham", "Barking", "District", 4},
+		{"Barking", "Upney", "District", 2},
+		{"Upney", "Becontree", "District", 2},
+		{"Becontree", "Dagenham Heathway", "District", 2},
+		{"Dagenham Heathway", "Dagenham East", "District", 3},
+		{"Dagenham East", "Elm Park", "District", 3},
+		{"Elm Park", "Hornchurch", "District", 2},
+		{"Hornchurch", "Upminster Bridge", "District", 2},
+		{"Upminster Bridge", "Upminster", "District", 2},
+		{"Richmond", "Kew Gardens", "District", 4},
+		{"Kew Gardens", "Gunnersbury", "District", 3},
+		{"Gunnersbury", "Turnham Green", "District", 5},
+		{"Wimbledon", "Wimbledon Park", "District", 3},
+		{"Wimbledon Park", "Southfields", "District", 3},
+		{"Southfields", "East Putney", "District", 2},
+		{"East Putney", "Putney Bridge", "District", 3},
+		{"Putney Bridge", "Parsons Green", "District", 2},
+		{"Parsons Green", "Fulham Broadway", "District", 1},
+		{"Fulham Broadway", "West Brompton", "District", 2},
+		{"West Brompton", "Earl's Court", "District", 3},
+		{"Earl's Court", "High Street Kensington", "District", 3},
+		{"High Street Kensington", "Notting Hill Gate", "District", 2},
+		{"Notting Hill Gate", "Bayswater", "District", 1},
+		{"Bayswater", "Paddington", "District", 3},
+		{"Paddington", "Edgware Road", "District", 2},
+
+		// DOCKLANDS LIGHT RAILWAY LINE
+		{"Stratford International", "Stratford", "Docklands Light Railway", 2},
+		{"Stratford", "Stratford High Street", "Docklands Light Railway", 1},
+		{"Stratford High Street", "Abbey Road", "Docklands Light Railway", 2},
+		{"Abbey Road", "West Ham", "Docklands Light Railway", 2},
+		{"West Ham", "Star Lane", "Docklands Light Railway", 2},
+		{"Star Lane", "Canning Town", "Docklands Light Railway", 2},
+		{"Canning Town", "West Silvertown", "Docklands Light Railway", 3},
+		{"West Silvertown", "Pontoon Dock", "Docklands Light Railway", 1},
+		{"Pontoon Dock", "London City Airport", "Docklands Light Railway", 2},
+		{"London City Airport", "King George V", "Docklands Light Railway", 2},
+		{"King George V", "Woolwich Arsenal", "Docklands Light Railway", 4},
+		{"Lewisham", "Elverson Road", "Docklands Light Railway", 2},
+		{"Elverson Road", "Deptford Bridge", "Docklands Light Railway", 1},
+		{"Deptford Bridge", "Greenwich", "Docklands Light Railway", 2},
+		{"Greenwich", "Cutty Sark for Maritime Greenwich", "Docklands Light Railway", 2},
+		{"Cutty Sark for Maritime Greenwich", "Island Gardens", "Docklands Light Railway", 1},
+		{"Island Gardens", "Mudchute", "Docklands Light Railway", 2},
+		{"Mudchute", "Crossharbour", "Docklands Light Railway", 1},
+		{"Crossharbour", "South Quay", "Docklands Light Railway", 2},
+		{"South Quay", "Heron Quays", "Docklands Light Railway", 1},
+		{"Heron Quays", "Canary Wharf", "Docklands Light Railway", 2},
+		{"Canary Wharf", "West India Quay", "Docklands Light Railway", 1},
+		{"West India Quay", "Westferry", "Docklands Light Railway", 2},
+		{"Westferry", "Limehouse", "Docklands Light Railway", 2},
+		{"Limehouse", "Shadwell", "Docklands Light Railway", 2},
+		{"Shadwell", "Bank", "Docklands Light Railway", 3},
+		{"Poplar", "Blackwall", "Docklands Light Railway", 3},
+		{"Blackwall", "East India", "Docklands Light Railway", 3},
+		{"East India", "Canning Town", "Docklands Light Railway", 3},
+		{"Canning Town", "Royal Victoria", "Docklands Light Railway", 3},
+		{"Royal Victoria", "Custom House for ExCeL", "Docklands Light Railway", 3},
+		{"Custom House for ExCeL", "Prince Regent", "Docklands Light Railway", 3},
+		{"Prince Regent", "Royal Albert", "Docklands Light Railway", 3},
+		{"Royal Albert", "Beckton Park", "Docklands Light Railway", 3},
+		{"Beckton Park", "Cyprus", "Docklands Light Railway", 3},
+		{"Cyprus", "Gallions Reach", "Docklands Light Railway", 3},
+		{"Gallions Reach", "Beckton", "Docklands Light Railway", 3},
+		{"Stratford", "Pudding Mill Lane", "Docklands Light Railway", 2},
+		{"Pudding Mill Lane", "Bow Church", "Docklands Light Railway", 2},
+		{"Bow Church", "Devons Road", "Docklands Light Railway", 2},
+		{"Devons Road", "Langdon Park", "Docklands Light Railway", 1},
+		{"Langdon Park", "All Saints", "Docklands Light Railway", 2},
+		{"All Saints", "Poplar", "Docklands Light Railway", 1},
+		{"Tower Gateway", "Shadwell", "Docklands Light Railway", 2},
+
+		// ELIZABETH LINE
+		{"Reading", "Twyford", "Elizabeth", 5},
+		{"Twyford", "Maidenhead", "Elizabeth", 7},
+		{"Maidenhead", "Taplow", "Elizabeth", 3},
+		{"Taplow", "Burnham", "Elizabeth", 4},
+		{"Burnham", "Slough", "Elizabeth", 3},
+		{"Slough", "Langley", "Elizabeth", 4},
+		{"Langley", "Iver", "Elizabeth", 2},
+		{"Iver", "West Drayton", "Elizabeth", 3},
+		{"West Drayton", "Hayes & Harlington", "Elizabeth", 3},
+		{"Heathrow Terminal 5", "Heathrow Terminals 2 & 3", "Elizabeth", 4},
+		{"Heathrow Terminals 4", "Heathrow Terminals 2 & 3", "Elizabeth", 4},
+		{"Heathrow Terminals 2 & 3", "Hayes & Harlington", "Elizabeth", 5},
+		{"Hayes & Harlington", "Southall", "Elizabeth", 3},
+		{"Southall", "Hanwell", "Elizabeth", 3},
+		{"Hanwell", "West Ealing", "Elizabeth", 3},
+		{"West Ealing", "Ealing Broadway", "Elizabeth", 3},
+		{"Ealing Broadway", "Acton Main Line", "Elizabeth", 3},
+		{"Acton Main Line", "Paddington", "Elizabeth", 3},
+		{"Paddington", "Bond Street", "Elizabeth", 3},
+		{"Bond Street", "Tottenham Court Road", "Elizabeth", 3},
+		{"Tottenham Court Road", "Farringdon", "Elizabeth", 3},
+		{"Farringdon", "Liverpool Street", "Elizabeth", 3},
+		{"Liverpool Street", "Whitechapel", "Elizabeth", 3},
+		{"Whitechapel", "Canary Wharf", "Elizabeth", 3},
+		{"Canary Wharf", "Custom House for ExCeL", "Elizabeth", 4},
+		{"Custom House for ExCeL", "Woolwich", "Elizabeth", 4},
+		{"Woolwich", "Abbey Wood", "Elizabeth", 5},
+		{"Whitechapel", "Stratford", "Elizabeth", 6},
+		{"Stratford", "Maryland", "Elizabeth", 2},
+		{"Maryland", "Forest Gate", "Elizabeth", 2},
+		{"Forest Gate", "Manor Park", "Elizabeth", 2},
+		{"Manor Park", "Ilford", "Elizabeth", 3},
+		{"Ilford", "Seven Kings", "Elizabeth", 3},
+		{"Seven Kings", "Goodmayes", "Elizabeth", 2},
+		{"Goodmayes", "Chadwell Heath", "Elizabeth", 2},
+		{"Chadwell Heath", "Romford", "Elizabeth", 4},
+		{"Romford", "Gidea Park", "Elizabeth", 3},
+		{"Gidea Park", "Harold Wood", "Elizabeth", 3},
+		{"Harold Wood", "Brentwood", "Elizabeth", 4},
+		{"Brentwood", "Shenfield", "Elizabeth", 6},
+
+		// HAMMERSMITH & CITY LINE
+		{"Hammersmith", "Goldhawk Road", "Hammersmith & City", 4},
+		{"Goldhawk Road", "Shepherd's Bush Market", "Hammersmith & City", 1},
+		{"Shepherd's Bush Market", "Wood Lane", "Hammersmith & City", 1},
+		{"Wood Lane", "Latimer Road", "Hammersmith & City", 2},
+		{"Latimer Road", "Ladbroke Grove", "Hammersmith & City", 1},
+		{"Ladbroke Grove", "Westbourne Park", "Hammersmith & City", 2},
+		{"Westbourne Park", "Royal Oak", "Hammersmith & City", 2},
+		{"Royal Oak", "Paddington", "Hammersmith & City", 2},
+		{"Paddington", "Edgware Road", "Hammersmith & City", 3},
+		{"Edgware Road", "Baker Street", "Hammersmith & City", 3},
+		{"Baker Street", "Great Portland Street", "Hammersmith & City", 2},
+		{"Great Portland Street", "Euston Square", "Hammersmith & City", 2},
+		{"Euston Square", "King's Cross St. Pancras", "Hammersmith & City", 2},
+		{"King's Cross St. Pancras", "Farringdon", "Hammersmith & City", 3},
+		{"Farringdon", "Barbican", "Hammersmith & City", 1},
+		{"Barbican", "Moorgate", "Hammersmith & City", 2},
+		{"Moorgate", "Liverpool Street", "Hammersmith & City", 2},
+		{"Liverpool Street", "Aldgate East", "Hammersmith & City", 6},
+		{"Aldgate East", "Whitechapel", "Hammersmith & City", 2},
+		{"Whitechapel", "Stepney Green", "Hammersmith & City", 2},
+		{"Stepney Green", "Mile End", "Hammersmith & City", 2},
+		{"Mile End", "Bow Road", "Hammersmith & City", 2},
+		{"Bow Road", "Bromley-by-Bow", "Hammersmith & City", 2},
+		{"Bromley-by-Bow", "West Ham", "Hammersmith & City", 3},
+		{"West Ham", "Plaistow", "Hammersmith & City", 2},
+		{"Plaistow", "Upton Park", "Hammersmith & City", 2},
+		{"Upton Park", "East Ham", "Hammersmith & City", 2},
+		{"East Ham", "Barking", "Hammersmith & City", 4},
+
+		// JUBILEE LINE
+		{"Stanmore", "Canons Park", "Jubilee", 4},
+		{"Canons Park", "Queensbury", "Jubilee", 3},
+		{"Queensbury", "Kingsbury", "Jubilee", 2},
+		{"Kingsbury", "Wembley Park", "Jubilee", 4},
+		{"Wembley Park", "Neasden", "Jubilee", 3},
+		{"Neasden", "Dollis Hill", "Jubilee", 2},
+		{"Dollis Hill", "Willesden Green", "Jubilee", 2},
+		{"Willesden Green", "Kilburn", "Jubilee", 2},
+		{"Kilburn", "West Hampstead", "Jubilee", 2},
+		{"West Hampstead", "Finchley Road", "Jubilee", 1},
+		{"Finchley Road", "Swiss Cottage", "Jubilee", 2},
+		{"Swiss Cottage", "St. John's Wood", "Jubilee", 1},
+		{"St. John's Wood", "Baker Street", "Jubilee", 3},
+		{"Baker Street", "Bond Street", "Jubilee", 2},
+		{"Bond Street", "Green Park", "Jubilee", 2},
+		{"Green Park", "Westminster", "Jubilee", 2},
+		{"Westminster", "Waterloo", "Jubilee", 2},
+		{"Waterloo", "Southwark", "Jubilee", 1},
+		{"Southwark", "London Bridge", "Jubilee", 2},
+		{"London Bridge", "Bermondsey", "Jubilee", 2},
+		{"Bermondsey", "Canada Water", "Jubilee", 2},
+		{"Canada Water", "Canary Wharf", "Jubilee", 2},
+		{"Canary Wharf", "North Greenwich", "Jubilee", 3},
+		{"North Greenwich", "Canning Town", "Jubilee", 2},
+		{"Canning Town", "West Ham", "Jubilee", 3},
+		{"West Ham", "Stratford", "Jubilee", 3},
+
+		// METROPOLITAN LINE
+		{"Uxbridge", "Hillingdon", "Metropolitan", 3},
+		{"Hillingdon", "Ickenham", "Metropolitan", 2},
+		{"Ickenham", "Ruislip", "Metropolitan", 3},
+		{"Ruislip", "Ruislip Manor", "Metropolitan", 1},
+		{"Ruislip Manor", "Eastcote", "Metropolitan", 2},
+		{"Eastcote", "Rayners Lane", "Metropolitan", 4},
+		{"Rayners Lane", "West Harrow", "Metropolitan", 2},
+		{"West Harrow", "Harrow-on-the-Hill", "Metropolitan", 2},
+		{"Harrow-on-the-Hill", "Northwick Park", "Metropolitan", 3},
+		{"Northwick Park", "Preston Road", "Metropolitan", 2},
+		{"Preston Road", "Wembley Park", "Metropolitan", 3},
+		{"Wembley Park", "Finchley Road", "Metropolitan", 7},
+		{"Finchley Road", "Baker Street", "Metropolitan", 6},
+		{"Baker Street", "Great Portland Street", "Metropolitan", 2},
+		{"Great Portland Street", "Euston Square", "Metropolitan", 2},
+		{"Euston Square", "King's Cross St. Pancras", "Metropolitan", 2},
+		{"King's Cross St. Pancras", "Farringdon", "Metropolitan", 3},
+		{"Farringdon", "Barbican", "Metropolitan", 1},
+		{"Barbican", "Moorgate", "Metropolitan", 2},
+		{"Moorgate", "Liverpool Street", "Metropolitan", 2},
+		{"Liverpool Street", "Aldgate", "Metropolitan", 3},
+		{"Amersham", "Chalfont & Latimer", "Metropolitan", 4},
+		{"Chalfont & Latimer", "Chorleywood", "Metropolitan", 4},
+		{"Chorleywood", "Rickmansworth", "Metropolitan", 4},
+		{"Rickmansworth", "Moor Park", "Metropolitan", 4},
+		{"Moor Park", "Northwood", "Metropolitan", 3},
+		{"Northwood", "Northwood Hills", "Metropolitan", 2},
+		{"Northwood Hills", "Pinner", "Metropolitan", 3},
+		{"Pinner", "North Harrow", "Metropolitan", 2},
+		{"North Harrow", "Harrow-on-the-Hill", "Metropolitan", 3},
+		{"Chesham", "Chalfont & Latimer", "Metropolitan", 8},
+		{"Watford", "Croxley", "Metropolitan", 4},
+		{"Croxley", "Moor Park", "Metropolitan", 4},
+
+		// NORTHERN LINE
+		{"Morden", "South Wimbledon", "Northern", 3},
+		{"South Wimbledon", "Colliers Wood", "Northern", 2},
+		{"Colliers Wood", "Tooting Broadway", "Northern", 2},
+		{"Tooting Broadway", "Tooting Bec", "Northern", 2},
+		{"Tooting Bec", "Balham", "Northern", 1},
+		{"Balham", "Clapham South", "Northern", 2},
+		{"Clapham South", "Clapham Common", "Northern", 2},
+		{"Clapham Common", "Clapham North", "Northern", 2},
+		{"Clapham North", "Stockwell", "Northern", 1},
+		{"Stockwell", "Oval", "Northern", 2},
+		{"Oval", "Kennington", "Northern", 3},
+		{"Kennington", "Elephant & Castle", "Northern", 2},
+		{"Elephant & Castle", "Borough", "Northern", 2},
+		{"Borough", "London Bridge", "Northern", 1},
+		{"London Bridge", "Bank", "Northern", 2},
+		{"Bank", "Moorgate", "Northern", 2},
+		{"Moorgate", "Old Street", "Northern", 2},
+		{"Old Street", "Angel", "Northern", 2},
+		{"Angel", "King's Cross St. Pancras", "Northern", 2},
+		{"King's Cross St. Pancras", "Euston", "Northern", 2},
+		{"Euston", "Camden Town", "Northern", 4},
+		{"Camden Town", "Kentish Town", "Northern", 2},
+		{"Kentish Town", "Tufnell Park", "Northern", 1},
+		{"Tufnell Park", "Archway", "Northern", 2},
+		{"Archway", "Highgate", "Northern", 2},
+		{"Highgate", "East Finchley", "Northern", 3},
+		{"East Finchley", "Finchley Central", "Northern", 4},
+		{"Finchley Central", "West Finchley", "Northern", 3},
+		{"West Finchley", "Woodside Park", "Northern", 1},
+		{"Woodside Park", "Totteridge & Whetstone", "Northern", 3},
+		{"Totteridge & Whetstone", "High Barnet", "Northern", 3},
+		{"Finchley Central", "Mill Hill East", "Northern", 2},
+		{"Battersea Power Station", "Nine Elms", "Northern", 3},
+		{"Nine Elms", "Kennington", "Northern", 3},
+		{"Kennington", "Waterloo", "Northern", 2},
+		{"Waterloo", "Embankment", "Northern", 2},
+		{"Embankment", "Charing Cross", "Northern", 1},
+		{"Charing Cross", "Leicester Square", "Northern", 1},
+		{"Leicester Square", "Tottenham Court Road", "Northern", 1},
+		{"Tottenham Court Road", "Goodge Street", "Northern", 2},
+		{"Goodge Street", "Warren Street", "Northern", 1},
+		{"Warren Street", "Euston", "Northern", 2},
+		{"Euston", "Mornington Crescent", "Northern", 2},
+		{"Mornington Crescent", "Camden Town", "Northern", 2},
+		{"Camden Town", "Chalk Farm", "Northern", 2},
+		{"Chalk Farm", "Belsize Park", "Northern", 2},
+		{"Belsize Park", "Hampstead", "Northern", 2},
+		{"Hampstead", "Golders Green", "Northern", 3},
+		{"Golders Green", "Brent Cross", "Northern", 2},
+		{"Brent Cross", "Hendon Central", "Northern", 2},
+		{"Hendon Central", "Colindale", "Northern", 3},
+		{"Colindale", "Burnt Oak", "Northern", 2},
+		{"Burnt Oak", "Edgware", "Northern", 2},
+
+		// OVERGROUND LINE
+		{"Watford Junction", "Watford High Street", "Overground", 3},
+		{"Watford High Street", "Bushey", "Overground", 2},
+		{"Bushey", "Carpenders Park", "Overground", 3},
+		{"Carpenders Park", "Hatch End", "Overground", 3},
+		{"Hatch End", "Headstone Lane", "Overground", 2},
+		{"Headstone Lane", "Harrow & Wealdstone", "Overground", 3},
+		{"Harrow & Wealdstone", "Kenton", "Overground", 4},
+		{"Kenton", "South Kenton", "Overground", 2},
+		{"South Kenton", "North Wembley", "Overground", 2},
+		{"North Wembley", "Wembley Central", "Overground", 2},
+		{"Wembley Central", "Stonebridge Park", "Overground", 3},
+		{"Stonebridge Park", "Harlesden", "Overground", 2},
+		{"Harlesden", "Willesden Junction", "Overground", 3},
+		{"Willesden Junction", "Kensal Green", "Overground", 2},
+		{"Kensal Green", "Queen's Park", "Overground", 3},
+		{"Queen's Park", "Kilburn High Road", "Overground", 2},
+		{"Kilburn High Road", "South Hampstead", "Overground", 2},
+		{"South Hampstead", "Euston", "Overground", 9},
+		{"Gospel Oak", "Upper Holloway", "Overground", 4},
+		{"Upper Holloway", "Crouch Hill", "Overground", 2},
+		{"Crouch Hill", "Harringay Green Lanes", "Overground", 3},
+		{"Harringay Green Lanes", "South Tottenham", "Overground", 4},
+		{"South Tottenham", "Blackhorse Road", "Overground", 3},
+		{"Blackhorse Road", "Walthamstow Queen's Road", "Overground", 2},
+		{"Walthamstow Queen's Road", "Leyton Midland Road", "Overground", 3},
+		{"Leyton Midland Road", "Leytonstone High Road", "Overground", 2},
+		{"Leytonstone High Road", "Wanstead Park", "Overground", 3},
+		{"Wanstead Park", "Woodgrange Park", "Overground", 3},
+		{"Woodgrange Park", "Barking", "Overground", 5},
+		{"Barking", "Barking Riverside", "Overground", 7},
+		{"Romford", "Emerson Park", "Overground", 5},
+		{"Emerson Park", "Upminster", "Overground", 4},
+		{"Clapham Junction", "Imperial Wharf", "Overground", 4},
+		{"Imperial Wharf", "West Brompton", "Overground", 3},
+		{"West Brompton", "Kensington (Olympia)", "Overground", 3},
+		{"Kensington (Olympia)", "Shepherd's Bush", "Overground", 3},
+		{"Shepherd's Bush", "Willesden Junction", "Overground", 7},
+		{"Richmond", "Kew Gardens", "Overground", 3},
+		{"Kew Gardens", "Gunnersbury", "Overground", 3},
+		{"Gunnersbury", "South Acton", "Overground", 3},
+		{"South Acton", "Acton Central", "Overground", 3},
+		{"Acton Central", "Willesden Junction", "Overground", 5},
+		{"Willesden Junction", "Kensal Rise", "Overground", 3},
+		{"Kensal Rise", "Brondesbury Park", "Overground", 2},
+		{"Brondesbury Park", "Brondesbury", "Overground", 1},
+		{"Brondesbury", "West Hampstead", "Overground", 2},
+		{"West Hampstead", "Finchley Road & Frognal", "Overground", 2},
+		{"Finchley Road & Frognal", "Hampstead Heath", "Overground", 2},
+		{"Hampstead Heath", "Gospel Oak", "Overground", 3},
+		{"Gospel Oak", "Kentish Town West", "Overground", 2},
+		{"Kentish Town West", "Camden Road", "Overground", 3},
+		{"Camden Road", "Caledonian Road & Barnsbury", "Overground", 3},
+		{"Caledonian Road & Barnsbury", "Highbury & Islington", "Overground", 3},
+		{"Highbury & Islington", "Canonbury", "Overground", 2},
+		{"Canonbury", "Dalston Kingsland", "Overground", 3},
+		{"Dalston Kingsland", "Hackney Central", "Overground", 2},
+		{"Hackney Central", "Homerton", "Overground", 3},
+		{"Homerton", "Hackney Wick", "Overground", 2},
+		{"Hackney Wick", "Stratford", "Overground", 6},
+		{"Liverpool Street", "Bethnal Green (Overground)", "Overground", 3},
+		{"Bethnal Green (Overground)", "Cambridge Heath", "Overground", 2},
+		{"Cambridge Heath", "London Fields", "Overground", 2},
+		{"London Fields", "Hackney Downs", "Overground", 2},
+		{"Bethnal Green (Overground)", "Hackney Downs", "Overground", 4},
+		{"Hackney Downs", "Clapton", "Overground", 3},
+		{"Clapton", "St. James Street", "Overground", 3},
+		{"St. James Street", "Walthamstow Central", "Overground", 2},
+		{"Walthamstow Central", "Wood Street", "Overground", 3},
+		{"Wood Street", "Highams Park", "Overground", 3},
+		{"Highams Park", "Chingford", "Overground", 6},
+		{"Hackney Downs", "Rectory Road", "Overground", 3},
+		{"Rectory Road", "Stoke Newington", "Overground", 1},
+		{"Stoke Newington", "Stamford Hill", "Overground", 2},
+		{"Stamford Hill", "Seven Sisters", "Overground", 3},
+		{"Seven Sisters", "Bruce Grove", "Overground", 2},
+		{"Bruce Grove", "White Hart Lane", "Overground", 2},
+		{"White Hart Lane", "Silver Street", "Overground", 2},
+		{"Silver Street", "Edmonton Green", "Overground", 2},
+		{"Edmonton Green", "Bush Hill Park", "Overground", 2},
+		{"Bush Hill Park", "Enfield Town", "Overground", 5},
+		{"Edmonton Green", "Southbury", "Overground", 3},
+		{"Southbury", "Turkey Street", "Overground", 3},
+		{"Turkey Street", "Theobalds Grove", "Overground", 2},
+		{"Theobalds Grove", "Cheshunt", "Overground", 5},
+		{"Highbury & Islington", "Canonbury", "Overground", 2},
+		{"Canonbury", "Dalston Junction", "Overground", 3},
+		{"Dalston Junction", "Haggerston", "Overground", 2},
+		{"Haggerston", "Hoxton", "Overground", 2},
+		{"Hoxton", "Shoreditch High Street", "Overground", 3},
+		{"Shoreditch High Street", "Whitechapel", "Overground", 2},
+		{"Whitechapel", "Shadwell", "Overground", 2},
+		{"Shadwell", "Wapping", "Overground", 2},
+		{"Wapping", "Rotherhithe", "Overground", 2},
+		{"Rotherhithe", "Canada Water", "Overground", 2},
+		{"Canada Water", "Surrey Quays", "Overground", 2},
+		{"Surrey Quays", "New Cross", "Overground", 4},
+		{"Surrey Quays", "Queens Road Peckham", "Overground", 7},
+		{"Queens Road Peckham", "Peckham Rye", "Overground", 4},
+		{"Peckham Rye", "Denmark Hill", "Overground", 4},
+		{"Denmark Hill", "Clapham High Street", "Overground", 4},
+		{"Clapham High Street", "Wandsworth Road", "Overground", 2},
+		{"Wandsworth Road", "Clapham Junction", "Overground", 8},
+		{"Surrey Quays", "New Cross Gate", "Overground", 5},
+		{"New Cross Gate", "Brockley", "Overground", 3},
+		{"Brockley", "Honor Oak Park", "Overground", 2},
+		{"Honor Oak Park", "Forest Hill", "Overground", 3},
+		{"Forest Hill", "Sydenham", "Overground", 3},
+		{"Sydenham", "Crystal Palace", "Overground", 5},
+		{"Sydenham", "Penge West", "Overground", 2},
+		{"Penge West", "Anerley", "Overground", 2},
+		{"Anerley", "Norwood Junction", "Overground", 3},
+		{"Norwood Junction", "West Croydon", "Overground", 10},
+
+		// PICCADILLY LINE
+		{"Uxbridge", "Hillingdon", "Piccadilly", 3},
+		{"Hillingdon", "Ickenham", "Piccadilly", 2},
+		{"Ickenham", "Ruislip", "Piccadilly", 3},
+		{"Ruislip", "Ruislip Manor", "Piccadilly", 1},
+		{"Ruislip Manor", "Eastcote", "Piccadilly", 2},
+		{"Eastcote", "Rayners Lane", "Piccadilly", 4},
+		{"Rayners Lane", "South Harrow", "Piccadilly", 3},
+		{"South Harrow", "Sudbury Hill", "Piccadilly", 2},
+		{"Sudbury Hill", "Sudbury Town", "Piccadilly", 2},
+		{"Sudbury Town", "Alperton", "Piccadilly", 3},
+		{"Alperton", "Park Royal", "Piccadilly", 3},
+		{"Park Royal", "North Ealing", "Piccadilly", 3},
+		{"North Ealing", "Ealing Common", "Piccadilly", 2},
+		{"Ealing Common", "Acton Town", "Piccadilly", 2},
+		{"Acton Town", "Turnham Green", "Piccadilly", 3},
+		{"Turnham Green", "Hammersmith", "Piccadilly", 3},
+		{"Hammersmith", "Barons Court", "Piccadilly", 2},
+		{"Barons Court", "Earl's Court", "Piccadilly", 3},
+		{"Earl's Court", "Gloucester Road", "Piccadilly", 2},
+		{"Gloucester Road", "South Kensington", "Piccadilly", 1},
+		{"South Kensington", "Knightsbridge", "Piccadilly", 3},
+		{"Knightsbridge", "Hyde Park Corner", "Piccadilly", 2},
+		{"Hyde Park Corner", "Green Park", "Piccadilly", 2},
+		{"Green Park", "Piccadilly Circus", "Piccadilly", 2},
+		{"Piccadilly Circus", "Leicester Square", "Piccadilly", 1},
+		{"Leicester Square", "Covent Garden", "Piccadilly", 1},
+		{"Covent Garden", "Holborn", "Piccadilly", 2},
+		{"Holborn", "Russell Square", "Piccadilly", 2},
+		{"Russell Square", "King's Cross St. Pancras", "Piccadilly", 2},
+		{"King's Cross St. Pancras", "Caledonian Road", "Piccadilly", 3},
+		{"Caledonian Road", "Holloway Road", "Piccadilly", 3},
+		{"Holloway Road", "Arsenal", "Piccadilly", 1},
+		{"Arsenal", "Finsbury Park", "Piccadilly", 2},
+		{"Finsbury Park", "Manor House", "Piccadilly", 2},
+		{"Manor House", "Turnpike Lane", "Piccadilly", 3},
+		{"Turnpike Lane", "Wood Green", "Piccadilly", 2},
+		{"Wood Green", "Bounds Green", "Piccadilly", 3},
+		{"Bounds Green", "Arnos Grove", "Piccadilly", 4},
+		{"Arnos Grove", "Southgate", "Piccadilly", 2},
+		{"Southgate", "Oakwood", "Piccadilly", 3},
+		{"Oakwood", "Cockfosters", "Piccadilly", 3},
+		{"Heathrow Terminal 5", "Heathrow Terminals 2 & 3", "Piccadilly", 4},
+		{"Heathrow Terminals 2 & 3", "Hatton Cross", "Piccadilly", 3},
+		{"Hatton Cross", "Heathrow Terminal 4", "Piccadilly", 4},
+		{"Heathrow Terminal 4", "Heathrow Terminals 2 & 3", "Piccadilly", 6},
+		{"Hatton Cross", "Hounslow West", "Piccadilly", 4},
+		{"Hounslow West", "Hounslow Central", "Piccadilly", 2},
+		{"Hounslow Central", "Hounslow East", "Piccadilly", 2},
+		{"Hounslow East", "Osterley", "Piccadilly", 2},
+		{"Osterley", "Boston Manor", "Piccadilly", 3},
+		{"Boston Manor", "Northfields", "Piccadilly", 3},
+		{"Northfields", "South Ealing", "Piccadilly", 1},
+		{"South Ealing", "Acton Town", "Piccadilly", 4},
+
+		// TRAMLINK LINE
+		{"Wimbledon", "Dundonald Road", "Tramlink", 1},
+		{"Dundonald Road", "Merton Park", "Tramlink", 2},
+		{"Merton Park", "Morden Road", "Tramlink", 1},
+		{"Morden Road", "Phipps Bridge", "Tramlink", 2},
+		{"Phipps Bridge", "Belgrave Walk", "Tramlink", 1},
+		{"Belgrave Walk", "Mitcham", "Tramlink", 2},
+		{"Mitcham", "Mitcham Junction", "Tramlink", 3},
+		{"Mitcham Junction", "Beddington Lane", "Tramlink", 2},
+		{"Beddington Lane", "Therapia Lane", "Tramlink", 2},
+		{"Therapia Lane", "Ampere Way", "Tramlink", 1},
+		{"Ampere Way", "Waddon Marsh", "Tramlink", 2},
+		{"Waddon Marsh", "Wandle Park", "Tramlink", 1},
+		{"Wandle Park", "Reeves Corner", "Tramlink", 2},
+		{"Reeves Corner", "Centrale", "Tramlink", 2},
+		{"Centrale", "West Croydon", "Tramlink", 2},
+		{"West Croydon", "Wellesley Road", "Tramlink", 3},
+		{"Wellesley Road", "East Croydon", "Tramlink", 2},
+		{"East Croydon", "Lebanon Road", "Tramlink", 2},
+		{"Lebanon Road", "Sandilands", "Tramlink", 2},
+		{"Sandilands", "Addiscombe", "Tramlink", 3},
+		{"Addiscombe", "Blackhorse Lane", "Tramlink", 1},
+		{"Blackhorse Lane", "Woodside", "Tramlink", 1},
+		{"Woodside", "Arena", "Tramlink", 2},
+		{"Arena", "Harrington Road", "Tramlink", 2},
+		{"Harrington Road", "Birkbeck", "Tramlink", 3},
+		{"Birkbeck", "Avenue Road", "Tramlink", 1},
+		{"Avenue Road", "Beckenham Road", "Tramlink", 1},
+		{"Beckenham Road", "Beckenham Junction", "Tramlink", 3},
+		{"Elmers End", "Arena", "Tramlink", 2},
+		{"New Addington", "King Henry's Drive", "Tramlink", 1},
+		{"King Henry's Drive", "Fieldway", "Tramlink", 2},
+		{"Fieldway", "Addington Village", "Tramlink", 2},
+		{"Addington Village", "Gravel Hill", "Tramlink", 2},
+		{"Gravel Hill", "Coombe Lane", "Tramlink", 3},
+		{"Coombe Lane", "Lloyd Park", "Tramlink", 3},
+		{"Lloyd Park", "Sandilands", "Tramlink", 4},
+		{"East Croydon", "George Street", "Tramlink", 2},
+		{"George Street", "Church Street", "Tramlink", 1},
+		{"Church Street", "Centrale", "Tramlink", 1},
+
+		// VICTORIA LINE
+		{"Brixton", "Stockwell", "Victoria", 3},
+		{"Stockwell", "Vauxhall", "Victoria", 2},
+		{"Vauxhall", "Pimlico", "Victoria", 1},
+		{"Pimlico", "Victoria", "Victoria", 2},
+		{"Victoria", "Green Park", "Victoria", 2},
+		{"Green Park", "Oxford Circus", "Victoria", 2},
+		{"Oxford Circus", "Warren Street", "Victoria", 2},
+		{"Warren Street", "Euston", "Victoria", 1},
+		{"Euston", "King's Cross St. Pancras", "Victoria", 2},
+		{"King's Cross St. Pancras", "Highbury & Islington", "Victoria", 3},
+		{"Highbury & Islington", "Finsbury Park", "Victoria", 2},
+		{"Finsbury Park", "Seven Sisters", "Victoria", 2},
+		{"Seven Sisters", "Tottenham Hale", "Victoria", 2},
+		{"Tottenham Hale", "Blackhorse Road", "Victoria", 2},
+		{"Blackhorse Road", "Walthamstow Central", "Victoria", 1},
+
+		// WATERLOO & CITY LINE
+		{"Waterloo", "Bank", "Waterloo & City", 5},
+	}
+}
+
+// Return list of all interchanges in the transit map
+func GetInterchanges() []Interchange {
+	return []Interchange{
+		// INTERCHANGES FROM BAKERLOO LINE
+		{"Baker Street", "Bakerloo", "Baker Street", "Circle", 4},
+		{"Baker Street", "Bakerloo", "Baker Street", "Hammersmith & City", 4},
+		{"Baker Street", "Bakerloo", "Baker Street", "Jubilee", 4},
+		{"Baker Street", "Bakerloo", "Baker Street", "Metropolitan", 4},
+		{"Charing Cross", "Bakerloo", "Charing Cross", "Northern", 3},
+		{"Elephant & Castle", "Bakerloo", "Elephant & Castle", "Northern", 4},
+		{"Embankment", "Bakerloo", "Embankment", "Circle", 4},
+		{"Embankment", "Bakerloo", "Embankment", "District", 4},
+		{"Embankment", "Bakerloo", "Embankment", "Northern", 4},
+		{"Harrow & Wealdstone", "Bakerloo", "Harrow & Wealdstone", "Overground", 4},
+		{"Kenton", "Bakerloo", "Kenton", "Overground", 4},
+		{"Kenton", "Bakerloo", "Northwick Park", "Metropolitan", 8},
+		{"Oxford Circus", "Bakerloo", "Oxford Circus", "Central", 3},
+		{"Oxford Circus", "Bakerloo", "Oxford Circus", "Victoria", 3},
+		{"Paddington", "Bakerloo", "Paddington", "Circle", 4},
+		{"Paddington", "Bakerloo", "Paddington", "District", 4},
+		{"Paddington", "Bakerloo", "Paddington", "Elizabeth", 6},
+		{"Paddington", "Bakerloo", "Paddington", "Hammersmith & City", 4},
+		{"Piccadilly Circus", "Bakerloo", "Piccadilly Circus", "Piccadilly", 3},
+		{"Queen's Park", "Bakerloo", "Queen's Park", "Overground", 4},
+		{"Waterloo", "Bakerloo", "Waterloo", "Jubilee", 4},
+		{"Waterloo", "Bakerloo", "Waterloo", "Northern", 4},
+		{"Waterloo", "Bakerloo", "Waterloo", "Waterloo & City", 4},
+		{"Wembley Central", "Bakerloo", "Wembley Central", "Overground", 3},
+		{"Willesden Junction", "Bakerloo", "Willesden Junction", "Overground", 4},
+
+		// INTERCHANGES FROM CENTRAL LINE
+		{"Bank", "Central", "Bank", "Docklands Light Railway", 6},
+		{"Bank", "Central", "Bank", "Northern", 4},
+		{"Bank", "Central", "Bank", "Waterloo & City", 4},
+		{"Bank", "Central", "Monument", "Circle", 5},
+		{"Bank", "Central", "Monument", "District", 5},
+		{"Bond Street", "Central", "Bond Street", "Elizabeth", 5},
+		{"Bond Street", "Central", "Bond Street", "Jubilee", 4},
+		{"Ealing Broadway", "Central", "Ealing Broadway", "District", 4},
+		{"Ealing Broadway", "Central", "Ealing Broadway", "Elizabeth", 5},
+		{"Hanger Lane", "Central", "Park Royal", "Piccadilly", 11},
+		{"Holborn", "Central", "Holborn", "Piccadilly", 4},
+		{"Leytonstone", "Central", "Leytonstone High Road", "Overground", 14},
+		{"Liverpool Street", "Central", "Liverpool Street", "Circle", 4},
+		{"Liverpool Street", "Central", "Liverpool Street", "Elizabeth", 6},
+		{"Liverpool Street", "Central", "Liverpool Street", "Hammersmith & City", 4},
+		{"Liverpool Street", "Central", "Liverpool Street", "Metropolitan", 4},
+		{"Mile End", "Central", "Mile End", "District", 4},
+		{"Mile End", "Central", "Mile End", "Hammersmith & City", 4},
+		{"Notting Hill Gate", "Central", "Notting Hill Gate", "Circle", 4},
+		{"Notting Hill Gate", "Central", "Notting Hill Gate", "District", 4},
+		{"Oxford Circus", "Central", "Oxford Circus", "Victoria", 4},
+		{"Shepherd's Bush", "Central", "Shepherd's Bush", "Overground", 5},
+		{"Stratford", "Central", "Stratford", "Docklands Light Railway", 5},
+		{"Stratford", "Central", "Stratford", "Elizabeth", 5},
+		{"Stratford", "Central", "Stratford", "Jubilee", 4},
+		{"Stratford", "Central", "Stratford", "Overground", 6},
+		{"Tottenham Court Road", "Central", "Tottenham Court Road", "Elizabeth", 5},
+		{"Tottenham Court Road", "Central", "Tottenham Court Road", "Northern", 4},
+		{"West Ruislip", "Central", "Ickenham", "Metropolitan", 15},
+		{"West Ruislip", "Central", "Ickenham", "Piccadilly", 15},
+		{"White City", "Central", "Wood Lane", "Circle", 4},
+		{"White City", "Central", "Wood Lane", "Hammersmith & City", 4},
+
+		// INTERCHANGES FROM CIRCLE LINE */
+		{"Aldgate", "Circle", "Aldgate", "Metropolitan", 4},
+		{"Aldgate", "Circle", "Tower Gateway", "Docklands Light Railway", 10},
+		{"Baker Street", "Circle", "Baker Street", "Hammersmith & City", 3},
+		{"Baker Street", "Circle", "Baker Street", "Jubilee", 4},
+		{"Baker Street", "Circle", "Baker Street", "Metropolitan", 4},
+		{"Barbican", "Circle", "Barbican", "Hammersmith & City", 3},
+		{"Barbican", "Circle", "Barbican", "Metropolitan", 3},
+		{"Barbican", "Circle", "Farringdon", "Elizabeth", 5},
+		{"Blackfriars", "Circle", "Blackfriars", "District", 4},
+		{"Cannon Street", "Circle", "Cannon Street", "District", 4},
+		{"Edgware Road", "Circle", "Edgware Road", "District", 4},
+		{"Edgware Road", "Circle", "Edgware Road", "Hammersmith & City", 3},
+		{"Embankment", "Circle", "Embankment", "District", 4},
+		{"Embankment", "Circle", "Embankment", "Northern", 4},
+		{"Euston Square", "Circle", "Euston Square", "Hammersmith & City", 3},
+		{"Euston Square", "Circle", "Euston Square", "Metropolitan", 3},
+		{"Euston Square", "Circle", "Euston", "Northern", 6},
+		{"Euston Square", "Circle", "Euston", "Overground", 7},
+		{"Euston Square", "Circle", "Euston", "Victoria", 6},
+		{"Euston Square", "Circle", "Warren Street", "Northern", 9},
+		{"Euston Square", "Circle", "Warren Street", "Victoria", 9},
+		{"Farringdon", "Circle", "Farringdon", "Elizabeth", 5},
+		{"Farringdon", "Circle", "Farringdon", "Hammersmith & City", 3},
+		{"Farringdon", "Circle", "Farringdon", "Metropolitan", 3},
+		{"Gloucester Road", "Circle", "Gloucester Road", "District", 4},
+		{"Gloucester Road", "Circle", "Gloucester Road", "Piccadilly", 4},
+		{"Hammersmith", "Circle", "Hammersmith", "District", 4},
+		{"Hammersmith", "Circle", "Hammersmith", "Hammersmith & City", 3},
+		{"Hammersmith", "Circle", "Hammersmith", "Piccadilly", 4},
+		{"King's Cross St. Pancras", "Circle", "King's Cross St. Pancras", "Hammersmith & City", 3},
+		{"King's Cross St. Pancras", "Circle", "King's Cross St. Pancras", "Metropolitan", 3},
+		{"King's Cross St. Pancras", "Circle", "King's Cross St. Pancras", "Northern", 4},
+		{"King's Cross St. Pancras", "Circle", "King's Cross St. Pancras", "Piccadilly", 4},
+		{"King's Cross St. Pancras", "Circle", "King's Cross St. Pancras", "Victoria", 4},
+		{"Liverpool Street", "Circle", "Liverpool Street", "Elizabeth", 7},
+		{"Liverpool Street", "Circle", "Liverpool Street", "Hammersmith & City", 4},
+		{"Liverpool Street", "Circle", "Liverpool Street", "Metropolitan", 4},
+		{"Monument", "Circle", "Bank", "Docklands Light Railway", 6},
+		{"Monument", "Circle", "Bank", "Northern", 5},
+		{"Monument", "Circle", "Bank", "Waterloo & City", 5},
+		{"Monument", "Circle", "Monument", "District", 4},
+		{"Moorgate", "Circle", "Liverpool Street", "Elizabeth", 10},
+		{"Moorgate", "Circle", "Moorgate", "Hammersmith & City", 4},
+		{"Moorgate", "Circle", "Moorgate", "Metropolitan", 4},
+		{"Moorgate", "Circle", "Moorgate", "Northern", 4},
+		{"Notting Hill Gate", "Circle", "Notting Hill Gate", "District", 4},
+		{"Paddington", "Circle", "Paddington", "District", 4},
+		{"Paddington", "Circle", "Paddington", "Elizabeth", 5},
+		{"Paddington", "Circle", "Paddington", "Hammersmith & City", 3},
+		{"South Kensington", "Circle", "South Kensington", "District", 4},
+		{"South Kensington", "Circle", "South Kensington", "Piccadilly", 4},
+		{"Tower Hill", "Circle", "Tower Gateway", "Docklands Light Railway", 5},
+		{"Tower Hill", "Circle", "Tower Hill", "District", 4},
+		{"Victoria", "Circle", "Victoria", "District", 4},
+		{"Victoria", "Circle", "Victoria", "Victoria", 4},
+		{"Westminster", "Circle", "Westminster", "District", 4},
+		{"Westminster", "Circle", "Westminster", "Jubilee", 4},
+		{"Wood Lane", "Circle", "Wood Lane", "Hammersmith & City", 3},
+
+		// INTERCHANGES FROM DISTRICT LINE *
+		{"Acton Town", "District", "Acton Town", "Piccadilly", 4},
+		{"Aldgate East", "District", "Aldgate East", "Hammersmith & City", 4},
+		{"Barking", "District", "Barking", "Hammersmith & City", 4},
+		{"Barking", "District", "Barking", "Overground", 6},
+		{"Bow Road", "District", "Bow Church", "Docklands Light Railway", 6},
+		{"Bow Road", "District", "Bow Road", "Hammersmith & City", 4},
+		{"Ealing Broadway", "District", "Ealing Broadway", "Elizabeth", 5},
+		{"Ealing Common", "District", "Ealing Common", "Piccadilly", 4},
+		{"Earl's Court", "District", "Earl's Court", "Piccadilly", 4},
+		{"Embankment", "District", "Embankment", "Northern", 4},
+		{"Gloucester Road", "District", "Gloucester Road", "Piccadilly", 4},
+		{"Gunnersbury", "District", "Gunnersbury", "Overground", 5},
+		{"Hammersmith", "District", "Hammersmith", "Hammersmith & City", 3},
+		{"Hammersmith", "District", "Hammersmith", "Piccadilly", 4},
+		{"Mile End", "District", "Mile End", "Hammersmith & City", 4},
+		{"Monument", "District", "Bank", "Docklands Light Railway", 6},
+		{"Monument", "District", "Bank", "Northern", 5},
+		{"Monument", "District", "Bank", "Waterloo & City", 5},
+		{"Paddington", "District", "Paddington", "Elizabeth", 5},
+		{"Paddington", "District", "Paddington", "Hammersmith & City", 4},
+		{"Richmond", "District", "Richmond", "Overground", 5},
+		{"South Kensington", "District", "South Kensington", "Piccadilly", 4},
+		{"Tower Hill", "District", "Tower Gateway", "Docklands Light Railway", 5},
+		{"Upminster", "District", "Upminster", "Overground", 6},
+		{"Victoria", "District", "Victoria", "Victoria", 4},
+		{"West Brompton", "District", "West Brompton", "Overground", 5},
+		{"West Ham", "District", "West Ham", "Docklands Light Railway", 5},
+		{"West Ham", "District", "West Ham", "Hammersmith & City", 4},
+		{"West Ham", "District", "West Ham", "Jubilee", 4},
+		{"Westminster", "District", "Westminster", "Jubilee", 4},
+		{"Whitechapel", "District", "Whitechapel", "Elizabeth", 5},
+		{"Whitechapel", "District", "Whitechapel", "Hammersmith & City", 4},
+		{"Whitechapel", "District", "Whitechapel", "Overground", 5},
+		{"Wimbledon", "District", "Wimbledon", "Tramlink", 4},
+
+		// INTERCHANGES FROM DOCKLANDS LIGHT RAILWAY LINE
+		{"Bank", "Docklands Light Railway", "Bank", "Northern", 5},
+		{"Bank", "Docklands Light Railway", "Bank", "Waterloo & City", 5},
+		{"Bow Church", "Docklands Light Railway", "Bow Road", "Hammersmith & City", 6},
+		{"Canary Wharf", "Docklands Light Railway", "Canary Wharf", "Elizabeth", 5},
+		{"Canary Wharf", "Docklands Light Railway", "Canary Wharf", "Jubilee", 4},
+		{"Canning Town", "Docklands Light Railway", "Canning Town", "Jubilee", 5},
+		{"Custom House for ExCeL", "Docklands Light Railway", "Custom House for ExCeL", "Elizabeth", 6},
+		{"Heron Quays", "Docklands Light Railway", "Canary Wharf", "Jubilee", 5},
+		{"Poplar", "Docklands Light Railway", "Canary Wharf", "Elizabeth", 5},
+		{"Shadwell", "Docklands Light Railway", "Shadwell", "Overground", 6},
+		{"Stratford", "Docklands Light Railway", "Stratford", "Elizabeth", 5},
+		{"Stratford", "Docklands Light Railway", "Stratford", "Jubilee", 4},
+		{"Stratford", "Docklands Light Railway", "Stratford", "Overground", 5},
+		{"Tower Gateway", "Docklands Light Railway", "Aldgate", "Metropolitan", 10},
+		{"West Ham", "Docklands Light Railway", "West Ham", "Hammersmith & City", 4},
+		{"West Ham", "Docklands Light Railway", "West Ham", "Jubilee", 4},
+		{"West India Quay", "Docklands Light Railway", "Canary Wharf", "Elizabeth", 7},
+		{"Woolwich Arsenal", "Docklands Light Railway", "Woolwich", "Elizabeth", 6},
+
+		// INTERCHANGES FROM ELIZABETH LINE
+		{"Bond Street", "Elizabeth", "Bond Street", "Jubilee", 5},
+		{"Canary Wharf", "Elizabeth", "Canary Wharf", "Jubilee", 5},
+		{"Farringdon", "Elizabeth", "Barbican", "Hammersmith & City", 5},
+		{"Farringdon", "Elizabeth", "Barbican", "Metropolitan", 5},
+		{"Farringdon", "Elizabeth", "Farringdon", "Hammersmith & City", 5},
+		{"Farringdon", "Elizabeth", "Farringdon", "Metropolitan", 5},
+		{"Forest Gate", "Elizabeth", "Wanstead Park", "Overground", 6},
+		{"Heathrow Terminal 5", "Elizabeth", "Heathrow Terminals 2 & 3", "Piccadilly", 5},
+		{"Liverpool Street", "Elizabeth", "Liverpool Street", "Hammersmith & City", 5},
+		{"Liverpool Street", "Elizabeth", "Liverpool Street", "Metropolitan", 5},
+		{"Liverpool Street", "Elizabeth", "Liverpool Street", "Overground", 6},
+		{"Liverpool Street", "Elizabeth", "Moorgate", "Hammersmith & City", 6},
+		{"Liverpool Street", "Elizabeth", "Moorgate", "Metropolitan", 6},
+		{"Manor Park", "Elizabeth", "Woodgrange Park", "Overground", 10},
+		{"Paddington", "Elizabeth", "Paddington", "Hammersmith & City", 5},
+		{"Romford", "Elizabeth", "Romford", "Overground", 6},
+		{"Stratford", "Elizabeth", "Stratford", "Jubilee", 4},
+		{"Stratford", "Elizabeth", "Stratford", "Overground", 5},
+		{"Tottenham Court Road", "Elizabeth", "Tottenham Court Road", "Northern", 5},
+		{"Whitechapel", "Elizabeth", "Whitechapel", "Hammersmith & City", 5},
+		{"Whitechapel", "Elizabeth", "Whitechapel", "Overground", 6},
+
+		// INTERCHANGES FROM HAMMERSMITH & CITY LINE
+		{"Baker Street", "Hammersmith & City", "Baker Street", "Jubilee", 4},
+		{"Baker Street", "Hammersmith & City", "Baker Street", "Metropolitan", 4},
+		{"Barbican", "Hammersmith & City", "Barbican", "Metropolitan", 3},
+		{"Barking", "District", "Barking", "Overground", 6},
+		{"Euston Square", "Hammersmith & City", "Euston Square", "Metropolitan", 3},
+		{"Euston Square", "Hammersmith & City", "Euston", "Northern", 6},
+		{"Euston Square", "Hammersmith & City", "Euston", "Overground", 7},
+		{"Euston Square", "Hammersmith & City", "Euston", "Victoria", 6},
+		{"Euston Square", "Hammersmith & City", "Warren Street", "Northern", 9},
+		{"Euston Square", "Hammersmith & City", "Warren Street", "Victoria", 9},
+		{"Farringdon", "Hammersmith & City", "Farringdon", "Metropolitan", 3},
+		{"Hammersmith", "Hammersmith & City", "Hammersmith", "Piccadilly", 4},
+		{"King's Cross St. Pancras", "Hammersmith & City", "King's Cross St. Pancras", "Metropolitan", 3},
+		{"King's Cross St. Pancras", "Hammersmith & City", "King's Cross St. Pancras", "Northern", 4},
+		{"King's Cross St. Pancras", "Hammersmith & City", "King's Cross St. Pancras", "Piccadilly", 4},
+		{"King's Cross St. Pancras", "Hammersmith & City", "King's Cross St. Pancras", "Victoria", 4},
+		{"Liverpool Street", "Hammersmith & City", "Liverpool Street", "Metropolitan", 4},
+		{"Liverpool Street", "Hammersmith & City", "Liverpool Street", "Overground", 6},
+		{"Moorgate", "Hammersmith & City", "Moorgate", "Metropolitan", 4},
+		{"Moorgate", "Hammersmith & City", "Moorgate", "Northern", 4},
+		{"West Ham", "Hammersmith & City", "West Ham", "Jubilee", 4},
+		{"Whitechapel", "Hammersmith & City", "Whitechapel", "Overground", 5},
+
+		// INTERCHANGES FROM JUBILEE LINE
+		{"Baker Street", "Jubilee", "Baker Street", "Metropolitan", 4},
+		{"Canada Water", "Jubilee", "Canada Water", "Overground", 6},
+		{"Finchley Road", "Jubilee", "Finchley Road & Frognal", "Overground", 8},
+		{"Finchley Road", "Jubilee", "Finchley Road", "Metropolitan", 5},
+		{"Green Park", "Jubilee", "Green Park", "Piccadilly", 4},
+		{"Green Park", "Jubilee", "Green Park", "Victoria", 4},
+		{"Kilburn", "Jubilee", "Brondesbury", "Overground", 6},
+		{"London Bridge", "Jubilee", "London Bridge", "Northern", 4},
+		{"Stratford", "Jubilee", "Stratford", "Overground", 6},
+		{"Waterloo", "Jubilee", "Waterloo", "Northern", 4},
+		{"Waterloo", "Jubilee", "Waterloo", "Waterloo & City", 4},
+		{"Wembley Park", "Jubilee", "Wembley Park", "Metropolitan", 4},
+		{"West Hampstead", "Jubilee", "West Hampstead", "Overground", 5},
+
+		// INTERCHANGES FROM METROPOLITAN LINE
+		{"Euston Square", "Metropolitan", "Euston", "Northern", 6},
+		{"Euston Square", "Metropolitan", "Euston", "Overground", 7},
+		{"Euston Square", "Metropolitan", "Euston", "Victoria", 6},
+		{"Euston Square", "Metropolitan", "Warren Street", "Northern", 9},
+		{"Euston Square", "Metropolitan", "Warren Street", "Victoria", 9},
+		{"Finchley Road", "Metropolitan", "Finchley Road & Frognal", "Overground", 8},
+		{"Ickenham", "Metropolitan", "Ickenham", "Piccadilly", 4},
+		{"King's Cross St. Pancras", "Metropolitan", "King's Cross St. Pancras", "Northern", 4},
+		{"King's Cross St. Pancras", "Metropolitan", "King's Cross St. Pancras", "Piccadilly", 4},
+		{"King's Cross St. Pancras", "Metropolitan", "King's Cross St. Pancras", "Victoria", 4},
+		{"Liverpool Street", "Metropolitan", "Liverpool Street", "Overground", 6},
+		{"Moorgate", "Metropolitan", "Moorgate", "Northern", 4},
+		{"Northwick Park", "Metropolitan", "Kenton", "Overground", 8},
+		{"Rayners Lane", "Metropolitan", "Rayners Lane", "Piccadilly", 4},
+
+		// INTERCHANGES FROM NORTHERN LINE
+		{"Archway", "Northern", "Upper Holloway", "Overground", 7},
+		{"Camden Town", "Northern", "Camden Road", "Overground", 8},
+		{"Clapham North", "Northern", "Clapham High Street", "Overground", 6},
+		{"Euston", "Northern", "Euston", "Overground", 5},
+		{"Euston", "Northern", "Euston", "Victoria", 4},
+		{"Kentish Town", "Northern", "Kentish Town West", "Overground", 12},
+		{"King's Cross St. Pancras", "Northern", "King's Cross St. Pancras", "Piccadilly", 4},
+		{"King's Cross St. Pancras", "Northern", "King's Cross St. Pancras", "Victoria", 4},
+		{"Leicester Square", "Northern", "Leicester Square", "Piccadilly", 4},
+		{"Stockwell", "Northern", "Stockwell", "Victoria", 4},
+		{"Warren Street", "Northern", "Warren Street", "Victoria", 4},
+		{"Waterloo", "Northern", "Waterloo", "Waterloo & City", 4},
+
+		// INTERCHANGES FROM OVERGROUND LINE
+		{"Blackhorse Road", "Overground", "Blackhorse Road", "Victoria", 4},
+		{"Dalston Junction", "Overground", "Dalston Kingsland", "Overground", 6},
+		{"Euston", "Overground", "Euston", "Victoria", 5},
+		{"Hackney Downs", "Overground", "Hackney Central", "Overground", 12},
+		{"Highbury & Islington", "Overground", "Highbury & Islington", "Victoria", 5},
+		{"New Cross Gate", "Overground", "New Cross", "Overground", 15},
+		{"Seven Sisters", "Overground", "Seven Sisters", "Victoria", 5},
+		{"South Tottenham", "Overground", "Seven Sisters", "Victoria", 9},
+		{"Walthamstow Central", "Overground", "Walthamstow Central", "Victoria", 5},
+		{"Walthamstow Queen's Road", "Overground", "Walthamstow Central", "Victoria", 8},
+		{"West Croydon", "Overground", "West Croydon", "Tramlink", 6},
+
+		// INTERCHANGES FROM PICCADILLY LINE
+		{"Finsbury Park", "Piccadilly", "Finsbury Park", "Victoria", 4},
+		{"Green Park", "Piccadilly", "Green Park", "Victoria", 4},
+		{"King's Cross St. Pancras", "Piccadilly", "King's Cross St. Pancras", "Victoria", 4},
+	}
+}
+
+// Return list of rail links whose run times differ by time band, e.g. where
+// trains are held longer at busy stations in the peaks. Links not listed
+// here take their all-day transit time in every band.
+func GetBandedRunTimes() []BandedRunTime {
+	return []BandedRunTime{}
+}
+
+// Return the extra average wait for a train on each limited-stop line, whose
+// trains run less frequently than those on the Underground
+func GetLineWaits() []LineWait {
+	return []LineWait{
+		{"Elizabeth", 1},
+	}
+}
+
+// Return the first and last trains and the usual gaps between trains of each
+// line, as a guide to waiting times by time of day. The Overground figures
+// are for its less frequent branches, as its routes are not told apart here.
+func GetLineServices() []LineService {
+	return []LineService{
+		{"Bakerloo", clockTime(5, 30), clockTime(24, 30), 3, 5},
+		{"Central", clockTime(5, 30), clockTime(24, 30), 2, 4},
+		{"Circle", clockTime(5, 30), clockTime(24, 30), 10, 10},
+		{"District", clockTime(5, 30), clockTime(24, 30), 4, 5},
+		{"Docklands Light Railway", clockTime(5, 30), clockTime(24, 30), 4, 6},
+		{"Elizabeth", clockTime(5, 30), clockTime(24, 0), 3, 5},
+		{"Hammersmith & City", clockTime(5, 30), clockTime(24, 30), 6, 10},
+		{"Jubilee", clockTime(5, 30), clockTime(24, 30), 2, 4},
+		{"Metropolitan", clockTime(5, 30), clockTime(24, 30), 4, 6},
+		{"Northern", clockTime(5, 30), clockTime(24, 30), 3, 4},
+		{"Overground", clockTime(5, 30), clockTime(24, 0), 8, 15},
+		{"Piccadilly", clockTime(5, 30), clockTime(24, 30), 3, 4},
+		{"Tramlink", clockTime(5, 30), clockTime(24, 0), 5, 8},
+		{"Victoria", clockTime(5, 30), clockTime(24, 30), 2, 3},
+		{"Waterloo & City", clockTime(6, 0), clockTime(24, 30), 3, 5},
+	}
+}
+
+// Return the kinds of day on which each line runs, where it differs from the
+// usual weekday and weekend service with no trains at night. Night Tube runs
+// through Friday and Saturday nights on parts of five Underground lines, as
+// does the Overground between Highbury & Islington and New Cross Gate.
+func GetServiceCalendar() []ServiceDays {
+	allDays := DayWeekday | DayWeekend | DayNight
+	return []ServiceDays{
+		{"Central", allDays, nil},
+		{"Central", DayWeekday | DayWeekend, []string{"West Ruislip", "Ruislip Gardens", "South Ruislip",
+			"Northolt", "Greenford", "Perivale", "Hanger Lane", "Grange Hill", "Chigwell", "Roding Valley",
+			"Debden", "Theydon Bois", "Epping"}},
+		{"Jubilee", allDays, nil},
+		{"Northern", allDays, nil},
+		{"Northern", DayWeekday | DayWeekend, []string{"Elephant & Castle", "Borough", "London Bridge",
+			"Bank", "Moorgate", "Old Street", "Angel", "King's Cross St. Pancras", "Mill Hill East",
+			"Nine Elms", "Battersea Power Station"}},
+		{"Overground", allDays, []string{"Highbury & Islington", "Canonbury", "Dalston Junction",
+			"Haggerston", "Hoxton", "Shoreditch High Street", "Whitechapel", "Shadwell", "Wapping",
+			"Rotherhithe", "Canada Water", "Surrey Quays", "New Cross Gate"}},
+		{"Piccadilly", allDays, nil},
+		{"Piccadilly", DayWeekday | DayWeekend, []string{"Uxbridge", "Hillingdon", "Ickenham", "Ruislip",
+			"Ruislip Manor", "Eastcote", "Rayners Lane", "South Harrow", "Sudbury Hill", "Sudbury Town",
+			"Alperton", "Park Royal", "North Ealing", "Ealing Common", "Heathrow Terminal 4"}},
+		{"Victoria", allDays, nil},
+		{"Waterloo & City", DayWeekday, nil},
+	}
+}
+
+// Return the usual gaps between trains of each line running at night, which
+// take the place of the daytime services under the night profile. Night
+// trains run all night, so they are taken to be running at any time.
+func GetNightServices() []LineService {
+	return []LineService{
+		{"Central", clockTime(0, 0), clockTime(24, 0), 10, 10},
+		{"Jubilee", clockTime(0, 0), clockTime(24, 0), 10, 10},
+		{"Northern", clockTime(0, 0), clockTime(24, 0), 8, 8},
+		{"Overground", clockTime(0, 0), clockTime(24, 0), 15, 15},
+		{"Piccadilly", clockTime(0, 0), clockTime(24, 0), 10, 10},
+		{"Victoria", clockTime(0, 0), clockTime(24, 0), 10, 10},
+	}
+}
+
+// Return the gate-to-platform walking times at stations with deep or distant
+// platforms, such as those of the Elizabeth line through central London.
+// Times between two lines at the same station are already part of the
+// interchange times, so these only apply when starting or ending a trip.
+func GetPlatformAccessTimes() []PlatformAccess {
+	return []PlatformAccess{
+		{"Bond Street", "Elizabeth", 3},
+		{"Canary Wharf", "Elizabeth", 2},
+		{"Farringdon", "Elizabeth", 3},
+		{"Liverpool Street", "Elizabeth", 4},
+		{"Paddington", "Elizabeth", 3},
+		{"Tottenham Court Road", "Elizabeth", 3},
+		{"Whitechapel", "Elizabeth", 3},
+		{"Woolwich", "Elizabeth", 2},
+	}
+}
+
+// Return the least time changes between lines take at stations where it
+// differs much from the interchange time. Cross-platform changes can make a
+// train leaving a minute after arriving, while changes down long passages
+// and escalators take their whole interchange time before the wait for the
+// train can begin.
+func GetMinimumConnectionTimes() []ConnectionTime {
+	return []ConnectionTime{
+		{"Bank", "Central", "Docklands Light Railway", 6},
+		{"Canary Wharf", "Elizabeth", "Jubilee", 5},
+		{"Canary Wharf", "Jubilee", "Elizabeth", 5},
+		{"Euston", "Northern", "Victoria", 1},
+		{"Euston", "Victoria", "Northern", 1},
+		{"Finsbury Park", "Piccadilly", "Victoria", 1},
+		{"Finsbury Park", "Victoria", "Piccadilly", 1},
+		{"Liverpool Street", "Central", "Elizabeth", 6},
+		{"Liverpool Street", "Elizabeth", "Central", 6},
+		{"Mile End", "Central", "District", 1},
+		{"Mile End", "Central", "Hammersmith & City", 1},
+		{"Mile End", "District", "Central", 1},
+		{"Mile End", "Hammersmith & City", "Central", 1},
+		{"Oxford Circus", "Bakerloo", "Victoria", 1},
+		{"Oxford Circus", "Victoria", "Bakerloo", 1},
+		{"Stockwell", "Northern", "Victoria", 1},
+		{"Stockwell", "Victoria", "Northern", 1},
+		{"Whitechapel", "District", "Elizabeth", 5},
+	}
+}
+
+// Return the extra time trains stand at the busiest interchanges, where
+// crowds getting on and off hold them longer than at other stations. The
+// run times between stations already allow for an ordinary stop, so these
+// only cover the time beyond that.
+func GetStationDwellTimes() []StationDwell {
+	return []StationDwell{
+		{"Baker Street", 1},
+		{"Bank", 1},
+		{"Bond Street", 1},
+		{"Canary Wharf", 1},
+		{"Euston", 1},
+		{"Farringdon", 1},
+		{"Green Park", 1},
+		{"King's Cross St. Pancras", 1},
+		{"Liverpool Street", 1},
+		{"London Bridge", 1},
+		{"Oxford Circus", 1},
+		{"Paddington", 1},
+		{"Stratford", 1},
+		{"Tottenham Court Road", 1},
+		{"Victoria", 1},
+		{"Waterloo", 1},
+	}
+}
+
+// Return the platforms with step-free access from the street, as shown on
+// TfL's step-free Tube guide, along with how much longer the way by lift
+// takes. Interchanges between two of these platforms, at the same station
+// or on foot to a nearby one, are also step-free. Lines whose every stop is
+// step-free are listed by GetStepFreeLines instead.
+func GetStepFreeAccess() []StepFreeAccess {
+	return []StepFreeAccess{
+		{"Abbey Wood", "Elizabeth", 0},
+		{"Acton Main Line", "Elizabeth", 1},
+		{"Bank", "Northern", 2},
+		{"Barking", "District", 1},
+		{"Barking", "Hammersmith & City", 1},
+		{"Barking", "Overground", 1},
+		{"Battersea Power Station", "Northern", 1},
+		{"Bermondsey", "Jubilee", 1},
+		{"Bond Street", "Elizabeth", 1},
+		{"Bond Street", "Jubilee", 2},
+		{"Brentwood", "Elizabeth", 1},
+		{"Canada Water", "Jubilee", 1},
+		{"Canada Water", "Overground", 1},
+		{"Canary Wharf", "Elizabeth", 1},
+		{"Canary Wharf", "Jubilee", 1},
+		{"Canning Town", "Jubilee", 0},
+		{"Clapham Junction", "Overground", 1},
+		{"Cockfosters", "Piccadilly", 0},
+		{"Custom House for ExCeL", "Elizabeth", 0},
+		{"Dalston Junction", "Overground", 1},
+		{"Ealing Broadway", "District", 0},
+		{"Ealing Broadway", "Elizabeth", 1},
+		{"Earl's Court", "District", 2},
+		{"Edgware", "Northern", 0},
+		{"Farringdon", "Elizabeth", 1},
+		{"Farringdon", "Circle", 1},
+		{"Farringdon", "Hammersmith & City", 1},
+		{"Farringdon", "Metropolitan", 1},
+		{"Forest Gate", "Elizabeth", 1},
+		{"Green Park", "Jubilee", 2},
+		{"Green Park", "Piccadilly", 2},
+		{"Green Park", "Victoria", 2},
+		{"Hackney Central", "Overground", 1},
+		{"Haggerston", "Overground", 1},
+		{"Hainault", "Central", 0},
+		{"Hammersmith", "District", 0},
+		{"Hammersmith", "Piccadilly", 0},
+		{"Hanwell", "Elizabeth", 1},
+		{"Harold Wood", "Elizabeth", 1},
+		{"Hayes & Harlington", "Elizabeth", 1},
+		{"Heathrow Terminal 4", "Piccadilly", 1},
+		{"Heathrow Terminal 5", "Elizabeth", 1},
+		{"Heathrow Terminal 5", "Piccadilly", 1},
+		{"Heathrow Terminals 2 & 3", "Elizabeth", 2},
+		{"Heathrow Terminals 2 & 3", "Piccadilly", 2},
+		{"Highbury & Islington", "Overground", 1},
+		{"Hoxton", "Overground", 1},
+		{"Ilford", "Elizabeth", 1},
+		{"King's Cross St. Pancras", "Circle", 1},
+		{"King's Cross St. Pancras", "Hammersmith & City", 1},
+		{"King's Cross St. Pancras", "Metropolitan", 1},
+		{"King's Cross St. Pancras", "Northern", 3},
+		{"King's Cross St. Pancras", "Piccadilly", 3},
+		{"King's Cross St. Pancras", "Victoria", 3},
+		{"Liverpool Street", "Elizabeth", 1},
+		{"London Bridge", "Jubilee", 2},
+		{"London Bridge", "Northern", 2},
+		{"Maidenhead", "Elizabeth", 1},
+		{"Manor Park", "Elizabeth", 1},
+		{"Maryland", "Elizabeth", 1},
+		{"Nine Elms", "Northern", 1},
+		{"North Greenwich", "Jubilee", 1},
+		{"Paddington", "Elizabeth", 1},
+		{"Paddington", "Hammersmith & City", 0},
+		{"Paddington", "Circle", 0},
+		{"Reading", "Elizabeth", 1},
+		{"Richmond", "District", 0},
+		{"Richmond", "Overground", 0},
+		{"Romford", "Elizabeth", 1},
+		{"Shadwell", "Overground", 1},
+		{"Shenfield", "Elizabeth", 1},
+		{"Shoreditch High Street", "Overground", 1},
+		{"Slough", "Elizabeth", 1},
+		{"Southall", "Elizabeth", 1},
+		{"Southwark", "Jubilee", 1},
+		{"Stanmore", "Jubilee", 0},
+		{"Stratford", "Central", 1},
+		{"Stratford", "Elizabeth", 1},
+		{"Stratford", "Jubilee", 0},
+		{"Stratford", "Overground", 1},
+		{"Tottenham Court Road", "Central", 2},
+		{"Tottenham Court Road", "Elizabeth", 1},
+		{"Tottenham Court Road", "Northern", 2},
+		{"Tottenham Hale", "Victoria", 1},
+		{"Tower Hill", "Circle", 1},
+		{"Tower Hill", "District", 1},
+		{"Twyford", "Elizabeth", 1},
+		{"Upminster", "District", 1},
+		{"Vauxhall", "Victoria", 2},
+		{"Victoria", "Circle", 2},
+		{"Victoria", "District", 2},
+		{"Victoria", "Victoria", 2},
+		{"Waterloo", "Jubilee", 2},
+		{"Wembley Park", "Jubilee", 1},
+		{"Wembley Park", "Metropolitan", 1},
+		{"West Drayton", "Elizabeth", 1},
+		{"West Ealing", "Elizabeth", 1},
+		{"West Ham", "District", 1},
+		{"West Ham", "Hammersmith & City", 1},
+		{"West Ham", "Jubilee", 1},
+		{"West Hampstead", "Overground", 1},
+		{"Westminster", "Circle", 1},
+		{"Westminster", "District", 1},
+		{"Westminster", "Jubilee", 2},
+		{"Whitechapel", "District", 1},
+		{"Whitechapel", "Elizabeth", 1},
+		{"Whitechapel", "Hammersmith & City", 1},
+		{"Whitechapel", "Overground", 1},
+		{"Willesden Junction", "Overground", 1},
+		{"Wimbledon", "District", 0},
+		{"Woodford", "Central", 0},
+		{"Woolwich", "Elizabeth", 1},
+	}
+}
+
+// Return the lifts each step-free way through a station depends on, for
+// stations with several lifts where one being out of service leaves other
+// ways step-free. Interchanges not listed depend on the street lifts of both
+// lines, and ways at stations not listed on no lifts known by name.
+func GetLiftDependencies() []LiftDependency {
+	return []LiftDependency{
+		{"Bond Street", "", "Elizabeth", []string{"Hanover Square lift"}},
+		{"Bond Street", "", "Jubilee", []string{"Marylebone Lane lift", "Jubilee lift"}},
+		{"Bond Street", "Elizabeth", "Jubilee", []string{"Elizabeth link lift", "Jubilee lift"}},
+		{"Canary Wharf", "", "Elizabeth", []string{"Crossrail Place lift"}},
+		{"Canary Wharf", "", "Jubilee", []string{"Jubilee Place lift"}},
+		{"Farringdon", "", "Circle", []string{"Cowcross Street lift"}},
+		{"Farringdon", "", "Elizabeth", []string{"Lindsey Street lift"}},
+		{"Farringdon", "", "Hammersmith & City", []string{"Cowcross Street lift"}},
+		{"Farringdon", "", "Metropolitan", []string{"Cowcross Street lift"}},
+		{"Farringdon", "Circle", "Elizabeth", []string{"Turnmill Street lift"}},
+		{"Farringdon", "Elizabeth", "Hammersmith & City", []string{"Turnmill Street lift"}},
+		{"Farringdon", "Elizabeth", "Metropolitan", []string{"Turnmill Street lift"}},
+		{"Green Park", "", "Jubilee", []string{"street lift", "Jubilee lift"}},
+		{"Green Park", "", "Piccadilly", []string{"street lift", "Piccadilly lift"}},
+		{"Green Park", "", "Victoria", []string{"street lift", "Victoria lift"}},
+		{"Green Park", "Jubilee", "Piccadilly", []string{"Jubilee lift", "Piccadilly lift"}},
+		{"Green Park", "Jubilee", "Victoria", []string{"Jubilee lift", "Victoria lift"}},
+		{"Green Park", "Piccadilly", "Victoria", []string{"Piccadilly lift", "Victoria lift"}},
+		{"King's Cross St. Pancras", "", "Northern", []string{"Western ticket hall lift", "Northern lift"}},
+		{"King's Cross St. Pancras", "", "Piccadilly", []string{"Western ticket hall lift", "Piccadilly lift"}},
+		{"King's Cross St. Pancras", "", "Victoria", []string{"Western ticket hall lift", "Victoria lift"}},
+		{"King's Cross St. Pancras", "Northern", "Piccadilly", []string{"Northern lift", "Piccadilly lift"}},
+		{"King's Cross St. Pancras", "Northern", "Victoria", []string{"Northern lift", "Victoria lift"}},
+		{"King's Cross St. Pancras", "Piccadilly", "Victoria", []string{"Piccadilly lift", "Victoria lift"}},
+		{"Tottenham Court Road", "", "Central", []string{"Oxford Street lift", "Central lift"}},
+		{"Tottenham Court Road", "", "Elizabeth", []string{"Dean Street lift"}},
+		{"Tottenham Court Road", "", "Northern", []string{"Oxford Street lift", "Northern lift"}},
+		{"Tottenham Court Road", "Central", "Elizabeth", []string{"Central lift", "Elizabeth link lift"}},
+		{"Tottenham Court Road", "Central", "Northern", []string{"Central lift", "Northern lift"}},
+		{"Tottenham Court Road", "Elizabeth", "Northern", []string{"Elizabeth link lift", "Northern lift"}},
+		{"Westminster", "", "Circle", []string{"Bridge Street lift"}},
+		{"Westminster", "", "District", []string{"Bridge Street lift"}},
+		{"Westminster", "", "Jubilee", []string{"Bridge Street lift", "Jubilee lift"}},
+		{"Westminster", "Circle", "Jubilee", []string{"Jubilee lift"}},
+		{"Westminster", "District", "Jubilee", []string{"Jubilee lift"}},
+	}
+}
+
+// Return the step-free platforms where getting on or off the train needs a
+// manual boarding ramp, as shown on TfL's step-free Tube guide. Trains on the
+// Elizabeth line outside its central tunnels and on the Overground stand
+// well above the platforms, while older Tube platforms without humps leave a
+// step. Platforms not listed have level boarding.
+func GetBoardingRamps() []BoardingRamp {
+	return []BoardingRamp{
+		{"Acton Main Line", "Elizabeth"},
+		{"Barking", "District"},
+		{"Barking", "Hammersmith & City"},
+		{"Barking", "Overground"},
+		{"Brentwood", "Elizabeth"},
+		{"Clapham Junction", "Overground"},
+		{"Cockfosters", "Piccadilly"},
+		{"Ealing Broadway", "District"},
+		{"Ealing Broadway", "Elizabeth"},
+		{"Earl's Court", "District"},
+		{"Edgware", "Northern"},
+		{"Forest Gate", "Elizabeth"},
+		{"Hackney Central", "Overground"},
+		{"Hainault", "Central"},
+		{"Hammersmith", "District"},
+		{"Hammersmith", "Piccadilly"},
+		{"Hanwell", "Elizabeth"},
+		{"Harold Wood", "Elizabeth"},
+		{"Hayes & Harlington", "Elizabeth"},
+		{"Heathrow Terminal 4", "Piccadilly"},
+		{"Highbury & Islington", "Overground"},
+		{"Ilford", "Elizabeth"},
+		{"Maidenhead", "Elizabeth"},
+		{"Manor Park", "Elizabeth"},
+		{"Maryland", "Elizabeth"},
+		{"Reading", "Elizabeth"},
+		{"Richmond", "District"},
+		{"Richmond", "Overground"},
+		{"Romford", "Elizabeth"},
+		{"Shenfield", "Elizabeth"},
+		{"Slough", "Elizabeth"},
+		{"Southall", "Elizabeth"},
+		{"Stratford", "Central"},
+		{"Stratford", "Overground"},
+		{"Twyford", "Elizabeth"},
+		{"Upminster", "District"},
+		{"West Drayton", "Elizabeth"},
+		{"West Ealing", "Elizabeth"},
+		{"West Hampstead", "Overground"},
+		{"Willesden Junction", "Overground"},
+		{"Woodford", "Central"},
+	}
+}
+
+// Return the lines whose every stop has step-free access from the street
+func GetStepFreeLines() []string {
+	return []string{"Docklands Light Railway", "Tramlink"}
+}
+
+// Return the deep-level Tube lines, running in bored tunnels well below the
+// street for most of their length, whose trains, ventilation and lifts use
+// more energy per minute than lines nearer the surface
+func GetDeepLevelLines() []string {
+	return []string{"Bakerloo", "Central", "Jubilee", "Northern", "Piccadilly", "Victoria", "Waterloo & City"}
+}
+
+// Return the sub-surface Underground lines, running in shallow cut-and-cover
+// tunnels just below the street, with full-size trains
+func GetSubSurfaceLines() []string {
+	return []string{"Circle", "District", "Hammersmith & City", "Metropolitan"}
+}
+
+// Return the lines running above ground for most of their length, counting
+// the Elizabeth line, whose tunnels only cross central London
+func GetSurfaceLines() []string {
+	return []string{"Docklands Light Railway", "Elizabeth", "Overground", "Tramlink"}
+}
+
+// Return the lines whose whole fleet of trains is air-conditioned. None of
+// the deep-level lines' trains are, as their narrow tunnels leave no room to
+// get rid of the heat.
+func GetAirConditionedLines() []string {
+	return []string{"Circle", "District", "Elizabeth", "Hammersmith & City", "Metropolitan", "Overground"}
+}
+
+// Return the aids for passengers with hearing or visual impairments known to
+// be provided throughout each station: tactile paving along the platform
+// edges, induction hearing loops at help points, and both audio and visual
+// next train announcements. Stations not listed are not known to provide any.
+func GetStationAids() map[string]AccessibilityAids {
+	const allAids = TactilePaving | HearingLoop | AudioAnnouncements | VisualDisplays
+	return map[string]AccessibilityAids{
+		// Elizabeth line stations in central London, opened in 2022
+		"Abbey Wood":             allAids,
+		"Bond Street":            allAids,
+		"Canary Wharf":           allAids,
+		"Custom House for ExCeL": allAids,
+		"Farringdon":             allAids,
+		"Liverpool Street":       allAids,
+		"Paddington":             allAids,
+		"Tottenham Court Road":   allAids,
+		"Whitechapel":            allAids,
+		"Woolwich":               allAids,
+		// Jubilee line extension stations, opened in 1999
+		"Bermondsey":      allAids,
+		"Canada Water":    allAids,
+		"Canning Town":    allAids,
+		"London Bridge":   allAids,
+		"North Greenwich": allAids,
+		"Southwark":       allAids,
+		"Stratford":       allAids,
+		"Waterloo":        allAids,
+		"West Ham":        allAids,
+		"Westminster":     allAids,
+		// Other stations rebuilt in recent upgrades
+		"Bank":                     allAids,
+		"King's Cross St. Pancras": allAids,
+		"Victoria":                 allAids,
+	}
+}
+
+// Return the toilets and baby changing facilities known to be provided at
+// stations, with where they are. Those outside the ticket gates mean leaving
+// and re-entering the network, which pay as you go fares treat as two
+// separate journeys.
+func GetStationFacilities() []StationFacility {
+	return []StationFacility{
+		{"Bank", FacilityToilet, "by the Northern line platforms, inside the gates"},
+		{"Bank", FacilityBabyChange, "in the accessible toilet by the Northern line platforms, inside the gates"},
+		{"Canary Wharf", FacilityToilet, "on the Elizabeth line concourse, inside the gates"},
+		{"Canary Wharf", FacilityBabyChange, "on the Elizabeth line concourse, inside the gates"},
+		{"Charing Cross", FacilityToilet, "on the National Rail concourse, outside the gates"},
+		{"Clapham Junction", FacilityToilet, "on the footbridge, inside the gates"},
+		{"Clapham Junction", FacilityBabyChange, "in the accessible toilet on the footbridge, inside the gates"},
+		{"Ealing Broadway", FacilityToilet, "in the ticket hall, inside the gates"},
+		{"Euston", FacilityToilet, "on the National Rail concourse, outside the gates"},
+		{"Euston", FacilityBabyChange, "on the National Rail concourse, outside the gates"},
+		{"Hammersmith", FacilityToilet, "in the District and Piccadilly lines ticket hall, inside the gates"},
+		{"Heathrow Terminals 2 & 3", FacilityToilet, "on the Piccadilly line concourse, inside the gates"},
+		{"Heathrow Terminals 2 & 3", FacilityBabyChange, "on the Piccadilly line concourse, inside the gates"},
+		{"King's Cross St. Pancras", FacilityToilet, "in the Western ticket hall, inside the gates"},
+		{"King's Cross St. Pancras", FacilityBabyChange, "in the Western ticket hall, inside the gates"},
+		{"Liverpool Street", FacilityToilet, "on the National Rail concourse, outside the gates"},
+		{"Liverpool Street", FacilityBabyChange, "on the National Rail concourse, outside the gates"},
+		{"London Bridge", FacilityToilet, "on the main concourse, outside the gates"},
+		{"London Bridge", FacilityBabyChange, "on the main concourse, outside the gates"},
+		{"Marylebone", FacilityToilet, "on the National Rail concourse, outside the gates"},
+		{"North Greenwich", FacilityToilet, "in the ticket hall, inside the gates"},
+		{"Paddington", FacilityToilet, "on the National Rail concourse, outside the gates"},
+		{"Paddington", FacilityBabyChange, "on the National Rail concourse, outside the gates"},
+		{"Richmond", FacilityToilet, "on platform 1, inside the gates"},
+		{"Stratford", FacilityToilet, "on the main concourse, inside the gates"},
+		{"Stratford", FacilityBabyChange, "on the main concourse, inside the gates"},
+		{"Victoria", FacilityToilet, "on the National Rail concourse, outside the gates"},
+		{"Victoria", FacilityBabyChange, "on the National Rail concourse, outside the gates"},
+		{"Walthamstow Central", FacilityToilet, "in the ticket hall, inside the gates"},
+		{"Waterloo", FacilityToilet, "on the National Rail concourse, outside the gates"},
+		{"Waterloo", FacilityBabyChange, "on the National Rail concourse, outside the gates"},
+		{"Wimbledon", FacilityToilet, "on platform 5, inside the gates"},
+	}
+}
+
+// Return, for each line, the stations at which its services begin their
+// journeys: the line's termini, plus intermediate stations where a
+// significant share of trains start (e.g. after reversing in a siding)
+func GetServiceOrigins() map[string][]string {
+	return map[string][]string{
+		"Bakerloo":                {"Harrow & Wealdstone", "Stonebridge Park", "Queen's Park", "Elephant & Castle"},
+		"Central":                 {"West Ruislip", "Northolt", "Ealing Broadway", "White City", "Epping", "Loughton", "Hainault", "Newbury Park", "Woodford"},
+		"Circle":                  {"Hammersmith", "Edgware Road"},
+		"District":                {"Ealing Broadway", "Richmond", "Wimbledon", "Edgware Road", "Tower Hill", "Barking", "Upminster"},
+		"Docklands Light Railway": {"Bank", "Tower Gateway", "Stratford", "Stratford International", "Beckton", "Lewisham", "Woolwich Arsenal"},
+		"Elizabeth":               {"Reading", "Maidenhead", "Heathrow Terminal 5", "Heathrow Terminals 4", "Paddington", "Liverpool Street", "Abbey Wood", "Gidea Park", "Shenfield"},
+		"Hammersmith & City":      {"Hammersmith", "Barking"},
+		"Jubilee":                 {"Stanmore", "Willesden Green", "North Greenwich", "Stratford"},
+		"Metropolitan":            {"Amersham", "Chesham", "Watford", "Uxbridge", "Baker Street", "Aldgate"},
+		"Northern":                {"Edgware", "High Barnet", "Mill Hill East", "Morden", "Kennington", "Battersea Power Station"},
+		"Overground":              {"Barking Riverside", "Cheshunt", "Chingford", "Clapham Junction", "Crystal Palace", "Enfield Town", "Euston", "Highbury & Islington", "Liverpool Street", "New Cross", "Richmond", "Romford", "Stratford", "Upminster", "Watford Junction", "West Croydon"},
+		"Piccadilly":              {"Cockfosters", "Arnos Grove", "Heathrow Terminal 5", "Heathrow Terminal 4", "Uxbridge", "Rayners Lane", "Northfields"},
+		"Tramlink":                {"Beckenham Junction", "Elmers End", "New Addington", "Wimbledon"},
+		"Victoria":                {"Brixton", "Seven Sisters", "Walthamstow Central"},
+		"Waterloo & City":         {"Bank", "Waterloo"},
+	}
+}
+
+// Return, for each line with a clear peak direction, the station its morning
+// peak flows towards, with the evening peak flowing away from it again. The
+// Circle and Overground are left out, as their flows have no single centre.
+func GetPeakFlowCentres() map[string]string {
+	return map[string]string{
+		"Bakerloo":                "Oxford Circus",
+		"Central":                 "Holborn",
+		"District":                "Embankment",
+		"Docklands Light Railway": "Canary Wharf",
+		"Elizabeth":               "Tottenham Court Road",
+		"Hammersmith & City":      "King's Cross St. Pancras",
+		"Jubilee":                 "Waterloo",
+		"Metropolitan":            "Baker Street",
+		"Northern":                "Bank",
+		"Piccadilly":              "Holborn",
+		"Tramlink":                "East Croydon",
+		"Victoria":                "Oxford Circus",
+		"Waterloo & City":         "Bank",
+	}
+}
+
+// Return the official colour of each line, as used on the Tube map, for
+// colouring the line names in directions
+func GetLineColours() map[string]LineColour {
+	return map[string]LineColour{
+		"Bakerloo":                {0xb3, 0x63, 0x05},
+		"Central":                 {0xe3, 0x20, 0x17},
+		"Circle":                  {0xff, 0xd3, 0x00},
+		"District":                {0x00, 0x78, 0x2a},
+		"Docklands Light Railway": {0x00, 0xa4, 0xa7},
+		"Elizabeth":               {0x69, 0x50, 0xa1},
+		"Hammersmith & City":      {0xf3, 0xa9, 0xbb},
+		"Jubilee":                 {0xa0, 0xa5, 0xa9},
+		"Metropolitan":            {0x9b, 0x00, 0x56},
+		"Northern":                {0x00, 0x00, 0x00},
+		"Overground":              {0xee, 0x7c, 0x0e},
+		"Piccadilly":              {0x00, 0x36, 0x88},
+		"Tramlink":                {0x84, 0xb8, 0x17},
+		"Victoria":                {0x00, 0x98, 0xd4},
+		"Waterloo & City":         {0x95, 0xcd, 0xba},
+	}
+}
+
+// Return walking guidance for interchanges where it helps to know which way to
+// go. Guidance is given per direction, as the way is rarely described the same
+// both ways round.
+func GetInterchangeGuidance() []InterchangeGuidance {
+	const crossPlatform = "Cross-platform interchange for trains in the same direction"
+	return []InterchangeGuidance{
+		{"Bank", "Central", "Bank", "Docklands Light Railway", "Follow the turquoise DLR signs, a long walk through passageways and down escalators"},
+		{"Canary Wharf", "Jubilee", "Canary Wharf", "Elizabeth", "Leave the station and follow the purple Elizabeth line signs to its separate entrance"},
+		{"Canary Wharf", "Elizabeth", "Canary Wharf", "Jubilee", "Leave the station and follow the grey Jubilee line signs to its separate entrance"},
+		{"Euston", "Northern", "Euston", "Victoria", crossPlatform + " from the Bank branch"},
+		{"Euston", "Victoria", "Euston", "Northern", crossPlatform + " to the Bank branch"},
+		{"Finsbury Park", "Piccadilly", "Finsbury Park", "Victoria", crossPlatform},
+		{"Finsbury Park", "Victoria", "Finsbury Park", "Piccadilly", crossPlatform},
+		{"Highbury & Islington", "Victoria", "Highbury & Islington", "Overground", "Follow the orange Overground signs"},
+		{"Liverpool Street", "Central", "Liverpool Street", "Elizabeth", "Follow the purple Elizabeth line signs, down long escalators to the deep platforms"},
+		{"Liverpool Street", "Elizabeth", "Liverpool Street", "Central", "Follow the red Central line signs, up long escalators from the deep platforms"},
+		{"Mile End", "Central", "Mile End", "District", crossPlatform},
+		{"Mile End", "District", "Mile End", "Central", crossPlatform},
+		{"Mile End", "Central", "Mile End", "Hammersmith & City", crossPlatform},
+		{"Mile End", "Hammersmith & City", "Mile End", "Central", crossPlatform},
+		{"Oxford Circus", "Bakerloo", "Oxford Circus", "Victoria", crossPlatform},
+		{"Oxford Circus", "Victoria", "Oxford Circus", "Bakerloo", crossPlatform},
+		{"Stockwell", "Northern", "Stockwell", "Victoria", crossPlatform},
+		{"Stockwell", "Victoria", "Stockwell", "Northern", crossPlatform},
+		{"Whitechapel", "District", "Whitechapel", "Elizabeth", "Follow the purple Elizabeth line signs, down escalators to the deep platforms"},
+		{"Whitechapel", "District", "Whitechapel", "Overground", "Follow the orange Overground signs"},
+	}
+}
+
+// Return the fare zone(s) of each station, as shown on the TfL map. Stations
+// outside the zonal fares area, and tram stops (which have flat fares), are
+// not listed.
+func GetStationZones() map[string]FareZone {
+	return map[string]FareZone{
+		"Abbey Road":                        {3, 3},
+		"Abbey Wood":                        {4, 4},
+		"Acton Central":                     {3, 3},
+		"Acton Main Line":                   {3, 3},
+		"Acton Town":                        {3, 3},
+		"Aldgate":                           {1, 1},
+		"Aldgate East":                      {1, 1},
+		"All Saints":                        {2, 2},
+		"Alperton":                          {4, 4},
+		"Amersham":                          {9, 9},
+		"Anerley":                           {4, 4},
+		"Angel":                             {1, 1},
+		"Archway":                           {2, 3},
+		"Arnos Grove":                       {4, 4},
+		"Arsenal":                           {2, 2},
+		"Baker Street":                      {1, 1},
+		"Balham":                            {3, 3},
+		"Bank":                              {1, 1},
+		"Barbican":                          {1, 1},
+		"Barking":                           {4, 4},
+		"Barking Riverside":                 {4, 4},
+		"Barkingside":                       {4, 4},
+		"Barons Court":                      {2, 2},
+		"Battersea Power Station":           {1, 1},
+		"Bayswater":                         {1, 1},
+		"Beckenham Junction":                {4, 4},
+		"Beckton":                           {3, 3},
+		"Beckton Park":                      {3, 3},
+		"Becontree":                         {5, 5},
+		"Belsize Park":                      {2, 2},
+		"Bermondsey":                        {2, 2},
+		"Bethnal Green (Central)":           {2, 2},
+		"Bethnal Green (Overground)":        {2, 2},
+		"Blackfriars":                       {1, 1},
+		"Blackhorse Road":                   {3, 3},
+		"Blackwall":                         {2, 2},
+		"Bond Street":                       {1, 1},
+		"Borough":                           {1, 1},
+		"Boston Manor":                      {4, 4},
+		"Bounds Green":                      {3, 4},
+		"Bow Church":                        {2, 2},
+		"Bow Road":                          {2, 2},
+		"Brent Cross":                       {3, 3},
+		"Brixton":                           {2, 2},
+		"Brockley":                          {2, 2},
+		"Bromley-by-Bow":                    {2, 3},
+		"Brondesbury":                       {2, 2},
+		"Brondesbury Park":                  {2, 2},
+		"Bruce Grove":                       {3, 3},
+		"Buckhurst Hill":                    {5, 5},
+		"Burnt Oak":                         {4, 4},
+		"Bush Hill Park":                    {5, 5},
+		"Bushey":                            {8, 8},
+		"Caledonian Road":                   {2, 2},
+		"Caledonian Road & Barnsbury":       {2, 2},
+		"Cambridge Heath":                   {2, 2},
+		"Camden Road":                       {2, 2},
+		"Camden Town":                       {2, 2},
+		"Canada Water":                      {2, 2},
+		"Canary Wharf":                      {2, 2},
+		"Canning Town":                      {2, 3},
+		"Cannon Street":                     {1, 1},
+		"Canonbury":                         {2, 2},
+		"Canons Park":                       {5, 5},
+		"Carpenders Park":                   {7, 7},
+		"Chadwell Heath":                    {5, 5},
+		"Chalfont & Latimer":                {8, 8},
+		"Chalk Farm":                        {2, 2},
+		"Chancery Lane":                     {1, 1},
+		"Charing Cross":                     {1, 1},
+		"Chesham":                           {9, 9},
+		"Cheshunt":                          {8, 8},
+		"Chigwell":                          {4, 4},
+		"Chingford":                         {5, 5},
+		"Chiswick Park":                     {3, 3},
+		"Chorleywood":                       {8, 8},
+		"Clapham Common":                    {2, 2},
+		"Clapham High Street":               {2, 2},
+		"Clapham Junction":                  {2, 2},
+		"Clapham North":                     {2, 2},
+		"Clapham South":                     {2, 3},
+		"Clapton":                           {2, 2},
+		"Cockfosters":                       {5, 5},
+		"Colindale":                         {4, 4},
+		"Colliers Wood":                     {3, 3},
+		"Covent Garden":                     {1, 1},
+		"Crossharbour":                      {2, 2},
+		"Crouch Hill":                       {3, 3},
+		"Croxley":                           {7, 7},
+		"Crystal Palace":                    {3, 4},
+		"Custom House for ExCeL":            {3, 3},
+		"Cutty Sark for Maritime Greenwich": {2, 3},
+		"Cyprus":                            {3, 3},
+		"Dagenham East":                     {5, 5},
+		"Dagenham Heathway":                 {5, 5},
+		"Dalston Junction":                  {2, 2},
+		"Dalston Kingsland":                 {2, 2},
+		"Debden":                            {6, 6},
+		"Denmark Hill":                      {2, 2},
+		"Deptford Bridge":                   {2, 3},
+		"Devons Road":                       {2, 2},
+		"Dollis Hill":                       {3, 3},
+		"Ealing Broadway":                   {3, 3},
+		"Ealing Common":                     {3, 3},
+		"Earl's Court":                      {1, 2},
+		"East Acton":                        {2, 2},
+		"East Croydon":                      {5, 5},
+		"East Finchley":                     {3, 3},
+		"East Ham":                          {3, 4},
+		"East India":                        {2, 3},
+		"East Putney":                       {2, 3},
+		"Eastcote":                          {5, 5},
+		"Edgware":                           {5, 5},
+		"Edgware Road":                      {1, 1},
+		"Edmonton Green":                    {4, 4},
+		"Elephant & Castle":                 {1, 2},
+		"Elm Park":                          {6, 6},
+		"Elmers End":                        {4, 4},
+		"Elverson Road":                     {2, 3},
+		"Embankment":                        {1, 1},
+		"Emerson Park":                      {6, 6},
+		"Enfield Town":                      {5, 5},
+		"Epping":                            {6, 6},
+		"Euston":                            {1, 1},
+		"Euston Square":                     {1, 1},
+		"Fairlop":                           {4, 4},
+		"Farringdon":                        {1, 1},
+		"Finchley Central":                  {4, 4},
+		"Finchley Road":                     {2, 2},
+		"Finchley Road & Frognal":           {2, 2},
+		"Finsbury Park":                     {2, 2},
+		"Forest Gate":                       {3, 3},
+		"Forest Hill":                       {3, 3},
+		"Fulham Broadway":                   {2, 2},
+		"Gallions Reach":                    {3, 3},
+		"Gants Hill":                        {4, 4},
+		"Gidea Park":                        {6, 6},
+		"Gloucester Road":                   {1, 1},
+		"Golders Green":                     {3, 3},
+		"Goldhawk Road":                     {2, 2},
+		"Goodge Street":                     {1, 1},
+		"Goodmayes":                         {4, 4},
+		"Gospel Oak":                        {2, 2},
+		"Grange Hill":                       {4, 4},
+		"Great Portland Street":             {1, 1},
+		"Green Park":                        {1, 1},
+		"Greenford":                         {4, 4},
+		"Greenwich":                         {2, 3},
+		"Gunnersbury":                       {3, 3},
+		"Hackney Central":                   {2, 2},
+		"Hackney Downs":                     {2, 2},
+		"Hackney Wick":                      {2, 2},
+		"Haggerston":                        {2, 2},
+		"Hainault":                          {4, 4},
+		"Hammersmith":                       {2, 2},
+		"Hampstead":                         {2, 3},
+		"Hampstead Heath":                   {2, 2},
+		"Hanger Lane":                       {3, 3},
+		"Hanwell":                           {4, 4},
+		"Harlesden":                         {3, 3},
+		"Harold Wood":                       {6, 6},
+		"Harringay Green Lanes":             {3, 3},
+		"Harrow & Wealdstone":               {5, 5},
+		"Harrow-on-the-Hill":                {5, 5},
+		"Hatch End":                         {6, 6},
+		"Hatton Cross":                      {5, 6},
+		"Hayes & Harlington":                {5, 5},
+		"Headstone Lane":                    {5, 5},
+		"Heathrow Terminal 4":               {6, 6},
+		"Heathrow Terminal 5":               {6, 6},
+		"Heathrow Terminals 2 & 3":          {6, 6},
+		"Heathrow Terminals 4":              {6, 6},
+		"Hendon Central":                    {3, 4},
+		"Heron Quays":                       {2, 2},
+		"High Barnet":                       {5, 5},
+		"High Street Kensington":            {1, 1},
+		"Highams Park":                      {4, 4},
+		"Highbury & Islington":              {2, 2},
+		"Highgate":                          {3, 3},
+		"Hillingdon":                        {6, 6},
+		"Holborn":                           {1, 1},
+		"Holland Park":                      {2, 2},
+		"Holloway Road":                     {2, 2},
+		"Homerton":                          {2, 2},
+		"Honor Oak Park":                    {3, 3},
+		"Hornchurch":                        {6, 6},
+		"Hounslow Central":                  {4, 4},
+		"Hounslow East":                     {4, 4},
+		"Hounslow West":                     {5, 5},
+		"Hoxton":                            {1, 2},
+		"Hyde Park Corner":                  {1, 1},
+		"Ickenham":                          {6, 6},
+		"Ilford":                            {4, 4},
+		"Imperial Wharf":                    {2, 2},
+		"Island Gardens":                    {2, 2},
+		"Kennington":                        {1, 2},
+		"Kensal Green":                      {2, 2},
+		"Kensal Rise":                       {2, 2},
+		"Kensington (Olympia)":              {2, 2},
+		"Kentish Town":                      {2, 2},
+		"Kentish Town West":                 {2, 2},
+		"Kenton":                            {4, 4},
+		"Kew Gardens":                       {3, 4},
+		"Kilburn":                           {2, 2},
+		"Kilburn High Road":                 {2, 2},
+		"Kilburn Park":                      {2, 2},
+		"King George V":                     {3, 3},
+		"King's Cross St. Pancras":          {1, 1},
+		"Kingsbury":                         {4, 4},
+		"Knightsbridge":                     {1, 1},
+		"Ladbroke Grove":                    {2, 2},
+		"Lambeth North":                     {1, 1},
+		"Lancaster Gate":                    {1, 1},
+		"Langdon Park":                      {2, 2},
+		"Latimer Road":                      {2, 2},
+		"Leicester Square":                  {1, 1},
+		"Lewisham":                          {2, 3},
+		"Leyton":                            {3, 3},
+		"Leyton Midland Road":               {3, 3},
+		"Leytonstone":                       {3, 4},
+		"Leytonstone High Road":             {3, 3},
+		"Limehouse":                         {2, 2},
+		"Liverpool Street":                  {1, 1},
+		"London Bridge":                     {1, 1},
+		"London City Airport":               {3, 3},
+		"London Fields":                     {2, 2},
+		"Loughton":                          {6, 6},
+		"Maida Vale":                        {2, 2},
+		"Manor House":                       {2, 3},
+		"Manor Park":                        {3, 4},
+		"Mansion House":                     {1, 1},
+		"Marble Arch":                       {1, 1},
+		"Maryland":                          {3, 3},
+		"Marylebone":                        {1, 1},
+		"Mile End":                          {2, 2},
+		"Mill Hill East":                    {4, 4},
+		"Mitcham Junction":                  {4, 4},
+		"Monument":                          {1, 1},
+		"Moor Park":                         {6, 7},
+		"Moorgate":                          {1, 1},
+		"Morden":                            {4, 4},
+		"Mornington Crescent":               {2, 2},
+		"Mudchute":                          {2, 2},
+		"Neasden":                           {3, 3},
+		"New Cross":                         {2, 2},
+		"New Cross Gate":                    {2, 2},
+		"Newbury Park":                      {4, 4},
+		"Nine Elms":                         {1, 1},
+		"North Acton":                       {2, 3},
+		"North Ealing":                      {3, 3},
+		"North Greenwich":                   {2, 3},
+		"North Harrow":                      {5, 5},
+		"North Wembley":                     {4, 4},
+		"Northfields":                       {3, 3},
+		"Northolt":                          {5, 5},
+		"Northwick Park":                    {4, 4},
+		"Northwood":                         {6, 6},
+		"Northwood Hills":                   {6, 6},
+		"Norwood Junction":                  {4, 4},
+		"Notting Hill Gate":                 {1, 2},
+		"Oakwood":                           {5, 5},
+		"Old Street":                        {1, 1},
+		"Osterley":                          {4, 4},
+		"Oval":                              {2, 2},
+		"Oxford Circus":                     {1, 1},
+		"Paddington":                        {1, 1},
+		"Park Royal":                        {3, 3},
+		"Parsons Green":                     {2, 2},
+		"Peckham Rye":                       {2, 2},
+		"Penge West":                        {4, 4},
+		"Perivale":                          {4, 4},
+		"Piccadilly Circus":                 {1, 1},
+		"Pimlico":                           {1, 1},
+		"Pinner":                            {5, 5},
+		"Plaistow":                          {3, 3},
+		"Pontoon Dock":                      {3, 3},
+		"Poplar":                            {2, 2},
+		"Preston Road":                      {4, 4},
+		"Prince Regent":                     {3, 3},
+		"Pudding Mill Lane":                 {2, 3},
+		"Putney Bridge":                     {2, 2},
+		"Queen's Park":                      {2, 2},
+		"Queens Road Peckham":               {2, 2},
+		"Queensbury":                        {4, 4},
+		"Queensway":                         {1, 1},
+		"Ravenscourt Park":                  {2, 2},
+		"Rayners Lane":                      {5, 5},
+		"Rectory Road":                      {2, 2},
+		"Redbridge":                         {4, 4},
+		"Regent's Park":                     {1, 1},
+		"Richmond":                          {4, 4},
+		"Rickmansworth":                     {7, 7},
+		"Roding Valley":                     {5, 5},
+		"Romford":                           {6, 6},
+		"Rotherhithe":                       {2, 2},
+		"Royal Albert":                      {3, 3},
+		"Royal Oak":                         {2, 2},
+		"Royal Victoria":                    {3, 3},
+		"Ruislip":                           {6, 6},
+		"Ruislip Gardens":                   {5, 5},
+		"Ruislip Manor":                     {6, 6},
+		"Russell Square":                    {1, 1},
+		"Seven Kings":                       {4, 4},
+		"Seven Sisters":                     {3, 3},
+		"Shadwell":                          {2, 2},
+		"Shepherd's Bush":                   {2, 2},
+		"Shepherd's Bush Market":            {2, 2},
+		"Shoreditch High Street":            {1, 1},
+		"Silver Street":                     {4, 4},
+		"Sloane Square":                     {1, 1},
+		"Snaresbrook":                       {4, 4},
+		"South Acton":                       {3, 3},
+		"South Ealing":                      {3, 3},
+		"South Hampstead":                   {2, 2},
+		"South Harrow":                      {5, 5},
+		"South Kensington":                  {1, 1},
+		"South Kenton":                      {4, 4},
+		"South Quay":                        {2, 2},
+		"South Ruislip":                     {5, 5},
+		"South Tottenham":                   {3, 3},
+		"South Wimbledon":                   {3, 4},
+		"South Woodford":                    {4, 4},
+		"Southall":                          {4, 4},
+		"Southbury":                         {5, 5},
+		"Southfields":                       {3, 3},
+		"Southgate":                         {4, 4},
+		"Southwark":                         {1, 1},
+		"St. James Street":                  {3, 3},
+		"St. James's Park":                  {1, 1},
+		"St. John's Wood":                   {2, 2},
+		"St. Paul's":                        {1, 1},
+		"Stamford Hill":                     {3, 3},
+		"Stamford Park":                     {2, 2},
+		"Stanmore":                          {5, 5},
+		"Star Lane":                         {3, 3},
+		"Stepney Green":                     {2, 2},
+		"Stockwell":                         {2, 2},
+		"Stoke Newington":                   {2, 2},
+		"Stonebridge Park":                  {3, 3},
+		"Stratford":                         {2, 3},
+		"Stratford High Street":             {2, 3},
+		"Stratford International":           {2, 3},
+		"Sudbury Hill":                      {4, 4},
+		"Sudbury Town":                      {4, 4},
+		"Surrey Quays":                      {2, 2},
+		"Swiss Cottage":                     {2, 2},
+		"Sydenham":                          {3, 3},
+		"Temple":                            {1, 1},
+		"Theobalds Grove":                   {7, 7},
+		"Theydon Bois":                      {6, 6},
+		"Tooting Bec":                       {3, 3},
+		"Tooting Broadway":                  {3, 3},
+		"Tottenham Court Road":              {1, 1},
+		"Tottenham Hale":                    {3, 3},
+		"Totteridge & Whetstone":            {4, 4},
+		"Tower Gateway":                     {1, 1},
+		"Tower Hill":                        {1, 1},
+		"Tufnell Park":                      {2, 2},
+		"Turkey Street":                     {6, 6},
+		"Turnham Green":                     {2, 3},
+		"Turnpike Lane":                     {3, 3},
+		"Upminster":                         {6, 6},
+		"Upminster Bridge":                  {6, 6},
+		"Upney":                             {4, 4},
+		"Upper Holloway":                    {2, 2},
+		"Upton Park":                        {3, 3},
+		"Uxbridge":                          {6, 6},
+		"Vauxhall":                          {1, 2},
+		"Victoria":                          {1, 1},
+		"Walthamstow Central":               {3, 3},
+		"Walthamstow Queen's Road":          {3, 3},
+		"Wandsworth Road":                   {2, 2},
+		"Wanstead":                          {4, 4},
+		"Wanstead Park":                     {3, 3},
+		"Wapping":                           {2, 2},
+		"Warren Street":                     {1, 1},
+		"Warwick Avenue":                    {2, 2},
+		"Waterloo":                          {1, 1},
+		"Watford":                           {7, 7},
+		"Watford High Street":               {8, 8},
+		"Wembley Central":                   {4, 4},
+		"Wembley Park":                      {4, 4},
+		"West Acton":                        {3, 3},
+		"West Brompton":                     {2, 2},
+		"West Croydon":                      {5, 5},
+		"West Drayton":                      {6, 6},
+		"West Ealing":                       {3, 3},
+		"West Finchley":                     {4, 4},
+		"West Ham":                          {2, 3},
+		"West Hampstead":                    {2, 2},
+		"West Harrow":                       {5, 5},
+		"West India Quay":                   {2, 2},
+		"West Kensington":                   {2, 2},
+		"West Ruislip":                      {6, 6},
+		"West Silvertown":                   {3, 3},
+		"Westbourne Park":                   {2, 2},
+		"Westferry":                         {2, 2},
+		"Westminster":                       {1, 1},
+		"White City":                        {2, 2},
+		"White Hart Lane":                   {3, 3},
+		"Whitechapel":                       {2, 2},
+		"Willesden Green":                   {2, 3},
+		"Willesden Junction":                {2, 3},
+		"Wimbledon":                         {3, 3},
+		"Wimbledon Park":                    {3, 3},
+		"Wood Green":                        {3, 3},
+		"Wood Lane":                         {2, 2},
+		"Wood Street":                       {4, 4},
+		"Woodford":                          {4, 4},
+		"Woodgrange Park":                   {3, 4},
+		"Woodside Park":                     {4, 4},
+		"Woolwich":                          {4, 4},
+		"Woolwich Arsenal":                  {4, 4},
+	}
+}
+
+// Return the adult pay as you go fares for rail journeys by the range of
+// zones travelled in, approximating TfL's. Tram journeys are charged a flat
+// fare of their own (see tramFare in fares.go) instead.
+func GetZoneFares() []ZoneFare {
+	return []ZoneFare{
+		{true, 1, 290, 280},
+		{true, 2, 350, 290},
+		{true, 3, 390, 310},
+		{true, 4, 470, 330},
+		{true, 5, 560, 360},
+		{true, 6, 610, 380},
+		{true, 7, 670, 440},
+		{true, 8, 780, 440},
+		{true, 9, 800, 450},
+		{false, 1, 210, 200},
+		{false, 2, 210, 200},
+		{false, 3, 260, 210},
+		{false, 4, 300, 220},
+		{false, 5, 360, 230},
+		{false, 6, 380, 240},
+		{false, 7, 400, 250},
+		{false, 8, 420, 260},
+	}
+}
@@ -0,0 +1,198 @@
+package transit
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Longest plausible times in minutes for a rail link between neighbouring
+// stations and for an interchange, beyond which the data is most likely
+// mistyped
+const (
+	maxPlausibleRailMinutes        = 30
+	maxPlausibleInterchangeMinutes = 20
+)
+
+// Fastest plausible average speed of a train between neighbouring stations
+// in km/h, judged from their coordinates where known
+const maxPlausibleRailKMH = 120
+
+// Names the checks ValidateGraph makes of a transit graph
+type GraphCheck string
+
+const (
+	CheckDisconnected GraphCheck = "disconnected"
+	CheckAsymmetric   GraphCheck = "asymmetric"
+	CheckTravelTime   GraphCheck = "travel time"
+	CheckInterchange  GraphCheck = "interchange"
+	CheckOrphan       GraphCheck = "orphan"
+	// Checked by CheckStationOverrides rather than ValidateGraph
+	CheckOverride GraphCheck = "station override"
+)
+
+// Represents a problem found in a transit graph by ValidateGraph, which
+// makes searches of the graph go wrong unless it is only a Warning, in which
+// case they go by assumptions which may not hold
+type GraphIssue struct {
+	Check   GraphCheck
+	Message string
+	Warning bool
+}
+
+// Describe the specified Node for a GraphIssue
+func describeNode(node *Node) string {
+	return fmt.Sprintf("%s (%s)", node.station, node.line)
+}
+
+// Check the specified graph for problems which would make searches of it
+// silently go wrong, returning every one found, sorted by check:
+//   - parts of the network which cannot be reached from the rest of it,
+//   - links with no matching link back, as every connection runs both ways,
+//   - rail links taking no time, or implausibly long or (by the coordinates
+//     given, which may be empty) implausibly fast, and interchanges taking
+//     implausibly long,
+//   - stations where lines meet with no interchange between them, so trips
+//     cannot change lines there, or only one assumed for lack of data (see
+//     GraphOptions.AssumedInterchangeMinutes), either of which is a
+//     Warning,
+//   - lines at a station which no rail link serves.
+func ValidateGraph(nodeMap NodeMap, coords map[string]Coordinates) []GraphIssue {
+	issues := make([]GraphIssue, 0)
+	nodes := make([]*Node, 0)
+	for _, station := range slices.Sorted(maps.Keys(nodeMap)) {
+		for _, line := range slices.Sorted(maps.Keys(nodeMap[station])) {
+			nodes = append(nodes, nodeMap[station][line])
+		}
+	}
+
+	// Links should come in pairs, one each way with the same time and mode
+	type linkKey struct {
+		from, to *Node
+		time     uint16
+		mode     LinkMode
+	}
+	linkCounts := make(map[linkKey]int)
+	for _, node := range nodes {
+		for _, link := range node.adj {
+			linkCounts[linkKey{node, link.endNode, link.time, link.attrs.Mode}]++
+		}
+	}
+	for _, node := range nodes {
+		for _, link := range node.adj {
+			key := linkKey{node, link.endNode, link.time, link.attrs.Mode}
+			back := linkCounts[linkKey{link.endNode, node, link.time, link.attrs.Mode}]
+			if linkCounts[key] > back {
+				issues = append(issues, GraphIssue{Check: CheckAsymmetric, Message: fmt.Sprintf(
+					"%s link from %s to %s taking %d minutes has no matching link back",
+					link.attrs.Mode, describeNode(node), describeNode(link.endNode), link.time)})
+				linkCounts[key]--
+			}
+		}
+	}
+
+	for _, node := range nodes {
+		for _, link := range node.adj {
+			// Report each connection once, from the end sorting first
+			if compareNodeIDs(node.ID(), link.endNode.ID()) > 0 {
+				continue
+			}
+			from, to := describeNode(node), describeNode(link.endNode)
+			var problem string
+			switch {
+			case link.attrs.Mode != ModeRail:
+				if link.time > maxPlausibleInterchangeMinutes {
+					problem = fmt.Sprintf("%s between %s and %s takes %d minutes, longer than %d",
+						link.attrs.Mode, from, to, link.time, maxPlausibleInterchangeMinutes)
+				}
+			case link.time == 0:
+				problem = fmt.Sprintf("rail link between %s and %s takes no time", from, to)
+			case link.time > maxPlausibleRailMinutes:
+				problem = fmt.Sprintf("rail link between %s and %s takes %d minutes, longer than %d",
+					from, to, link.time, maxPlausibleRailMinutes)
+			default:
+				a, knownA := coords[node.station]
+				b, knownB := coords[link.endNode.station]
+				if !knownA || !knownB {
+					break
+				}
+				if kmh := DistanceKM(a, b) / float64(link.time) * 60; kmh > maxPlausibleRailKMH {
+					problem = fmt.Sprintf("rail link between %s and %s takes %d minutes, %.0f km/h on average",
+						from, to, link.time, kmh)
+				}
+			}
+			if problem != "" {
+				issues = append(issues, GraphIssue{Check: CheckTravelTime, Message: problem})
+			}
+		}
+	}
+
+	for _, station := range slices.Sorted(maps.Keys(nodeMap)) {
+		lines := slices.Sorted(maps.Keys(nodeMap[station]))
+		for i, lineA := range lines {
+			for _, lineB := range lines[i+1:] {
+				nodeA, nodeB := nodeMap[station][lineA], nodeMap[station][lineB]
+				linkIdx := slices.IndexFunc(nodeA.adj, func(link *Link) bool { return link.endNode == nodeB })
+				switch {
+				case linkIdx < 0:
+					issues = append(issues, GraphIssue{Check: CheckInterchange, Message: fmt.Sprintf(
+						"no interchange between the %s and %s lines at %s, so trips cannot change there",
+						lineA, lineB, station), Warning: true})
+				case nodeA.adj[linkIdx].attrs.Assumed:
+					issues = append(issues, GraphIssue{Check: CheckInterchange, Message: fmt.Sprintf(
+						"interchange between the %s and %s lines at %s is assumed to take %d minutes, for lack of data",
+						lineA, lineB, station, nodeA.adj[linkIdx].time), Warning: true})
+				}
+			}
+		}
+	}
+
+	orphans := make(map[*Node]bool)
+	for _, node := range nodes {
+		if !slices.ContainsFunc(node.adj, func(link *Link) bool { return link.attrs.Mode == ModeRail }) {
+			orphans[node] = true
+			issues = append(issues, GraphIssue{Check: CheckOrphan, Message: fmt.Sprintf(
+				"no rail link serves the %s line at %s", node.line, node.station)})
+		}
+	}
+
+	// Group the Nodes into the parts of the network joined by links in either
+	// direction, leaving out those already reported as served by no train
+	component := make(map[*Node]int)
+	components := make([][]*Node, 0)
+	for _, node := range nodes {
+		if _, seen := component[node]; seen || orphans[node] {
+			continue
+		}
+		idx := len(components)
+		members, stack := make([]*Node, 0), []*Node{node}
+		component[node] = idx
+		for len(stack) > 0 {
+			cur := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			members = append(members, cur)
+			for _, link := range cur.adj {
+				if _, seen := component[link.endNode]; !seen {
+					component[link.endNode] = idx
+					stack = append(stack, link.endNode)
+				}
+			}
+		}
+		components = append(components, members)
+	}
+	slices.SortStableFunc(components, func(a, b []*Node) int { return cmp.Compare(len(b), len(a)) })
+	for _, members := range components[min(1, len(components)):] {
+		names := make([]string, len(members))
+		for idx, node := range members {
+			names[idx] = describeNode(node)
+		}
+		slices.Sort(names)
+		issues = append(issues, GraphIssue{Check: CheckDisconnected, Message: fmt.Sprintf(
+			"%s cannot be reached from the rest of the network", strings.Join(names, ", "))})
+	}
+
+	slices.SortStableFunc(issues, func(a, b GraphIssue) int { return cmp.Compare(a.Check, b.Check) })
+	return issues
+}
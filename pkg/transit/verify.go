@@ -0,0 +1,182 @@
+package transit
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Represents a journey planned by the TfL Journey Planner, reduced to what
+// is compared against the local route: its duration and the lines ridden
+type TfLJourney struct {
+	Minutes uint16
+	Lines   []string
+}
+
+// Represents the comparison of a locally planned route between two stations
+// with the TfL Journey Planner's for the same trip
+type Verification struct {
+	From         string
+	To           string
+	LocalMinutes uint16
+	LocalLines   []string
+	TfL          TfLJourney
+}
+
+// Return the lines ridden along the specified journey, in order, without
+// repeating a line ridden twice in a row
+func JourneyLines(journey *Journey) []string {
+	lines := make([]string, 0)
+	for _, leg := range journey.Legs {
+		if leg.Type == string(ModeRail) && (len(lines) == 0 || lines[len(lines)-1] != leg.Line) {
+			lines = append(lines, leg.Line)
+		}
+	}
+	return lines
+}
+
+// Plan the trip between the specified stations with the TfL Journey Planner,
+// setting off at the specified time, and return the fastest journey it
+// suggests by the modes in the transit data
+func FetchTfLJourney(client *http.Client, start, dest string, departAt time.Time) (TfLJourney, error) {
+	ids := make([]string, 0, 2)
+	for _, station := range []string{start, dest} {
+		stopPoint, found, err := searchTfLStopPoint(client, station)
+		if err != nil {
+			return TfLJourney{}, err
+		} else if !found {
+			return TfLJourney{}, fmt.Errorf("TfL has no stop point matching %s", station)
+		}
+		ids = append(ids, stopPoint.ID)
+	}
+
+	london := departAt.In(londonTime())
+	query := url.Values{"mode": {tflStopPointModes}, "timeIs": {"Departing"},
+		"date": {london.Format("20060102")}, "time": {london.Format("1504")}}
+	if key := os.Getenv("TFL_APP_KEY"); key != "" {
+		query.Set("app_key", key)
+	}
+	reqURL := fmt.Sprintf("%s/Journey/JourneyResults/%s/to/%s?%s", tflAPIBase,
+		url.PathEscape(ids[0]), url.PathEscape(ids[1]), query.Encode())
+	resp, err := client.Get(reqURL)
+	if err != nil {
+		return TfLJourney{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return TfLJourney{}, fmt.Errorf("TfL API returned %s", resp.Status)
+	}
+	var result struct {
+		Journeys []struct {
+			Duration int `json:"duration"`
+			Legs     []struct {
+				Mode struct {
+					ID string `json:"id"`
+				} `json:"mode"`
+				RouteOptions []struct {
+					Name           string `json:"name"`
+					LineIdentifier struct {
+						ID string `json:"id"`
+					} `json:"lineIdentifier"`
+				} `json:"routeOptions"`
+			} `json:"legs"`
+		} `json:"journeys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return TfLJourney{}, err
+	}
+	if len(result.Journeys) == 0 {
+		return TfLJourney{}, fmt.Errorf("TfL found no journey from %s to %s", start, dest)
+	}
+
+	fastest := result.Journeys[0]
+	for _, journey := range result.Journeys[1:] {
+		if journey.Duration < fastest.Duration {
+			fastest = journey
+		}
+	}
+	tflJourney := TfLJourney{Minutes: uint16(min(max(fastest.Duration, 0), math.MaxUint16)),
+		Lines: make([]string, 0)}
+	for _, leg := range fastest.Legs {
+		if leg.Mode.ID == "walking" || len(leg.RouteOptions) == 0 {
+			continue
+		}
+		option := leg.RouteOptions[0]
+		line, renamed := tflLineNames[option.LineIdentifier.ID]
+		if !renamed {
+			line = option.Name
+		}
+		if len(tflJourney.Lines) == 0 || tflJourney.Lines[len(tflJourney.Lines)-1] != line {
+			tflJourney.Lines = append(tflJourney.Lines, line)
+		}
+	}
+	return tflJourney, nil
+}
+
+// Return the time zone the TfL Journey Planner takes times in, falling back
+// on UTC where the time zone database is missing
+func londonTime() *time.Location {
+	if location, err := time.LoadLocation("Europe/London"); err == nil {
+		return location
+	}
+	return time.UTC
+}
+
+// Return a description of each way the local route differs from TfL's: a
+// travel time differing by more than the specified tolerance in minutes, or
+// different lines ridden
+func (v Verification) Discrepancies(tolerance uint16) []string {
+	discrepancies := make([]string, 0)
+	if diff := int(v.LocalMinutes) - int(v.TfL.Minutes); diff > int(tolerance) || -diff > int(tolerance) {
+		discrepancies = append(discrepancies, fmt.Sprintf("takes %d minutes here but %d on TfL (%+d)",
+			v.LocalMinutes, v.TfL.Minutes, diff))
+	}
+	if !slices.Equal(v.LocalLines, v.TfL.Lines) {
+		discrepancies = append(discrepancies, fmt.Sprintf("rides %s here but %s on TfL",
+			describeLines(v.LocalLines), describeLines(v.TfL.Lines)))
+	}
+	return discrepancies
+}
+
+// Return the specified lines joined for a discrepancy, e.g.
+// "Victoria, Northern"
+func describeLines(lines []string) string {
+	if len(lines) == 0 {
+		return "no lines"
+	}
+	return strings.Join(lines, ", ")
+}
+
+// Read the station pairs to verify from a CSV file with one "from,to"
+// record per line. Lines starting with "#" are comments.
+func LoadStationPairs(r io.Reader, nodeMap NodeMap) ([][2]string, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = 2
+	reader.TrimLeadingSpace = true
+	pairs := make([][2]string, 0)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		lineNum, _ := reader.FieldPos(0)
+		for _, station := range record {
+			if _, exists := nodeMap[strings.TrimSpace(station)]; !exists {
+				return nil, fmt.Errorf("line %d: %s is not a valid station", lineNum, station)
+			}
+		}
+		pairs = append(pairs, [2]string{strings.TrimSpace(record[0]), strings.TrimSpace(record[1])})
+	}
+	return pairs, nil
+}
@@ -0,0 +1,69 @@
+package transit
+
+import (
+	"maps"
+	"math"
+	"slices"
+)
+
+// Walking pace along the street in metres per minute, about 4.8 km/h
+const walkMetresPerMinute = 80
+
+// Ratio of the distance walked along streets to the straight-line distance
+// between two stations
+const walkDetourFactor = 1.3
+
+// Minutes allowed on top of the walk itself for leaving one station and
+// entering the other
+const walkStationAllowance = 2
+
+// Return the time in minutes assumed for walking between stations the
+// specified straight-line distance apart
+func walkingTime(km float64) uint16 {
+	walk := math.Ceil(km * 1000 * walkDetourFactor / walkMetresPerMinute)
+	return uint16(min(walk+walkStationAllowance, math.MaxUint16))
+}
+
+// Add a station interchange on foot, timed from the distance between them,
+// between every line of every pair of stations within the specified radius
+// in metres of each other, by the specified coordinates, for which the
+// transit data defines no interchange at all. Each is marked as assumed so
+// that journeys relying on one can be flagged. Stations and lines are
+// visited in sorted order so the graph comes out the same on every run.
+func AddWalkingInterchanges(npq *NodePriorityQueue, nodeMap NodeMap, coords map[string]Coordinates,
+	radiusMetres float64) {
+	if radiusMetres <= 0 {
+		return
+	}
+	stations := make([]string, 0, len(coords))
+	for station := range coords {
+		if _, exists := nodeMap[station]; exists {
+			stations = append(stations, station)
+		}
+	}
+	slices.Sort(stations)
+	linked := func(stationA, stationB string) bool {
+		for _, node := range nodeMap[stationA] {
+			if slices.ContainsFunc(node.adj, func(link *Link) bool {
+				return link.endNode.station == stationB
+			}) {
+				return true
+			}
+		}
+		return false
+	}
+	for i, stationA := range stations {
+		for _, stationB := range stations[i+1:] {
+			km := DistanceKM(coords[stationA], coords[stationB])
+			if km*1000 > radiusMetres || linked(stationA, stationB) {
+				continue
+			}
+			for _, lineA := range slices.Sorted(maps.Keys(nodeMap[stationA])) {
+				for _, lineB := range slices.Sorted(maps.Keys(nodeMap[stationB])) {
+					ic := Interchange{stationA, lineA, stationB, lineB, walkingTime(km)}
+					AddConnection(npq, nodeMap, &ic, LinkAttributes{Mode: ModeStationInterchange, Assumed: true})
+				}
+			}
+		}
+	}
+}
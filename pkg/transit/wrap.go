@@ -0,0 +1,140 @@
+package transit
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Narrowest output width accepted by --width, and the width at or below
+// which directions switch to their compact wording
+const (
+	MinOutputWidth     = 16
+	compactOutputWidth = 40
+)
+
+// Return the number of columns the specified text takes up on a terminal,
+// leaving out any ANSI escape codes (see ColourLineName)
+func displayWidth(s string) int {
+	width, escaped := 0, false
+	for _, r := range s {
+		switch {
+		case r == '\x1b':
+			escaped = true
+		case escaped:
+			escaped = r != 'm'
+		default:
+			width++
+		}
+	}
+	return width
+}
+
+// Return the ANSI escape codes in the specified text which are still in
+// effect at its end, i.e. those since it last reset them
+func openEscapes(s string) string {
+	if idx := strings.LastIndex(s, ansiReset); idx >= 0 {
+		s = s[idx+len(ansiReset):]
+	}
+	var open strings.Builder
+	for {
+		start := strings.IndexByte(s, '\x1b')
+		if start < 0 {
+			return open.String()
+		}
+		end := strings.IndexByte(s[start:], 'm')
+		if end < 0 {
+			return open.String()
+		}
+		open.WriteString(s[start : start+end+1])
+		s = s[start+end+1:]
+	}
+}
+
+// Split the specified word after the specified number of columns, keeping
+// any ANSI escape codes whole
+func splitWord(word string, columns int) (string, string) {
+	width, escaped := 0, false
+	for idx, r := range word {
+		switch {
+		case r == '\x1b':
+			escaped = true
+		case escaped:
+			escaped = r != 'm'
+		case width == columns:
+			return word[:idx], word[idx:]
+		default:
+			width++
+		}
+	}
+	return word, ""
+}
+
+// Word-wrap a single line of directions to the specified width. Continuation
+// lines are indented to line up with the text after the line's "N) " or "- "
+// prefix, or with the line's own indentation, and words too long to fit on a
+// line of their own are split. Widths leave out ANSI escape codes.
+func WrapLine(line string, width int) []string {
+	if displayWidth(line) <= width {
+		return []string{line}
+	}
+	leading := utf8.RuneCountInString(line) - utf8.RuneCountInString(strings.TrimLeft(line, " "))
+	indent := leading
+	if prefix, _, found := strings.Cut(line, " "); found &&
+		(prefix == "-" || strings.HasSuffix(prefix, ")")) {
+		indent = utf8.RuneCountInString(prefix) + 1
+	}
+	if indent > width/2 {
+		indent, leading = 0, 0
+	}
+
+	// Colours still on at the end of a line are turned off there, and on
+	// again for the next word, so that the indent is left uncoloured
+	wrapped := make([]string, 0)
+	breakLine := func(text string) string {
+		open := openEscapes(text)
+		if open != "" {
+			text += ansiReset
+		}
+		wrapped = append(wrapped, text)
+		return open
+	}
+	current := strings.Repeat(" ", leading)
+	for _, word := range strings.Fields(line) {
+		for {
+			lineLen, wordLen := displayWidth(current), displayWidth(word)
+			if current == "" || current == strings.Repeat(" ", indent) {
+				// Start of a line: split the word if even that won't fit
+				if lineLen+wordLen <= width {
+					current += word
+					break
+				}
+				head, tail := splitWord(word, width-lineLen)
+				word = breakLine(current+head) + tail
+				current = strings.Repeat(" ", indent)
+				continue
+			}
+			if lineLen+1+wordLen <= width {
+				current += " " + word
+				break
+			}
+			word = breakLine(current) + word
+			current = strings.Repeat(" ", indent)
+		}
+	}
+	if strings.TrimSpace(current) != "" {
+		wrapped = append(wrapped, current)
+	}
+	return wrapped
+}
+
+// Print directions for the specified trip wrapped to fit the specified
+// number of columns, using compact wording on narrow displays such as phone
+// terminals and receipt printers
+func PrintDirectionsWidth(journey *Journey, width int, detailed bool) {
+	for _, line := range DirectionLines(journey, width <= compactOutputWidth, detailed) {
+		for _, wrapped := range WrapLine(line, width) {
+			fmt.Println(wrapped)
+		}
+	}
+}
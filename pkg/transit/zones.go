@@ -0,0 +1,61 @@
+package transit
+
+// Mark every rail link between stations in different fare zones as crossing
+// a zone boundary. Stations on a boundary, which belong to two zones, only
+// count as being in a different zone from stations in neither of them, and
+// links at stations with no known zone are left unmarked. Each station's
+// Nodes are also given its zone, for estimating fares.
+func MarkZoneBoundaries(nodeMap NodeMap, zones map[string]FareZone) {
+	for station, lines := range nodeMap {
+		zoneA, knownA := zones[station]
+		for _, node := range lines {
+			node.zone = zoneA
+			for _, link := range node.adj {
+				zoneB, knownB := zones[link.endNode.station]
+				if link.attrs.Mode == ModeRail && knownA && knownB {
+					link.attrs.CrossesZoneBoundary = zoneA.high < zoneB.low || zoneB.high < zoneA.low
+				}
+			}
+		}
+	}
+}
+
+// Represents how a trip crosses the fare zones, which transport analysts
+// use as a proxy for its cost where fares are not modelled in full
+type ZoneCrossings struct {
+	// Number of zone boundaries crossed, counting each zone passed over
+	// between stations more than one zone apart
+	Boundaries int
+	// Whether the trip passes through a station only in zone 1, so that its
+	// fare is charged for zone 1 (see EstimateFare)
+	Zone1 bool
+}
+
+// Return how a trip visiting the specified Nodes, in either order, crosses
+// the fare zones. Stations on a boundary count in whichever of their zones
+// means crossing fewer boundaries, and tram stops and stations with no known
+// zone are passed over.
+func RouteZoneCrossings(route []*Node) ZoneCrossings {
+	var crossings ZoneCrossings
+	// The zones the trip could be counted as being in so far
+	var low, high uint8
+	for _, node := range route {
+		if node.line == tramLine || node.zone.low == 0 {
+			continue
+		}
+		crossings.Zone1 = crossings.Zone1 || node.zone.high == 1
+		switch {
+		case low == 0:
+			low, high = node.zone.low, node.zone.high
+		case node.zone.high < low:
+			crossings.Boundaries += int(low - node.zone.high)
+			low, high = node.zone.low, node.zone.high
+		case node.zone.low > high:
+			crossings.Boundaries += int(node.zone.low - high)
+			low, high = node.zone.low, node.zone.high
+		default:
+			low, high = max(low, node.zone.low), min(high, node.zone.high)
+		}
+	}
+	return crossings
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/maxboyko1/TubePlanner/pkg/transit"
+)
+
+// Entry point for the "places" subcommand, which prints the travel times
+// between every pair of a list of the user's places, e.g. to choose between
+// flats, offices or venues
+func RunPlacesCommand(args []string) {
+	fs := flag.NewFlagSet("places", flag.ExitOnError)
+	formatFlag := fs.String("format", "text", "output `format`: a text table, or csv")
+	geocodeFlag := fs.Bool("geocode", false, "look up places given by postcode or address instead of a station")
+	geocoderURLFlag := fs.String("geocoder-url", transit.NominatimURL, "base `URL` of the Nominatim service to look places up with")
+	zonesFlag := fs.Bool("zone-crossings", false,
+		"also give the fare zone boundaries each trip crosses and whether it goes through zone 1")
+	dataSourceFlags.register(fs)
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "USAGE: ./tubeplanner places [--format csv] [--zone-crossings] [--geocode [--geocoder-url <url>]] "+dataSourceUsage+" <places file>")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	if *formatFlag != "text" && *formatFlag != "csv" {
+		fmt.Fprintf(os.Stderr, "ERROR: Unknown output format: %s\n", *formatFlag)
+		os.Exit(1)
+	}
+	_, nodeMap := buildGraph()
+	var geocoder transit.Geocoder
+	if *geocodeFlag {
+		geocoder = transit.NewNominatimGeocoder(&http.Client{Timeout: 10 * time.Second}, *geocoderURLFlag)
+	}
+	places, err := transit.LoadPlaces(fs.Arg(0), nodeMap, geocoder)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+		os.Exit(1)
+	}
+	if len(places) < 2 {
+		fmt.Fprintln(os.Stderr, "ERROR: At least two places are needed")
+		os.Exit(1)
+	}
+	matrix, zones := transit.PlaceZoneMatrix(nodeMap, places, &transit.SearchOptions{DepartAt: time.Now()})
+
+	// With zone crossings, the CSV has one row per pair of places rather
+	// than one per origin, to fit the extra columns
+	if *formatFlag == "csv" && *zonesFlag {
+		w := csv.NewWriter(os.Stdout)
+		w.Write([]string{"from", "to", "minutes", "zoneBoundaries", "throughZone1"})
+		for i, origin := range places {
+			for j, dest := range places {
+				if i == j || matrix[i][j] == math.MaxUint16 {
+					continue
+				}
+				w.Write([]string{origin.Name, dest.Name, strconv.Itoa(int(matrix[i][j])),
+					strconv.Itoa(zones[i][j].Boundaries), strconv.FormatBool(zones[i][j].Zone1)})
+			}
+		}
+		w.Flush()
+		return
+	}
+	if *formatFlag == "csv" {
+		w := csv.NewWriter(os.Stdout)
+		header := []string{"from"}
+		for _, place := range places {
+			header = append(header, place.Name)
+		}
+		w.Write(header)
+		for i, origin := range places {
+			row := []string{origin.Name}
+			for _, minutes := range matrix[i] {
+				if minutes == math.MaxUint16 {
+					row = append(row, "")
+				} else {
+					row = append(row, strconv.Itoa(int(minutes)))
+				}
+			}
+			w.Write(row)
+		}
+		w.Flush()
+		return
+	}
+
+	fmt.Println("Places:")
+	for _, place := range places {
+		if place.DistanceKM > 0 {
+			fmt.Printf("- %s: nearest station %s (%.1f km)\n", place.Name, place.Station, place.DistanceKM)
+		} else {
+			fmt.Printf("- %s: %s\n", place.Name, place.Station)
+		}
+	}
+	fmt.Println("\nTravel times in minutes, from each row to each column:")
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprint(tw, "\t")
+	for _, place := range places {
+		fmt.Fprintf(tw, "%s\t", place.Name)
+	}
+	fmt.Fprintln(tw)
+	for i, origin := range places {
+		fmt.Fprintf(tw, "%s\t", origin.Name)
+		for j, minutes := range matrix[i] {
+			switch {
+			case i == j:
+				fmt.Fprint(tw, "-\t")
+			case minutes == math.MaxUint16:
+				fmt.Fprint(tw, "no route\t")
+			default:
+				fmt.Fprintf(tw, "%d\t", minutes)
+			}
+		}
+		fmt.Fprintln(tw)
+	}
+	tw.Flush()
+	if !*zonesFlag {
+		return
+	}
+
+	fmt.Println("\nFare zone boundaries crossed, from each row to each column (* through zone 1):")
+	tw = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprint(tw, "\t")
+	for _, place := range places {
+		fmt.Fprintf(tw, "%s\t", place.Name)
+	}
+	fmt.Fprintln(tw)
+	for i, origin := range places {
+		fmt.Fprintf(tw, "%s\t", origin.Name)
+		for j, crossings := range zones[i] {
+			switch {
+			case i == j:
+				fmt.Fprint(tw, "-\t")
+			case matrix[i][j] == math.MaxUint16:
+				fmt.Fprint(tw, "no route\t")
+			case crossings.Zone1:
+				fmt.Fprintf(tw, "%d *\t", crossings.Boundaries)
+			default:
+				fmt.Fprintf(tw, "%d\t", crossings.Boundaries)
+			}
+		}
+		fmt.Fprintln(tw)
+	}
+	tw.Flush()
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/maxboyko1/TubePlanner/pkg/transit"
+)
+
+// Set graphOptions.Area from the --bbox and --zones options, either of which may be
+// empty; when both are given, only stations selected by both are kept
+func selectGraphArea(bbox, zones string) error {
+	areas := make([]func(station string) bool, 0, 2)
+	if bbox != "" {
+		box, err := transit.ParseBoundingBox(bbox)
+		if err != nil {
+			return err
+		}
+		if len(transit.GetStationCoordinates()) == 0 {
+			return fmt.Errorf("no station coordinates are known, import them with " +
+				"\"./tubeplanner import-coords\" to use --bbox")
+		}
+		areas = append(areas, transit.BoundingBoxArea(box))
+	}
+	if zones != "" {
+		low, high, err := transit.ParseZoneRange(zones)
+		if err != nil {
+			return err
+		}
+		areas = append(areas, transit.ZoneArea(low, high))
+	}
+	switch len(areas) {
+	case 1:
+		graphOptions.Area = areas[0]
+	case 2:
+		graphOptions.Area = func(station string) bool {
+			return areas[0](station) && areas[1](station)
+		}
+	}
+	return nil
+}
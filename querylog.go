@@ -0,0 +1,75 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/maxboyko1/TubePlanner/pkg/transit"
+)
+
+// Summarize the answer given in the specified response, as its error or its
+// total time and the sequence of lines ridden, for comparing answers to the
+// same query
+func describeAnswer(response transit.JSONResponse) string {
+	if response.Journey == nil {
+		return "error: " + response.Error
+	}
+	lines := make([]string, 0)
+	for _, leg := range response.Journey.Legs {
+		switch leg.Type {
+		case string(transit.ModeRail):
+			lines = append(lines, leg.Line)
+		case string(transit.ModeStationInterchange):
+			lines = append(lines, "walk to "+leg.To)
+		}
+	}
+	if len(lines) == 0 {
+		return "already at destination"
+	}
+	return fmt.Sprintf("%d minutes via %s", response.Journey.TotalMinutes, strings.Join(lines, ", "))
+}
+
+// Entry point for the "replay" subcommand, which answers every query in a
+// query log again with the current planner and data, and reports those
+// whose answers have changed since they were logged. The queries are given
+// the same default budget as when they were logged, so that answers cut
+// short by it are not reported as changed.
+func RunReplayCommand(args []string) {
+	fs := flag.NewFlagSet("replay", flag.ExitOnError)
+	budgetFlag := fs.Duration("budget", 0,
+		"default time budget for honouring a query's preferences, as given with --budget when the log was written")
+	dataSourceFlags.register(fs)
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "USAGE: ./tubeplanner replay [--budget 50ms] "+dataSourceUsage+" <query log>")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	entries, err := transit.OpenQueryLog(fs.Arg(0)).Entries()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: Could not read query log: %v\n", err)
+		os.Exit(1)
+	}
+
+	_, nodeMap := buildGraph()
+	changed := 0
+	for idx, entry := range entries {
+		before := describeAnswer(entry.Response)
+		after := describeAnswer(transit.AnswerJSONQuery(nodeMap, entry.Query, *budgetFlag))
+		if before == after {
+			continue
+		}
+		changed++
+		fmt.Printf("%d) %s to %s (logged %s):\n", idx+1, entry.Query.From, entry.Query.To,
+			entry.LoggedAt.Local().Format("2006-01-02 15:04"))
+		fmt.Printf("- was: %s\n", before)
+		fmt.Printf("- now: %s\n", after)
+	}
+	fmt.Printf("Replayed %d queries: %d unchanged, %d changed.\n",
+		len(entries), len(entries)-changed, changed)
+}
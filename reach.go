@@ -0,0 +1,57 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/maxboyko1/TubePlanner/pkg/transit"
+)
+
+// Entry point for the "who-can-reach" subcommand, which lists every station
+// from which a target station can be reached within a time budget, soonest
+// first, e.g. to choose a venue convenient for people coming from many places
+func RunWhoCanReachCommand(args []string) {
+	fs := flag.NewFlagSet("who-can-reach", flag.ExitOnError)
+	withinFlag := fs.Uint("within", 30, "time budget in `minutes`")
+	dataSourceFlags.register(fs)
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "USAGE: ./tubeplanner who-can-reach [--within 30] "+dataSourceUsage+" <station>")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	target := fs.Arg(0)
+	_, nodeMap := buildGraph()
+	if _, exists := nodeMap[target]; !exists {
+		fmt.Fprintf(os.Stderr, "ERROR: %s is not a valid station\n", target)
+		os.Exit(1)
+	}
+	if closure, isClosed := transit.StationClosure(nodeMap, target); isClosed {
+		fmt.Fprintf(os.Stderr, "ERROR: %s\n", closure)
+		os.Exit(1)
+	}
+
+	within := uint16(min(*withinFlag, math.MaxUint16-1))
+	reaching := transit.StationsReaching(nodeMap, target, within, nil)
+	origins := make([]string, 0, len(reaching))
+	for station := range reaching {
+		origins = append(origins, station)
+	}
+	slices.SortFunc(origins, func(a, b string) int {
+		if reaching[a] != reaching[b] {
+			return int(reaching[a]) - int(reaching[b])
+		}
+		return strings.Compare(a, b)
+	})
+	fmt.Printf("%d stations can reach %s within %d minutes:\n", len(origins), target, within)
+	for _, station := range origins {
+		fmt.Printf("- %s (%d minutes)\n", station, reaching[station])
+	}
+}
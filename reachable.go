@@ -0,0 +1,158 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"maps"
+	"math"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/maxboyko1/TubePlanner/pkg/transit"
+)
+
+// Entry point for the "reachable" subcommand, which lists every station
+// reachable from a start station within a time budget, grouped into bands of
+// travel time, e.g. to see how well connected a place to live would be.
+// Given several start stations, it lists those reachable from all of them
+// instead, e.g. to choose somewhere for a group to meet.
+func RunReachableCommand(args []string) {
+	fs := flag.NewFlagSet("reachable", flag.ExitOnError)
+	withinFlag := fs.Uint("within", 30, "time budget in `minutes`")
+	bandFlag := fs.Uint("band", 10, "width of each band of travel times in `minutes`")
+	dataSourceFlags.register(fs)
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "USAGE: ./tubeplanner reachable [--within 30] [--band 10] "+dataSourceUsage+" <station>...")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	if *bandFlag == 0 {
+		fmt.Fprintln(os.Stderr, "ERROR: Bands must be at least 1 minute wide")
+		os.Exit(1)
+	}
+	origins := slices.Compact(slices.Sorted(slices.Values(fs.Args())))
+	_, nodeMap := buildGraph()
+	for _, origin := range origins {
+		if _, exists := nodeMap[origin]; !exists {
+			fmt.Fprintf(os.Stderr, "ERROR: %s is not a valid station\n", origin)
+			os.Exit(1)
+		}
+		if closure, isClosed := transit.StationClosure(nodeMap, origin); isClosed {
+			fmt.Fprintf(os.Stderr, "ERROR: %s\n", closure)
+			os.Exit(1)
+		}
+	}
+
+	// Searches record their progress on the graph, so each origin is
+	// searched from on a copy of its own, all at once
+	within := uint16(min(*withinFlag, math.MaxUint16-1))
+	band := uint16(min(*bandFlag, math.MaxUint16))
+	graphs := []transit.NodeMap{nodeMap}
+	for len(graphs) < len(origins) {
+		graphs = append(graphs, transit.CloneGraph(nodeMap))
+	}
+	times := make([]map[string]uint16, len(origins))
+	var wg sync.WaitGroup
+	for idx, origin := range origins {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			times[idx] = transit.TravelTimesFrom(graphs[idx], origin, nil)
+		}()
+	}
+	wg.Wait()
+
+	if len(origins) == 1 {
+		start := origins[0]
+		reachable := make([]string, 0, len(times[0]))
+		for station, minutes := range times[0] {
+			if station != start && minutes <= within {
+				reachable = append(reachable, station)
+			}
+		}
+		sortByTime(reachable, times[0])
+		fmt.Printf("%d stations are reachable from %s within %d minutes:\n", len(reachable), start, within)
+		printTimeBands(reachable, times[0], band, nil)
+		return
+	}
+
+	// Stations reachable from every origin, by the longest of their trips
+	anyOrigin := make(map[string]bool)
+	for idx, origin := range origins {
+		count := 0
+		for station, minutes := range times[idx] {
+			if station != origin && minutes <= within {
+				anyOrigin[station] = true
+				count++
+			}
+		}
+		fmt.Printf("%d stations are reachable from %s within %d minutes.\n", count, origin, within)
+	}
+	longest := make(map[string]uint16)
+	for station := range nodeMap {
+		worst, reachedByAll := uint16(0), true
+		for idx := range origins {
+			minutes, reached := times[idx][station]
+			if !reached || minutes > within {
+				reachedByAll = false
+				break
+			}
+			worst = max(worst, minutes)
+		}
+		if reachedByAll {
+			longest[station] = worst
+		}
+	}
+	overlap := slices.Collect(maps.Keys(longest))
+	sortByTime(overlap, longest)
+	fmt.Printf("%d stations are reachable from at least one of them, and %d from all of them:\n",
+		len(anyOrigin), len(overlap))
+	printTimeBands(overlap, longest, band, func(station string) string {
+		trips := make([]string, len(origins))
+		for idx, origin := range origins {
+			trips[idx] = fmt.Sprintf("%d from %s", times[idx][station], origin)
+		}
+		return fmt.Sprintf("%d minutes at most: %s", longest[station], strings.Join(trips, ", "))
+	})
+}
+
+// Sort the specified stations by the specified travel times, quickest first,
+// breaking ties by name
+func sortByTime(stations []string, times map[string]uint16) {
+	slices.SortFunc(stations, func(a, b string) int {
+		if times[a] != times[b] {
+			return int(times[a]) - int(times[b])
+		}
+		return strings.Compare(a, b)
+	})
+}
+
+// Print the specified stations, sorted by travel time, under a heading for
+// each band of travel times of the specified width which has any. Each
+// station is described by the specified function, if any, or else by its
+// travel time.
+func printTimeBands(stations []string, times map[string]uint16, band uint16, describe func(string) string) {
+	var lastBand uint16 = math.MaxUint16
+	for _, station := range stations {
+		// Bands run from one past a multiple of the width up to the next
+		if idx := (max(times[station], 1) - 1) / band; idx != lastBand {
+			lastBand = idx
+			if idx == 0 {
+				fmt.Printf("Up to %d minutes:\n", band)
+			} else {
+				fmt.Printf("%d-%d minutes:\n", int(idx)*int(band)+1, (int(idx)+1)*int(band))
+			}
+		}
+		if describe != nil {
+			fmt.Printf("- %s (%s)\n", station, describe(station))
+		} else {
+			fmt.Printf("- %s (%d minutes)\n", station, times[station])
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/maxboyko1/TubePlanner/pkg/transit"
+)
+
+// Entry point for the "resilience" subcommand, which ranks the lines by how
+// badly trips across the network would suffer if each vanished in turn: how
+// many trips between pairs of stations could no longer be made at all, and
+// how many more would take much longer, e.g. to see which lines the network
+// leans on most
+func RunResilienceCommand(args []string) {
+	fs := flag.NewFlagSet("resilience", flag.ExitOnError)
+	slowerFlag := fs.Uint("slower", 10, "count trips taking more than this many `minutes` longer as slower")
+	formatFlag := fs.String("format", "text", "output `format`: a text table, or csv")
+	dataSourceFlags.register(fs)
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "USAGE: ./tubeplanner resilience [--slower 10] [--format csv] "+dataSourceUsage)
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 0 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	if *formatFlag != "text" && *formatFlag != "csv" {
+		fmt.Fprintf(os.Stderr, "ERROR: Unknown output format: %s\n", *formatFlag)
+		os.Exit(1)
+	}
+	_, nodeMap := buildGraph()
+	impacts, trips := transit.LineRemovalImpacts(nodeMap, uint16(min(*slowerFlag, math.MaxUint16-1)), nil)
+
+	if *formatFlag == "csv" {
+		w := csv.NewWriter(os.Stdout)
+		w.Write([]string{"line", "disconnected", "slower", "stranded"})
+		for _, impact := range impacts {
+			w.Write([]string{impact.Line, strconv.Itoa(impact.Disconnected), strconv.Itoa(impact.Slower),
+				strconv.Itoa(impact.Stranded)})
+		}
+		w.Flush()
+		return
+	}
+
+	fmt.Printf("Lines ranked by the trips they would cut off or slow down by more than %d minutes if closed,\n"+
+		"out of %d trips between %d stations:\n", *slowerFlag, trips, len(nodeMap))
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "Line\tCut off\tSlower\tStations served by no other line\t")
+	for _, impact := range impacts {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t\n", impact.Line, impact.Disconnected, impact.Slower, impact.Stranded)
+	}
+	tw.Flush()
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/maxboyko1/TubePlanner/pkg/transit"
+)
+
+// Print the directions for both halves of a round trip, the way out and the
+// way back, followed by the time the whole round trip takes. The two halves
+// can differ, as run times and interchanges are not always the same in both
+// directions, so a note says how when they do. Each half is stamped with
+// its hash and the data version and options of the specified stamp.
+func printRoundTrip(outbound, back *transit.Route, width int, detailed bool, needs transit.AccessibilityAids,
+	base *transit.Stamp) {
+	fmt.Printf("Outbound, %s to %s (%d minutes):\n", outbound.Start(), outbound.Destination(),
+		outbound.TotalMinutes())
+	printRouteDirections(outbound, width, detailed, needs, base)
+	fmt.Println()
+	fmt.Printf("Return, %s to %s (%d minutes):\n", back.Start(), back.Destination(), back.TotalMinutes())
+	printRouteDirections(back, width, detailed, needs, base)
+	fmt.Println()
+	fmt.Printf("Round trip: %d minutes in all.\n", int(outbound.TotalMinutes())+int(back.TotalMinutes()))
+
+	// A route ridden back the same way takes its lines in reverse order
+	outLines := transit.JourneyLines(outbound.Journey())
+	slices.Reverse(outLines)
+	sameLines := slices.Equal(outLines, transit.JourneyLines(back.Journey()))
+	diff := int(back.TotalMinutes()) - int(outbound.TotalMinutes())
+	switch {
+	case diff == 0 && !sameLines:
+		fmt.Println("(The return takes as long as the way out, but by a different route.)")
+	case diff != 0:
+		direction := "more"
+		if diff < 0 {
+			direction, diff = "less", -diff
+		}
+		how := "on the same lines, as run times, interchanges and waits differ in that direction"
+		if !sameLines {
+			how = "by a different route"
+		}
+		unit := "minutes"
+		if diff == 1 {
+			unit = "minute"
+		}
+		fmt.Printf("(The return takes %d %s %s than the way out, %s.)\n", diff, unit, direction, how)
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/maxboyko1/TubePlanner/pkg/transit"
+)
+
+// Entry point for the "search" subcommand, which finds stations matching a
+// query across the built-in network and any others given, labelling each
+// with the network it belongs to
+func RunSearchCommand(args []string) {
+	fs := flag.NewFlagSet("search", flag.ExitOnError)
+	networks := []transit.Network{transit.BuiltinNetwork()}
+	fs.Func("network", "also search the network in the YAML dataset, GTFS feed or OSM extract at `name=path`",
+		func(value string) error {
+			name, path, found := strings.Cut(value, "=")
+			if !found || name == "" || path == "" {
+				return fmt.Errorf("expected name=path")
+			}
+			network, err := transit.LoadNetwork(name, path)
+			if err != nil {
+				return err
+			}
+			networks = append(networks, network)
+			return nil
+		})
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "USAGE: ./tubeplanner search [--network <name=path>]... <query>")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 || strings.TrimSpace(fs.Arg(0)) == "" {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	matches := transit.SearchStations(networks, fs.Arg(0))
+	if len(matches) == 0 {
+		fmt.Fprintf(os.Stderr, "ERROR: No stations match %q\n", fs.Arg(0))
+		os.Exit(1)
+	}
+	for _, match := range matches {
+		fmt.Printf("%s (%s): %s\n", match.Station, match.Network, strings.Join(match.Lines, ", "))
+	}
+}
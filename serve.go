@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/maxboyko1/TubePlanner/pkg/transit"
+)
+
+// Entry point for the "serve" subcommand, which builds the transit graph once
+// and answers routing queries over HTTP until interrupted, e.g. as the
+// backend of a web app. On SIGHUP the transit data is loaded again and
+// swapped in without dropping queries. Meanwhile, the line statuses are
+// fetched every --status-interval and added to the status history store.
+func RunServeCommand(args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addrFlag := fs.String("addr", "localhost:8080", "`address` to listen on")
+	budgetFlag := fs.Duration("budget", 0,
+		"default time budget for honouring a query's preferences, e.g. 50ms")
+	queryLogFlag := fs.String("query-log", "", "append each query and its response to the JSON lines `file`")
+	hierarchyFlag := fs.String("hierarchy", transit.DefaultHierarchyPath(),
+		"answer queries without options from a contraction hierarchy kept in this `file`, or \"\" for none")
+	warmFlag := fs.String("warm", "", "CSV `file` of popular trips, one from,to per line, to plan and cache at startup")
+	journeyStoreFlag := fs.String("journey-store", "",
+		"keep users' favourites and history in this JSON or SQLite (.db) `file`, or \"\" for none")
+	statusStoreFlag := fs.String("status-store", transit.DefaultStatusHistoryPath(),
+		"path of the line status history store")
+	statusIntervalFlag := fs.Duration("status-interval", 5*time.Minute,
+		"time between fetches of the line statuses for the status history, or 0 for none")
+	dataSourceFlags.register(fs)
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "USAGE: ./tubeplanner serve [--addr localhost:8080] [--query-log <file>] [--hierarchy <file>] [--warm <pairs file>] [--journey-store <file>] [--status-interval 5m] "+dataSourceUsage)
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 0 {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	var queryLog *transit.QueryLog
+	if *queryLogFlag != "" {
+		queryLog = transit.OpenQueryLog(*queryLogFlag)
+	}
+	var journeys transit.JourneyStore
+	if *journeyStoreFlag != "" {
+		var err error
+		if journeys, err = transit.OpenJourneyStore(*journeyStoreFlag); err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	_, nodeMap := buildGraph()
+	handler := transit.NewHTTPServer(nodeMap, queryLog, *budgetFlag)
+	if journeys != nil {
+		handler.UseJourneyStore(journeys)
+	}
+	useHierarchy := func(graph *transit.Graph) {
+		if *hierarchyFlag == "" {
+			return
+		}
+		if err := graph.UseContractionHierarchy(*hierarchyFlag); err != nil {
+			fmt.Fprintf(os.Stderr, "WARNING: Could not save the contraction hierarchy: %v\n", err)
+		}
+	}
+	useHierarchy(handler.Graph())
+	if *warmFlag != "" {
+		file, err := os.Open(*warmFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+			os.Exit(1)
+		}
+		pairs, err := transit.LoadStationPairs(file, nodeMap)
+		file.Close()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: %s: %v\n", *warmFlag, err)
+			os.Exit(1)
+		}
+		began := time.Now()
+		handler.Warm(pairs)
+		fmt.Fprintf(os.Stderr, "Cached routes for %d trips in %v.\n", handler.CachedRoutes(),
+			time.Since(began).Round(time.Millisecond))
+	}
+	// gRPC clients call over HTTP/2, which is accepted without TLS alongside
+	// HTTP/1.1 on the same address
+	server := &http.Server{
+		Addr:              *addrFlag,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+		Protocols:         new(http.Protocols),
+	}
+	server.Protocols.SetHTTP1(true)
+	server.Protocols.SetUnencryptedHTTP2(true)
+
+	// A bad edit to the transit data should not take the server down, so
+	// the old graph stays in use if the new one fails to build. The data is
+	// read again from the same place it was at startup, through a fresh
+	// DataSource, as each one loads its data only once.
+	reloadOptions, reloadPaths := graphOptions, dataSourceFlags
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	go func() {
+		for range reload {
+			source, err := reloadPaths.source()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "ERROR: Reloading transit data: %v\n", err)
+				continue
+			}
+			reloadOptions.Source = source
+			graph, err := transit.LoadGraph(reloadOptions)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "ERROR: Reloading transit data: %v\n", err)
+				continue
+			}
+			useHierarchy(graph)
+			handler.SwapGraph(graph)
+			fmt.Fprintln(os.Stderr, "Reloaded the transit data.")
+		}
+	}()
+
+	if *statusIntervalFlag > 0 {
+		store := transit.OpenStatusHistory(*statusStoreFlag)
+		go func() {
+			for {
+				if err := recordLineStatuses(store, 10*time.Second); err != nil {
+					fmt.Fprintf(os.Stderr, "WARNING: %v\n", err)
+				}
+				time.Sleep(*statusIntervalFlag)
+			}
+		}()
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+	fmt.Fprintf(os.Stderr, "Listening on %s, press Ctrl-C to quit.\n", *addrFlag)
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+		os.Exit(1)
+	}
+}
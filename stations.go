@@ -0,0 +1,78 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/maxboyko1/TubePlanner/pkg/transit"
+)
+
+// Entry point for the "stations" subcommand, which lists the stations of
+// the network, or those served by a line, one per line for use in scripts
+func RunStationsCommand(args []string) {
+	fs := flag.NewFlagSet("stations", flag.ExitOnError)
+	lineFlag := fs.String("line", "", "only list the stations served by the `line`")
+	dataSourceFlags.register(fs)
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "USAGE: ./tubeplanner stations [--line <line>] "+dataSourceUsage)
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 0 {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	_, nodeMap := buildGraph()
+	graph := transit.NewGraph(nodeMap)
+	stations := graph.Stations()
+	if *lineFlag != "" {
+		if !transit.LineExists(nodeMap, *lineFlag) {
+			fmt.Fprintf(os.Stderr, "ERROR: %s is not a valid line\n", *lineFlag)
+			os.Exit(1)
+		}
+		stations = graph.StationsOn(*lineFlag)
+	}
+	for _, station := range stations {
+		fmt.Println(station)
+	}
+}
+
+// Entry point for the "lines" subcommand, which lists the lines of the
+// network with the stations each calls at in order, or just those of the
+// lines given
+func RunLinesCommand(args []string) {
+	fs := flag.NewFlagSet("lines", flag.ExitOnError)
+	namesFlag := fs.Bool("names", false, "only list the names of the lines, one per line")
+	dataSourceFlags.register(fs)
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "USAGE: ./tubeplanner lines [--names] "+dataSourceUsage+" [<line>...]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	_, nodeMap := buildGraph()
+	graph := transit.NewGraph(nodeMap)
+	lines := fs.Args()
+	for _, line := range lines {
+		if !transit.LineExists(nodeMap, line) {
+			fmt.Fprintf(os.Stderr, "ERROR: %s is not a valid line\n", line)
+			os.Exit(1)
+		}
+	}
+	if len(lines) == 0 {
+		lines = graph.LineNames()
+	}
+	for _, line := range lines {
+		if *namesFlag {
+			fmt.Println(line)
+			continue
+		}
+		fmt.Printf("%s (%d stations)\n", line, len(graph.StationsOn(line)))
+		for _, section := range graph.LineSections(line) {
+			fmt.Printf("  %s\n", strings.Join(section, " - "))
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/maxboyko1/TubePlanner/pkg/transit"
+)
+
+// Entry point for the "status" subcommand, supporting
+// "status history <line> [--since <window>] [--store <path>]" and
+// "status reliability [--since <window>] [--store <path>]"
+func RunStatusCommand(args []string, nodeMap transit.NodeMap) {
+	usage := func() {
+		fmt.Fprintln(os.Stderr, "USAGE: ./tubeplanner status history <line> [--since 7d] [--store <path>]")
+		fmt.Fprintln(os.Stderr, "       ./tubeplanner status reliability [--since 30d] [--store <path>]")
+		os.Exit(1)
+	}
+	if len(args) == 0 {
+		usage()
+	}
+	switch args[0] {
+	case "history":
+		if len(args) < 2 {
+			usage()
+		}
+		line := args[1]
+		fs := flag.NewFlagSet("status history", flag.ExitOnError)
+		sinceFlag := fs.String("since", "7d", "how far back to report, e.g. 7d, 36h")
+		storeFlag := fs.String("store", transit.DefaultStatusHistoryPath(), "path of the status history store")
+		fs.Parse(args[2:])
+
+		if !transit.LineExists(nodeMap, line) {
+			fmt.Fprintf(os.Stderr, "ERROR: %s is not a valid line\n", line)
+			os.Exit(1)
+		}
+		since := parseSinceFlag(*sinceFlag)
+		statuses, err := transit.OpenStatusHistory(*storeFlag).Query(line, since)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: Could not read status history: %v\n", err)
+			os.Exit(1)
+		}
+		transit.PrintStatusHistory(line, since, statuses)
+	case "reliability":
+		fs := flag.NewFlagSet("status reliability", flag.ExitOnError)
+		sinceFlag := fs.String("since", "30d", "how far back to judge by, e.g. 30d")
+		storeFlag := fs.String("store", transit.DefaultStatusHistoryPath(), "path of the status history store")
+		fs.Parse(args[1:])
+
+		since := parseSinceFlag(*sinceFlag)
+		reliability, err := transit.OpenStatusHistory(*storeFlag).Reliability(since)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: Could not read status history: %v\n", err)
+			os.Exit(1)
+		}
+		transit.PrintLineReliability(since, reliability)
+	default:
+		usage()
+	}
+}
+
+// Return the start of the look-back window given with --since, exiting with
+// an error if it is invalid
+func parseSinceFlag(s string) time.Time {
+	window, err := transit.ParseSince(s)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+		os.Exit(1)
+	}
+	return time.Now().Add(-window)
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/maxboyko1/TubePlanner/pkg/transit"
+)
+
+// Return the specified amount in pence as pounds, e.g. "£3.80"
+func formatPounds(pence uint16) string {
+	return fmt.Sprintf("£%d.%02d", pence/100, pence%100)
+}
+
+// Print a taxi ride of a trip, as a line of its directions
+func printTaxiLeg(leg *transit.TaxiLeg) {
+	fmt.Printf("Taxi from %s to %s: %.1f km, about %d minutes, %s.\n",
+		leg.From, leg.To, leg.KM, leg.Minutes, formatPounds(leg.Pence))
+}
+
+// Print the directions for a trip taking a taxi for part or all of the way,
+// planned because there was no route by train alone at the specified
+// departure time, along with its arrival time and fares
+func printTaxiPlan(start, dest string, plan *transit.TaxiPlan, departAt time.Time, width int, detailed bool) {
+	howFar := "part of the way"
+	if plan.Route == nil {
+		howFar = "all the way"
+	}
+	fmt.Printf("No route by train alone from %s to %s at %s, so taking a taxi %s.\n",
+		start, dest, departAt.Format("15:04"), howFar)
+	if plan.First != nil {
+		printTaxiLeg(plan.First)
+	}
+	railDepartAt := departAt
+	if plan.Route != nil {
+		if plan.First != nil {
+			railDepartAt = departAt.Add(time.Duration(plan.First.Minutes) * time.Minute)
+		}
+		transit.RelativeLead = transit.LeadMinutes(railDepartAt, time.Now())
+		if width > 0 {
+			transit.PrintDirectionsWidth(plan.Route.Journey(), width, detailed)
+		} else {
+			transit.PrintDirections(plan.Route.Journey(), detailed)
+		}
+	}
+	if plan.Last != nil {
+		printTaxiLeg(plan.Last)
+	}
+	total := plan.TotalMinutes()
+	fmt.Printf("Arrive at %s after %d minutes, at %s. Taxi fares: %s", dest, total,
+		departAt.Add(time.Duration(total)*time.Minute).Format("15:04"), formatPounds(plan.TaxiPence()))
+	if plan.Route == nil {
+		fmt.Println(".")
+		return
+	}
+	fmt.Println(", plus the fare by train.")
+	transit.PrintFare(plan.Route.Journey(), railDepartAt)
+}
@@ -1,245 +1,209 @@
 package main
 
 import (
-	"container/heap"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"image/png"
+	"io"
+	"maps"
 	"math"
+	"math/rand/v2"
+	"net/http"
 	"os"
 	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/maxboyko1/TubePlanner/pkg/transit"
 )
 
-// Represents an "edge" in the transit graph, either a rail link or an interchange
-type Link struct {
-	endNode  *Node
-	time     uint16
-	linkType string
-}
+// How the transit graph is built: the library's defaults, plus the graph
+// cache and the station overrides, lift outages and link times files in the
+// user's config directory, each of which flags may change
+var graphOptions = func() transit.GraphOptions {
+	opts := transit.DefaultGraphOptions()
+	opts.CachePath = transit.DefaultGraphCachePath()
+	opts.StationOverridesPath = transit.DefaultStationOverridesPath()
+	opts.LiftOutagesPath = transit.DefaultLiftOutagesPath()
+	opts.LinkTimesPath = transit.DefaultLinkTimesPath()
+	return opts
+}()
 
-// Represents a "vertex" in the transit graph, with each existing combination
-// of station name and line name being its own vertex
-type Node struct {
-	station   string
-	line      string
-	adj       []*Link
-	totalTime uint16
-	index     int
+// Paths of the transit data to build the graph from instead of the built-in
+// data, at most one of which may be set
+type dataSourcePaths struct {
+	dataset, dataDir, gtfs, osm, db string
 }
 
-// Map of each station and line name combination to its corresponding Node
-// pointer in the graph
-type NodeMap map[string]map[string]*Node
+// The transit data chosen by flags, which buildGraph builds the graph from
+var dataSourceFlags dataSourcePaths
 
-// List of all nodes in the graph, min heap-ordered according to the shortest
-// time taken to arrive there from user's chosen starting point (all necessary
-// Go heap interface methods are implemented below)
-type NodePriorityQueue []*Node
+// Usage of the flags registered by dataSourcePaths.register
+const dataSourceUsage = "[--dataset <file> | --data-dir <directory> | --gtfs <feed> | --osm <extract> | --db <file>]"
 
-// Return number of nodes in the heap
-func (npq NodePriorityQueue) Len() int {
-	return len(npq)
+// Register flags on the specified flag set for choosing the transit data to
+// build the graph from, each setting one of the paths
+func (paths *dataSourcePaths) register(fs *flag.FlagSet) {
+	fs.StringVar(&paths.dataset, "dataset", "",
+		"build the transit graph from the YAML dataset `file` instead of the built-in data")
+	fs.StringVar(&paths.dataDir, "data-dir", "",
+		"build the transit graph from railLinks.csv and interchanges.csv in the `directory` instead of the built-in data")
+	fs.StringVar(&paths.gtfs, "gtfs", "",
+		"build the transit graph from the GTFS `feed` (directory or zip) instead of the built-in data")
+	fs.StringVar(&paths.osm, "osm", "",
+		"build the transit graph from the OpenStreetMap XML `extract` instead of the built-in data (experimental)")
+	fs.StringVar(&paths.db, "db", "",
+		"build the transit graph from the SQLite network database `file` instead of the built-in data")
 }
 
-// Return whether the total travel time to Node at index i is less than the
-// total travel time to Node at index j
-func (npq NodePriorityQueue) Less(i, j int) bool {
-	return npq[i].totalTime < npq[j].totalTime
+// Return the DataSource for the transit data the paths name: the YAML
+// dataset, the CSV files, the GTFS feed, the OpenStreetMap extract or the
+// network database if any is set, or else nil for the built-in data
+func (paths dataSourcePaths) source() (transit.DataSource, error) {
+	sources := 0
+	for _, path := range []string{paths.dataset, paths.dataDir, paths.gtfs, paths.osm, paths.db} {
+		if path != "" {
+			sources++
+		}
+	}
+	switch {
+	case sources > 1:
+		return nil, errors.New("only one of a YAML dataset, a CSV data directory, a GTFS feed, " +
+			"an OpenStreetMap extract and a network database can be used")
+	case paths.dataset != "":
+		return transit.YAMLDataSource(paths.dataset), nil
+	case paths.dataDir != "":
+		return transit.CSVDataSource(paths.dataDir), nil
+	case paths.gtfs != "":
+		return transit.GTFSDataSource(paths.gtfs), nil
+	case paths.osm != "":
+		return transit.OSMDataSource(paths.osm), nil
+	case paths.db != "":
+		return transit.NetworkDatabase{Path: paths.db}, nil
+	}
+	return nil, nil
 }
 
-// Swap positions of Nodes at indices i and j in the heap
-func (npq NodePriorityQueue) Swap(i, j int) {
-	npq[i], npq[j] = npq[j], npq[i]
-	npq[i].index = i
-	npq[j].index = j
+// Return the DataSource for the transit data chosen by flags, exiting with
+// an error if more than one was given
+func chosenDataSource() transit.DataSource {
+	source, err := dataSourceFlags.source()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+		os.Exit(1)
+	}
+	return source
 }
 
-// Add a new Node to the end of the heap
-func (npq *NodePriorityQueue) Push(x any) {
-	n := len(*npq)
-	node := x.(*Node)
-	node.index = n
-	*npq = append(*npq, node)
+// Build the transit graph from the transit data chosen by flags, exiting
+// with an error if it is invalid. GTFS feeds and OpenStreetMap extracts
+// seldom have a time for every change between lines, so changes without one
+// are assumed to take importedInterchangeMinutes unless
+// --assume-interchanges says otherwise.
+func buildGraph() (transit.NodePriorityQueue, transit.NodeMap) {
+	graphOptions.Source = chosenDataSource()
+	if !assumedInterchangesGiven && (dataSourceFlags.gtfs != "" || dataSourceFlags.osm != "") {
+		graphOptions.AssumedInterchangeMinutes = importedInterchangeMinutes
+	}
+	npq, nodeMap, err := transit.BuildTransitGraph(graphOptions)
+	if errors.Is(err, transit.ErrNoInterchanges) {
+		fmt.Fprintf(os.Stderr, "ERROR: %v; pass --assume-interchanges <minutes> for trips to change lines\n", err)
+		os.Exit(1)
+	} else if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+		os.Exit(1)
+	}
+	return npq, nodeMap
 }
 
-// Remove the minimum priority Node from the heap and return it
-func (npq *NodePriorityQueue) Pop() any {
-	old := *npq
-	n := len(old)
-	node := old[n-1]
-	old[n-1] = nil
-	node.index = -1
-	*npq = old[0 : n-1]
-	return node
-}
+// Minutes assumed for changing between lines without an interchange time in
+// a GTFS feed or OpenStreetMap extract, unless --assume-interchanges is given
+const importedInterchangeMinutes = 5
 
-// Update the specified node with a new total travel time, then restore the heap ordering
-func (npq *NodePriorityQueue) update(node *Node, newTotalTime uint16) {
-	node.totalTime = newTotalTime
-	heap.Fix(npq, node.index)
-}
+// Whether an --assume-interchanges flag was given
+var assumedInterchangesGiven bool
 
-// Helper function for BuildTransitGraph() which adds a connection between two
-// Nodes of the specified type and transit time to the graph
-func AddConnection(npq *NodePriorityQueue, nodeMap NodeMap, connection any, lType string) {
-	// Retrieve station/line names and transit time for the specified connection
-	var stationA, lineA, stationB, lineB string
-	var transitTime uint16
-	switch conn := connection.(type) {
-	case *RailLink:
-		stationA, stationB = conn.fromStation, conn.toStation
-		lineA, lineB = conn.line, conn.line
-		transitTime = conn.transitTime
-	case *Interchange:
-		stationA, stationB = conn.fromStation, conn.toStation
-		lineA, lineB = conn.fromLine, conn.toLine
-		transitTime = conn.transitTime
-	default:
-		fmt.Fprintln(os.Stderr, "ERROR: Connection type must be RailLink or Interchange")
-		os.Exit(1)
+// Set graphOptions.AssumedInterchangeMinutes from the value of an
+// --assume-interchanges flag
+func setAssumedInterchanges(value string) error {
+	minutes, err := strconv.ParseUint(value, 10, 16)
+	if err != nil {
+		return fmt.Errorf("invalid number of minutes %q", value)
 	}
+	graphOptions.AssumedInterchangeMinutes = uint16(minutes)
+	assumedInterchangesGiven = true
+	return nil
+}
 
-	// Create a graph Node for the first station/line if it does not exist already,
-	// with travel distance initialized to infinity
-	var nodeAExists bool = false
-	_, mapAExists := nodeMap[stationA]
-	if mapAExists {
-		_, nodeAExists = nodeMap[stationA][lineA]
-	} else {
-		nodeMap[stationA] = make(map[string]*Node)
-	}
-	if !nodeAExists {
-		newNode := &Node{stationA, lineA, make([]*Link, 0), math.MaxUint16, 0}
-		npq.Push(newNode)
-		nodeMap[stationA][lineA] = newNode
-	}
+// Usage of an --assume-interchanges flag
+const assumeInterchangesUsage = "assume changing lines takes this many `minutes` at stations where the data " +
+	"has no interchange time between them, flagging trips that rely on it (default 5 for --gtfs and --osm, " +
+	"none otherwise)"
 
-	// Create a graph Node for the second station/line, if it does not exist already,
-	// with travel distance initialized to infinity
-	var nodeBExists bool = false
-	_, mapBExists := nodeMap[stationB]
-	if mapBExists {
-		_, nodeBExists = nodeMap[stationB][lineB]
-	} else {
-		nodeMap[stationB] = make(map[string]*Node)
-	}
-	if !nodeBExists {
-		newNode := &Node{stationB, lineB, make([]*Link, 0), math.MaxUint16, 0}
-		npq.Push(newNode)
-		nodeMap[stationB][lineB] = newNode
-	}
+// How far back line statuses are taken into account when judging the
+// reliability of each line
+const reliabilityWindow = 30 * 24 * time.Hour
 
-	// Add a link to node B to node A's adjacency list, and vice versa
-	nodeA, nodeB := nodeMap[stationA][lineA], nodeMap[stationB][lineB]
-	nodeA.adj = append(nodeA.adj, &Link{nodeB, transitTime, lType})
-	nodeB.adj = append(nodeB.adj, &Link{nodeA, transitTime, lType})
+// Exit with the error noRouteError returns for the specified stations
+func exitNoRoute(planner *transit.Planner, start, dest string, err error) {
+	fmt.Fprintf(os.Stderr, "ERROR: %v\n", noRouteError(planner, start, dest, err))
+	os.Exit(1)
 }
 
-// Retrieve the list of rail links and interchanges defined in transitdata.go
-// and add each one as a connection in the transit graph
-func BuildTransitGraph() (NodePriorityQueue, NodeMap) {
-	railLinks, interchanges := GetRailLinks(), GetInterchanges()
-	npq, nodeMap := make(NodePriorityQueue, 0), make(NodeMap)
-
-	for _, rl := range railLinks {
-		AddConnection(&npq, nodeMap, &rl, "rail")
+// Return an error saying there is no route between the specified stations,
+// given the error planning it failed with: explaining when that is only
+// because the trains needed have stopped running at the planner's departure
+// time, or giving the reason when the search gave up for some other reason.
+// A search failing its cross-check (see --crosscheck) is reported as it is.
+func noRouteError(planner *transit.Planner, start, dest string, err error) error {
+	if errors.Is(err, transit.ErrCrossCheckFailed) {
+		return err
 	}
-	for _, ic := range interchanges {
-		if ic.fromStation == ic.toStation {
-			AddConnection(&npq, nodeMap, &ic, "line interchange")
-		} else {
-			AddConnection(&npq, nodeMap, &ic, "station interchange")
-		}
+	if !errors.Is(err, transit.ErrNoRoute) {
+		return fmt.Errorf("No route available from %s to %s: %w", start, dest, err)
 	}
-
-	return npq, nodeMap
+	anyTime := transit.NewPlanner(planner.Graph())
+	anyTime.Options = planner.Options
+	anyTime.Options.DepartAt = time.Time{}
+	if _, err := anyTime.Plan(start, dest); err == nil {
+		return fmt.Errorf("No route available from %s to %s at %s, "+
+			"as the trains needed are not running then (see --depart-at)",
+			start, dest, planner.Options.DepartAt.Format("15:04"))
+	}
+	return fmt.Errorf("No route available from %s to %s", start, dest)
 }
 
-// Run a binary heap variation of Dijkstra's shortest paths algorithm on the
-// completed transit graph to calculate the shortest possible trip between
-// the provided start and end stations
-func RunShortestPaths(npq *NodePriorityQueue, nodeMap NodeMap,
-	start, dest string) ([]*Node, []string) {
-	if start == dest {
-		return nil, nil
-	}
-	nodePrev := make(map[*Node]*Node)
-	linkPrev := make(map[*Node]*Link)
-	// Initialize valid starting Nodes in graph (any transit line departing
-	// from specified start station) with travel times of 0
-	for _, node := range nodeMap[start] {
-		npq.update(node, 0)
-		nodePrev[node] = nil
-		linkPrev[node] = nil
-	}
-	var curNode *Node = nil
-	for len(*npq) > 0 {
-		// Retrieve the Node of minimum established travel time from the heap
-		curNode = heap.Pop(npq).(*Node)
-		// If this Node represents the desired destination, we are done
-		if curNode.station == dest {
-			break
-		}
-		// For every node directly reachable from the current node, update the
-		// travel time to that node if the path to it from the current node is
-		// an improvement on its previously established travel time
-		for _, link := range curNode.adj {
-			altDistance := curNode.totalTime + link.time
-			if altDistance < link.endNode.totalTime {
-				link.endNode.totalTime = altDistance
-				nodePrev[link.endNode] = curNode
-				linkPrev[link.endNode] = link
-				npq.update(link.endNode, altDistance)
-			}
+// Return why the specified station cannot begin a trip, or end one if
+// specified, or nil if it can: it must be in the graph and open, and have
+// working step-free access if specified
+func checkTripStation(nodeMap transit.NodeMap, station string, dest, stepFree bool) error {
+	if graphOptions.Area != nil && !graphOptions.Area(station) &&
+		transit.StationInDataset(graphOptions.Source, station) {
+		return fmt.Errorf("%s is outside the selected area", station)
+	}
+	if _, exists := nodeMap[station]; !exists {
+		if graphOptions.ServiceProfile != 0 && transit.StationInDataset(graphOptions.Source, station) {
+			return fmt.Errorf("No trains call at %s in the %s service", station, graphOptions.ServiceProfile)
+		} else if dest {
+			return fmt.Errorf("%s is not a valid destination", station)
 		}
+		return fmt.Errorf("%s is not a valid initial station", station)
 	}
-	// Construct the route from the start to ending Nodes by continually
-	// following pointers to the previous node in the path until the start is
-	// reached, tracking the type of the link at each step as well
-	route, linkTypes := make([]*Node, 0), make([]string, 0)
-	for linkPrev[curNode] != nil {
-		route = append(route, curNode)
-		linkTypes = append(linkTypes, linkPrev[curNode].linkType)
-		curNode = nodePrev[curNode]
+	if closure, isClosed := transit.StationClosure(nodeMap, station); isClosed {
+		return errors.New(closure)
 	}
-	route = append(route, curNode)
-	slices.Reverse(linkTypes)
-	slices.Reverse(route)
-	return route, linkTypes
-}
-
-// From the specified transit trip, as represented by the sequence of nodes
-// visited as well as the types of connections between each, print a clear,
-// readable series of directions for the user to follow to complete their trip
-func PrintDirections(route []*Node, linkTypes []string) {
-	if route == nil {
-		fmt.Println("Already at destination!")
-		return
+	if !stepFree {
+		return nil
 	}
-	fmt.Printf("1) Begin journey at %s station. (0 minutes)\n", route[0].station)
-	var idx, step int
-	for idx, step = 0, 2; idx < len(linkTypes); idx++ {
-		switch linkTypes[idx] {
-		case "rail":
-			if idx == 0 || linkTypes[idx-1] != "rail" {
-				fmt.Printf("%d) Travel on the %s line, through station stops:\n",
-					step, route[idx+1].line)
-				step++
-			}
-			fmt.Printf("- %s (%d minutes)\n", route[idx+1].station, route[idx+1].totalTime)
-		case "line interchange":
-			fmt.Printf("%d) Get off at %s and interchange to the %s line. (%d minutes)\n",
-				step, route[idx+1].station, route[idx+1].line, route[idx+1].totalTime)
-			step++
-		case "station interchange":
-			fmt.Printf("%d) From %s, interchange on foot to nearby %s station. (%d minutes)\n",
-				step, route[idx].station, route[idx+1].station, route[idx+1].totalTime)
-			step++
-		default:
-			fmt.Fprintf(os.Stderr, "ERROR: Invalid transit link type: %s\n", linkTypes[idx])
-			os.Exit(1)
-		}
-	}
-	fmt.Printf("%d) Reach destination at %s station. (%d minutes)\n",
-		step, route[idx].station, route[idx].totalTime)
+	if outage, broken := transit.StepFreeOutage(nodeMap, station); broken {
+		return errors.New(outage)
+	} else if !transit.HasStepFreeAccess(nodeMap, station) {
+		return fmt.Errorf("%s has no step-free access to its platforms", station)
+	}
+	return nil
 }
 
 // Program that builds a graph to represent the London commuter transit map data
@@ -247,20 +211,882 @@ func PrintDirections(route []*Node, linkTypes []string) {
 // between the user-provided start and end point stations, and prints to console
 // a series of directions to follow to complete said trip
 func main() {
-	if len(os.Args) != 3 {
-		fmt.Fprintln(os.Stderr, "USAGE: ./tubeplanner <start> <destination>")
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "status":
+			_, nodeMap := buildGraph()
+			RunStatusCommand(os.Args[2:], nodeMap)
+			return
+		case "import-coords":
+			_, nodeMap := buildGraph()
+			RunImportCoordsCommand(os.Args[2:], nodeMap)
+			return
+		case "who-can-reach":
+			RunWhoCanReachCommand(os.Args[2:])
+			return
+		case "reachable":
+			RunReachableCommand(os.Args[2:])
+			return
+		case "dashboard":
+			RunDashboardCommand(os.Args[2:])
+			return
+		case "dataset":
+			RunDatasetCommand(os.Args[2:])
+			return
+		case "db":
+			RunDatabaseCommand(os.Args[2:])
+			return
+		case "replay":
+			RunReplayCommand(os.Args[2:])
+			return
+		case "search":
+			RunSearchCommand(os.Args[2:])
+			return
+		case "stations":
+			RunStationsCommand(os.Args[2:])
+			return
+		case "lines":
+			RunLinesCommand(os.Args[2:])
+			return
+		case "places":
+			RunPlacesCommand(os.Args[2:])
+			return
+		case "matrix":
+			RunMatrixCommand(os.Args[2:])
+			return
+		case "resilience":
+			RunResilienceCommand(os.Args[2:])
+			return
+		case "serve":
+			RunServeCommand(os.Args[2:])
+			return
+		case "verify":
+			RunVerifyCommand(os.Args[2:])
+			return
+		case "diff":
+			RunDiffCommand(os.Args[2:])
+			return
+		case "loadtest":
+			RunLoadTestCommand(os.Args[2:])
+			return
+		case "validate":
+			RunValidateCommand(os.Args[2:])
+			return
+		case "demo":
+			RunDemoCommand(os.Args[2:])
+			return
+		case "favourites":
+			RunFavouritesCommand(os.Args[2:])
+			return
+		case "history":
+			RunHistoryCommand(os.Args[2:])
+			return
+		}
+	}
+	adviseFlag := flag.Uint("advise", 0,
+		"compare leaving now against waiting up to `N` minutes, given current disruptions")
+	statusStoreFlag := flag.String("status-store", transit.DefaultStatusHistoryPath(),
+		"path of the line status history store")
+	journeyStoreFlag := flag.String("journey-store", transit.DefaultJourneyStorePath(),
+		"path of the JSON or SQLite (.db) store of favourite trips and journey history")
+	noHistoryFlag := flag.Bool("no-history", false, "do not add the journey to the journey history")
+	liveFlag := flag.Bool("live", false,
+		"fetch the latest line statuses from TfL, avoiding closed lines and weighing in delays")
+	liveTimeoutFlag := flag.Duration("live-timeout", 5*time.Second,
+		"how long to wait for TfL before falling back on the stored statuses")
+	openInFlag := flag.String("open-in", "",
+		"print a transit directions link for the same trip in `maps`, google or apple")
+	launchFlag := flag.Bool("launch", false, "also open the --open-in link in a browser or maps app")
+	noInputFlag := flag.Bool("no-input", false,
+		"run unattended, e.g. from cron or CI, refusing options that need someone at the screen such as --launch")
+	flag.StringVar(&graphOptions.StationOverridesPath, "overrides", graphOptions.StationOverridesPath,
+		"path of the station overrides file marking temporarily closed stations")
+	flag.StringVar(&graphOptions.LiftOutagesPath, "lift-outages", graphOptions.LiftOutagesPath,
+		"path of the lift outages file marking lifts out of service, for --step-free")
+	flag.StringVar(&graphOptions.LinkTimesPath, "link-times", graphOptions.LinkTimesPath,
+		"path of the link times file giving your own times for particular rail links and interchanges")
+	flag.Float64Var(&graphOptions.WalkRadiusMetres, "walk-radius", graphOptions.WalkRadiusMetres,
+		"join stations within this many `metres` of each other by walks where the data has none (0 for none)")
+	flag.Func("assume-interchanges", assumeInterchangesUsage, setAssumedInterchanges)
+	dataSourceFlags.register(flag.CommandLine)
+	crossCheckFlag := flag.Bool("crosscheck", false,
+		"debug: check every A* or bidirectional search against Dijkstra's algorithm, failing if they disagree")
+	flag.StringVar(&graphOptions.CachePath, "graph-cache", graphOptions.CachePath,
+		"`file` caching the built transit graph between runs, rebuilt when the data changes (\"\" for none)")
+	avoidLines, avoidStations := make(map[string]bool), make(map[string]bool)
+	flag.Func("avoid-line", "do not use the `line` at all (repeatable)", func(line string) error {
+		avoidLines[line] = true
+		return nil
+	})
+	flag.Func("avoid-station", "do not pass through the `station` at all, "+
+		"not even on a train running through it (repeatable)", func(station string) error {
+		avoidStations[station] = true
+		return nil
+	})
+	strikeFlag := flag.String("strike", "",
+		"plan for a strike closing the comma-separated `lines`, e.g. \"RMT on Central,Victoria\"")
+	preferSeatFlag := flag.Uint("prefer-seat", 0,
+		"board lines near where their trains start, if it costs at most `N` extra minutes")
+	interchangeSpeedFlag := flag.Float64("interchange-speed", 1,
+		"walking speed within stations when changing lines, relative to the data's (e.g. 0.8)")
+	streetSpeedFlag := flag.Float64("street-speed", 1,
+		"walking speed along the street between nearby stations, relative to the data's")
+	interchangePenaltyFlag := flag.String("interchange-penalty", "",
+		"extra `minutes` to count against every change of train, or by kind, e.g. line=3,street=10")
+	departAtFlag := flag.String("depart-at", "",
+		"departure `time` (HH:MM today, or RFC 3339) used to pick peak or off-peak run times")
+	departWindowFlag := flag.String("depart-window", "",
+		"plan the trip setting off at times across a `window`, e.g. 08:00-09:00, and compare them")
+	everyFlag := flag.Duration("every", 10*time.Minute,
+		"with --depart-window, the interval between departures, e.g. 15m")
+	arriveByFlag := flag.String("arrive-by", "",
+		"plan the trip setting off latest that still arrives by this `time` (HH:MM today, or RFC 3339)")
+	formatFlag := flag.String("format", "text",
+		"output `format`: text directions, json, symbols for a one-line summary, markdown or csv for a table of legs, or png for a map")
+	outFlag := flag.String("out", "", "with --format png, the `file` to write the map image to")
+	tileURLFlag := flag.String("tile-url", transit.OSMTileURL,
+		"with --format png, `template` of the map tile URLs, with {z}, {x} and {y} placeholders")
+	mapSizeFlag := flag.String("map-size", "800x600", "with --format png, the image size in pixels, `WxH`")
+	widthFlag := flag.Int("width", 0,
+		"wrap directions to `N` columns, using compact wording at 40 or fewer")
+	relativeFlag := flag.Bool("relative", false,
+		"give times in directions as countdowns from now, e.g. \"in 12 min\", for following along on the move")
+	noColorFlag := flag.Bool("no-color", false,
+		"do not show line names in their colours, as is done when writing to a terminal")
+	bboxFlag := flag.String("bbox", "",
+		"only route between stations within `minLat,minLon,maxLat,maxLon`")
+	zonesFlag := flag.String("zones", "",
+		"only route between stations in the fare `zones` given, e.g. 1-2")
+	whenFlag := flag.String("when", "",
+		"only route over the lines running on a `day`: weekday, weekend or night (Night Tube)")
+	accessibilityFlag := flag.String("accessibility", "",
+		"prefer changing at stations with aids for `needs`: hearing, visual or hearing,visual")
+	stepFreeFlag := flag.Bool("step-free", false,
+		"only start, end and change where lifts or ramps reach the platforms, e.g. for wheelchairs")
+	needFlag := flag.String("need", "",
+		"call at a station with a `facility` on the way, toilet or baby-change, and say where it is")
+	optimizeFlag := flag.String("optimize", "time",
+		"what to minimize: total `time`, changes (breaking ties on time), time "+
+			"including the delays usual on each line (reliable), the estimated fare (cheapest), or "+
+			"time weighted against deep-level lines and boardings (energy, experimental)")
+	maxDurationFlag := flag.Duration("max-duration", 0,
+		"refuse to give a journey taking longer than this, e.g. 90m, and say which options lengthen it")
+	alternativesFlag := flag.Int("alternatives", 1, "show up to `N` distinct routes, fastest first")
+	sortFlag := flag.String("sort", "time",
+		"with --alternatives, order the routes by `criterion`: time, changes, walk or fare")
+	taxiFlag := flag.Bool("taxi", false,
+		"if there is no route by train alone, e.g. after the last trains, take a taxi for part or all of the way")
+	taxiSpeedFlag := flag.Float64("taxi-speed", transit.DefaultTaxi.SpeedKMH,
+		"with --taxi, the taxi's average speed in `km/h`")
+	taxiFareFlag := flag.String("taxi-fare", "3.80+2.20",
+		"with --taxi, the taxi's `fare` in pounds: a flat fare plus a fare per km")
+	simulateFlag := flag.Int("simulate", 0,
+		"simulate the journey `N` times with random waits for each train and report the spread of times")
+	tradeoffsFlag := flag.Bool("tradeoffs", false,
+		"show every route that no other beats on journey time, number of changes and minutes walking")
+	roundTripFlag := flag.Bool("round-trip", false,
+		"plan the way back from the destination as well, and give the time of the whole round trip")
+	toFlag := flag.String("to", "",
+		"destination, or several separated by | to head for whichever is reached soonest")
+	detailedFlag := flag.Bool("detailed", false,
+		"add walking guidance to interchanges, where the transit data has it")
+	batchFlag := flag.String("batch", "",
+		"plan every trip in the CSV `file` of from,to pairs (- for stdin), writing one CSV row per trip")
+	stdioJSONFlag := flag.Bool("stdio-json", false,
+		"answer one JSON query per line of stdin with one JSON response per line of stdout")
+	budgetFlag := flag.Duration("budget", 0,
+		"with --stdio-json, default time budget for honouring a query's preferences, e.g. 50ms")
+	queryLogFlag := flag.String("query-log", "",
+		"with --stdio-json, append each query and its response to the JSON lines `file`")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "USAGE: ./tubeplanner [options] <start> <destination>")
+		fmt.Fprintln(os.Stderr, "       ./tubeplanner [options] --to \"<destination>|<destination>...\" <start>")
+		fmt.Fprintln(os.Stderr, "       ./tubeplanner [options] @<favourite>")
+		fmt.Fprintln(os.Stderr, "       ./tubeplanner [options] --batch <pairs file>")
+		fmt.Fprintln(os.Stderr, "       ./tubeplanner --stdio-json [--query-log <file>]")
+		fmt.Fprintln(os.Stderr, "       ./tubeplanner serve [--addr localhost:8080] [--query-log <file>]")
+		fmt.Fprintln(os.Stderr, "       ./tubeplanner loadtest [--url http://localhost:8080] [--concurrency 8] [--duration 30s]")
+		fmt.Fprintln(os.Stderr, "       ./tubeplanner replay <query log>")
+		fmt.Fprintln(os.Stderr, "       ./tubeplanner dataset (export [--gtfs <feed> | --osm <extract>] | validate <file> | lint [--fix] <file>)")
+		fmt.Fprintln(os.Stderr, "       ./tubeplanner db (init <file> | add-link <file> ... | remove-link <file> ... | add-interchange <file> ... | remove-interchange <file> ...)")
+		fmt.Fprintln(os.Stderr, "       ./tubeplanner dashboard [--commutes <file>]")
+		fmt.Fprintln(os.Stderr, "       ./tubeplanner who-can-reach [--within 30] <station>")
+		fmt.Fprintln(os.Stderr, "       ./tubeplanner reachable [--within 30] [--band 10] <station>...")
+		fmt.Fprintln(os.Stderr, "       ./tubeplanner search [--network <name=path>]... <query>")
+		fmt.Fprintln(os.Stderr, "       ./tubeplanner stations [--line <line>]")
+		fmt.Fprintln(os.Stderr, "       ./tubeplanner lines [--names] [<line>...]")
+		fmt.Fprintln(os.Stderr, "       ./tubeplanner places [--format csv] <places file>")
+		fmt.Fprintln(os.Stderr, "       ./tubeplanner matrix [--format csv|json] --from <station>... --to <station>...")
+		fmt.Fprintln(os.Stderr, "       ./tubeplanner resilience [--slower 10] [--format csv]")
+		fmt.Fprintln(os.Stderr, "       ./tubeplanner validate [--dataset <file> | --data-dir <directory>]")
+		fmt.Fprintln(os.Stderr, "       ./tubeplanner status history <line> [--since 7d]")
+		fmt.Fprintln(os.Stderr, "       ./tubeplanner status reliability [--since 30d]")
+		fmt.Fprintln(os.Stderr, "       ./tubeplanner import-coords (--csv <file> | --tfl)")
+		fmt.Fprintln(os.Stderr, "       ./tubeplanner verify (<from> <to> | --pairs <file>)")
+		fmt.Fprintln(os.Stderr, "       ./tubeplanner diff [--pairs <file> | --sample 1000] <before> <after>")
+		fmt.Fprintln(os.Stderr, "       ./tubeplanner favourites (add <name> <start> <destination> | list | remove <name>)")
+		fmt.Fprintln(os.Stderr, "       ./tubeplanner history [--limit 20]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	transit.ColourDirections = !*noColorFlag && transit.ColourSupported(os.Stdout)
+	if err := checkAlternatives(*alternativesFlag); err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+		flag.Usage()
+		os.Exit(1)
+	}
+	if err := selectGraphArea(*bboxFlag, *zonesFlag); err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+		os.Exit(1)
+	}
+	if *whenFlag != "" {
+		day, err := transit.ParseDayType(*whenFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+			os.Exit(1)
+		}
+		graphOptions.ServiceProfile = day
+	}
+	if *noInputFlag && *launchFlag {
+		fmt.Fprintln(os.Stderr, "ERROR: --launch opens a browser or maps app, which --no-input rules out")
+		os.Exit(1)
+	}
+	if *stdioJSONFlag {
+		var queryLog *transit.QueryLog
+		if *queryLogFlag != "" {
+			queryLog = transit.OpenQueryLog(*queryLogFlag)
+		}
+		_, nodeMap := buildGraph()
+		if err := transit.RunStdioJSON(nodeMap, os.Stdin, os.Stdout, queryLog, *budgetFlag); err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	args, destArg := flag.Args(), *toFlag
+	// A batch takes its trips from a file, and is planned with the options
+	// common to every trip once they are all set up below
+	batching := *batchFlag != ""
+	if batching {
+		if len(args) != 0 || destArg != "" {
+			fmt.Fprintln(os.Stderr, "ERROR: --batch reads the trips to plan from its file, not the command line")
+			os.Exit(1)
+		}
+		if *adviseFlag > 0 || *preferSeatFlag > 0 || *needFlag != "" || *alternativesFlag > 1 ||
+			*tradeoffsFlag || *roundTripFlag || *arriveByFlag != "" || *departWindowFlag != "" ||
+			*simulateFlag > 0 || *taxiFlag || *openInFlag != "" || *maxDurationFlag > 0 ||
+			*formatFlag != "text" || *optimizeFlag == string(transit.OptimizeCheapest) {
+			fmt.Fprintln(os.Stderr, "ERROR: --batch writes one CSV row per trip, and cannot be combined with "+
+				"--advise, --prefer-seat, --need, --alternatives, --tradeoffs, --round-trip, --arrive-by, "+
+				"--depart-window, --simulate, --taxi, --open-in, --max-duration, --format or --optimize cheapest")
+			os.Exit(1)
+		}
+	} else {
+		if destArg == "" && len(args) == 1 && strings.HasPrefix(args[0], "@") {
+			favourite := findFavourite(*journeyStoreFlag, strings.TrimPrefix(args[0], "@"))
+			args, destArg = []string{favourite.From}, favourite.To
+		}
+		if destArg == "" && len(args) == 2 {
+			args, destArg = args[:1], args[1]
+		}
+		if len(args) != 1 || destArg == "" {
+			flag.Usage()
+			os.Exit(1)
+		}
+	}
+	_, nodeMap := buildGraph()
+	var start string
+	var dests []string
+	if !batching {
+		start, dests = args[0], strings.Split(destArg, "|")
+		for idx := range dests {
+			dests[idx] = strings.TrimSpace(dests[idx])
+		}
+		for idx, station := range append([]string{start}, dests...) {
+			if err := checkTripStation(nodeMap, station, idx > 0, *stepFreeFlag); err != nil {
+				fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+				os.Exit(1)
+			}
+		}
+	}
+	if *interchangeSpeedFlag <= 0 || *streetSpeedFlag <= 0 {
+		fmt.Fprintln(os.Stderr, "ERROR: Walking speeds must be greater than zero")
 		os.Exit(1)
 	}
-	graph, nodeMap := BuildTransitGraph()
-	start, dest := os.Args[1], os.Args[2]
-	if _, startExists := nodeMap[start]; !startExists {
-		fmt.Fprintf(os.Stderr, "ERROR: %s is not a valid initial station\n", start)
+	if !slices.Contains([]string{"text", "json", "symbols", "markdown", "csv", "png"}, *formatFlag) {
+		fmt.Fprintf(os.Stderr, "ERROR: Unknown output format: %s\n", *formatFlag)
+		os.Exit(1)
+	}
+	var mapWidth, mapHeight int
+	if *formatFlag == "png" {
+		if *outFlag == "" {
+			fmt.Fprintln(os.Stderr, "ERROR: --format png needs --out <file> to write the map to")
+			os.Exit(1)
+		}
+		if *alternativesFlag > 1 || *tradeoffsFlag {
+			fmt.Fprintln(os.Stderr, "ERROR: --format png cannot be combined with --alternatives or --tradeoffs")
+			os.Exit(1)
+		}
+		if n, err := fmt.Sscanf(*mapSizeFlag, "%dx%d", &mapWidth, &mapHeight); n != 2 || err != nil ||
+			mapWidth < 200 || mapHeight < 200 || mapWidth > 4096 || mapHeight > 4096 {
+			fmt.Fprintf(os.Stderr, "ERROR: Invalid map size %q, expected WxH between 200 and 4096 pixels\n",
+				*mapSizeFlag)
+			os.Exit(1)
+		}
+	}
+	if *widthFlag != 0 && *widthFlag < transit.MinOutputWidth {
+		fmt.Fprintf(os.Stderr, "ERROR: Output width must be at least %d columns\n", transit.MinOutputWidth)
 		os.Exit(1)
 	}
-	if _, destExists := nodeMap[dest]; !destExists {
-		fmt.Fprintf(os.Stderr, "ERROR: %s is not a valid destination\n", dest)
+	planner := transit.NewPlanner(transit.NewGraph(nodeMap))
+	opts := &planner.Options
+	opts.InterchangeSpeed, opts.StreetSpeed = *interchangeSpeedFlag, *streetSpeedFlag
+	opts.CrossCheck = *crossCheckFlag
+	opts.DepartAt = time.Now()
+	if *departAtFlag != "" {
+		var err error
+		if opts.DepartAt, err = transit.ParseDepartAt(*departAtFlag, time.Now()); err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	var windowFrom, windowTo time.Time
+	if *departWindowFlag != "" {
+		var err error
+		if windowFrom, windowTo, err = transit.ParseDepartWindow(*departWindowFlag, time.Now()); err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+			os.Exit(1)
+		}
+		if *everyFlag < time.Minute {
+			fmt.Fprintln(os.Stderr, "ERROR: --every must be at least a minute")
+			os.Exit(1)
+		}
+		if *departAtFlag != "" || *arriveByFlag != "" || *adviseFlag > 0 || *preferSeatFlag > 0 ||
+			*alternativesFlag > 1 || *tradeoffsFlag || *taxiFlag || *simulateFlag > 0 ||
+			*optimizeFlag == string(transit.OptimizeCheapest) || *needFlag != "" || *formatFlag != "text" {
+			fmt.Fprintln(os.Stderr, "ERROR: --depart-window only works with text output, "+
+				"not with --depart-at, --arrive-by, --advise, --prefer-seat, --alternatives, --tradeoffs, "+
+				"--taxi, --simulate, --need, --format or --optimize cheapest")
+			os.Exit(1)
+		}
+	} else if *everyFlag != 10*time.Minute {
+		fmt.Fprintln(os.Stderr, "ERROR: --every only works with --depart-window")
 		os.Exit(1)
 	}
-	route, linkTypes := RunShortestPaths(&graph, nodeMap, start, dest)
-	PrintDirections(route, linkTypes)
+	var arriveBy time.Time
+	if *arriveByFlag != "" {
+		var err error
+		if arriveBy, err = transit.ParseDepartAt(*arriveByFlag, time.Now()); err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+			os.Exit(1)
+		}
+		if *departAtFlag != "" || *adviseFlag > 0 || *preferSeatFlag > 0 || *alternativesFlag > 1 ||
+			*tradeoffsFlag || *optimizeFlag == string(transit.OptimizeCheapest) {
+			fmt.Fprintln(os.Stderr, "ERROR: --arrive-by cannot be combined with --depart-at, --advise, "+
+				"--prefer-seat, --alternatives, --tradeoffs or --optimize cheapest")
+			os.Exit(1)
+		}
+	}
+	var err error
+	if opts.Optimize, err = transit.ParseObjective(*optimizeFlag); err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+		os.Exit(1)
+	}
+	if *interchangePenaltyFlag != "" {
+		if opts.InterchangePenalty, err = transit.ParseInterchangePenalty(*interchangePenaltyFlag); err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	var needs transit.AccessibilityAids
+	if *accessibilityFlag != "" {
+		var err error
+		if needs, err = transit.ParseAccessibilityNeeds(*accessibilityFlag); err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+			os.Exit(1)
+		}
+		opts.BoardingPenalty = transit.AccessibilityBoardingPenalty(needs)
+	}
+	for line := range avoidLines {
+		if !transit.LineExists(nodeMap, line) {
+			fmt.Fprintf(os.Stderr, "ERROR: %s is not a valid line\n", line)
+			os.Exit(1)
+		}
+	}
+	for station := range avoidStations {
+		if _, exists := nodeMap[station]; !exists {
+			fmt.Fprintf(os.Stderr, "ERROR: %s is not a valid station\n", station)
+			os.Exit(1)
+		}
+		if station == start || slices.Contains(dests, station) {
+			fmt.Fprintf(os.Stderr, "ERROR: Cannot avoid %s, where the trip begins or ends\n", station)
+			os.Exit(1)
+		}
+	}
+	opts.ClosedLines, opts.AvoidStations = avoidLines, avoidStations
+	opts.StepFree = *stepFreeFlag
+	var need transit.Facility
+	if *needFlag != "" {
+		var err error
+		if need, err = transit.ParseFacility(*needFlag); err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	var strike *transit.Strike
+	if *strikeFlag != "" {
+		parsed, err := transit.ParseStrike(*strikeFlag)
+		if err == nil {
+			err = parsed.Validate(nodeMap)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: Invalid strike: %v\n", err)
+			os.Exit(1)
+		}
+		strike = &parsed
+		strike.Apply(opts)
+	}
+	var liveStatuses map[string]transit.LineStatus
+	liveClosed := make([]string, 0)
+	if *liveFlag {
+		liveStatuses = loadLiveStatuses(*statusStoreFlag, *liveTimeoutFlag)
+		for _, line := range slices.Sorted(maps.Keys(transit.ClosedLinesAt(liveStatuses, opts.DepartAt))) {
+			if !opts.ClosedLines[line] {
+				liveClosed = append(liveClosed, line)
+			}
+		}
+		transit.ApplyLineStatuses(opts, liveStatuses, opts.DepartAt)
+	}
+	if batching {
+		runBatch(planner, *batchFlag, *stepFreeFlag)
+		return
+	}
+	relaxations := make([]relaxation, 0)
+	for _, line := range slices.Sorted(maps.Keys(avoidLines)) {
+		relaxations = append(relaxations, reopenLines("--avoid-line "+line, line))
+	}
+	for _, station := range slices.Sorted(maps.Keys(avoidStations)) {
+		relaxations = append(relaxations, relaxation{"--avoid-station " + station,
+			func(opts *transit.SearchOptions) {
+				opts.AvoidStations = maps.Clone(opts.AvoidStations)
+				delete(opts.AvoidStations, station)
+			}})
+	}
+	if strike != nil {
+		relaxations = append(relaxations, reopenLines("--strike", strike.Lines()...))
+	}
+	if len(liveClosed) > 0 {
+		relaxations = append(relaxations, reopenLines("--live", liveClosed...))
+	}
+	if *accessibilityFlag != "" {
+		relaxations = append(relaxations, relaxation{"--accessibility", func(opts *transit.SearchOptions) {
+			opts.BoardingPenalty = nil
+		}})
+	}
+	if *stepFreeFlag {
+		relaxations = append(relaxations, relaxation{"--step-free", func(opts *transit.SearchOptions) {
+			opts.StepFree = false
+		}})
+	}
+	if opts.Optimize != transit.OptimizeTime {
+		relaxations = append(relaxations, relaxation{"--optimize " + string(opts.Optimize),
+			func(opts *transit.SearchOptions) { opts.Optimize = transit.OptimizeTime }})
+	}
+	if *preferSeatFlag > 0 {
+		relaxations = append(relaxations, relaxation{"--prefer-seat", func(*transit.SearchOptions) {}})
+	}
+	if need != "" {
+		relaxations = append(relaxations, relaxation{"--need", func(*transit.SearchOptions) {}})
+	}
+	if *interchangeSpeedFlag != 1 || *streetSpeedFlag != 1 {
+		relaxations = append(relaxations, relaxation{"the slower walking speeds", func(opts *transit.SearchOptions) {
+			opts.InterchangeSpeed, opts.StreetSpeed = 1, 1
+		}})
+	}
+	if *departAtFlag != "" {
+		relaxations = append(relaxations, relaxation{"--depart-at, leaving now instead",
+			func(opts *transit.SearchOptions) { opts.DepartAt = time.Now() }})
+	}
+	// Arriving by a set time means setting off as late as possible, and the
+	// rest of the plan is then made for leaving then
+	if !arriveBy.IsZero() {
+		if need != "" {
+			fmt.Fprintln(os.Stderr, "ERROR: --arrive-by cannot be combined with --need")
+			os.Exit(1)
+		}
+		route, departAt, err := planner.PlanArriveBy(start, dests, arriveBy)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: No route from %s arrives at %s by %s\n",
+				start, strings.Join(dests, " or "), arriveBy.Format("15:04"))
+			os.Exit(1)
+		}
+		opts.DepartAt, dests = departAt, []string{route.Destination()}
+	}
+	transit.RelativeDirections = *relativeFlag
+	transit.RelativeLead = transit.LeadMinutes(opts.DepartAt, time.Now())
+	// Given several candidate destinations, head for whichever can be
+	// reached soonest
+	dest := dests[0]
+	if len(dests) > 1 {
+		chosen, err := planner.PlanToAny(start, dests)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: No route available from %s to any of %s\n",
+				start, strings.Join(dests, ", "))
+			os.Exit(1)
+		}
+		dest = chosen.Destination()
+	}
+	var mapsURL string
+	if *openInFlag != "" {
+		var err error
+		if mapsURL, err = transit.MapsDirectionsURL(*openInFlag, start, dest); err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if opts.Optimize == transit.OptimizeCheapest && (*adviseFlag > 0 || *preferSeatFlag > 0 || *alternativesFlag > 1) {
+		fmt.Fprintln(os.Stderr, "ERROR: --optimize cheapest cannot be combined with --advise, --prefer-seat or --alternatives")
+		os.Exit(1)
+	}
+	if need != "" && (*adviseFlag > 0 || *preferSeatFlag > 0 || *alternativesFlag > 1 ||
+		opts.Optimize == transit.OptimizeCheapest) {
+		fmt.Fprintln(os.Stderr, "ERROR: --need cannot be combined with --advise, --prefer-seat, "+
+			"--alternatives or --optimize cheapest")
+		os.Exit(1)
+	}
+	if *simulateFlag < 0 || *simulateFlag > 1_000_000 {
+		fmt.Fprintln(os.Stderr, "ERROR: --simulate takes between 1 and 1000000 runs")
+		os.Exit(1)
+	}
+	if *simulateFlag > 0 && (*formatFlag != "text" || *alternativesFlag > 1 || *tradeoffsFlag) {
+		fmt.Fprintln(os.Stderr, "ERROR: --simulate only works with text output of a single route, "+
+			"not with --format, --alternatives or --tradeoffs")
+		os.Exit(1)
+	}
+	var taxi *transit.Taxi
+	if *taxiFlag {
+		if *formatFlag != "text" || *alternativesFlag > 1 || *tradeoffsFlag || *adviseFlag > 0 ||
+			*preferSeatFlag > 0 || need != "" || opts.Optimize == transit.OptimizeCheapest ||
+			!arriveBy.IsZero() || len(dests) > 1 {
+			fmt.Fprintln(os.Stderr, "ERROR: --taxi only works with text output of a single route to one destination, "+
+				"not with --format, --alternatives, --tradeoffs, --advise, --prefer-seat, --need, "+
+				"--optimize cheapest or --arrive-by")
+			os.Exit(1)
+		}
+		if len(transit.GetStationCoordinates()) == 0 {
+			fmt.Fprintln(os.Stderr, "ERROR: No station coordinates are known, import them with "+
+				"\"./tubeplanner import-coords\" to use --taxi")
+			os.Exit(1)
+		}
+		if *taxiSpeedFlag <= 0 {
+			fmt.Fprintln(os.Stderr, "ERROR: Taxi speed must be greater than zero")
+			os.Exit(1)
+		}
+		flat, perKM, err := transit.ParseTaxiFare(*taxiFareFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+			os.Exit(1)
+		}
+		taxi = &transit.Taxi{SpeedKMH: *taxiSpeedFlag, FlatPence: flat, PencePerKM: perKM}
+	}
+	if *sortFlag != "time" && *alternativesFlag <= 1 {
+		fmt.Fprintln(os.Stderr, "ERROR: --sort only works with --alternatives")
+		os.Exit(1)
+	}
+	// What every trip is planned from, for telling whether two answers match:
+	// the network and the options in effect when the trip is printed, with
+	// each trip's route hash added as it is
+	runStamp := func() *transit.Stamp {
+		options := opts.Describe()
+		if *accessibilityFlag != "" {
+			options += "; accessibility=" + *accessibilityFlag
+		}
+		if *preferSeatFlag > 0 {
+			options += fmt.Sprintf("; prefer-seat=%d", *preferSeatFlag)
+		}
+		if need != "" {
+			options += fmt.Sprintf("; need=%s", need)
+		}
+		if *adviseFlag > 0 {
+			options += fmt.Sprintf("; advise=%d", *adviseFlag)
+		}
+		return &transit.Stamp{DataVersion: planner.Graph().DataVersion(), Options: options}
+	}
+	if *roundTripFlag {
+		if *formatFlag != "text" || *adviseFlag > 0 || *preferSeatFlag > 0 || *alternativesFlag > 1 ||
+			*tradeoffsFlag || need != "" || taxi != nil || *simulateFlag > 0 || *departWindowFlag != "" ||
+			opts.Optimize == transit.OptimizeCheapest {
+			fmt.Fprintln(os.Stderr, "ERROR: --round-trip only works with text output, not with --format, "+
+				"--advise, --prefer-seat, --alternatives, --tradeoffs, --need, --taxi, --simulate, "+
+				"--depart-window or --optimize cheapest")
+			os.Exit(1)
+		}
+		outbound, err := planner.Plan(start, dest)
+		if err != nil {
+			exitNoRoute(planner, start, dest, err)
+		}
+		back, err := planner.Plan(dest, start)
+		if err != nil {
+			exitNoRoute(planner, dest, start, err)
+		}
+		if len(dests) > 1 {
+			fmt.Printf("Heading for %s, the soonest reachable of the %d destinations.\n",
+				dest, len(dests))
+		}
+		printRoundTrip(outbound, back, *widthFlag, *detailedFlag, needs, runStamp())
+		return
+	}
+	if *departWindowFlag != "" {
+		samples, err := planner.SampleDepartures(start, dest, windowFrom, windowTo, *everyFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+			os.Exit(1)
+		}
+		if len(dests) > 1 {
+			fmt.Printf("Heading for %s, the soonest reachable of the %d destinations.\n",
+				dest, len(dests))
+		}
+		transit.PrintDepartureSamples(samples)
+		return
+	}
+	if *tradeoffsFlag {
+		if *adviseFlag > 0 || *preferSeatFlag > 0 || *alternativesFlag > 1 || need != "" ||
+			opts.Optimize == transit.OptimizeCheapest {
+			fmt.Fprintln(os.Stderr, "ERROR: --tradeoffs cannot be combined with --advise, --prefer-seat, "+
+				"--alternatives, --need or --optimize cheapest")
+			os.Exit(1)
+		}
+		tradeoffs, err := planner.Tradeoffs(start, dest)
+		if err != nil {
+			exitNoRoute(planner, start, dest, err)
+		}
+		if *formatFlag == "text" && len(dests) > 1 {
+			fmt.Printf("Heading for %s, the soonest reachable of the %d destinations.\n",
+				dest, len(dests))
+		}
+		checkMaxDuration(planner, start, dest, tradeoffs[0].TotalMinutes(), *maxDurationFlag, relaxations)
+		printTradeoffs(tradeoffs, *formatFlag, *widthFlag, *detailedFlag, needs, runStamp())
+		return
+	}
+	if *alternativesFlag > 1 {
+		if *adviseFlag > 0 || *preferSeatFlag > 0 {
+			fmt.Fprintln(os.Stderr, "ERROR: --alternatives cannot be combined with --advise or --prefer-seat")
+			os.Exit(1)
+		}
+		order, err := transit.ParseJourneyOrder(*sortFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+			os.Exit(1)
+		}
+		routes, err := planner.Alternatives(start, dest, *alternativesFlag)
+		if err != nil {
+			exitNoRoute(planner, start, dest, err)
+		}
+		if *formatFlag == "text" && len(dests) > 1 {
+			fmt.Printf("Heading for %s, the soonest reachable of the %d destinations.\n",
+				dest, len(dests))
+		}
+		checkMaxDuration(planner, start, dest, routes[0].TotalMinutes(), *maxDurationFlag, relaxations)
+		printAlternatives(routes, order, opts.DepartAt, *formatFlag, *widthFlag, *detailedFlag, needs,
+			runStamp())
+		return
+	}
+	// Weigh each line by how often it has been delayed of late, from the
+	// recorded line statuses
+	if opts.Optimize == transit.OptimizeReliable {
+		reliability, err := transit.OpenStatusHistory(*statusStoreFlag).Reliability(time.Now().Add(-reliabilityWindow))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: Could not read status history: %v\n", err)
+			os.Exit(1)
+		}
+		if len(reliability) == 0 {
+			fmt.Fprintf(os.Stderr, "ERROR: No line statuses recorded in %s over the last %d days "+
+				"to judge reliability by\n", *statusStoreFlag, int(reliabilityWindow.Hours()/24))
+			os.Exit(1)
+		}
+		opts.AddLineDelays(transit.LineDelays(reliability))
+	}
+	// Optimizing for anything but time may cost time, so plan the fastest
+	// route as well to say how much
+	var fastestMinutes uint16
+	var fastestFare transit.Fare
+	if opts.Optimize != transit.OptimizeTime {
+		fastestPlanner := transit.NewPlanner(planner.Graph())
+		fastestPlanner.Options = planner.Options
+		fastestPlanner.Options.Optimize = transit.OptimizeTime
+		if fastest, err := fastestPlanner.Plan(start, dest); err == nil {
+			fastestMinutes = fastest.TotalMinutes()
+			fastestFare, _ = transit.EstimateFare(fastest.Journey(), opts.DepartAt)
+		}
+	}
+	var journey *transit.Journey
+	var extraTime uint16
+	var fare transit.Fare
+	if *adviseFlag > 0 {
+		journey = RunAdvisor(start, dest, uint16(min(*adviseFlag, 24*60)), *statusStoreFlag, opts)
+	} else {
+		var err error
+		if *preferSeatFlag > 0 {
+			journey, extraTime, err = transit.PlanSeatFriendlyRoute(nodeMap, start, dest,
+				uint16(min(*preferSeatFlag, math.MaxUint16)), opts)
+		} else if need != "" {
+			journey, err = transit.PlanFacilityRoute(nodeMap, start, dest, need, opts)
+		} else if opts.Optimize == transit.OptimizeCheapest {
+			journey, fare, err = transit.PlanCheapestRoute(nodeMap, start, dest, opts)
+		} else {
+			var planned *transit.Route
+			if planned, err = planner.Plan(start, dest); err == nil {
+				journey = planned.Journey()
+			} else if taxi != nil && errors.Is(err, transit.ErrNoRoute) {
+				plan, err := planner.PlanWithTaxi(start, dest, *taxi)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+					os.Exit(1)
+				}
+				printTaxiPlan(start, dest, plan, opts.DepartAt, *widthFlag, *detailedFlag)
+				return
+			}
+		}
+		if err != nil {
+			exitNoRoute(planner, start, dest, err)
+		}
+	}
+	// Trips already at their destination have no legs
+	travelling := len(journey.Legs) > 0
+	if travelling {
+		checkMaxDuration(planner, start, dest, journey.TotalMinutes, *maxDurationFlag, relaxations)
+	}
+	base := runStamp()
+	journey.Stamp = transit.NewStamp(journey, base.DataVersion, base.Options)
+	if !*noHistoryFlag && travelling {
+		recordJourney(*journeyStoreFlag, journey)
+	}
+	switch *formatFlag {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		if estimated, err := transit.EstimateFare(journey, opts.DepartAt); err == nil && travelling {
+			journey.Fare = &estimated
+		}
+		enc.Encode(journey)
+	case "symbols":
+		fmt.Printf("%s [route %s, data %s]\n", transit.JourneySymbols(journey), journey.Stamp.RouteHash,
+			journey.Stamp.DataVersion)
+	case "markdown":
+		fmt.Print(transit.JourneyMarkdown(journey, opts.DepartAt))
+		fmt.Printf("\nStamp: %s\n", journey.Stamp)
+	case "csv":
+		// The stamp goes to stderr, keeping the output a plain table
+		transit.WriteJourneyCSV(os.Stdout, journey, opts.DepartAt)
+		fmt.Fprintf(os.Stderr, "Stamp: %s\n", journey.Stamp)
+	case "png":
+		client := &http.Client{Timeout: 10 * time.Second}
+		img, err := transit.RenderRouteMap(client, journey, *tileURLFlag, mapWidth, mapHeight)
+		if err == nil {
+			err = transit.WriteFileAtomic(*outFlag, func(w io.Writer) error { return png.Encode(w, img) })
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: Could not draw the route map: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Wrote a map of the route from %s to %s to %s.\n", start, dest, *outFlag)
+		fmt.Printf("Stamp: %s\n", journey.Stamp)
+	default:
+		if strike != nil {
+			fmt.Printf("%s: planning without those lines.\n", strike)
+		}
+		if !arriveBy.IsZero() {
+			fmt.Printf("Leave %s at %s to arrive at %s by %s.\n", start, opts.DepartAt.Format("15:04"),
+				dest, arriveBy.Format("15:04"))
+		}
+		if disruptions := transit.DescribeDisruptions(liveStatuses); len(disruptions) > 0 {
+			fmt.Println("Line disruptions reported by TfL:")
+			for _, disruption := range disruptions {
+				fmt.Printf("- %s\n", disruption)
+			}
+		}
+		if len(dests) > 1 {
+			fmt.Printf("Heading for %s, the soonest reachable of the %d destinations.\n",
+				dest, len(dests))
+		}
+		if *widthFlag > 0 {
+			transit.PrintDirectionsWidth(journey, *widthFlag, *detailedFlag)
+		} else {
+			transit.PrintDirections(journey, *detailedFlag)
+		}
+		transit.PrintDataCoverage(journey)
+		if needs != 0 {
+			transit.PrintAccessibilityNotes(journey, needs)
+		}
+		if need != "" {
+			transit.PrintFacilityNotes(journey, need)
+		}
+		if opts.StepFree && travelling {
+			fmt.Println("(Step-free route: lifts or ramps at every entrance, exit and interchange used. " +
+				"Where a train needs a boarding ramp, the directions say to ask staff for one.)")
+		}
+		transit.PrintPeakFlowHints(nodeMap, journey, opts.DepartAt)
+		transit.PrintFare(journey, opts.DepartAt)
+		if opts.Optimize == transit.OptimizeChanges && travelling {
+			changes := journey.Changes()
+			if slower := int(journey.TotalMinutes) - int(fastestMinutes); slower > 0 {
+				fmt.Printf("(Fewest changes: %d, %d minutes slower than the fastest route.)\n",
+					changes, slower)
+			} else {
+				fmt.Printf("(Fewest changes: %d, as fast as any other route.)\n", changes)
+			}
+		}
+		if opts.Optimize == transit.OptimizeReliable && travelling {
+			delay := transit.ExpectedRouteDelay(journey, opts.LineDelays)
+			if slower := int(journey.TotalMinutes) - int(fastestMinutes); slower > 0 {
+				fmt.Printf("(Most dependable route: usual delays add about %d minutes, "+
+					"%d minutes slower than the fastest route.)\n", delay, slower)
+			} else {
+				fmt.Printf("(Most dependable route: usual delays add about %d minutes, "+
+					"as fast as any other route.)\n", delay)
+			}
+		}
+		if opts.Optimize == transit.OptimizeEnergy && travelling {
+			deepMinutes, boardings := transit.RouteEnergy(journey)
+			if slower := int(journey.TotalMinutes) - int(fastestMinutes); slower > 0 {
+				fmt.Printf("(Energy-efficient route: %d minutes on deep-level lines over %d boardings, "+
+					"%d minutes slower than the fastest route.)\n", deepMinutes, boardings, slower)
+			} else {
+				fmt.Printf("(Energy-efficient route: %d minutes on deep-level lines over %d boardings, "+
+					"as fast as any other route.)\n", deepMinutes, boardings)
+			}
+		}
+		if opts.Optimize == transit.OptimizeCheapest && travelling && fare.Pence > 0 {
+			slower := int(journey.TotalMinutes) - int(fastestMinutes)
+			if saving := int(fastestFare.Pence) - int(fare.Pence); slower > 0 && saving > 0 {
+				fmt.Printf("(Cheapest route: saves £%d.%02d on the fastest route, but is %d minutes slower.)\n",
+					saving/100, saving%100, slower)
+			} else if slower > 0 {
+				fmt.Printf("(Cheapest route: %d minutes slower than the fastest route.)\n", slower)
+			} else {
+				fmt.Println("(Cheapest route: as fast as any other route.)")
+			}
+		}
+		if extraTime > 0 {
+			fmt.Printf("(Boarding nearer where trains start for a better chance of a seat, "+
+				"%d minutes slower than the fastest route.)\n", extraTime)
+		}
+		if *simulateFlag > 0 && travelling {
+			transit.PrintSimulation(transit.SimulateRoute(journey, opts, *simulateFlag,
+				rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))))
+		}
+		fmt.Printf("Stamp: %s\n", journey.Stamp)
+		if mapsURL != "" {
+			fmt.Printf("\nOpen in maps: %s\n", mapsURL)
+		}
+	}
+	if mapsURL != "" {
+		if *launchFlag {
+			if err := transit.OpenURL(mapsURL); err != nil {
+				fmt.Fprintf(os.Stderr, "ERROR: Could not open link: %v\n", err)
+				os.Exit(1)
+			}
+		}
+	}
 }
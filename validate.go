@@ -0,0 +1,51 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/maxboyko1/TubePlanner/pkg/transit"
+)
+
+// Entry point for the "validate" subcommand, which checks the built transit
+// graph for problems that would otherwise make trips route badly without
+// any error, such as parts of the network cut off from the rest or
+// implausible travel times. Exits with status 1 if any problem is found,
+// though not for warnings alone.
+func RunValidateCommand(args []string) {
+	fs := flag.NewFlagSet("validate", flag.ExitOnError)
+	dataSourceFlags.register(fs)
+	fs.Func("assume-interchanges", assumeInterchangesUsage, setAssumedInterchanges)
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "USAGE: ./tubeplanner validate [--assume-interchanges <minutes>] "+dataSourceUsage)
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 0 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	_, nodeMap := buildGraph()
+	issues := transit.ValidateGraph(nodeMap, transit.GetStationCoordinates())
+	overrides, err := transit.LoadStationOverrides(graphOptions.StationOverridesPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+		os.Exit(1)
+	}
+	issues = append(issues, transit.CheckStationOverrides(nodeMap, overrides)...)
+
+	problems := 0
+	for _, issue := range issues {
+		if issue.Warning {
+			fmt.Printf("WARNING: %s: %s\n", issue.Check, issue.Message)
+		} else {
+			fmt.Printf("ERROR: %s: %s\n", issue.Check, issue.Message)
+			problems++
+		}
+	}
+	fmt.Printf("Checked %d stations: %d problems, %d warnings\n", len(nodeMap), problems, len(issues)-problems)
+	if problems > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/maxboyko1/TubePlanner/pkg/transit"
+)
+
+// Entry point for the "verify" subcommand, which plans trips both locally and
+// with the TfL Journey Planner and reports where the two disagree, to help
+// find errors in the transit data. Exits with status 1 if any trip differs.
+func RunVerifyCommand(args []string) {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	pairsFlag := fs.String("pairs", "", "CSV `file` of trips to verify, one from,to per line")
+	toleranceFlag := fs.Uint("tolerance", 5, "travel time difference in `minutes` to accept")
+	departAtFlag := fs.String("depart-at", "", "departure `time` (HH:MM today, or RFC 3339) for both planners")
+	timeoutFlag := fs.Duration("timeout", 10*time.Second, "how long to wait for each TfL request")
+	dataSourceFlags.register(fs)
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "USAGE: ./tubeplanner verify [--tolerance 5] [--depart-at HH:MM] "+dataSourceUsage+" (<from> <to> | --pairs <file>)")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if (*pairsFlag == "") == (fs.NArg() == 0) || (fs.NArg() != 0 && fs.NArg() != 2) {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	_, nodeMap := buildGraph()
+	var pairs [][2]string
+	if *pairsFlag != "" {
+		file, err := os.Open(*pairsFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+			os.Exit(1)
+		}
+		pairs, err = transit.LoadStationPairs(file, nodeMap)
+		file.Close()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: %s: %v\n", *pairsFlag, err)
+			os.Exit(1)
+		}
+	} else {
+		for _, station := range fs.Args() {
+			if _, exists := nodeMap[station]; !exists {
+				fmt.Fprintf(os.Stderr, "ERROR: %s is not a valid station\n", station)
+				os.Exit(1)
+			}
+		}
+		pairs = [][2]string{{fs.Arg(0), fs.Arg(1)}}
+	}
+	departAt := time.Now()
+	if *departAtFlag != "" {
+		var err error
+		if departAt, err = transit.ParseDepartAt(*departAtFlag, time.Now()); err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	planner := transit.NewPlanner(transit.NewGraph(nodeMap))
+	planner.Options.DepartAt = departAt
+	client := &http.Client{Timeout: *timeoutFlag}
+	differing, failed := 0, 0
+	for _, pair := range pairs {
+		from, to := pair[0], pair[1]
+		tflJourney, err := transit.FetchTfLJourney(client, from, to, departAt)
+		if err != nil {
+			fmt.Printf("%s to %s: could not ask TfL: %v\n", from, to, err)
+			failed++
+			continue
+		}
+		route, err := planner.Plan(from, to)
+		if err != nil {
+			fmt.Printf("%s to %s:\n- has no route here but takes %d minutes on TfL\n",
+				from, to, tflJourney.Minutes)
+			differing++
+			continue
+		}
+		v := transit.Verification{From: from, To: to, LocalMinutes: route.TotalMinutes(),
+			LocalLines: transit.JourneyLines(route.Journey()), TfL: tflJourney}
+		discrepancies := v.Discrepancies(uint16(min(*toleranceFlag, 60)))
+		if len(discrepancies) == 0 {
+			fmt.Printf("%s to %s: agrees with TfL (%d minutes here, %d on TfL)\n",
+				from, to, v.LocalMinutes, v.TfL.Minutes)
+			continue
+		}
+		differing++
+		fmt.Printf("%s to %s:\n", from, to)
+		for _, discrepancy := range discrepancies {
+			fmt.Printf("- %s\n", discrepancy)
+		}
+	}
+
+	fmt.Printf("\n%d of %d trips differ from TfL", differing, len(pairs))
+	if failed > 0 {
+		fmt.Printf(", and %d could not be checked", failed)
+	}
+	fmt.Println(".")
+	if differing > 0 || failed > 0 {
+		os.Exit(1)
+	}
+}